@@ -30,13 +30,16 @@ import (
 
 // Global Stork Agent state.
 type StorkAgent struct {
-	Settings       *cli.Context
-	AppMonitor     AppMonitor
-	HTTPClient     *HTTPClient // to communicate with Kea Control Agent and named statistics-channel
-	server         *grpc.Server
-	logTailer      *logTailer
-	keaInterceptor *keaInterceptor
-	shutdownOnce   sync.Once
+	Settings   *cli.Context
+	AppMonitor AppMonitor
+	HTTPClient *HTTPClient // to communicate with Kea Control Agent and named statistics-channel
+	// Mappings of the configuration paths of the containerized apps
+	// to the paths on the host.
+	ConfigPathMappings PathMappings
+	server             *grpc.Server
+	logTailer          *logTailer
+	keaInterceptor     *keaInterceptor
+	shutdownOnce       sync.Once
 
 	agentapi.UnimplementedAgentServer
 }
@@ -136,6 +139,13 @@ func newGRPCServerWithTLS() (*grpc.Server, error) {
 
 // Setup the agent as gRPC server endpoint.
 func (sa *StorkAgent) Setup() error {
+	if sa.Settings != nil {
+		mappings, err := ParsePathMappings(sa.Settings.StringSlice("config-path-mapping"))
+		if err != nil {
+			return err
+		}
+		sa.ConfigPathMappings = mappings
+	}
 	server, err := newGRPCServerWithTLS()
 	if err != nil {
 		return err
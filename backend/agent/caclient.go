@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -21,7 +22,14 @@ var CredentialsFile = "/etc/stork/agent-credentials.json" // nolint:gochecknoglo
 // HTTPClient is a normal http client.
 type HTTPClient struct {
 	client      *http.Client
+	tlsConfig   *tls.Config
 	credentials *CredentialsStore
+	// Clients verifying the server certificates using the CA bundle or
+	// the trust anchors specified in the credentials store. They are
+	// indexed by the trust anchor paths.
+	trustAnchorClients map[string]*http.Client
+	// Protects the trust anchor clients replaced on the credentials reload.
+	mutex sync.RWMutex
 }
 
 // Create a client to contact with Kea Control Agent or named statistics-channel.
@@ -44,15 +52,7 @@ func NewHTTPClient(skipTLSVerification bool) *HTTPClient {
 		log.Warnf("cannot read TLS credentials, use HTTP protocol, %+v", err)
 	}
 
-	httpTransport := &http.Transport{
-		// Creating empty, non-nil map here disables the HTTP/2.
-		TLSNextProto:    make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
-		TLSClientConfig: &tlsConfig,
-	}
-
-	httpClient := &http.Client{
-		Transport: httpTransport,
-	}
+	httpClient := newHTTPClientWithTLSConfig(&tlsConfig)
 
 	credentialsStore := NewCredentialsStore()
 	// Check if the credential file exist
@@ -73,13 +73,80 @@ func NewHTTPClient(skipTLSVerification bool) *HTTPClient {
 	}
 
 	client := &HTTPClient{
-		client:      httpClient,
-		credentials: credentialsStore,
+		client:             httpClient,
+		tlsConfig:          &tlsConfig,
+		credentials:        credentialsStore,
+		trustAnchorClients: newTrustAnchorClients(&tlsConfig, credentialsStore),
 	}
 
 	return client
 }
 
+// Creates the clients verifying the server certificates using the
+// CA bundle and the trust anchors from the credentials store.
+func newTrustAnchorClients(tlsConfig *tls.Config, credentialsStore *CredentialsStore) map[string]*http.Client {
+	trustAnchorClients := make(map[string]*http.Client)
+	for _, trustAnchor := range credentialsStore.GetAllTrustAnchors() {
+		trustAnchorCertPool, err := readTrustAnchor(trustAnchor)
+		if err != nil {
+			log.Warnf("cannot read the trust anchor, the default CA certificates are used instead, %+v", err)
+			continue
+		}
+		trustAnchorTLSConfig := tlsConfig.Clone()
+		trustAnchorTLSConfig.RootCAs = trustAnchorCertPool
+		trustAnchorClients[trustAnchor] = newHTTPClientWithTLSConfig(trustAnchorTLSConfig)
+	}
+	return trustAnchorClients
+}
+
+// Re-reads the credentials file and replaces the credentials used by the
+// client without restarting the agent. It allows for adding the credentials
+// for a newly deployed Kea CA. If the file is missing or invalid, the
+// reload is rejected and the client keeps using the current credentials.
+func (c *HTTPClient) ReloadCredentials() error {
+	file, err := os.Open(CredentialsFile)
+	if err != nil {
+		return errors.Wrapf(err, "cannot open the credentials file (%s)", CredentialsFile)
+	}
+	defer file.Close()
+	if err = c.credentials.Reload(file); err != nil {
+		return errors.WithMessagef(err, "cannot reload the credentials file (%s)", CredentialsFile)
+	}
+	trustAnchorClients := newTrustAnchorClients(c.tlsConfig, c.credentials)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.trustAnchorClients = trustAnchorClients
+	return nil
+}
+
+// Creates the HTTP client using the specified TLS configuration.
+func newHTTPClientWithTLSConfig(tlsConfig *tls.Config) *http.Client {
+	httpTransport := &http.Transport{
+		// Creating empty, non-nil map here disables the HTTP/2.
+		TLSNextProto:    make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+		TLSClientConfig: tlsConfig,
+	}
+
+	return &http.Client{
+		Transport: httpTransport,
+	}
+}
+
+// Returns the HTTP client to be used to send the request to the specified
+// URL. If the trust anchor or the CA bundle is specified for the URL, the
+// client verifies the server certificate using it. Otherwise, the default
+// client is returned.
+func (c *HTTPClient) getClientByURL(url string) *http.Client {
+	if trustAnchor, ok := c.credentials.GetTrustAnchorByURL(url); ok {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+		if client, ok := c.trustAnchorClients[trustAnchor]; ok {
+			return client
+		}
+	}
+	return c.client
+}
+
 func (c *HTTPClient) Call(url string, payload io.Reader) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, payload)
 	if err != nil {
@@ -96,13 +163,27 @@ func (c *HTTPClient) Call(url string, payload io.Reader) (*http.Response, error)
 		req.Header.Add("Authorization", headerContent)
 	}
 
-	rsp, err := c.client.Do(req)
+	rsp, err := c.getClientByURL(url).Do(req)
 	if err != nil {
 		err = errors.Wrapf(err, "problem sending POST to %s", url)
 	}
 	return rsp, err
 }
 
+// Reads the CA certificates from the trust anchor file.
+func readTrustAnchor(trustAnchor string) (*x509.CertPool, error) {
+	ca, err := os.ReadFile(trustAnchor)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read the trust anchor: %s", trustAnchor)
+	}
+
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(ca); !ok {
+		return nil, errors.Errorf("no valid CA certificates in the trust anchor: %s", trustAnchor)
+	}
+	return certPool, nil
+}
+
 // TLS support - inspired by https://sirsean.medium.com/mutually-authenticated-tls-from-a-go-client-92a117e605a1
 func readTLSCredentials() (*x509.CertPool, []tls.Certificate, error) {
 	// Certificates
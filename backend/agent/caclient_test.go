@@ -3,6 +3,7 @@ package agent
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"isc.org/stork/testutil"
 	storkutil "isc.org/stork/util"
 )
 
@@ -168,3 +170,150 @@ func TestCallWithMissingBody(t *testing.T) {
 	require.NoError(t, err)
 	defer res.Body.Close()
 }
+
+// Creates the credentials file with the specified CA bundle and the trust
+// anchor for the test server location. The empty paths are not included.
+func writeTrustAnchorCredentialsFile(t *testing.T, sb *testutil.Sandbox, serverURL, caBundle, trustAnchor string) {
+	serverIP, serverPort, _ := storkutil.ParseURL(serverURL)
+
+	content := "{"
+	if caBundle != "" {
+		content += fmt.Sprintf(`"ca_bundle": "%s",`, caBundle)
+	}
+	content += `"tls": [`
+	if trustAnchor != "" {
+		content += fmt.Sprintf(`{"ip": "%s", "port": %d, "trust-anchor": "%s"}`, serverIP, serverPort, trustAnchor)
+	}
+	content += "]}"
+
+	var err error
+	CredentialsFile, err = sb.Write("credentials.json", content)
+	require.NoError(t, err)
+}
+
+// Test that the global CA bundle is used to verify the server certificate
+// when no trust anchor is specified for the server location.
+func TestCallWithCABundle(t *testing.T) {
+	restorePaths := RememberPaths()
+	defer restorePaths()
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	serverCAFile, err := sb.Write("server-ca.pem", string(serverCA))
+	require.NoError(t, err)
+
+	// The server certificate cannot be verified without the CA bundle.
+	writeTrustAnchorCredentialsFile(t, sb, ts.URL, "", "")
+	client := NewHTTPClient(false)
+	res, err := client.Call(ts.URL, nil)
+	require.Error(t, err)
+	require.Nil(t, res)
+
+	// The server certificate is verified using the CA bundle.
+	writeTrustAnchorCredentialsFile(t, sb, ts.URL, serverCAFile, "")
+	client = NewHTTPClient(false)
+	res, err = client.Call(ts.URL, nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+}
+
+// Test that the trust anchor specified for the server location overrides
+// the global CA bundle.
+func TestCallWithTrustAnchorOverridingCABundle(t *testing.T) {
+	restorePaths := RememberPaths()
+	defer restorePaths()
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	serverCAFile, err := sb.Write("server-ca.pem", string(serverCA))
+	require.NoError(t, err)
+	otherCAFile, err := sb.Write("other-ca.pem", string(testutil.GetCACertPEMContent()))
+	require.NoError(t, err)
+
+	// The trust anchor for the server location is valid while the CA
+	// bundle is not.
+	writeTrustAnchorCredentialsFile(t, sb, ts.URL, otherCAFile, serverCAFile)
+	client := NewHTTPClient(false)
+	res, err := client.Call(ts.URL, nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// The trust anchor for the server location is invalid while the CA
+	// bundle is valid. The trust anchor takes precedence.
+	writeTrustAnchorCredentialsFile(t, sb, ts.URL, serverCAFile, otherCAFile)
+	client = NewHTTPClient(false)
+	res, err = client.Call(ts.URL, nil)
+	require.Error(t, err)
+	require.Nil(t, res)
+}
+
+// Test that the HTTP client uses the new credentials after reloading
+// the credentials file and keeps the current credentials when the new
+// file is invalid.
+func TestReloadCredentials(t *testing.T) {
+	restorePaths := RememberPaths()
+	defer restorePaths()
+
+	CredentialsFile = path.Join(t.TempDir(), "credentials.json")
+	err := os.WriteFile(CredentialsFile, []byte(`{
+		"basic_auth": [
+			{
+				"ip": "192.0.2.1",
+				"port": 8000,
+				"user": "foo",
+				"password": "bar"
+			}
+		]
+	}`), 0o600)
+	require.NoError(t, err)
+
+	client := NewHTTPClient(true)
+	_, ok := client.credentials.GetBasicAuth("192.0.2.1", 8000)
+	require.True(t, ok)
+	_, ok = client.credentials.GetBasicAuth("192.0.2.2", 8000)
+	require.False(t, ok)
+
+	// Add the credentials for another Kea CA.
+	err = os.WriteFile(CredentialsFile, []byte(`{
+		"basic_auth": [
+			{
+				"ip": "192.0.2.1",
+				"port": 8000,
+				"user": "foo",
+				"password": "bar"
+			},
+			{
+				"ip": "192.0.2.2",
+				"port": 8000,
+				"user": "baz",
+				"password": "qux"
+			}
+		]
+	}`), 0o600)
+	require.NoError(t, err)
+
+	err = client.ReloadCredentials()
+	require.NoError(t, err)
+	credentials, ok := client.credentials.GetBasicAuth("192.0.2.2", 8000)
+	require.True(t, ok)
+	require.EqualValues(t, "baz", credentials.User)
+
+	// The invalid file must not affect the current credentials.
+	err = os.WriteFile(CredentialsFile, []byte(`{ "basic_auth": [`), 0o600)
+	require.NoError(t, err)
+
+	err = client.ReloadCredentials()
+	require.Error(t, err)
+	_, ok = client.credentials.GetBasicAuth("192.0.2.1", 8000)
+	require.True(t, ok)
+	_, ok = client.credentials.GetBasicAuth("192.0.2.2", 8000)
+	require.True(t, ok)
+}
@@ -12,15 +12,20 @@ package agent
 import (
 	"encoding/json"
 	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	storkutil "isc.org/stork/util"
 )
 
 // Kea CA location in the network. It is a key of the credentials store.
-// It is the internal structure of the credentials store.
+// It is the internal structure of the credentials store. The host is
+// an IP address in the canonical form or a lower case hostname.
 type location struct {
-	IP   string
+	Host string
 	Port int64
 }
 
@@ -31,27 +36,46 @@ type BasicAuthCredentials struct {
 }
 
 // Credentials store with an API to add/update/delete the content.
+// The store is safe for concurrent use.
 type CredentialsStore struct {
+	mutex                *sync.RWMutex
 	basicAuthCredentials map[location]*BasicAuthCredentials
+	// Path to the CA bundle used to verify the Kea CA certificates
+	// when no trust anchor is specified for the network location.
+	caBundle     string
+	trustAnchors map[location]string
 }
 
 // Structure of the credentials JSON file.
 type CredentialsStoreContent struct {
 	BasicAuth []CredentialsStoreContentBasicAuthEntry `json:"basic_auth"`
+	CABundle  *string                                 `json:"ca_bundle,omitempty"`
+	TLS       []CredentialsStoreContentTLSEntry       `json:"tls,omitempty"`
 }
 
 // Single Basic Auth item of the credentials JSON file.
 type CredentialsStoreContentBasicAuthEntry struct {
-	IP       *string
-	Port     *int64
-	User     *string
-	Password *string
+	IP       *string `json:"ip"`
+	Port     *int64  `json:"port"`
+	User     *string `json:"user"`
+	Password *string `json:"password"`
+}
+
+// Single TLS item of the credentials JSON file. It specifies the path
+// to the trust anchor (a file with the CA certificates) used to verify
+// the Kea CA certificate at the specified network location.
+type CredentialsStoreContentTLSEntry struct {
+	IP          *string `json:"ip"`
+	Port        *int64  `json:"port"`
+	TrustAnchor *string `json:"trust-anchor"`
 }
 
 // Constructor of the credentials store.
 func NewCredentialsStore() *CredentialsStore {
 	return &CredentialsStore{
+		mutex:                &sync.RWMutex{},
 		basicAuthCredentials: make(map[location]*BasicAuthCredentials),
+		trustAnchors:         make(map[location]string),
 	}
 }
 
@@ -66,46 +90,121 @@ func NewBasicAuthCredentials(user, password string) *BasicAuthCredentials {
 // Get Basic Auth credentials by URL
 // The Basic Auth is often used during HTTP calls. It is helper function
 // to retrieve the credentials based on the request URL. The URL contains
-// a protocol, URL segments and the query parameters.
+// a protocol, URL segments and the query parameters. The host component
+// of the URL may be an IP address or a hostname.
 func (cs *CredentialsStore) GetBasicAuthByURL(url string) (*BasicAuthCredentials, bool) {
 	address, port, _ := storkutil.ParseURL(url)
 	return cs.GetBasicAuth(address, port)
 }
 
-// Get Basic Auth credentials by the network location (IP address and port).
+// Get Basic Auth credentials by the network location (IP address or
+// hostname and port).
 func (cs *CredentialsStore) GetBasicAuth(address string, port int64) (*BasicAuthCredentials, bool) {
 	location, err := newLocation(address, port)
 	if err != nil {
 		return nil, false
 	}
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
 	item, ok := cs.basicAuthCredentials[location]
 	return item, ok
 }
 
-// Add or update the Basic Auth credentials by the network location (IP address and port).
-// If the credentials already exist in the store then they will be override.
+// Add or update the Basic Auth credentials by the network location (IP address
+// or hostname and port). If the credentials already exist in the store then they
+// will be override.
 func (cs *CredentialsStore) AddOrUpdateBasicAuth(address string, port int64, credentials *BasicAuthCredentials) error {
 	location, err := newLocation(address, port)
 	if err != nil {
 		return err
 	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
 	cs.basicAuthCredentials[location] = credentials
 	return nil
 }
 
-// Remove the Basic Auth credentials by the network location (IP address and port).
+// Remove the Basic Auth credentials by the network location (IP address or hostname and port).
 // If the credentials don't exist then this function does nothing.
 func (cs *CredentialsStore) RemoveBasicAuth(address string, port int64) {
 	location, err := newLocation(address, port)
 	if err != nil {
 		return
 	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
 	delete(cs.basicAuthCredentials, location)
 }
 
+// Set the path to the CA bundle used to verify the Kea CA certificates
+// when no trust anchor is specified for the network location.
+func (cs *CredentialsStore) SetCABundle(caBundle string) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.caBundle = caBundle
+}
+
+// Get the trust anchor by URL. See GetTrustAnchor for details.
+func (cs *CredentialsStore) GetTrustAnchorByURL(url string) (string, bool) {
+	address, port, _ := storkutil.ParseURL(url)
+	return cs.GetTrustAnchor(address, port)
+}
+
+// Get the path to the trust anchor used to verify the Kea CA certificate
+// at the network location (IP address or hostname and port). The trust anchor specified
+// for the location overrides the global CA bundle. It returns false if
+// neither the trust anchor nor the CA bundle is specified.
+func (cs *CredentialsStore) GetTrustAnchor(address string, port int64) (string, bool) {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+	if location, err := newLocation(address, port); err == nil {
+		if trustAnchor, ok := cs.trustAnchors[location]; ok {
+			return trustAnchor, true
+		}
+	}
+	if cs.caBundle != "" {
+		return cs.caBundle, true
+	}
+	return "", false
+}
+
+// Get the paths to all trust anchors and the CA bundle specified in the store.
+// The paths are not duplicated.
+func (cs *CredentialsStore) GetAllTrustAnchors() []string {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+	var trustAnchors []string
+	unique := make(map[string]bool)
+	if cs.caBundle != "" {
+		trustAnchors = append(trustAnchors, cs.caBundle)
+		unique[cs.caBundle] = true
+	}
+	for _, trustAnchor := range cs.trustAnchors {
+		if !unique[trustAnchor] {
+			trustAnchors = append(trustAnchors, trustAnchor)
+			unique[trustAnchor] = true
+		}
+	}
+	return trustAnchors
+}
+
+// Add or update the trust anchor by the network location (IP address or hostname and port).
+// If the trust anchor already exists in the store then it will be overridden.
+func (cs *CredentialsStore) AddOrUpdateTrustAnchor(address string, port int64, trustAnchor string) error {
+	location, err := newLocation(address, port)
+	if err != nil {
+		return err
+	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.trustAnchors[location] = trustAnchor
+	return nil
+}
+
 // Read the credentials store content from reader.
 // The file may contain IP addresses in the different forms,
-// they will be converted to canonical forms.
+// they will be converted to canonical forms. The ip fields may
+// also contain the hostnames.
 func (cs *CredentialsStore) Read(reader io.Reader) error {
 	rawContent, err := io.ReadAll(reader)
 	if err != nil {
@@ -119,19 +218,136 @@ func (cs *CredentialsStore) Read(reader io.Reader) error {
 	return cs.loadContent(&content)
 }
 
-// Constructor of the network location (IP address and port).
+// Write the credentials store content to writer. The output has the same
+// structure as the content consumed by Read. The entries are sorted by
+// the host and port, so the output is stable. The IP addresses are
+// written in the canonical forms and the hostnames in lower case.
+func (cs *CredentialsStore) Write(writer io.Writer) error {
+	content := cs.dumpContent()
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(content); err != nil {
+		return errors.Wrap(err, "cannot write the credentials")
+	}
+	return nil
+}
+
+// Replace the credentials store content with the content read from reader.
+// The new content is first read into a temporary store. If it is invalid,
+// the reload is rejected and the current content is preserved. Otherwise,
+// the content is swapped atomically, so the concurrent readers never see
+// the partially loaded store.
+func (cs *CredentialsStore) Reload(reader io.Reader) error {
+	newStore := NewCredentialsStore()
+	if err := newStore.Read(reader); err != nil {
+		return errors.WithMessage(err, "rejected reloading the credentials")
+	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.basicAuthCredentials = newStore.basicAuthCredentials
+	cs.caBundle = newStore.caBundle
+	cs.trustAnchors = newStore.trustAnchors
+	return nil
+}
+
+// Constructor of the network location (IP address or hostname and port).
+// The IP address is converted to the canonical form. The hostname is
+// converted to lower case.
 func newLocation(address string, port int64) (location, error) {
-	ip := storkutil.ParseIP(address)
-	if ip == nil {
-		return location{}, errors.Errorf("invalid IP address: %s", address)
+	if ip := storkutil.ParseIP(address); ip != nil {
+		return location{
+			Host: ip.NetworkAddress,
+			Port: port,
+		}, nil
+	}
+
+	hostname := strings.ToLower(address)
+	if !isValidHostname(hostname) {
+		return location{}, errors.Errorf("invalid IP address or hostname: %s", address)
 	}
 
 	return location{
-		IP:   ip.NetworkAddress,
+		Host: hostname,
 		Port: port,
 	}, nil
 }
 
+// Pattern of a single hostname label (RFC 1123).
+var hostnameLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Checks if the lower case string is a valid hostname. The top-level label
+// must not be all-numeric to avoid confusing the malformed IPv4 addresses
+// with the hostnames.
+func isValidHostname(hostname string) bool {
+	if len(hostname) == 0 || len(hostname) > 253 {
+		return false
+	}
+	labels := strings.Split(hostname, ".")
+	for _, label := range labels {
+		if !hostnameLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	topLevelLabel := labels[len(labels)-1]
+	return strings.Trim(topLevelLabel, "0123456789") != ""
+}
+
+// Sort the network locations by the host and port.
+func sortLocations(locations []location) {
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Host != locations[j].Host {
+			return locations[i].Host < locations[j].Host
+		}
+		return locations[i].Port < locations[j].Port
+	})
+}
+
+// Dump the credentials store to the structure of the JSON file.
+func (cs *CredentialsStore) dumpContent() *CredentialsStoreContent {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	content := &CredentialsStoreContent{
+		BasicAuth: []CredentialsStoreContentBasicAuthEntry{},
+	}
+	var basicAuthLocations []location
+	for location := range cs.basicAuthCredentials {
+		basicAuthLocations = append(basicAuthLocations, location)
+	}
+	sortLocations(basicAuthLocations)
+	for _, location := range basicAuthLocations {
+		location := location
+		credentials := cs.basicAuthCredentials[location]
+		content.BasicAuth = append(content.BasicAuth, CredentialsStoreContentBasicAuthEntry{
+			IP:       &location.Host,
+			Port:     &location.Port,
+			User:     &credentials.User,
+			Password: &credentials.Password,
+		})
+	}
+
+	if cs.caBundle != "" {
+		caBundle := cs.caBundle
+		content.CABundle = &caBundle
+	}
+
+	var trustAnchorLocations []location
+	for location := range cs.trustAnchors {
+		trustAnchorLocations = append(trustAnchorLocations, location)
+	}
+	sortLocations(trustAnchorLocations)
+	for _, location := range trustAnchorLocations {
+		location := location
+		trustAnchor := cs.trustAnchors[location]
+		content.TLS = append(content.TLS, CredentialsStoreContentTLSEntry{
+			IP:          &location.Host,
+			Port:        &location.Port,
+			TrustAnchor: &trustAnchor,
+		})
+	}
+	return content
+}
+
 // Load the content from JSON file to the credentials store.
 func (cs *CredentialsStore) loadContent(content *CredentialsStoreContent) error {
 	for _, entry := range content.BasicAuth {
@@ -155,5 +371,27 @@ func (cs *CredentialsStore) loadContent(content *CredentialsStoreContent) error
 			return err
 		}
 	}
+
+	if content.CABundle != nil {
+		cs.SetCABundle(*content.CABundle)
+	}
+
+	for _, entry := range content.TLS {
+		// Check required fields
+		if entry.IP == nil {
+			return errors.New("missing IP address")
+		}
+		if entry.Port == nil {
+			return errors.New("missing port")
+		}
+		if entry.TrustAnchor == nil {
+			return errors.New("missing trust anchor")
+		}
+
+		err := cs.AddOrUpdateTrustAnchor(*entry.IP, *entry.Port, *entry.TrustAnchor)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -39,7 +41,11 @@ func TestAddBasicAuthCredentials(t *testing.T) {
 func TestAddBasicAuthCredentialsInvalidIPs(t *testing.T) {
 	ipAddresses := []string{
 		"",
-		"foo",
+		" ",
+		"foo bar",
+		"foo_bar",
+		"foo..bar",
+		"-foo",
 		"ZZ:ZZ::",
 		"0",
 		":",
@@ -60,6 +66,40 @@ func TestAddBasicAuthCredentialsInvalidIPs(t *testing.T) {
 	}
 }
 
+// Test that the Basic Auth credentials may be added by hostname. The
+// hostnames are case insensitive.
+func TestAddBasicAuthCredentialsHostnames(t *testing.T) {
+	store := NewCredentialsStore()
+	credentials := NewBasicAuthCredentials("foo", "bar")
+
+	hostnames := []string{
+		"foo",
+		"kea-ca.example.org",
+		"Kea-CA2.Example.ORG",
+		"ca1.dhcp",
+	}
+	for _, hostname := range hostnames {
+		err := store.AddOrUpdateBasicAuth(hostname, 1, credentials)
+		require.NoError(t, err, "Hostname: %s", hostname)
+	}
+	require.Len(t, store.basicAuthCredentials, 4)
+
+	fetchedCredentials, ok := store.GetBasicAuth("KEA-CA.example.org", 1)
+	require.True(t, ok)
+	require.Equal(t, credentials, fetchedCredentials)
+
+	fetchedCredentials, ok = store.GetBasicAuth("kea-ca2.example.org", 1)
+	require.True(t, ok)
+	require.Equal(t, credentials, fetchedCredentials)
+
+	_, ok = store.GetBasicAuth("kea-ca.example.org", 2)
+	require.False(t, ok)
+
+	store.RemoveBasicAuth("FOO", 1)
+	_, ok = store.GetBasicAuth("foo", 1)
+	require.False(t, ok)
+}
+
 // Test that the empty Basic Auth credentials (without user and password)
 // are added to store correctly.
 func TestAddBasicAuthEmptyCredentials(t *testing.T) {
@@ -153,6 +193,37 @@ func TestGetBasicAuthCredentialsByURL(t *testing.T) {
 	}
 }
 
+// Get the Basic Auth credentials by URL with a hostname.
+func TestGetBasicAuthCredentialsByURLHostname(t *testing.T) {
+	store := NewCredentialsStore()
+	credentials := NewBasicAuthCredentials("foo", "bar")
+	err := store.AddOrUpdateBasicAuth("Kea-CA.example.org", 8000, credentials)
+	require.NoError(t, err)
+
+	validURLs := []string{
+		"http://kea-ca.example.org:8000",
+		"https://KEA-CA.EXAMPLE.ORG:8000/",
+		"http://kea-ca.example.org:8000?query=param",
+	}
+	invalidURLs := []string{
+		"http://kea-ca.example.org:8001",
+		"http://kea-ca:8000",
+		"http://example.org:8000",
+	}
+
+	for _, url := range validURLs {
+		fetchedCredentials, ok := store.GetBasicAuthByURL(url)
+		require.True(t, ok, "URL: %s", url)
+		require.Equal(t, credentials, fetchedCredentials)
+	}
+
+	for _, url := range invalidURLs {
+		fetchedCredentials, ok := store.GetBasicAuthByURL(url)
+		require.False(t, ok, "URL: %s", url)
+		require.Nil(t, fetchedCredentials)
+	}
+}
+
 // Test read the store from the proper JSON content.
 func TestReadStoreFromProperContent(t *testing.T) {
 	store := NewCredentialsStore()
@@ -325,3 +396,337 @@ func TestReadStoreFromInvalidContent(t *testing.T) {
 		})
 	}
 }
+
+// Test that the trust anchor specified for the network location overrides
+// the global CA bundle.
+func TestGetTrustAnchor(t *testing.T) {
+	store := NewCredentialsStore()
+
+	// Nothing specified.
+	trustAnchor, ok := store.GetTrustAnchor("192.168.0.1", 1234)
+	require.False(t, ok)
+	require.Empty(t, trustAnchor)
+
+	// The global CA bundle is used when no trust anchor is specified.
+	store.SetCABundle("/etc/ssl/internal-ca.pem")
+	trustAnchor, ok = store.GetTrustAnchor("192.168.0.1", 1234)
+	require.True(t, ok)
+	require.Equal(t, "/etc/ssl/internal-ca.pem", trustAnchor)
+
+	// The trust anchor for the location overrides the CA bundle.
+	err := store.AddOrUpdateTrustAnchor("192.168.0.1", 1234, "/etc/ssl/kea-ca.pem")
+	require.NoError(t, err)
+	trustAnchor, ok = store.GetTrustAnchorByURL("https://192.168.0.1:1234/")
+	require.True(t, ok)
+	require.Equal(t, "/etc/ssl/kea-ca.pem", trustAnchor)
+
+	// Other locations still use the CA bundle.
+	trustAnchor, ok = store.GetTrustAnchor("192.168.0.1", 1235)
+	require.True(t, ok)
+	require.Equal(t, "/etc/ssl/internal-ca.pem", trustAnchor)
+
+	require.ElementsMatch(t, []string{"/etc/ssl/internal-ca.pem", "/etc/ssl/kea-ca.pem"}, store.GetAllTrustAnchors())
+}
+
+// Test read the CA bundle and the trust anchors from the JSON content.
+func TestReadStoreWithTrustAnchors(t *testing.T) {
+	store := NewCredentialsStore()
+	content := strings.NewReader(`{
+		"ca_bundle": "/etc/ssl/internal-ca.pem",
+		"tls": [
+			{
+				"ip": "192.168.0.1",
+				"port": 1234,
+				"trust-anchor": "/etc/ssl/kea-ca.pem"
+			}
+		]
+	}`)
+
+	err := store.Read(content)
+	require.NoError(t, err)
+
+	trustAnchor, ok := store.GetTrustAnchor("192.168.0.1", 1234)
+	require.True(t, ok)
+	require.Equal(t, "/etc/ssl/kea-ca.pem", trustAnchor)
+
+	trustAnchor, ok = store.GetTrustAnchor("192.168.0.2", 1234)
+	require.True(t, ok)
+	require.Equal(t, "/etc/ssl/internal-ca.pem", trustAnchor)
+}
+
+// Test that the TLS entry without the trust anchor is rejected.
+func TestReadStoreWithMissingTrustAnchor(t *testing.T) {
+	store := NewCredentialsStore()
+	content := strings.NewReader(`{
+		"tls": [
+			{
+				"ip": "192.168.0.1",
+				"port": 1234
+			}
+		]
+	}`)
+
+	err := store.Read(content)
+	require.Error(t, err)
+}
+
+// Test that the credentials store content is replaced on reload.
+func TestReloadStore(t *testing.T) {
+	store := NewCredentialsStore()
+	err := store.Read(strings.NewReader(`{
+		"basic_auth": [
+			{
+				"ip": "192.168.0.1",
+				"port": 1234,
+				"user": "foo",
+				"password": "bar"
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	err = store.Reload(strings.NewReader(`{
+		"basic_auth": [
+			{
+				"ip": "192.168.0.2",
+				"port": 2345,
+				"user": "baz",
+				"password": "qux"
+			}
+		],
+		"ca_bundle": "/tmp/ca-bundle.pem"
+	}`))
+	require.NoError(t, err)
+
+	// The old credentials are gone.
+	_, ok := store.GetBasicAuth("192.168.0.1", 1234)
+	require.False(t, ok)
+
+	credentials, ok := store.GetBasicAuth("192.168.0.2", 2345)
+	require.True(t, ok)
+	require.EqualValues(t, "baz", credentials.User)
+	require.EqualValues(t, "qux", credentials.Password)
+
+	trustAnchor, ok := store.GetTrustAnchor("192.168.0.2", 2345)
+	require.True(t, ok)
+	require.EqualValues(t, "/tmp/ca-bundle.pem", trustAnchor)
+}
+
+// Test that the invalid content is rejected on reload and the current
+// store content is preserved.
+func TestReloadStoreInvalidContent(t *testing.T) {
+	store := NewCredentialsStore()
+	err := store.Read(strings.NewReader(`{
+		"basic_auth": [
+			{
+				"ip": "192.168.0.1",
+				"port": 1234,
+				"user": "foo",
+				"password": "bar"
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	contents := []string{
+		// Malformed JSON.
+		`{ "basic_auth": [ `,
+		// The first entry is valid but the second one lacks the password.
+		`{
+			"basic_auth": [
+				{
+					"ip": "192.168.0.2",
+					"port": 2345,
+					"user": "baz",
+					"password": "qux"
+				},
+				{
+					"ip": "192.168.0.3",
+					"port": 3456,
+					"user": "baz"
+				}
+			]
+		}`,
+	}
+	for _, content := range contents {
+		err = store.Reload(strings.NewReader(content))
+		require.ErrorContains(t, err, "rejected reloading the credentials")
+
+		credentials, ok := store.GetBasicAuth("192.168.0.1", 1234)
+		require.True(t, ok)
+		require.EqualValues(t, "foo", credentials.User)
+		require.EqualValues(t, "bar", credentials.Password)
+
+		_, ok = store.GetBasicAuth("192.168.0.2", 2345)
+		require.False(t, ok)
+	}
+}
+
+// Test that the credentials store content is written in the format
+// consumed by Read and that it can be read back.
+func TestWriteStoreRoundTrip(t *testing.T) {
+	// Arrange
+	store := NewCredentialsStore()
+	err := store.Read(strings.NewReader(`{
+		"basic_auth": [
+			{
+				"ip": "2001:0DB8:0000::1",
+				"port": 1234,
+				"user": "foo",
+				"password": "bar"
+			},
+			{
+				"ip": "192.0.2.1",
+				"port": 2345,
+				"user": "baz",
+				"password": "qux"
+			}
+		],
+		"ca_bundle": "/tmp/ca-bundle.pem",
+		"tls": [
+			{
+				"ip": "192.0.2.2",
+				"port": 3456,
+				"trust-anchor": "/tmp/trust-anchor.pem"
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	err = store.AddOrUpdateBasicAuth("192.0.2.1", 1111, NewBasicAuthCredentials("runtime", "secret"))
+	require.NoError(t, err)
+
+	// Act
+	var buffer strings.Builder
+	err = store.Write(&buffer)
+
+	// Assert
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"basic_auth": [
+			{
+				"ip": "192.0.2.1",
+				"port": 1111,
+				"user": "runtime",
+				"password": "secret"
+			},
+			{
+				"ip": "192.0.2.1",
+				"port": 2345,
+				"user": "baz",
+				"password": "qux"
+			},
+			{
+				"ip": "2001:db8::1",
+				"port": 1234,
+				"user": "foo",
+				"password": "bar"
+			}
+		],
+		"ca_bundle": "/tmp/ca-bundle.pem",
+		"tls": [
+			{
+				"ip": "192.0.2.2",
+				"port": 3456,
+				"trust-anchor": "/tmp/trust-anchor.pem"
+			}
+		]
+	}`, buffer.String())
+
+	restored := NewCredentialsStore()
+	err = restored.Read(strings.NewReader(buffer.String()))
+	require.NoError(t, err)
+	require.Equal(t, store.basicAuthCredentials, restored.basicAuthCredentials)
+	require.Equal(t, store.trustAnchors, restored.trustAnchors)
+	require.Equal(t, store.caBundle, restored.caBundle)
+}
+
+// Test that the empty credentials store is written as a valid content.
+func TestWriteEmptyStore(t *testing.T) {
+	// Arrange
+	store := NewCredentialsStore()
+
+	// Act
+	var buffer strings.Builder
+	err := store.Write(&buffer)
+
+	// Assert
+	require.NoError(t, err)
+	require.JSONEq(t, `{ "basic_auth": [ ] }`, buffer.String())
+	require.NoError(t, NewCredentialsStore().Read(strings.NewReader(buffer.String())))
+}
+
+// Test that the credentials store can be safely accessed from many
+// goroutines. This test is meaningful when run with the race detector.
+func TestConcurrentAccess(t *testing.T) {
+	// Arrange
+	store := NewCredentialsStore()
+	var wg sync.WaitGroup
+
+	// Act
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			address := fmt.Sprintf("192.0.2.%d", i)
+			for j := 0; j < 100; j++ {
+				credentials := NewBasicAuthCredentials("foo", fmt.Sprint(j))
+				require.NoError(t, store.AddOrUpdateBasicAuth(address, 8000, credentials))
+				_, _ = store.GetBasicAuth(address, 8000)
+				_, _ = store.GetBasicAuthByURL(fmt.Sprintf("http://%s:8000/", address))
+				if j%10 == 0 {
+					store.RemoveBasicAuth(address, 8000)
+				}
+			}
+		}(i)
+	}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_ = store.Read(strings.NewReader(`{
+					"basic_auth": [
+						{ "ip": "192.0.2.1", "port": 8000, "user": "bar", "password": "baz" }
+					]
+				}`))
+				_ = store.Reload(strings.NewReader(`{ "basic_auth": [ ] }`))
+				var buffer strings.Builder
+				_ = store.Write(&buffer)
+				_, _ = store.GetTrustAnchor("192.0.2.1", 8000)
+				_ = store.GetAllTrustAnchors()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	var buffer strings.Builder
+	require.NoError(t, store.Write(&buffer))
+	require.NoError(t, NewCredentialsStore().Read(strings.NewReader(buffer.String())))
+}
+
+// Test that the written content doesn't depend on the order in which the
+// credentials were added to the store.
+func TestWriteStoreDeterministic(t *testing.T) {
+	// Arrange
+	first := NewCredentialsStore()
+	second := NewCredentialsStore()
+	addresses := []string{"192.0.2.2", "kea.example.org", "2001:db8::1", "192.0.2.1"}
+	for i := range addresses {
+		credentials := NewBasicAuthCredentials("foo", "bar")
+		require.NoError(t, first.AddOrUpdateBasicAuth(addresses[i], 8000, credentials))
+		require.NoError(t, second.AddOrUpdateBasicAuth(addresses[len(addresses)-i-1], 8000, credentials))
+		require.NoError(t, first.AddOrUpdateTrustAnchor(addresses[i], 8000, "/tmp/trust-anchor.pem"))
+		require.NoError(t, second.AddOrUpdateTrustAnchor(addresses[len(addresses)-i-1], 8000, "/tmp/trust-anchor.pem"))
+	}
+
+	// Act
+	var firstBuffer, secondBuffer strings.Builder
+	require.NoError(t, first.Write(&firstBuffer))
+	require.NoError(t, second.Write(&secondBuffer))
+
+	// Assert
+	require.Equal(t, firstBuffer.String(), secondBuffer.String())
+	require.Less(t, strings.Index(firstBuffer.String(), "192.0.2.1"), strings.Index(firstBuffer.String(), "192.0.2.2"))
+	require.Less(t, strings.Index(firstBuffer.String(), "2001:db8::1"), strings.Index(firstBuffer.String(), "kea.example.org"))
+}
@@ -2,7 +2,9 @@ package agent
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"net"
 	"path"
 	"strings"
 
@@ -18,6 +20,9 @@ import (
 type KeaApp struct {
 	BaseApp
 	HTTPClient *HTTPClient // to communicate with Kea Control Agent
+	// Names of the daemons behind the Kea Control Agent for which the
+	// control sockets are configured, i.e., dhcp4, dhcp6 and d2.
+	ConfiguredDaemons []string
 }
 
 // Get base information about Kea app.
@@ -143,7 +148,7 @@ func (ka *KeaApp) DetectAllowedLogs() ([]string, error) {
 
 	// Control Agent should be configured to forward commands to some
 	// daemons behind it.
-	sockets := config.GetControlSockets()
+	sockets, _ := config.GetControlSockets()
 	daemonNames := sockets.ConfiguredDaemonNames()
 
 	// Apparently, it isn't configured to forward commands to the daemons behind it.
@@ -175,6 +180,12 @@ func (ka *KeaApp) DetectAllowedLogs() ([]string, error) {
 	return paths, nil
 }
 
+// Returns the address and port the Kea Control Agent listens on and the
+// flag indicating if it uses TLS. The configuration is parsed as JSON
+// with comments, so the parameters may be specified in any order and
+// formatting. It returns zero values if the configuration cannot be
+// parsed, it is not the Control Agent configuration or it lacks a valid
+// HTTP port.
 func getCtrlTargetFromKeaConfig(path string) (address string, port int64, useSecureProtocol bool) {
 	text, err := storkutil.ReadFileWithIncludes(path)
 	if err != nil {
@@ -188,10 +199,16 @@ func getCtrlTargetFromKeaConfig(path string) (address string, port int64, useSec
 		return
 	}
 
+	if !config.IsControlAgent() {
+		log.Warnf("Kea config file %s lacks the Control-agent node", path)
+		return
+	}
+
 	// Port
 	port, ok := config.GetHTTPPort()
-	if !ok {
+	if !ok || port <= 0 {
 		log.Warn("Cannot parse the port")
+		port = 0
 		return
 	}
 
@@ -203,7 +220,28 @@ func getCtrlTargetFromKeaConfig(path string) (address string, port int64, useSec
 	return
 }
 
-func detectKeaApp(match []string, cwd string, httpClient *HTTPClient) App {
+// Returns the names of the daemons for which the control sockets are
+// specified in the Kea Control Agent configuration file. It allows for
+// detecting the daemons behind the Kea Control Agent, including the Kea
+// DHCP-DDNS daemon (d2), when the app is discovered.
+func getConfiguredDaemonsFromKeaConfig(path string) []string {
+	text, err := storkutil.ReadFileWithIncludes(path)
+	if err != nil {
+		log.Warnf("Cannot read Kea config file: %+v", err)
+		return nil
+	}
+
+	config, err := keaconfig.NewFromJSON(text)
+	if err != nil {
+		log.Warnf("Cannot parse Kea Control Agent config file: %+v", err)
+		return nil
+	}
+
+	sockets, _ := config.GetControlSockets()
+	return sockets.ConfiguredDaemonNames()
+}
+
+func detectKeaApp(match []string, cwd string, httpClient *HTTPClient, pathMappings PathMappings) App {
 	if len(match) < 3 {
 		log.Warnf("Problem parsing Kea cmdline: %s", match[0])
 		return nil
@@ -215,6 +253,10 @@ func detectKeaApp(match []string, cwd string, httpClient *HTTPClient) App {
 		keaConfPath = path.Join(cwd, keaConfPath)
 	}
 
+	// if kea runs in a container then its config path must be translated
+	// to the path on the host
+	keaConfPath = pathMappings.Translate(keaConfPath)
+
 	address, port, useSecureProtocol := getCtrlTargetFromKeaConfig(keaConfPath)
 	if address == "" || port == 0 {
 		return nil
@@ -232,8 +274,20 @@ func detectKeaApp(match []string, cwd string, httpClient *HTTPClient) App {
 			Type:         AppTypeKea,
 			AccessPoints: accessPoints,
 		},
-		HTTPClient: httpClient,
+		HTTPClient:        httpClient,
+		ConfiguredDaemons: getConfiguredDaemonsFromKeaConfig(keaConfPath),
 	}
 
 	return keaApp
 }
+
+// Returns the control address and port of the Kea app. It identifies the
+// Kea Control Agent on the machine.
+func getKeaAppControlKey(app App) string {
+	for _, accessPoint := range app.GetBaseApp().AccessPoints {
+		if accessPoint.Type == AccessPointControl {
+			return net.JoinHostPort(accessPoint.Address, fmt.Sprint(accessPoint.Port))
+		}
+	}
+	return ""
+}
@@ -175,6 +175,9 @@ func printNewOrUpdatedApps(newApps []App, oldApps []App) {
 				acPts = append(acPts, s)
 			}
 			log.Printf("   %s: %s", app.GetBaseApp().Type, strings.Join(acPts, ", "))
+			if keaApp, ok := app.(*KeaApp); ok && len(keaApp.ConfiguredDaemons) > 0 {
+				log.Printf("      daemons: %s", strings.Join(keaApp.ConfiguredDaemons, ", "))
+			}
 		}
 	} else if len(oldApps) == 0 {
 		// Agent is starting up but no app to monitor has been detected.
@@ -195,6 +198,10 @@ func (sm *appMonitor) detectApps(storkAgent *StorkAgent) {
 	bind9Ptrn := regexp.MustCompile(`(.*?)named\s+(.*)`)
 
 	var apps []App
+	// Control addresses and ports of the detected Kea Control Agents. Many
+	// Kea Control Agents may run on one machine, but each of them must
+	// listen on a different port.
+	detectedKeaApps := make(map[string]bool)
 
 	procs, _ := process.Processes()
 	for _, p := range procs {
@@ -219,8 +226,9 @@ func (sm *appMonitor) detectApps(storkAgent *StorkAgent) {
 			// detect kea
 			m := keaPtrn.FindStringSubmatch(cmdline)
 			if m != nil {
-				keaApp := detectKeaApp(m, cwd, storkAgent.HTTPClient)
-				if keaApp != nil {
+				keaApp := detectKeaApp(m, cwd, storkAgent.HTTPClient, storkAgent.ConfigPathMappings)
+				if keaApp != nil && !detectedKeaApps[getKeaAppControlKey(keaApp)] {
+					detectedKeaApps[getKeaAppControlKey(keaApp)] = true
 					keaApp.GetBaseApp().Pid = p.Pid
 					apps = append(apps, keaApp)
 				}
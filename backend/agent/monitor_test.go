@@ -206,6 +206,104 @@ func TestGetCtrlAddressFromKeaConfigAddressColons(t *testing.T) {
 	require.False(t, useSecureProtocol)
 }
 
+// Test that the control address and port are extracted from the valid
+// JSON regardless of the parameters order, formatting and comments.
+func TestGetCtrlTargetFromKeaConfigFormatting(t *testing.T) {
+	testCases := map[string]struct {
+		config          string
+		expectedAddress string
+		expectedPort    int64
+	}{
+		"port before host": {
+			config:          `{ "Control-agent": { "http-port": 1234, "http-host": "192.0.2.1" } }`,
+			expectedAddress: "192.0.2.1",
+			expectedPort:    1234,
+		},
+		"multiple lines": {
+			config: `{
+				"Control-agent":
+				{
+					"http-port":
+						1234,
+					"http-host"
+						:
+						"::"
+				}
+			}`,
+			expectedAddress: "::1",
+			expectedPort:    1234,
+		},
+		"comments": {
+			config: `{
+				// The Control Agent configuration.
+				"Control-agent": {
+					/* "http-host": "192.0.2.2", */
+					"http-host": "0.0.0.0", # "http-host": "192.0.2.3",
+					# "http-port": 8004,
+					"http-port": 8001 // "http-port": 8005
+				}
+			}`,
+			expectedAddress: "127.0.0.1",
+			expectedPort:    8001,
+		},
+		"other parameters": {
+			config: `{
+				"Control-agent": {
+					"control-sockets": {
+						"dhcp4": { "socket-type": "unix", "socket-name": "/tmp/kea4-ctrl-socket" }
+					},
+					"http-port": 8000,
+					"loggers": [ { "name": "kea-ctrl-agent", "severity": "INFO" } ]
+				}
+			}`,
+			expectedAddress: "127.0.0.1",
+			expectedPort:    8000,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			configPath := path.Join(t.TempDir(), "kea-ctrl-agent.conf")
+			err := os.WriteFile(configPath, []byte(testCase.config), 0o600)
+			require.NoError(t, err)
+
+			// Act
+			address, port, useSecureProtocol := getCtrlTargetFromKeaConfig(configPath)
+
+			// Assert
+			require.Equal(t, testCase.expectedAddress, address)
+			require.EqualValues(t, testCase.expectedPort, port)
+			require.False(t, useSecureProtocol)
+		})
+	}
+}
+
+// Test that zero values are returned when the configuration lacks the
+// port or the Control Agent node.
+func TestGetCtrlTargetFromKeaConfigMissingPort(t *testing.T) {
+	configs := []string{
+		`{ "Control-agent": { "http-host": "192.0.2.1" } }`,
+		`{ "Dhcp4": { "http-host": "192.0.2.1", "http-port": 8000 } }`,
+		`{ "Control-agent": { "http-host": "192.0.2.1", "http-port": "8000" } }`,
+	}
+	for _, config := range configs {
+		// Arrange
+		configPath := path.Join(t.TempDir(), "kea-ctrl-agent.conf")
+		err := os.WriteFile(configPath, []byte(config), 0o600)
+		require.NoError(t, err)
+
+		// Act
+		address, port, useSecureProtocol := getCtrlTargetFromKeaConfig(configPath)
+
+		// Assert
+		require.Empty(t, address, config)
+		require.Zero(t, port, config)
+		require.False(t, useSecureProtocol, config)
+	}
+}
+
 func TestDetectApps(t *testing.T) {
 	am := &appMonitor{}
 	settings := cli.NewContext(nil, flag.NewFlagSet("", 0), nil)
@@ -387,12 +485,12 @@ func TestDetectKeaApp(t *testing.T) {
 	httpClient := NewHTTPClient(false)
 
 	// check kea app detection
-	app := detectKeaApp([]string{"", "", tmpFilePath}, "", httpClient)
+	app := detectKeaApp([]string{"", "", tmpFilePath}, "", httpClient, nil)
 	checkApp(app)
 
 	// check kea app detection when kea conf file is relative to CWD of kea process
 	cwd, file := path.Split(tmpFilePath)
-	app = detectKeaApp([]string{"", "", file}, cwd, httpClient)
+	app = detectKeaApp([]string{"", "", file}, cwd, httpClient, nil)
 	checkApp(app)
 
 	// Check configuration with an include statement
@@ -403,15 +501,169 @@ func TestDetectKeaApp(t *testing.T) {
 	defer os.Remove(nestedFile.Name())
 
 	// check kea app detection
-	app = detectKeaApp([]string{"", "", tmpFilePath}, "", httpClient)
+	app = detectKeaApp([]string{"", "", tmpFilePath}, "", httpClient, nil)
 	checkApp(app)
 
 	// check kea app detection when kea conf file is relative to CWD of kea process
 	cwd, file = path.Split(tmpFilePath)
-	app = detectKeaApp([]string{"", "", file}, cwd, httpClient)
+	app = detectKeaApp([]string{"", "", file}, cwd, httpClient, nil)
 	checkApp(app)
 }
 
+// Test that the daemons behind the Kea Control Agent, including the
+// DHCP-DDNS daemon, are detected from the control sockets.
+func TestDetectKeaAppConfiguredDaemons(t *testing.T) {
+	// Arrange
+	configPath := path.Join(t.TempDir(), "kea-ctrl-agent.conf")
+	err := os.WriteFile(configPath, []byte(`{
+		"Control-agent": {
+			"http-host": "localhost",
+			"http-port": 45634,
+			"control-sockets": {
+				"dhcp4": {
+					"socket-type": "unix",
+					"socket-name": "/tmp/kea4-ctrl-socket"
+				},
+				"d2": {
+					"socket-type": "unix",
+					"socket-name": "/tmp/kea-ddns-ctrl-socket"
+				}
+			}
+		}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	// Act
+	app := detectKeaApp([]string{"", "", configPath}, "", NewHTTPClient(false), nil)
+
+	// Assert
+	require.NotNil(t, app)
+	keaApp, ok := app.(*KeaApp)
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{"dhcp4", "d2"}, keaApp.ConfiguredDaemons)
+}
+
+// Test that no daemons are reported when the Kea Control Agent has no
+// control sockets configured.
+func TestDetectKeaAppNoConfiguredDaemons(t *testing.T) {
+	// Arrange
+	tmpFile, err := makeKeaConfFile()
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	// Act
+	app := detectKeaApp([]string{"", "", tmpFile.Name()}, "", NewHTTPClient(false), nil)
+
+	// Assert
+	require.NotNil(t, app)
+	require.Empty(t, app.(*KeaApp).ConfiguredDaemons)
+}
+
+// Test that the Kea Control Agents listening on the wildcard addresses
+// are identified by the loopback addresses, so the Control Agents with
+// the configurations pointing to the same address and port have the same
+// control key, and the ones listening on distinct ports do not.
+func TestDetectKeaAppControlKeys(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeConfig := func(name, host string, port int) string {
+		configPath := path.Join(dir, name)
+		err := os.WriteFile(configPath, []byte(fmt.Sprintf(`{
+			"Control-agent": {
+				"http-host": "%s",
+				"http-port": %d
+			}
+		}`, host, port)), 0o600)
+		require.NoError(t, err)
+		return configPath
+	}
+	httpClient := NewHTTPClient(false)
+
+	// Act
+	app1 := detectKeaApp([]string{"", "", writeConfig("ca1.conf", "0.0.0.0", 8000)}, "", httpClient, nil)
+	app2 := detectKeaApp([]string{"", "", writeConfig("ca2.conf", "::", 8001)}, "", httpClient, nil)
+	app3 := detectKeaApp([]string{"", "", writeConfig("ca3.conf", "127.0.0.1", 8000)}, "", httpClient, nil)
+	app4 := detectKeaApp([]string{"", "", writeConfig("ca4.conf", "192.0.2.1", 8000)}, "", httpClient, nil)
+
+	// Assert
+	require.NotNil(t, app1)
+	require.NotNil(t, app2)
+	require.NotNil(t, app3)
+	require.NotNil(t, app4)
+	require.Equal(t, "127.0.0.1:8000", getKeaAppControlKey(app1))
+	require.Equal(t, "[::1]:8001", getKeaAppControlKey(app2))
+	require.Equal(t, getKeaAppControlKey(app1), getKeaAppControlKey(app3))
+	require.Equal(t, "192.0.2.1:8000", getKeaAppControlKey(app4))
+}
+
+// Test that no app is detected when the configuration doesn't specify
+// the Kea Control Agent port or doesn't exist.
+func TestDetectKeaAppNoValidConfig(t *testing.T) {
+	// Arrange
+	configPath := path.Join(t.TempDir(), "kea-ctrl-agent.conf")
+	err := os.WriteFile(configPath, []byte(`{ "Control-agent": { } }`), 0o600)
+	require.NoError(t, err)
+
+	// Act
+	app := detectKeaApp([]string{"", "", configPath}, "", NewHTTPClient(false), nil)
+	appMissing := detectKeaApp([]string{"", "", "/non/existing/path"}, "", NewHTTPClient(false), nil)
+
+	// Assert
+	require.Nil(t, app)
+	require.Nil(t, appMissing)
+}
+
+// Test that the control address and port identify the Kea app.
+func TestGetKeaAppControlKey(t *testing.T) {
+	app := &KeaApp{
+		BaseApp: BaseApp{
+			Type: AppTypeKea,
+			AccessPoints: []AccessPoint{
+				{Type: AccessPointStatistics, Address: "192.0.2.2", Port: 9547},
+				{Type: AccessPointControl, Address: "::1", Port: 8000},
+			},
+		},
+	}
+	require.Equal(t, "[::1]:8000", getKeaAppControlKey(app))
+
+	app.AccessPoints = nil
+	require.Empty(t, getKeaAppControlKey(app))
+}
+
+// Test that the Kea configuration path inside a container is translated
+// to the path on the host using the path mappings.
+func TestDetectKeaAppInContainer(t *testing.T) {
+	// Arrange
+	// The host directory reflecting the /etc/kea directory in the container,
+	// e.g., /var/lib/docker/overlay2/abc/merged/etc/kea.
+	hostDir := path.Join(t.TempDir(), "var/lib/docker/overlay2/abc/merged/etc/kea")
+	err := os.MkdirAll(hostDir, 0o755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(hostDir, "kea-ctrl-agent.conf"), []byte(`{
+		"Control-agent": {
+			"http-host": "localhost",
+			"http-port": 45634
+		}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	mappings, err := ParsePathMappings([]string{"/etc/kea=" + hostDir})
+	require.NoError(t, err)
+
+	httpClient := NewHTTPClient(false)
+
+	// Act
+	app := detectKeaApp([]string{"", "", "/etc/kea/kea-ctrl-agent.conf"}, "", httpClient, mappings)
+	appRelative := detectKeaApp([]string{"", "", "kea-ctrl-agent.conf"}, "/etc/kea", httpClient, mappings)
+
+	// Assert
+	require.NotNil(t, app)
+	require.Len(t, app.GetBaseApp().AccessPoints, 1)
+	require.EqualValues(t, 45634, app.GetBaseApp().AccessPoints[0].Port)
+	require.NotNil(t, appRelative)
+	require.EqualValues(t, 45634, appRelative.GetBaseApp().AccessPoints[0].Port)
+}
+
 func TestGetAccessPoint(t *testing.T) {
 	bind9App := &Bind9App{
 		BaseApp: BaseApp{
@@ -0,0 +1,75 @@
+package agent
+
+// The apps running in the containers refer to their configuration files
+// using the paths inside the containers. These paths differ from the
+// paths seen by the agent running on the host. The path mappings translate
+// the container paths to the host paths, so the agent can locate the
+// configuration files on the host filesystem.
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Maps a path prefix inside a container to the path prefix on the host.
+type PathMapping struct {
+	ContainerPrefix string
+	HostPrefix      string
+}
+
+// A collection of the path mappings.
+type PathMappings []PathMapping
+
+// Parses the path mappings specified in the container-prefix=host-prefix
+// form, e.g., /etc/kea=/var/lib/docker/overlay2/abc/merged/etc/kea. Both
+// prefixes must be absolute paths. The mappings are ordered from the
+// longest container prefix, so the most specific mapping is used.
+func ParsePathMappings(specs []string) (PathMappings, error) {
+	var mappings PathMappings
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		containerPrefix, hostPrefix, found := strings.Cut(spec, "=")
+		if !found {
+			return nil, errors.Errorf("invalid path mapping %s: expected container-prefix=host-prefix", spec)
+		}
+		containerPrefix = strings.TrimSpace(containerPrefix)
+		hostPrefix = strings.TrimSpace(hostPrefix)
+		if !path.IsAbs(containerPrefix) || !path.IsAbs(hostPrefix) {
+			return nil, errors.Errorf("invalid path mapping %s: the prefixes must be absolute paths", spec)
+		}
+		mappings = append(mappings, PathMapping{
+			ContainerPrefix: path.Clean(containerPrefix),
+			HostPrefix:      path.Clean(hostPrefix),
+		})
+	}
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return len(mappings[i].ContainerPrefix) > len(mappings[j].ContainerPrefix)
+	})
+	return mappings, nil
+}
+
+// Translates the container path to the host path using the first matching
+// mapping. The prefix matches whole path segments only, e.g., /etc/kea
+// matches /etc/kea/kea-ctrl-agent.conf but not /etc/kea2/kea-ctrl-agent.conf.
+// The path is returned unchanged if no mapping matches.
+func (mappings PathMappings) Translate(containerPath string) string {
+	for _, mapping := range mappings {
+		if containerPath == mapping.ContainerPrefix {
+			return mapping.HostPrefix
+		}
+		prefix := mapping.ContainerPrefix
+		if prefix != "/" {
+			prefix += "/"
+		}
+		if strings.HasPrefix(containerPath, prefix) {
+			return path.Join(mapping.HostPrefix, strings.TrimPrefix(containerPath, prefix))
+		}
+	}
+	return containerPath
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the path mappings are parsed and ordered from the most
+// specific one.
+func TestParsePathMappings(t *testing.T) {
+	// Act
+	mappings, err := ParsePathMappings([]string{
+		"/etc=/host/etc",
+		" /etc/kea = /var/lib/docker/overlay2/abc/merged/etc/kea/ ",
+		"",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, mappings, 2)
+	require.Equal(t, "/etc/kea", mappings[0].ContainerPrefix)
+	require.Equal(t, "/var/lib/docker/overlay2/abc/merged/etc/kea", mappings[0].HostPrefix)
+	require.Equal(t, "/etc", mappings[1].ContainerPrefix)
+	require.Equal(t, "/host/etc", mappings[1].HostPrefix)
+}
+
+// Test that the malformed path mappings are rejected.
+func TestParsePathMappingsInvalid(t *testing.T) {
+	for _, spec := range []string{"/etc/kea", "etc/kea=/host/etc/kea", "/etc/kea=host/etc/kea", "=/etc"} {
+		mappings, err := ParsePathMappings([]string{spec})
+		require.Error(t, err, spec)
+		require.Nil(t, mappings, spec)
+	}
+}
+
+// Test that the container paths are translated to the host paths.
+func TestPathMappingsTranslate(t *testing.T) {
+	// Arrange
+	mappings, err := ParsePathMappings([]string{
+		"/etc/kea=/var/lib/docker/overlay2/abc/merged/etc/kea",
+		"/opt=/srv/opt",
+	})
+	require.NoError(t, err)
+
+	// Act & Assert
+	require.Equal(t, "/var/lib/docker/overlay2/abc/merged/etc/kea/kea-ctrl-agent.conf",
+		mappings.Translate("/etc/kea/kea-ctrl-agent.conf"))
+	require.Equal(t, "/var/lib/docker/overlay2/abc/merged/etc/kea",
+		mappings.Translate("/etc/kea"))
+	require.Equal(t, "/srv/opt/kea/etc/kea-ctrl-agent.conf",
+		mappings.Translate("/opt/kea/etc/kea-ctrl-agent.conf"))
+	// Only whole path segments are matched.
+	require.Equal(t, "/etc/kea2/kea-ctrl-agent.conf",
+		mappings.Translate("/etc/kea2/kea-ctrl-agent.conf"))
+	require.Equal(t, "/usr/local/etc/kea/kea-ctrl-agent.conf",
+		mappings.Translate("/usr/local/etc/kea/kea-ctrl-agent.conf"))
+	// No mappings.
+	require.Equal(t, "/etc/kea/kea-ctrl-agent.conf",
+		PathMappings(nil).Translate("/etc/kea/kea-ctrl-agent.conf"))
+}
@@ -83,3 +83,47 @@ func (c *Map) UseSecureProtocol() bool {
 	keyFile, _ := c.GetKeyFile()
 	return len(trustAnchor) != 0 && len(certFile) != 0 && len(keyFile) != 0
 }
+
+// Represents the basic HTTP authentication client in the Kea Control
+// Agent configuration.
+type BasicAuthClient struct {
+	User         string
+	Password     string
+	UserFile     string
+	PasswordFile string
+}
+
+// Represents the HTTP authentication configuration of the Kea Control
+// Agent.
+type Authentication struct {
+	Type      string
+	Realm     string
+	Directory string
+	Clients   []BasicAuthClient
+}
+
+// Returns the HTTP authentication configuration at the top level of the
+// configuration. If the authentication is not configured or it cannot
+// be parsed, the ok value returned is set to false.
+func (c *Map) GetAuthentication() (authentication *Authentication, ok bool) {
+	raw, ok := c.getTopLevelEntry("authentication")
+	if !ok {
+		return nil, false
+	}
+	authentication = &Authentication{}
+	if err := decode(raw, authentication); err != nil {
+		return nil, false
+	}
+	return authentication, true
+}
+
+// Returns the clients allowed to use the basic HTTP authentication. If
+// the authentication is not configured or its type is other than basic,
+// the clients are nil, and the ok value returned is set to false.
+func (c *Map) GetBasicAuthClients() (clients []BasicAuthClient, ok bool) {
+	authentication, ok := c.GetAuthentication()
+	if !ok || authentication.Type != "basic" {
+		return nil, false
+	}
+	return authentication.Clients, true
+}
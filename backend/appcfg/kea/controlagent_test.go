@@ -352,6 +352,24 @@ func TestKeaControlAgentConfigurationFromFullJSON(t *testing.T) {
 	certRequired, ok := config.GetCertRequired()
 	require.True(t, ok)
 	require.False(t, certRequired)
+	authentication, ok := config.GetAuthentication()
+	require.True(t, ok)
+	require.EqualValues(t, "basic", authentication.Type)
+	require.EqualValues(t, "kea-control-agent", authentication.Realm)
+	clients, ok := config.GetBasicAuthClients()
+	require.True(t, ok)
+	require.Len(t, clients, 1)
+	require.EqualValues(t, "foo", clients[0].User)
+	require.EqualValues(t, "bar", clients[0].Password)
+	sockets, ok := config.GetControlSockets()
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{"dhcp4", "dhcp6", "d2"}, sockets.ConfiguredDaemonNames())
+	require.EqualValues(t, "unix", sockets.Dhcp4.SocketType)
+	require.EqualValues(t, "/tmp/kea4-ctrl-socket", sockets.Dhcp4.SocketName)
+	require.EqualValues(t, "unix", sockets.Dhcp6.SocketType)
+	require.EqualValues(t, "/tmp/kea6-ctrl-socket", sockets.Dhcp6.SocketName)
+	require.EqualValues(t, "unix", sockets.D2.SocketType)
+	require.EqualValues(t, "/tmp/kea-ddns-ctrl-socket", sockets.D2.SocketName)
 }
 
 // Test that the HTTP host is resolved to IP address.
@@ -437,3 +455,90 @@ func TestKeaControlAgentConfigurationUseSecureProtocol(t *testing.T) {
 	// Assert
 	require.True(t, useSecure)
 }
+
+// Test that the missing authentication configuration is handled properly.
+func TestKeaControlAgentConfigurationNoAuthentication(t *testing.T) {
+	// Arrange
+	config, _ := NewFromJSON(`{ "Control-agent": { } }`)
+
+	// Act
+	authentication, ok := config.GetAuthentication()
+	clients, clientsOk := config.GetBasicAuthClients()
+
+	// Assert
+	require.False(t, ok)
+	require.Nil(t, authentication)
+	require.False(t, clientsOk)
+	require.Nil(t, clients)
+}
+
+// Test that the clients are not returned when the authentication type
+// is other than basic.
+func TestKeaControlAgentConfigurationNonBasicAuthentication(t *testing.T) {
+	// Arrange
+	config, _ := NewFromJSON(`{
+		"Control-agent": {
+			"authentication": {
+				"type": "digest",
+				"clients": [
+					{
+						"user": "foo",
+						"password": "bar"
+					}
+				]
+			}
+		}
+	}`)
+
+	// Act
+	authentication, ok := config.GetAuthentication()
+	clients, clientsOk := config.GetBasicAuthClients()
+
+	// Assert
+	require.True(t, ok)
+	require.EqualValues(t, "digest", authentication.Type)
+	require.False(t, clientsOk)
+	require.Nil(t, clients)
+}
+
+// Test that the basic authentication clients are parsed from the
+// configuration including the comments.
+func TestKeaControlAgentConfigurationBasicAuthClientsWithComments(t *testing.T) {
+	// Arrange
+	config, err := NewFromJSON(`{
+		"Control-agent": {
+			// The basic authentication.
+			"authentication": {
+				"type": "basic", # The only supported type.
+				"realm": "kea",
+				/* The clients
+				   allowed to connect. */
+				"clients": [
+					{
+						"user": "foo",
+						"password": "bar"
+					},
+					{
+						"user-file": "/etc/kea/user",
+						"password-file": "/etc/kea/password"
+					}
+				]
+			}
+		}
+	}`)
+	require.NoError(t, err)
+
+	// Act
+	authentication, ok := config.GetAuthentication()
+	clients, clientsOk := config.GetBasicAuthClients()
+
+	// Assert
+	require.True(t, ok)
+	require.EqualValues(t, "kea", authentication.Realm)
+	require.True(t, clientsOk)
+	require.Len(t, clients, 2)
+	require.EqualValues(t, "foo", clients[0].User)
+	require.EqualValues(t, "bar", clients[0].Password)
+	require.EqualValues(t, "/etc/kea/user", clients[1].UserFile)
+	require.EqualValues(t, "/etc/kea/password", clients[1].PasswordFile)
+}
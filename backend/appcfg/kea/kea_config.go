@@ -300,12 +300,16 @@ func (c *Map) GetLoggers() (parsedLoggers []Logger) {
 	return parsedLoggers
 }
 
-// Parses a map of control sockets in Kea Control Agent.
-func (c *Map) GetControlSockets() (parsedSockets ControlSockets) {
-	if socketsMap, ok := c.GetTopLevelMap("control-sockets"); ok {
+// Parses a map of control sockets in Kea Control Agent. The sockets are
+// returned for the services (daemons) for which they are specified. If
+// the control-sockets map does not exist, the ok value returned is set to
+// false.
+func (c *Map) GetControlSockets() (parsedSockets ControlSockets, ok bool) {
+	socketsMap, ok := c.GetTopLevelMap("control-sockets")
+	if ok {
 		_ = mapstructure.Decode(socketsMap, &parsedSockets)
 	}
-	return parsedSockets
+	return parsedSockets, ok
 }
 
 // Returns a list of daemons for which sockets have been configured.
@@ -460,6 +464,20 @@ func (c *Map) GetGlobalReservationModes() *ReservationModes {
 	return modes
 }
 
+// Parses the top-level configuration parameters into the specified
+// structure. The argument must be a pointer to a structure reflecting
+// selected global parameters.
+func (c *Map) DecodeTopLevelParameters(output interface{}) error {
+	rootNode, ok := c.getRootNode()
+	if !ok {
+		return errors.New("missing root node")
+	}
+	if err := decode(rootNode, output); err != nil {
+		return errors.WithMessage(err, "problem parsing top-level parameters")
+	}
+	return nil
+}
+
 // Hide any sensitive data in the config.
 func (c *Map) HideSensitiveData() {
 	hideSensitiveData((*map[string]interface{})(c))
@@ -526,3 +544,26 @@ func IsInAnyReservationModes(condition func(modes ReservationModes) (bool, bool)
 	}
 	return false
 }
+
+// Convenience function returning the effective value of a given host
+// reservation mode. The reservation modes specified using the variadic
+// parameters should be ordered from the lowest to highest configuration
+// level, e.g., subnet-level, shared network-level, and finally global-level
+// host reservation configuration. The first argument is a function
+// implementing a condition to be checked for each ReservationModes,
+// similarly to IsInAnyReservationModes.
+//
+// Unlike IsInAnyReservationModes, this function follows the Kea
+// inheritance scheme strictly. The value explicitly set at the lowest
+// configuration level wins, even if it disables the mode enabled at a
+// higher level. If the value isn't set explicitly at any level, the
+// default value returned for the last reservation mode is used.
+func GetEffectiveReservationMode(condition func(modes ReservationModes) (bool, bool), modes ...ReservationModes) bool {
+	for i, mode := range modes {
+		cond, explicit := condition(mode)
+		if explicit || i >= len(modes)-1 {
+			return cond
+		}
+	}
+	return false
+}
@@ -399,7 +399,8 @@ func TestGetControlSockets(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	sockets := cfg.GetControlSockets()
+	sockets, ok := cfg.GetControlSockets()
+	require.True(t, ok)
 
 	require.NotNil(t, sockets.D2)
 	require.Equal(t, "unix", sockets.D2.SocketType)
@@ -416,6 +417,20 @@ func TestGetControlSockets(t *testing.T) {
 	require.Nil(t, sockets.NetConf)
 }
 
+// Verifies that the missing control sockets are handled properly.
+func TestGetControlSocketsNoSockets(t *testing.T) {
+	cfg, err := NewFromJSON(`{ "Control-agent": { } }`)
+	require.NoError(t, err)
+
+	sockets, ok := cfg.GetControlSockets()
+	require.False(t, ok)
+	require.Nil(t, sockets.D2)
+	require.Nil(t, sockets.Dhcp4)
+	require.Nil(t, sockets.Dhcp6)
+	require.Nil(t, sockets.NetConf)
+	require.Empty(t, sockets.ConfiguredDaemonNames())
+}
+
 // Verifies that the list of daemons for which control sockets are specified
 // is returned correctly.
 func TestConfiguredDaemonNames(t *testing.T) {
@@ -447,7 +462,7 @@ func TestConfiguredDaemonNames(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	sockets := cfg.GetControlSockets()
+	sockets, _ := cfg.GetControlSockets()
 
 	names := sockets.ConfiguredDaemonNames()
 	require.Len(t, names, 4)
@@ -477,7 +492,7 @@ func TestConfiguredDaemonNames(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	sockets = cfg.GetControlSockets()
+	sockets, _ = cfg.GetControlSockets()
 
 	// This time only two sockets have been configured.
 	names = sockets.ConfiguredDaemonNames()
@@ -678,6 +693,44 @@ func TestGetAllDatabases(t *testing.T) {
 	})
 }
 
+// Test that the top-level parameters are decoded into a structure.
+func TestDecodeTopLevelParameters(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            "valid-lifetime": 1000,
+            "preferred-lifetime": 800
+        }
+    }`
+	cfg, err := NewFromJSON(configStr)
+	require.NoError(t, err)
+
+	var params struct {
+		ValidLifetime     *int64
+		PreferredLifetime *int64
+		RenewTimer        *int64
+	}
+	err = cfg.DecodeTopLevelParameters(&params)
+	require.NoError(t, err)
+	require.NotNil(t, params.ValidLifetime)
+	require.EqualValues(t, 1000, *params.ValidLifetime)
+	require.NotNil(t, params.PreferredLifetime)
+	require.EqualValues(t, 800, *params.PreferredLifetime)
+	require.Nil(t, params.RenewTimer)
+}
+
+// Test that decoding the top-level parameters fails for a configuration
+// without the root node.
+func TestDecodeTopLevelParametersNoRoot(t *testing.T) {
+	cfg, err := NewFromJSON(`{}`)
+	require.NoError(t, err)
+
+	var params struct {
+		ValidLifetime *int64
+	}
+	err = cfg.DecodeTopLevelParameters(&params)
+	require.Error(t, err)
+}
+
 // Test parsing global reservation modes when all of them
 // are explicitly set.
 func TestGetGlobalReservationModesEnableAll(t *testing.T) {
@@ -890,6 +943,41 @@ func TestIsInAnyReservationModes(t *testing.T) {
 	}, modes[0], modes[0]))
 }
 
+// Test a function returning the effective host reservation mode using
+// Kea inheritance scheme.
+func TestGetEffectiveReservationMode(t *testing.T) {
+	modes := []ReservationModes{
+		{
+			OutOfPool: nil,
+		},
+		{
+			OutOfPool: new(bool),
+		},
+		{
+			OutOfPool: new(bool),
+		},
+	}
+	*modes[2].OutOfPool = true
+
+	condition := func(modes ReservationModes) (bool, bool) {
+		return modes.IsOutOfPool()
+	}
+
+	// The lower level explicitly disables the mode enabled at the
+	// higher level.
+	require.False(t, GetEffectiveReservationMode(condition, modes[0], modes[1], modes[2]))
+	// The lower level inherits the mode enabled at the higher level.
+	require.True(t, GetEffectiveReservationMode(condition, modes[0], modes[2], modes[1]))
+	// The lower level explicitly enables the mode.
+	require.True(t, GetEffectiveReservationMode(condition, modes[2], modes[0]))
+	// The mode isn't set at any level, so the default is used.
+	require.False(t, GetEffectiveReservationMode(condition, modes[0], modes[0]))
+	require.True(t, GetEffectiveReservationMode(func(modes ReservationModes) (bool, bool) {
+		return modes.IsInSubnet()
+	}, modes[0], modes[0]))
+	require.False(t, GetEffectiveReservationMode(condition))
+}
+
 // Test that the sensitive data are hidden.
 func TestHideSensitiveData(t *testing.T) {
 	// Arrange
@@ -9,24 +9,24 @@ import (
 
 // Represents address pool structure within Kea configuration.
 type Pool struct {
-	Pool string
+	Pool string `json:"pool"`
 }
 
 // Represents prefix delegation pool structure within Kea configuration.
 type PdPool struct {
-	Prefix       string
-	PrefixLen    int `mapstructure:"prefix-len"`
-	DelegatedLen int `mapstructure:"delegated-len"`
+	Prefix       string `json:"prefix"`
+	PrefixLen    int    `mapstructure:"prefix-len" json:"prefix-len"`
+	DelegatedLen int    `mapstructure:"delegated-len" json:"delegated-len"`
 }
 
 // Represents a subnet with pools within Kea configuration.
 type Subnet struct {
-	ID           int64
-	Subnet       string
-	ClientClass  string `mapstructure:"client-class"`
-	Pools        []Pool
-	PdPools      []PdPool `mapstructure:"pd-pools"`
-	Reservations []Reservation
+	ID           int64         `json:"id,omitempty"`
+	Subnet       string        `json:"subnet"`
+	ClientClass  string        `mapstructure:"client-class" json:"client-class,omitempty"`
+	Pools        []Pool        `json:"pools,omitempty"`
+	PdPools      []PdPool      `mapstructure:"pd-pools" json:"pd-pools,omitempty"`
+	Reservations []Reservation `json:"reservations,omitempty"`
 }
 
 // Represents a shared network with subnets within Kea configuration.
@@ -0,0 +1,119 @@
+package keaconfig
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	storkutil "isc.org/stork/util"
+)
+
+// Result of comparing the subnets of two Kea configurations. The subnets
+// are identified by their prefixes in the canonical form.
+type SubnetsDiff struct {
+	// Subnets present only in the new configuration.
+	Added []string
+	// Subnets present only in the old configuration.
+	Removed []string
+	// Subnets present in both configurations with different parameters,
+	// pools, host reservations or shared networks.
+	Changed []string
+	// Subnets present in both configurations without any differences.
+	Unchanged []string
+}
+
+// Subnet extracted from the configuration for comparison.
+type diffedSubnet struct {
+	sharedNetwork string
+	raw           map[string]interface{}
+	// Indicates that the prefix is specified more than once in the
+	// configuration, so the subnets cannot be reliably compared.
+	duplicate bool
+}
+
+// Returns the subnets of the configuration indexed by their canonical
+// prefixes.
+func getDiffedSubnets(config *Map) (map[string]*diffedSubnet, error) {
+	type sharedNetwork struct {
+		Name    string
+		Subnet4 []map[string]interface{}
+		Subnet6 []map[string]interface{}
+	}
+
+	var decodedSharedNetworks []sharedNetwork
+	if err := config.DecodeSharedNetworks(&decodedSharedNetworks); err != nil {
+		return nil, err
+	}
+	var decodedSubnets []map[string]interface{}
+	if err := config.DecodeTopLevelSubnets(&decodedSubnets); err != nil {
+		return nil, err
+	}
+	// The top-level subnets belong to the artificial shared network with
+	// the empty name.
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets,
+	})
+
+	subnets := make(map[string]*diffedSubnet)
+	for _, network := range decodedSharedNetworks {
+		for _, raw := range append(network.Subnet4, network.Subnet6...) {
+			prefix, _ := raw["subnet"].(string)
+			if parsed := storkutil.ParseIP(prefix); parsed != nil {
+				prefix = parsed.GetNetworkPrefixWithLength()
+			}
+			if existing, ok := subnets[prefix]; ok {
+				existing.duplicate = true
+				continue
+			}
+			subnets[prefix] = &diffedSubnet{
+				sharedNetwork: network.Name,
+				raw:           raw,
+			}
+		}
+	}
+	return subnets, nil
+}
+
+// Compares the subnets in two Kea configurations. It allows for
+// committing only the subnets that have been modified instead of
+// processing the entire configuration. A subnet is considered changed
+// when any of its parameters, including the pools and host reservations,
+// differ or when it has been moved to another shared network. The subnets
+// with duplicated prefixes are always considered changed. The returned
+// prefixes are sorted.
+func DiffSubnets(oldConfig, newConfig *Map) (*SubnetsDiff, error) {
+	oldSubnets, err := getDiffedSubnets(oldConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "problem parsing subnets of the old configuration")
+	}
+	newSubnets, err := getDiffedSubnets(newConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "problem parsing subnets of the new configuration")
+	}
+
+	diff := &SubnetsDiff{}
+	for prefix, newSubnet := range newSubnets {
+		oldSubnet, ok := oldSubnets[prefix]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, prefix)
+		case oldSubnet.duplicate || newSubnet.duplicate,
+			oldSubnet.sharedNetwork != newSubnet.sharedNetwork,
+			!reflect.DeepEqual(oldSubnet.raw, newSubnet.raw):
+			diff.Changed = append(diff.Changed, prefix)
+		default:
+			diff.Unchanged = append(diff.Unchanged, prefix)
+		}
+	}
+	for prefix := range oldSubnets {
+		if _, ok := newSubnets[prefix]; !ok {
+			diff.Removed = append(diff.Removed, prefix)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Unchanged)
+	return diff, nil
+}
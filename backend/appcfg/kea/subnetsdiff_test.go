@@ -0,0 +1,165 @@
+package keaconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the added, removed, changed and unchanged subnets are
+// detected, including the subnets moved between the shared networks
+// and the subnets with modified host reservations.
+func TestDiffSubnets(t *testing.T) {
+	oldConfig, err := NewFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24"
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 3,
+                    "subnet": "10.0.0.0/24",
+                    "pools": [ { "pool": "10.0.0.10-10.0.0.20" } ]
+                },
+                {
+                    "id": 4,
+                    "subnet": "10.0.1.0/24",
+                    "reservations": [ { "hw-address": "01:02:03:04:05:06", "ip-address": "10.0.1.10" } ]
+                },
+                {
+                    "id": 5,
+                    "subnet": "10.0.2.0/24"
+                }
+            ]
+        }
+    }`)
+	require.NoError(t, err)
+
+	newConfig, err := NewFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24"
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24"
+                },
+                {
+                    "id": 3,
+                    "subnet": "10.0.0.1/24",
+                    "pools": [ { "pool": "10.0.0.10-10.0.0.20" } ]
+                },
+                {
+                    "id": 4,
+                    "subnet": "10.0.1.0/24",
+                    "reservations": [ { "hw-address": "01:02:03:04:05:06", "ip-address": "10.0.1.11" } ]
+                },
+                {
+                    "id": 6,
+                    "subnet": "10.0.3.0/24"
+                }
+            ]
+        }
+    }`)
+	require.NoError(t, err)
+
+	diff, err := DiffSubnets(oldConfig, newConfig)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+
+	require.Equal(t, []string{"10.0.3.0/24"}, diff.Added)
+	require.Equal(t, []string{"10.0.2.0/24"}, diff.Removed)
+	require.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24", "192.0.3.0/24"}, diff.Changed)
+	require.Equal(t, []string{"192.0.2.0/24"}, diff.Unchanged)
+}
+
+// Test that comparing the same configurations yields no differences.
+func TestDiffSubnetsSameConfig(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64"
+                }
+            ]
+        }
+    }`
+	oldConfig, err := NewFromJSON(configStr)
+	require.NoError(t, err)
+	newConfig, err := NewFromJSON(configStr)
+	require.NoError(t, err)
+
+	diff, err := DiffSubnets(oldConfig, newConfig)
+	require.NoError(t, err)
+	require.Empty(t, diff.Added)
+	require.Empty(t, diff.Removed)
+	require.Empty(t, diff.Changed)
+	require.Equal(t, []string{"2001:db8:1::/64", "2001:db8:2::/64"}, diff.Unchanged)
+}
+
+// Test that the subnets with duplicated prefixes are always considered
+// changed.
+func TestDiffSubnetsDuplicates(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24"
+                },
+                {
+                    "id": 2,
+                    "subnet": "192.0.2.0/24"
+                }
+            ]
+        }
+    }`
+	oldConfig, err := NewFromJSON(configStr)
+	require.NoError(t, err)
+	newConfig, err := NewFromJSON(configStr)
+	require.NoError(t, err)
+
+	diff, err := DiffSubnets(oldConfig, newConfig)
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.0/24"}, diff.Changed)
+	require.Empty(t, diff.Unchanged)
+}
+
+// Test that an error is returned for the configuration without the
+// supported root node.
+func TestDiffSubnetsInvalidConfig(t *testing.T) {
+	oldConfig, err := NewFromJSON(`{ "Control-agent": { } }`)
+	require.NoError(t, err)
+	newConfig, err := NewFromJSON(`{ "Dhcp4": { } }`)
+	require.NoError(t, err)
+
+	diff, err := DiffSubnets(oldConfig, newConfig)
+	require.Error(t, err)
+	require.Nil(t, diff)
+}
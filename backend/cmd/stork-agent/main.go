@@ -90,19 +90,30 @@ func runAgent(settings *cli.Context, reload bool) error {
 
 	// Handle signals.
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGINT, syscall.SIGHUP)
-	sig := <-c
-	switch sig {
-	case syscall.SIGHUP:
-		log.Info("Reloading Stork Agent after receiving SIGHUP signal")
-		// Trigger shutdown with setting the reload flag. It doesn't
-		// matter we have deferred another shutdown already. It will
-		// be executed only once.
-		storkAgent.Shutdown(true)
-		return &sighupError{}
-	default:
-		log.Info("Received Ctrl-C signal")
-		return &ctrlcError{}
+	signal.Notify(c, os.Interrupt, syscall.SIGINT, syscall.SIGHUP, syscall.SIGUSR1)
+	for {
+		sig := <-c
+		switch sig {
+		case syscall.SIGUSR1:
+			// Reload the credentials without restarting the agent. The
+			// current credentials are kept if the new file is invalid.
+			log.Infof("Reloading the credentials file (%s) after receiving SIGUSR1 signal", agent.CredentialsFile)
+			if err := storkAgent.HTTPClient.ReloadCredentials(); err != nil {
+				log.Errorf("Failed to reload the credentials, the current credentials are kept: %+v", err)
+			} else {
+				log.Info("Successfully reloaded the credentials")
+			}
+		case syscall.SIGHUP:
+			log.Info("Reloading Stork Agent after receiving SIGHUP signal")
+			// Trigger shutdown with setting the reload flag. It doesn't
+			// matter we have deferred another shutdown already. It will
+			// be executed only once.
+			storkAgent.Shutdown(true)
+			return &sighupError{}
+		default:
+			log.Info("Received Ctrl-C signal")
+			return &ctrlcError{}
+		}
 	}
 }
 
@@ -218,6 +229,11 @@ func setupApp(reload bool) *cli.App {
 				Usage:   "Skip TLS certificate verification when the Stork Agent connects to Kea over TLS and Kea uses self-signed certificates",
 				EnvVars: []string{"STORK_AGENT_SKIP_TLS_CERT_VERIFICATION"},
 			},
+			&cli.StringSliceFlag{
+				Name:    "config-path-mapping",
+				Usage:   "Maps the configuration path prefix inside a container to the path prefix on the host in the container-prefix=host-prefix form, e.g., /etc/kea=/var/lib/docker/overlay2/abc/merged/etc/kea; it allows the Stork Agent to locate the configuration files of the apps running in containers; may be specified multiple times",
+				EnvVars: []string{"STORK_AGENT_CONFIG_PATH_MAPPING"},
+			},
 			// Registration related settings
 			&cli.StringFlag{
 				Name:    "server-url",
@@ -1,22 +1,78 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 
 	"isc.org/stork"
 	"isc.org/stork/server/certs"
+	"isc.org/stork/server/configreview"
 	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+	"isc.org/stork/server/hostimport"
+	"isc.org/stork/server/metrics"
 	storkutil "isc.org/stork/util"
 )
 
 // Random hash size in the generated password.
 const passwordGenRandomLength = 24
 
+// Output formats of the tool.
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// Database schema version printed by the db-version command in the JSON
+// output mode.
+type dbVersionOutput struct {
+	Current   int64 `json:"current"`
+	Available int64 `json:"available"`
+	UpToDate  bool  `json:"upToDate"`
+}
+
+// Migrations that would be run by the db-up or db-down command printed
+// in the dry-run mode in the JSON output mode.
+type dbMigrationPlanOutput struct {
+	Direction string  `json:"direction"`
+	Current   int64   `json:"current"`
+	Target    int64   `json:"target"`
+	Pending   []int64 `json:"pending"`
+}
+
+// Checks if the JSON output mode is selected.
+func isJSONOutput(settings *cli.Context) bool {
+	return settings.String("output") == outputFormatJSON
+}
+
+// Validates the output format. In the JSON output mode, the logs are
+// routed to stderr, so stdout contains only the JSON output.
+func setupOutput(settings *cli.Context) error {
+	switch settings.String("output") {
+	case outputFormatText:
+	case outputFormatJSON:
+		log.SetOutput(os.Stderr)
+	default:
+		return errors.Errorf("unsupported output format '%s'", settings.String("output"))
+	}
+	return nil
+}
+
+// Prints the value as JSON to stdout.
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "    ")
+	return errors.Wrap(encoder.Encode(value), "problem printing JSON output")
+}
+
 // Establish connection to a database using admin credentials.
 // Specifying db-url is not supported. The maintenance database name,
 // user and password are specified with db-maintenance-name,
@@ -212,12 +268,34 @@ func runDBMigrate(settings *cli.Context, command, version string) {
 
 	db := getDBConn(settings)
 
+	if (command == "up" || command == "down") && settings.Bool("dry-run") {
+		err := runDBMigrateDryRun(settings, db, args...)
+		db.Close()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	oldVersion, newVersion, err := dbops.Migrate(db, args...)
 	db.Close()
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 
+	if command == "version" && isJSONOutput(settings) {
+		availVersion := dbops.AvailableVersion()
+		err = printJSON(dbVersionOutput{
+			Current:   oldVersion,
+			Available: availVersion,
+			UpToDate:  oldVersion == availVersion,
+		})
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	if newVersion != oldVersion {
 		log.Infof("Migrated database from version %d to %d\n", oldVersion, newVersion)
 	} else {
@@ -230,11 +308,81 @@ func runDBMigrate(settings *cli.Context, command, version string) {
 	}
 }
 
+// Prints the migrations that would be run by the db-up or db-down command
+// without modifying the database schema. It doesn't fail when there are
+// pending migrations, so it can be used to review them before running.
+func runDBMigrateDryRun(settings *cli.Context, db *dbops.PgDB, args ...string) error {
+	plan, err := dbops.PlanMigration(db, args...)
+	if err != nil {
+		return err
+	}
+
+	if isJSONOutput(settings) {
+		pending := plan.Versions
+		if pending == nil {
+			pending = []int64{}
+		}
+		return printJSON(dbMigrationPlanOutput{
+			Direction: plan.Direction,
+			Current:   plan.CurrentVersion,
+			Target:    plan.TargetVersion,
+			Pending:   pending,
+		})
+	}
+
+	fmt.Printf("Dry run: migrating %s from version %d to %d\n", plan.Direction, plan.CurrentVersion, plan.TargetVersion)
+	if len(plan.Versions) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+	verb := "apply"
+	if plan.Direction == "down" {
+		verb = "revert"
+	}
+	fmt.Printf("%s would be run:\n", storkutil.FormatNoun(int64(len(plan.Versions)), "migration", "s"))
+	for i, version := range plan.Versions {
+		fmt.Printf("  %d. %s migration %d\n", i+1, verb, version)
+	}
+	return nil
+}
+
+// Execute db-dump command. It writes the logical dump of the Stork database
+// to the specified file or to stdout.
+func runDBDump(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	filename := settings.String("file")
+	if filename == "" {
+		return dbops.Dump(db, os.Stdout)
+	}
+
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create the file %s", filename)
+	}
+	defer file.Close()
+
+	if err = dbops.Dump(db, file); err != nil {
+		return err
+	}
+	log.Infof("Database dump written to %s", filename)
+	return nil
+}
+
 // Execute cert export command.
 func runCertExport(settings *cli.Context) error {
 	db := getDBConn(settings)
 
-	return certs.ExportSecret(db, settings.String("object"), settings.String("file"))
+	if isJSONOutput(settings) {
+		metadata, err := certs.ExportSecretMetadata(db, settings.String("object"), settings.String("encoding"), settings.String("file"))
+		if err != nil {
+			return err
+		}
+		return printJSON(metadata)
+	}
+
+	return certs.ExportSecret(db, settings.String("object"), settings.String("encoding"), settings.String("file"))
 }
 
 // Execute cert import command.
@@ -244,6 +392,235 @@ func runCertImport(settings *cli.Context) error {
 	return certs.ImportSecret(db, settings.String("object"), settings.String("file"))
 }
 
+// Execute cert check command. It reports the server and agent certificates
+// which expire within the specified number of days or have already expired.
+func runCertCheck(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	days := settings.Int("days")
+	if days < 0 {
+		return errors.Errorf("number of days must not be negative: %d", days)
+	}
+
+	now := time.Now().UTC()
+	expiring, err := certs.FindExpiringCerts(db, now, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	for _, cert := range expiring {
+		if cert.IsExpired(now) {
+			log.Errorf("The %s expired on %s", cert.Name, cert.NotAfter.Format(time.RFC3339))
+		} else {
+			log.Warnf("The %s expires on %s", cert.Name, cert.NotAfter.Format(time.RFC3339))
+		}
+	}
+	if len(expiring) == 0 {
+		log.Infof("No certificates expire within %d days", days)
+	}
+	return nil
+}
+
+// Execute cert expiry command. It prints the expiration dates of the CA and
+// server certificates and the number of days left. It returns an error if
+// any of the certificates expires within the specified number of days, so
+// the command exits with a non-zero status.
+func runCertExpiry(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	warningDays := settings.Int("warning-days")
+	if warningDays < 0 {
+		return errors.Errorf("number of warning days must not be negative: %d", warningDays)
+	}
+
+	now := time.Now().UTC()
+	serverCerts, err := certs.GetServerCertsExpiry(db)
+	if err != nil {
+		return err
+	}
+	expiring := 0
+	for _, cert := range serverCerts {
+		fmt.Printf("%s: expires on %s (%s left)\n", cert.Name,
+			cert.NotAfter.Format(time.RFC3339), storkutil.FormatNoun(int64(cert.DaysLeft(now)), "day", "s"))
+		if cert.NotAfter.Before(now.AddDate(0, 0, warningDays)) {
+			expiring++
+		}
+	}
+	if expiring > 0 {
+		return errors.Errorf("%s expire within %d days", storkutil.FormatNoun(int64(expiring), "certificate", "s"), warningDays)
+	}
+	return nil
+}
+
+// Execute cert rotate command. It regenerates the server key and cert
+// signed by the root CA held in the database. In the dry-run mode, it only
+// prints what would change.
+func runCertRotate(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	dryRun := settings.Bool("dry-run")
+	rotation, err := certs.RotateServerCert(db, settings.Int("validity-days"), settings.Bool("bump-token"), dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "has been"
+	if dryRun {
+		fmt.Println("Dry run: no changes are made in the database")
+		verb = "would be"
+	}
+	fmt.Printf("The %s and %s %s regenerated\n", certs.SecretTypeSrvKey, certs.SecretTypeSrvCert, verb)
+	if rotation.OldNotAfter != nil {
+		fmt.Printf("  old %s expiration: %s\n", certs.SecretTypeSrvCert, rotation.OldNotAfter.Format(time.RFC3339))
+	}
+	fmt.Printf("  new %s expiration: %s\n", certs.SecretTypeSrvCert, rotation.NotAfter.Format(time.RFC3339))
+	fmt.Printf("  DNS names: %s\n", strings.Join(rotation.DNSNames, ", "))
+	var ips []string
+	for _, ip := range rotation.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	fmt.Printf("  IP addresses: %s\n", strings.Join(ips, ", "))
+	if rotation.TokenBumped {
+		fmt.Printf("The %s %s regenerated\n", certs.SecretTypeSrvToken, verb)
+	}
+
+	if !dryRun {
+		log.Warn("The Stork server must be restarted to use the new server key and cert; " +
+			"the agents will need to re-register with the server")
+	}
+	return nil
+}
+
+// Execute metrics push command.
+func runMetricsPush(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	return metrics.PushMetrics(db, settings.String("pushgateway-url"), settings.String("job"))
+}
+
+// Execute hosts import command.
+func runHostsImport(settings *cli.Context) error {
+	file, err := os.Open(settings.String("file"))
+	if err != nil {
+		return errors.Wrapf(err, "cannot open the file %s", settings.String("file"))
+	}
+	defer file.Close()
+
+	db := getDBConn(settings)
+	defer db.Close()
+
+	result, err := hostimport.ImportHosts(db, file, settings.Bool("strict"))
+	if err != nil {
+		return err
+	}
+	for _, rowErr := range result.RowErrors {
+		log.Errorf("Skipped host reservation: %s", rowErr)
+	}
+	log.Infof("Imported %d host reservations, skipped %d", result.Imported, len(result.RowErrors))
+	return nil
+}
+
+// Output of the checkers-apply-profile command in the JSON mode.
+type checkerStateChangesOutput struct {
+	Items []configreview.CheckerStateChange `json:"items"`
+	Total int                               `json:"total"`
+}
+
+// Execute checkers apply profile command. It applies the profile of the
+// config checker states to the preferences stored in the database. The
+// profile may refer to the checkers registered by default only.
+func runCheckersApplyProfile(settings *cli.Context) error {
+	data, err := os.ReadFile(settings.String("file"))
+	if err != nil {
+		return errors.Wrapf(err, "cannot read the file %s", settings.String("file"))
+	}
+	profile, err := configreview.ParseCheckerProfile(data)
+	if err != nil {
+		return err
+	}
+
+	db := getDBConn(settings)
+	defer db.Close()
+
+	dispatcher := configreview.NewDispatcher(db)
+	configreview.RegisterDefaultCheckers(dispatcher)
+	if err = configreview.LoadAndValidateCheckerPreferences(db, dispatcher); err != nil {
+		return err
+	}
+
+	changes, err := configreview.ApplyCheckerProfile(db, dispatcher, profile)
+	if err != nil {
+		return err
+	}
+
+	if isJSONOutput(settings) {
+		if changes == nil {
+			changes = []configreview.CheckerStateChange{}
+		}
+		err = printJSON(checkerStateChangesOutput{
+			Items: changes,
+			Total: len(changes),
+		})
+	} else {
+		if len(changes) == 0 {
+			fmt.Println("No checker states changed")
+		} else {
+			fmt.Printf("%s changed:\n", storkutil.FormatNoun(int64(len(changes)), "checker state", "s"))
+		}
+		for _, change := range changes {
+			scope := "global"
+			if change.DaemonID != 0 {
+				scope = fmt.Sprintf("daemon %d", change.DaemonID)
+			}
+			fmt.Printf("  %s: %s checker %s -> %s\n", scope, change.CheckerName, change.OldState, change.NewState)
+		}
+	}
+	if err == nil && len(changes) > 0 {
+		log.Warn("The Stork server must be restarted to load the changed checker states")
+	}
+	return err
+}
+
+// Execute subnets export command. It writes the subnets of the specified
+// Kea DHCP daemon with their pools and host reservations in the Kea
+// configuration format to the specified file or to stdout.
+func runSubnetsExport(settings *cli.Context) error {
+	db := getDBConn(settings)
+	defer db.Close()
+
+	daemonID := settings.Int64("daemon-id")
+	daemon, err := dbmodel.GetDaemonByID(db, daemonID)
+	if err != nil {
+		return err
+	}
+	if daemon == nil {
+		return errors.Errorf("daemon with ID %d not found", daemonID)
+	}
+
+	subnetsConfig, err := dbmodel.ExportKeaSubnetsConfig(db, daemon)
+	if err != nil {
+		return err
+	}
+
+	filename := settings.String("file")
+	if filename == "" {
+		return printJSON(subnetsConfig)
+	}
+
+	content, err := json.MarshalIndent(subnetsConfig, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "problem serializing the subnets")
+	}
+	if err = os.WriteFile(filename, content, 0o600); err != nil {
+		return errors.Wrapf(err, "cannot write the file %s", filename)
+	}
+	log.Infof("Subnets of the daemon with ID %d written to %s", daemonID, filename)
+	return nil
+}
+
 // Prepare urfave cli app with all flags and commands defined.
 func setupApp() *cli.App {
 	cli.VersionPrinter = func(c *cli.Context) {
@@ -392,6 +769,25 @@ func setupApp() *cli.App {
 			EnvVars: []string{"STORK_TOOL_DB_VERSION"},
 		})
 
+	var dbMigrateFlags []cli.Flag
+	dbMigrateFlags = append(dbMigrateFlags, dbVerFlags...)
+	dbMigrateFlags = append(dbMigrateFlags,
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Usage:   "Print the migrations that would be run without modifying the database schema.",
+			EnvVars: []string{"STORK_TOOL_DB_DRY_RUN"},
+		})
+
+	var dbDumpFlags []cli.Flag
+	dbDumpFlags = append(dbDumpFlags, dbFlags...)
+	dbDumpFlags = append(dbDumpFlags,
+		&cli.StringFlag{
+			Name:    "file",
+			Usage:   "The file location where the dump should be saved. If not provided, then the dump is printed to stdout.",
+			Aliases: []string{"o"},
+			EnvVars: []string{"STORK_TOOL_DB_DUMP_FILE"},
+		})
+
 	var certExportFlags []cli.Flag
 	certExportFlags = append(certExportFlags, dbFlags...)
 	certExportFlags = append(certExportFlags,
@@ -407,6 +803,13 @@ func setupApp() *cli.App {
 			Usage:   "The file location where the object should be saved. If not provided, then object is printed to stdout.",
 			Aliases: []string{"o"},
 			EnvVars: []string{"STORK_TOOL_CERT_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "encoding",
+			Usage:   "The encoding of the exported object; it can be one of 'raw', 'pem', 'der', 'base64'. The server token can be exported only as 'raw' or 'base64'.",
+			Value:   certs.ExportEncodingRaw,
+			Aliases: []string{"e"},
+			EnvVars: []string{"STORK_TOOL_CERT_ENCODING"},
 		})
 
 	var certImportFlags []cli.Flag
@@ -426,11 +829,113 @@ func setupApp() *cli.App {
 			EnvVars: []string{"STORK_TOOL_CERT_FILE"},
 		})
 
+	var certCheckFlags []cli.Flag
+	certCheckFlags = append(certCheckFlags, dbFlags...)
+	certCheckFlags = append(certCheckFlags,
+		&cli.IntFlag{
+			Name:    "days",
+			Usage:   "The number of days before the expiration in which the certificate is reported.",
+			Value:   int(certs.DefaultCertExpiryWindow / (24 * time.Hour)),
+			EnvVars: []string{"STORK_TOOL_CERT_DAYS"},
+		})
+
+	var certExpiryFlags []cli.Flag
+	certExpiryFlags = append(certExpiryFlags, dbFlags...)
+	certExpiryFlags = append(certExpiryFlags,
+		&cli.IntFlag{
+			Name:    "warning-days",
+			Usage:   "The number of days before the expiration in which the command exits with an error.",
+			Value:   int(certs.DefaultCertExpiryWindow / (24 * time.Hour)),
+			EnvVars: []string{"STORK_TOOL_CERT_WARNING_DAYS"},
+		})
+
+	var certRotateFlags []cli.Flag
+	certRotateFlags = append(certRotateFlags, dbFlags...)
+	certRotateFlags = append(certRotateFlags,
+		&cli.IntFlag{
+			Name:    "validity-days",
+			Usage:   "The number of days the new server certificate is valid.",
+			Value:   certs.DefaultRotatedCertValidityDays,
+			EnvVars: []string{"STORK_TOOL_CERT_VALIDITY_DAYS"},
+		},
+		&cli.BoolFlag{
+			Name:    "bump-token",
+			Usage:   "Regenerate the server token used during the agent registration.",
+			EnvVars: []string{"STORK_TOOL_CERT_BUMP_TOKEN"},
+		},
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Usage:   "Print what would change without modifying the database.",
+			EnvVars: []string{"STORK_TOOL_CERT_DRY_RUN"},
+		})
+
+	var metricsPushFlags []cli.Flag
+	metricsPushFlags = append(metricsPushFlags, dbFlags...)
+	metricsPushFlags = append(metricsPushFlags,
+		&cli.StringFlag{
+			Name:     "pushgateway-url",
+			Usage:    "The URL of the Prometheus PushGateway the metrics are pushed to.",
+			Required: true,
+			Aliases:  []string{"g"},
+			EnvVars:  []string{"STORK_TOOL_METRICS_PUSHGATEWAY_URL"},
+		},
+		&cli.StringFlag{
+			Name:    "job",
+			Usage:   "The job name under which the metrics are grouped in the Prometheus PushGateway.",
+			Value:   "stork_server",
+			Aliases: []string{"j"},
+			EnvVars: []string{"STORK_TOOL_METRICS_JOB"},
+		})
+
+	var hostsImportFlags []cli.Flag
+	hostsImportFlags = append(hostsImportFlags, dbFlags...)
+	hostsImportFlags = append(hostsImportFlags,
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "The CSV file with the host reservations. The columns are: subnet-prefix, identifier-type, identifier, ip-address, hostname.",
+			Required: true,
+			Aliases:  []string{"i"},
+			EnvVars:  []string{"STORK_TOOL_HOSTS_FILE"},
+		},
+		&cli.BoolFlag{
+			Name:    "strict",
+			Usage:   "Abort the whole import when any row is invalid. By default, the invalid rows are skipped.",
+			EnvVars: []string{"STORK_TOOL_HOSTS_STRICT"},
+		})
+
+	var checkersApplyProfileFlags []cli.Flag
+	checkersApplyProfileFlags = append(checkersApplyProfileFlags, dbFlags...)
+	checkersApplyProfileFlags = append(checkersApplyProfileFlags,
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "The JSON file with the profile of the global and per-daemon config checker states.",
+			Required: true,
+			Aliases:  []string{"i"},
+			EnvVars:  []string{"STORK_TOOL_CHECKERS_PROFILE_FILE"},
+		})
+
+	var subnetsExportFlags []cli.Flag
+	subnetsExportFlags = append(subnetsExportFlags, dbFlags...)
+	subnetsExportFlags = append(subnetsExportFlags,
+		&cli.Int64Flag{
+			Name:     "daemon-id",
+			Usage:    "The ID of the Kea DHCP daemon which subnets should be exported.",
+			Required: true,
+			Aliases:  []string{"d"},
+			EnvVars:  []string{"STORK_TOOL_SUBNETS_DAEMON_ID"},
+		},
+		&cli.StringFlag{
+			Name:    "file",
+			Usage:   "The file location where the subnets should be saved. If not provided, then the subnets are printed to stdout.",
+			Aliases: []string{"o"},
+			EnvVars: []string{"STORK_TOOL_SUBNETS_FILE"},
+		})
+
 	app := &cli.App{
 		Name: "Stork Tool",
 		Usage: `A tool for managing Stork Server.
 
-   The tool operates in three areas:
+   The tool operates in six areas:
 
    - Certificate Management - it allows for exporting Stork Server keys, certificates,
      and tokens that are used to secure communication between the Stork Server
@@ -440,9 +945,27 @@ func setupApp() *cli.App {
      and a user that can access this database with a generated password;
 
    - Database Migration - it allows for performing database schema migrations,
-     overwriting the db schema version and getting its current value.`,
+     overwriting the db schema version and getting its current value;
+
+   - Metrics - it allows for pushing the snapshot of the Stork Server metrics
+     to the Prometheus PushGateway;
+
+   - Host Reservations - it allows for importing the host reservations from
+     a CSV file;
+
+   - Configuration Review - it allows for applying a profile of the config
+     checker states.`,
 		Version:  stork.Version,
 		HelpName: "stork-tool",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Usage:   "The output format; it can be one of 'text' or 'json'. In the 'json' mode, the db-version command prints the schema versions and the cert-export command prints the exported object metadata instead of its content, the db-up and db-down commands print the pending migrations in the dry-run mode, and the checkers-apply-profile command prints the changed checker states as JSON to stdout, and the logs are printed to stderr.",
+				Value:   outputFormatText,
+				EnvVars: []string{"STORK_TOOL_OUTPUT"},
+			},
+		},
+		Before: setupOutput,
 		Commands: []*cli.Command{
 			// DATABASE CREATION COMMANDS
 			{
@@ -485,9 +1008,9 @@ func setupApp() *cli.App {
 			{
 				Name:        "db-up",
 				Usage:       "Run all available migrations or use -t to specify version",
-				UsageText:   "stork-tool db-up [options for db connection] [-t version]",
+				UsageText:   "stork-tool db-up [options for db connection] [-t version] [--dry-run]",
 				Description: ``,
-				Flags:       dbVerFlags,
+				Flags:       dbMigrateFlags,
 				Category:    "Database Migration",
 				Action: func(c *cli.Context) error {
 					runDBMigrate(c, "up", c.String("version"))
@@ -497,9 +1020,9 @@ func setupApp() *cli.App {
 			{
 				Name:        "db-down",
 				Usage:       "Revert last migration or use -t to specify version to downgrade to",
-				UsageText:   "stork-tool db-down [options for db connection] [-t version]",
+				UsageText:   "stork-tool db-down [options for db connection] [-t version] [--dry-run]",
 				Description: ``,
-				Flags:       dbVerFlags,
+				Flags:       dbMigrateFlags,
 				Category:    "Database Migration",
 				Action: func(c *cli.Context) error {
 					runDBMigrate(c, "down", c.String("version"))
@@ -542,11 +1065,20 @@ func setupApp() *cli.App {
 					return nil
 				},
 			},
+			{
+				Name:        "db-dump",
+				Usage:       "Dump the schema version and the machines, apps, daemons and subnets as JSON with secrets redacted",
+				UsageText:   "stork-tool db-dump [options for db connection] [-o filename]",
+				Description: ``,
+				Flags:       dbDumpFlags,
+				Category:    "Database Migration",
+				Action:      runDBDump,
+			},
 			// CERTIFICATE MANAGEMENT
 			{
 				Name:        "cert-export",
 				Usage:       "Export certificate or other secret data",
-				UsageText:   "stork-tool cert-export [options for db connection] [-f object] [-o filename]",
+				UsageText:   "stork-tool cert-export [options for db connection] [-f object] [-o filename] [-e encoding]",
 				Description: ``,
 				Flags:       certExportFlags,
 				Category:    "Certificates Management",
@@ -561,6 +1093,73 @@ func setupApp() *cli.App {
 				Category:    "Certificates Management",
 				Action:      runCertImport,
 			},
+			{
+				Name:        "cert-check",
+				Usage:       "Report the server and agent certificates nearing expiry",
+				UsageText:   "stork-tool cert-check [options for db connection] [--days days]",
+				Description: ``,
+				Flags:       certCheckFlags,
+				Category:    "Certificates Management",
+				Action:      runCertCheck,
+			},
+			{
+				Name:        "cert-expiry",
+				Usage:       "Print the expiration dates of the CA and server certificates",
+				UsageText:   "stork-tool cert-expiry [options for db connection] [--warning-days days]",
+				Description: ``,
+				Flags:       certExpiryFlags,
+				Category:    "Certificates Management",
+				Action:      runCertExpiry,
+			},
+			{
+				Name:        "cert-rotate",
+				Usage:       "Regenerate the server key and certificate signed by the existing CA",
+				UsageText:   "stork-tool cert-rotate [options for db connection] [--validity-days days] [--bump-token] [--dry-run]",
+				Description: ``,
+				Flags:       certRotateFlags,
+				Category:    "Certificates Management",
+				Action:      runCertRotate,
+			},
+			// METRICS
+			{
+				Name:        "metrics-push",
+				Usage:       "Push the current metrics to the Prometheus PushGateway",
+				UsageText:   "stork-tool metrics-push [options for db connection] -g url [-j job]",
+				Description: ``,
+				Flags:       metricsPushFlags,
+				Category:    "Metrics",
+				Action:      runMetricsPush,
+			},
+			// HOST RESERVATIONS
+			{
+				Name:        "hosts-import",
+				Usage:       "Import host reservations from a CSV file",
+				UsageText:   "stork-tool hosts-import [options for db connection] -i filename [--strict]",
+				Description: ``,
+				Flags:       hostsImportFlags,
+				Category:    "Host Reservations",
+				Action:      runHostsImport,
+			},
+			// CONFIGURATION REVIEW
+			{
+				Name:        "checkers-apply-profile",
+				Usage:       "Apply the profile of the global and per-daemon config checker states",
+				UsageText:   "stork-tool checkers-apply-profile [options for db connection] -i filename",
+				Description: ``,
+				Flags:       checkersApplyProfileFlags,
+				Category:    "Configuration Review",
+				Action:      runCheckersApplyProfile,
+			},
+			// SUBNETS
+			{
+				Name:        "subnets-export",
+				Usage:       "Export the subnets of a Kea DHCP daemon with their pools and host reservations in the Kea configuration format",
+				UsageText:   "stork-tool subnets-export [options for db connection] -d daemon-id [-o filename]",
+				Description: ``,
+				Flags:       subnetsExportFlags,
+				Category:    "Subnets",
+				Action:      runSubnetsExport,
+			},
 		},
 	}
 
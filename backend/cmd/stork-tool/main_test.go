@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"strconv"
@@ -14,6 +17,8 @@ import (
 
 	"isc.org/stork"
 	"isc.org/stork/server/certs"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
 	"isc.org/stork/testutil"
 )
@@ -37,14 +42,23 @@ func getExpectedMainFragments() []string {
 		"--version",
 		"-h",
 		"--help",
+		"--output",
 		"cert-export",
 		"cert-import",
+		"cert-check",
+		"cert-expiry",
+		"cert-rotate",
 		"db-init",
 		"db-up",
 		"db-down",
 		"db-reset",
 		"db-version",
 		"db-set-version",
+		"db-dump",
+		"metrics-push",
+		"hosts-import",
+		"subnets-export",
+		"checkers-apply-profile",
 	}
 }
 
@@ -142,6 +156,74 @@ func TestRunDBMigrate(t *testing.T) {
 	main()
 }
 
+// Check if db-down in the dry-run mode prints the pending migrations
+// without modifying the database schema.
+func TestRunDBMigrateDryRun(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	version, err := dbops.CurrentVersion(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "db-down",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-t", strconv.FormatInt(version-2, 10),
+		"--dry-run",
+	}
+	stdout, _, err := testutil.CaptureOutput(main)
+	require.NoError(t, err)
+
+	require.Contains(t, string(stdout), fmt.Sprintf("Dry run: migrating down from version %d to %d", version, version-2))
+	require.Contains(t, string(stdout), "2 migrations would be run:")
+	require.Contains(t, string(stdout), fmt.Sprintf("1. revert migration %d", version))
+	require.Contains(t, string(stdout), fmt.Sprintf("2. revert migration %d", version-1))
+
+	current, err := dbops.CurrentVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, version, current)
+}
+
+// Check if db-down in the dry-run mode prints the pending migrations
+// as JSON in the JSON output mode.
+func TestRunDBMigrateDryRunJSON(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	version, err := dbops.CurrentVersion(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "--output", "json", "db-down",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-t", strconv.FormatInt(version-2, 10),
+		"--dry-run",
+	}
+	stdout, _, err := testutil.CaptureOutput(main)
+	require.NoError(t, err)
+
+	var plan dbMigrationPlanOutput
+	require.NoError(t, json.Unmarshal(stdout, &plan))
+	require.Equal(t, "down", plan.Direction)
+	require.Equal(t, version, plan.Current)
+	require.Equal(t, version-2, plan.Target)
+	require.Equal(t, []int64{version, version - 1}, plan.Pending)
+
+	current, err := dbops.CurrentVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, version, current)
+}
+
 // Check if cert-export can be invoked.
 func TestRunCertExport(t *testing.T) {
 	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -163,6 +245,336 @@ func TestRunCertExport(t *testing.T) {
 	main()
 }
 
+// Check if cert-export prints the metadata of the exported object as JSON
+// in the JSON output mode.
+func TestRunCertExportJSON(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, err := certs.GenerateServerToken(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "--output", "json", "cert-export",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-f", "srvtkn",
+	}
+	stdout, _, err := testutil.CaptureOutput(main)
+	require.NoError(t, err)
+
+	var metadata certs.ExportedSecretMetadata
+	require.NoError(t, json.Unmarshal(stdout, &metadata))
+	require.Equal(t, "srvtkn", metadata.Object)
+	require.Equal(t, certs.SecretTypeSrvToken, metadata.Name)
+	require.NotZero(t, metadata.Size)
+	require.NotEmpty(t, metadata.SHA256)
+}
+
+// Check if db-version prints the schema versions as JSON in the JSON
+// output mode.
+func TestRunDBVersionJSON(t *testing.T) {
+	_, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	os.Args = []string{
+		"stork-tool", "--output", "json", "db-version",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+	}
+	stdout, _, err := testutil.CaptureOutput(main)
+	require.NoError(t, err)
+
+	var version dbVersionOutput
+	require.NoError(t, json.Unmarshal(stdout, &version))
+	require.NotZero(t, version.Current)
+	require.Equal(t, version.Current, version.Available)
+	require.True(t, version.UpToDate)
+}
+
+// Check that an unsupported output format is rejected.
+func TestUnsupportedOutputFormat(t *testing.T) {
+	app := setupApp()
+	err := app.Run([]string{"stork-tool", "--output", "xml", "db-password-gen"})
+	require.ErrorContains(t, err, "unsupported output format")
+}
+
+// Check if cert-check can be invoked.
+func TestRunCertCheck(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, _, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "cert-check",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"--days", "60",
+	}
+	main()
+}
+
+// Check if cert-expiry can be invoked. The newly generated certificates
+// do not expire within the warning period, so the command succeeds.
+func TestRunCertExpiry(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, _, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "cert-expiry",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"--warning-days", "30",
+	}
+	main()
+}
+
+// Check that cert-check accepts the number of days from the environment
+// variable and doesn't fail when the certificates expire within this
+// period.
+func TestRunCertCheckDaysFromEnv(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, _, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+	t.Setenv("STORK_TOOL_CERT_DAYS", "100000")
+
+	// Act
+	err = setupApp().Run([]string{
+		"stork-tool", "cert-check",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+// Check that cert-expiry returns an error when the certificates expire
+// within the warning period.
+func TestRunCertExpiryWithinWarningDays(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, _, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+
+	// Act
+	err = setupApp().Run([]string{
+		"stork-tool", "cert-expiry",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"--warning-days", "100000",
+	})
+
+	// Assert
+	require.ErrorContains(t, err, "2 certificates expire within 100000 days")
+}
+
+// Check that cert-expiry returns an error when the certificates are
+// missing in the database.
+func TestRunCertExpiryMissingCerts(t *testing.T) {
+	// Arrange
+	_, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	// Act
+	err := setupApp().Run([]string{
+		"stork-tool", "cert-expiry",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+	})
+
+	// Assert
+	require.ErrorContains(t, err, "is missing in the database")
+}
+
+// Check if cert-rotate regenerates the server cert and prints the warning
+// that the agents need to re-register.
+func TestRunCertRotate(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, serverCertPEM, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "cert-rotate",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"--validity-days", "90",
+	}
+
+	// Act
+	stdout, _, err := testutil.CaptureOutput(main)
+
+	// Assert
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "has been regenerated")
+	require.Contains(t, string(stdout), "the agents will need to re-register")
+	secret, err := dbmodel.GetSecret(db, dbmodel.SecretServerCert)
+	require.NoError(t, err)
+	require.NotEqualValues(t, serverCertPEM, secret)
+}
+
+// Check if cert-rotate in the dry-run mode doesn't modify the server cert.
+func TestRunCertRotateDryRun(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	_, serverCertPEM, _, err := certs.SetupServerCerts(db)
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "cert-rotate",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"--bump-token",
+		"--dry-run",
+	}
+
+	// Act
+	stdout, _, err := testutil.CaptureOutput(main)
+
+	// Assert
+	require.NoError(t, err)
+	require.Contains(t, string(stdout), "Dry run")
+	require.Contains(t, string(stdout), "would be regenerated")
+	secret, err := dbmodel.GetSecret(db, dbmodel.SecretServerCert)
+	require.NoError(t, err)
+	require.EqualValues(t, serverCertPEM, secret)
+}
+
+// Check if db-dump writes the database dump to a file.
+func TestRunDBDump(t *testing.T) {
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+
+	_, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	dumpFile, err := sb.Join("dump.json")
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "db-dump",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-o", dumpFile,
+	}
+	main()
+
+	content, err := os.ReadFile(dumpFile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"schema_version"`)
+	require.Contains(t, string(content), `"machine"`)
+}
+
+// Check if subnets-export writes the subnets of the daemon to a file.
+func TestRunSubnetsExport(t *testing.T) {
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := dbmodel.AddMachine(db, machine)
+	require.NoError(t, err)
+
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	_, err = dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	subnet := &dbmodel.Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	err = dbmodel.AddSubnet(db, subnet)
+	require.NoError(t, err)
+	err = dbmodel.AddDaemonToSubnet(db, subnet, app.Daemons[0])
+	require.NoError(t, err)
+
+	exportFile, err := sb.Join("subnets.json")
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "subnets-export",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-d", strconv.FormatInt(app.Daemons[0].ID, 10),
+		"-o", exportFile,
+	}
+	main()
+
+	content, err := os.ReadFile(exportFile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"subnet4"`)
+	require.Contains(t, string(content), `"192.0.2.0/24"`)
+}
+
 // Check if cert-import can be invoked.
 func TestRunCertImport(t *testing.T) {
 	sb := testutil.NewSandbox()
@@ -193,6 +605,190 @@ func TestRunCertImport(t *testing.T) {
 	main()
 }
 
+// Check if metrics-push pushes the metrics to the PushGateway.
+func TestRunMetricsPush(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	err := dbmodel.InitializeSettings(db, 0)
+	require.NoError(t, err)
+
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	os.Args = []string{
+		"stork-tool", "metrics-push",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-g", server.URL,
+	}
+	main()
+
+	require.Contains(t, body, "storkserver_auth_authorized_machine_total")
+	require.Contains(t, body, "storkserver_auth_unauthorized_machine_total")
+	require.Contains(t, body, "storkserver_auth_unreachable_machine_total")
+}
+
+// Check if hosts-import imports the host reservations from the CSV file.
+func TestRunHostsImport(t *testing.T) {
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := dbmodel.AddMachine(db, machine)
+	require.NoError(t, err)
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	_, err = dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+	subnet := &dbmodel.Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	err = dbmodel.AddSubnet(db, subnet)
+	require.NoError(t, err)
+	err = dbmodel.AddDaemonToSubnet(db, subnet, app.Daemons[0])
+	require.NoError(t, err)
+
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+	path, err := sb.Write("hosts.csv", strings.Join([]string{
+		"subnet-prefix,identifier-type,identifier,ip-address,hostname",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:06,192.0.2.10,foo.example.org",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:07,192.0.3.10,bar.example.org",
+	}, "\n"))
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "hosts-import",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-i", path,
+	}
+	main()
+
+	// The invalid row should be skipped.
+	hosts, err := dbmodel.GetHostsBySubnetID(db, subnet.ID)
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, "foo.example.org", hosts[0].Hostname)
+}
+
+// Check if checkers-apply-profile applies the checker states from the
+// profile file and prints the changes as JSON.
+func TestRunCheckersApplyProfile(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	require.NoError(t, dbmodel.AddMachine(db, machine))
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	daemons, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+	path, err := sb.Write("profile.json", fmt.Sprintf(`{
+		"global": { "stat_cmds_presence": "disabled" },
+		"daemons": { "%d": { "stat_cmds_presence": "enabled" } }
+	}`, daemons[0].ID))
+	require.NoError(t, err)
+
+	os.Args = []string{
+		"stork-tool", "--output", "json", "checkers-apply-profile",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-i", path,
+	}
+
+	// Act
+	stdout, _, err := testutil.CaptureOutput(main)
+
+	// Assert
+	require.NoError(t, err)
+	var output checkerStateChangesOutput
+	require.NoError(t, json.Unmarshal(stdout, &output))
+	require.Equal(t, 2, output.Total)
+	require.Len(t, output.Items, 2)
+	require.Equal(t, "stat_cmds_presence", output.Items[0].CheckerName)
+	require.Zero(t, output.Items[0].DaemonID)
+	require.EqualValues(t, "disabled", output.Items[0].NewState)
+	require.Equal(t, daemons[0].ID, output.Items[1].DaemonID)
+	require.EqualValues(t, "enabled", output.Items[1].NewState)
+
+	preferences, err := dbmodel.GetAllCheckerPreferences(db)
+	require.NoError(t, err)
+	require.Len(t, preferences, 2)
+}
+
+// Check if checkers-apply-profile rejects the profile with unknown checkers.
+func TestRunCheckersApplyProfileUnknownCheckers(t *testing.T) {
+	// Arrange
+	db, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dbOpts := gOpts.BaseDatabaseSettings
+
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+	path, err := sb.Write("profile.json", `{
+		"global": { "stat_cmds_presence": "disabled", "foo": "disabled" }
+	}`)
+	require.NoError(t, err)
+
+	app := setupApp()
+
+	// Act
+	err = app.Run([]string{
+		"stork-tool", "checkers-apply-profile",
+		"--db-name", dbOpts.DBName,
+		"--db-user", dbOpts.User,
+		"--db-password", dbOpts.Password,
+		"--db-host", dbOpts.Host,
+		"--db-port", strconv.Itoa(dbOpts.Port),
+		"-i", path,
+	})
+
+	// Assert
+	require.ErrorContains(t, err, "unknown config checkers in the profile: foo")
+	preferences, err := dbmodel.GetAllCheckerPreferences(db)
+	require.NoError(t, err)
+	require.Empty(t, preferences)
+}
+
 // Check if db-create command can be invoked.
 func TestRunDBCreate(t *testing.T) {
 	_, gOpts, teardown := dbtest.SetupDatabaseTestCase(t)
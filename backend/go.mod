@@ -9,6 +9,7 @@ require (
 	github.com/apparentlymart/go-cidr v1.0.1
 	github.com/asaskevich/govalidator v0.0.0-20190424111038-f61b66f89f4a
 	github.com/brianvoe/gofakeit v3.18.0+incompatible
+	github.com/go-jose/go-jose/v3 v3.0.1
 	github.com/go-openapi/errors v0.19.2
 	github.com/go-openapi/loads v0.19.3
 	github.com/go-openapi/runtime v0.19.6
@@ -122,6 +122,15 @@ func GenCAKeyCert(serialNumber int64) (*ecdsa.PrivateKey, []byte, *x509.Certific
 // server by certs module in setupServerKeyAndCert to generate server
 // key and cert using CA key and cert.
 func GenKeyCert(name string, dnsNames []string, ipAddresses []net.IP, serialNumber int64, parentCert *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	return GenKeyCertWithExpiry(name, dnsNames, ipAddresses, serialNumber, time.Now().AddDate(CertValidityYears, 0, 0), parentCert, parentKey)
+}
+
+// Generate a key and a certificate valid until the specified time for
+// provided DNS names and IP addresses, using provided serial number and
+// a CA key and a CA cert. Return them in PEM format. This function is
+// public and is used in server by certs module in RotateServerCert to
+// regenerate server key and cert with the custom validity period.
+func GenKeyCertWithExpiry(name string, dnsNames []string, ipAddresses []net.IP, serialNumber int64, notAfter time.Time, parentCert *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, []byte, error) {
 	// check args
 	if len(dnsNames) == 0 {
 		return nil, nil, errors.New("DNS names cannot be empty")
@@ -149,7 +158,7 @@ func GenKeyCert(name string, dnsNames []string, ipAddresses []net.IP, serialNumb
 			CommonName:         dnsNames[0],
 		},
 		NotBefore:      time.Now(),
-		NotAfter:       time.Now().AddDate(CertValidityYears, 0, 0), // 30 years of cert validity
+		NotAfter:       notAfter,
 		IsCA:           false,
 		MaxPathLenZero: true,
 		IPAddresses:    ipAddresses,
@@ -103,6 +103,26 @@ func TestGenKeyCert(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Test if GenKeyCertWithExpiry generates the cert valid until the
+// specified time.
+func TestGenKeyCertWithExpiry(t *testing.T) {
+	// Arrange
+	parentKey, _, parentCert, _, err := GenCAKeyCert(1)
+	require.NoError(t, err)
+	notAfter := time.Now().AddDate(0, 0, 90).UTC().Truncate(time.Second)
+
+	// Act
+	certPEM, privKeyPEM, err := GenKeyCertWithExpiry("name", []string{"name"}, []net.IP{net.ParseIP("192.0.2.1")}, 2, notAfter, parentCert, parentKey)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotEmpty(t, privKeyPEM)
+	cert, err := ParseCert(certPEM)
+	require.NoError(t, err)
+	require.Equal(t, notAfter, cert.NotAfter.UTC())
+	require.NoError(t, cert.CheckSignatureFrom(parentCert))
+}
+
 // Test if GenCSRUsingKey checks arguments passed to it and if
 // returned CSR looks reasonably.
 func TestGenCSRUsingKey(t *testing.T) {
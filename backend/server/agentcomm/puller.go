@@ -28,7 +28,9 @@ type PeriodicPuller struct {
 // to periodically trigger an action. This action is supplied as a function instance.
 // This function is executed within a goroutine periodically according to the timer
 // interval available in the database. The intervalSettingName is a name of this
-// setting in the database. The pullerName is used for logging purposes.
+// setting in the database. The pullerName is used for logging purposes. The
+// function is not executed while all pullers are paused (see
+// dbmodel.PauseAllPullers).
 func NewPeriodicPuller(db *dbops.PgDB, agents ConnectedAgents, pullerName, intervalSettingName string, pullFunc func() error) (*PeriodicPuller, error) {
 	var lastInvokedAt atomic.Value
 	var lastFinishedAt atomic.Value
@@ -38,8 +40,17 @@ func NewPeriodicPuller(db *dbops.PgDB, agents ConnectedAgents, pullerName, inter
 	periodicExecutor, err := storkutil.NewPeriodicExecutor(
 		pullerName,
 		func() error {
+			// Skip the execution when the pullers have been paused, e.g.,
+			// for the time of the Kea maintenance.
+			paused, _, err := dbmodel.GetPullersPauseState(db)
+			if err != nil {
+				return errors.WithMessage(err, "Problem checking if the pullers are paused")
+			}
+			if paused {
+				return nil
+			}
 			lastInvokedAt.Store(time.Now())
-			err := pullFunc()
+			err = pullFunc()
 			lastFinishedAt.Store(time.Now())
 			return err
 		},
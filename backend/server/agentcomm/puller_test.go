@@ -141,3 +141,34 @@ func TestPullerSavesLastInvokedTime(t *testing.T) {
 	require.LessOrEqual(t, startTime, *pullTime)
 	require.LessOrEqual(t, invokedTime, *pullTime)
 }
+
+// Test that the puller doesn't execute its function while all pullers
+// are paused and that it resumes the execution when they are resumed.
+func TestPullerPausedForMaintenance(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+	_ = dbmodel.SetSettingInt(db, "kea_hosts_puller_interval", 1)
+	_ = dbmodel.PauseAllPullers(db, time.Time{})
+
+	var callCount int64
+	puller, _ := NewPeriodicPuller(db, nil, "test puller", "kea_hosts_puller_interval",
+		func() error {
+			atomic.AddInt64(&callCount, 1)
+			return nil
+		})
+	defer puller.Shutdown()
+
+	// Act & Assert
+	require.Never(t, func() bool {
+		return atomic.LoadInt64(&callCount) > 0
+	}, 3*time.Second, 500*time.Millisecond)
+	require.Zero(t, puller.GetLastInvokedAt())
+
+	_ = dbmodel.ResumeAllPullers(db)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&callCount) > 0
+	}, 5*time.Second, 500*time.Millisecond)
+}
@@ -147,7 +147,7 @@ func getStateFromCA(ctx context.Context, agents agentcomm.ConnectedAgents, dbApp
 		}
 	}
 
-	sockets := dmn.KeaDaemon.Config.GetControlSockets()
+	sockets, _ := dmn.KeaDaemon.Config.GetControlSockets()
 	if sockets.Dhcp4 != nil {
 		allDaemons = append(allDaemons, dhcp4)
 		dhcpDaemons = append(dhcpDaemons, dhcp4)
@@ -507,6 +507,49 @@ func deleteDaemonAssociations(tx *pg.Tx, daemon *dbmodel.Daemon) error {
 	return nil
 }
 
+// Removes associations between the daemon, global hosts and services. The
+// associations with the subnets and their hosts are preserved, so they can
+// be updated incrementally.
+func deleteDaemonGlobalAssociations(tx *pg.Tx, daemon *dbmodel.Daemon) error {
+	_, err := dbmodel.DeleteDaemonFromSubnetHosts(tx, daemon.ID, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = dbmodel.DeleteDaemonFromServices(tx, daemon.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Compares the subnets in the daemon's configuration stored in the database
+// with the subnets in its new configuration. It returns nil when the daemon
+// is new or the comparison is not possible. In that case, all subnets must
+// be committed.
+func getDaemonSubnetsDiff(tx *pg.Tx, daemon *dbmodel.Daemon) (*keaconfig.SubnetsDiff, error) {
+	if daemon.ID == 0 || daemon.KeaDaemon == nil || daemon.KeaDaemon.Config == nil {
+		return nil, nil
+	}
+	if daemon.Name != dbmodel.DaemonNameDHCPv4 && daemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, nil
+	}
+	oldDaemon, err := dbmodel.GetDaemonByID(tx, daemon.ID)
+	if err != nil {
+		return nil, err
+	}
+	if oldDaemon == nil || oldDaemon.KeaDaemon == nil || oldDaemon.KeaDaemon.Config == nil {
+		return nil, nil
+	}
+	diff, err := keaconfig.DiffSubnets(oldDaemon.KeaDaemon.Config.Map, daemon.KeaDaemon.Config.Map)
+	if err != nil {
+		log.WithError(err).Warnf("Unable to compare subnets in the configurations of Kea daemon %d; committing all subnets", daemon.ID)
+		return nil, nil
+	}
+	return diff, nil
+}
+
 // Deletes empty shared networks and orphaned subnets and hosts.
 func deleteEmptyAndOrphanedObjects(tx *pg.Tx) error {
 	// Removed the hosts that no longer belong to any app.
@@ -590,6 +633,9 @@ func CommitAppIntoDB(db *dbops.PgDB, app *dbmodel.App, eventCenter eventcenter.E
 		networks := make(map[string][]dbmodel.SharedNetwork)
 		subnets := make(map[string][]dbmodel.Subnet)
 		globalHosts := make(map[string][]dbmodel.Host)
+		// Differences between the old and new subnets of the daemons. If the
+		// differences are known, only the modified subnets are committed.
+		subnetsDiffs := make(map[string]*keaconfig.SubnetsDiff)
 
 		for _, daemon := range app.Daemons {
 			if state != nil && state.SameConfigDaemons != nil {
@@ -602,8 +648,19 @@ func CommitAppIntoDB(db *dbops.PgDB, app *dbmodel.App, eventCenter eventcenter.E
 				}
 			}
 
-			// Remove daemon associations with hosts and subnets.
-			err = deleteDaemonAssociations(tx, daemon)
+			subnetsDiffs[daemon.Name], err = getDaemonSubnetsDiff(tx, daemon)
+			if err != nil {
+				return err
+			}
+
+			// Remove daemon associations with hosts and subnets. The associations
+			// with the subnets are updated incrementally if the differences
+			// between the configurations are known.
+			if subnetsDiffs[daemon.Name] != nil {
+				err = deleteDaemonGlobalAssociations(tx, daemon)
+			} else {
+				err = deleteDaemonAssociations(tx, daemon)
+			}
 			if err != nil {
 				return err
 			}
@@ -656,7 +713,12 @@ func CommitAppIntoDB(db *dbops.PgDB, app *dbmodel.App, eventCenter eventcenter.E
 		for _, daemon := range app.Daemons {
 			// For the given daemon, iterate over the networks and subnets and update their
 			// global instances accordingly in the database.
-			addedSubnets, err := dbmodel.CommitNetworksIntoDB(tx, networks[daemon.Name], subnets[daemon.Name], daemon)
+			var addedSubnets []*dbmodel.Subnet
+			if diff := subnetsDiffs[daemon.Name]; diff != nil {
+				addedSubnets, err = dbmodel.CommitChangedNetworksIntoDB(tx, networks[daemon.Name], subnets[daemon.Name], daemon, diff)
+			} else {
+				addedSubnets, err = dbmodel.CommitNetworksIntoDB(tx, networks[daemon.Name], subnets[daemon.Name], daemon)
+			}
 			if err != nil {
 				return err
 			}
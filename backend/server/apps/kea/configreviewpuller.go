@@ -0,0 +1,124 @@
+package kea
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"isc.org/stork/server/agentcomm"
+	"isc.org/stork/server/configreview"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Name of the setting holding the global interval of the periodic
+// configuration reviews.
+const configReviewPullerIntervalSettingName = "config_review_puller_interval"
+
+// Instance of the puller that periodically reviews the configurations of
+// the Kea DHCP daemons. The daemons are reviewed according to the global
+// interval unless they have the interval overrides. The overrides allow
+// for reviewing the stable daemons less often.
+type ConfigReviewPuller struct {
+	*agentcomm.PeriodicPuller
+	ReviewDispatcher configreview.Dispatcher
+}
+
+// Create an instance of the puller that periodically schedules the
+// configuration reviews of the Kea DHCP daemons.
+func NewConfigReviewPuller(db *dbops.PgDB, reviewDispatcher configreview.Dispatcher) (*ConfigReviewPuller, error) {
+	reviewPuller := &ConfigReviewPuller{
+		ReviewDispatcher: reviewDispatcher,
+	}
+	periodicPuller, err := agentcomm.NewPeriodicPuller(db, nil, "Kea Config Review puller",
+		configReviewPullerIntervalSettingName, reviewPuller.pull)
+	if err != nil {
+		return nil, err
+	}
+	reviewPuller.PeriodicPuller = periodicPuller
+	return reviewPuller, nil
+}
+
+// Stops the timer triggering the configuration reviews.
+func (puller *ConfigReviewPuller) Shutdown() {
+	puller.PeriodicPuller.Shutdown()
+}
+
+// Checks if the daemon's configuration review is due. The interval is the
+// effective review interval of the daemon and the pullerInterval is the
+// global interval at which the puller checks the daemons. The reviews can
+// only be scheduled when the puller runs, and the last review completes
+// slightly after the puller run. Therefore, the review is considered due
+// when the remaining time is shorter than half of the puller interval.
+// The daemons that have never been reviewed are always due. The zero
+// interval disables the periodic reviews.
+func isConfigReviewDue(lastReviewAt, now time.Time, interval, pullerInterval int64) bool {
+	if interval <= 0 {
+		return false
+	}
+	if lastReviewAt.IsZero() {
+		return true
+	}
+	remaining := time.Duration(interval)*time.Second - now.Sub(lastReviewAt)
+	return remaining <= time.Duration(pullerInterval)*time.Second/2
+}
+
+// Schedules the configuration reviews of the Kea DHCP daemons which reviews
+// are due. The effective interval of the daemon is its interval override
+// or the global interval if the daemon has no override.
+func (puller *ConfigReviewPuller) pull() error {
+	pullerInterval, err := dbmodel.GetSettingInt(puller.DB, configReviewPullerIntervalSettingName)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := dbmodel.GetConfigReviewIntervals(puller.DB)
+	if err != nil {
+		return err
+	}
+
+	daemons, err := dbmodel.GetKeaDHCPDaemons(puller.DB)
+	if err != nil {
+		return err
+	}
+
+	var (
+		scheduledCount int
+		skippedCount   int
+	)
+
+	now := time.Now().UTC()
+	for i := range daemons {
+		daemon := &daemons[i]
+		if daemon.KeaDaemon == nil || daemon.KeaDaemon.Config == nil {
+			continue
+		}
+
+		interval := pullerInterval
+		if override, ok := overrides[daemon.ID]; ok {
+			interval = override
+		}
+
+		var lastReviewAt time.Time
+		if daemon.ConfigReview != nil {
+			lastReviewAt = daemon.ConfigReview.CreatedAt
+		}
+
+		if !isConfigReviewDue(lastReviewAt, now, interval, pullerInterval) {
+			skippedCount++
+			continue
+		}
+
+		if puller.ReviewDispatcher.BeginReview(daemon, configreview.ManualRun, nil) {
+			scheduledCount++
+		} else {
+			skippedCount++
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"scheduled_count": scheduledCount,
+		"skipped_count":   skippedCount,
+	}).Info("Completed scheduling periodic config reviews of Kea daemons")
+
+	return nil
+}
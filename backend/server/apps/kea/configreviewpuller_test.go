@@ -0,0 +1,169 @@
+package kea
+
+import (
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+	"isc.org/stork/server/configreview"
+	dbmodel "isc.org/stork/server/database/model"
+	dbtest "isc.org/stork/server/database/test"
+	storktest "isc.org/stork/server/test/dbmodel"
+)
+
+// Test that the review is due when the interval has elapsed since the
+// last review, taking into account the puller interval.
+func TestIsConfigReviewDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// The daemon has never been reviewed.
+	require.True(t, isConfigReviewDue(time.Time{}, now, 3600, 3600))
+	// The periodic reviews are disabled.
+	require.False(t, isConfigReviewDue(time.Time{}, now, 0, 3600))
+	// The interval has elapsed.
+	require.True(t, isConfigReviewDue(now.Add(-2*time.Hour), now, 3600, 60))
+	// The interval has not elapsed.
+	require.False(t, isConfigReviewDue(now.Add(-30*time.Minute), now, 3600, 60))
+	// The last review completed slightly after the previous puller run.
+	require.True(t, isConfigReviewDue(now.Add(-time.Hour).Add(5*time.Second), now, 3600, 3600))
+	// The puller runs less frequently than the daemon's interval.
+	require.True(t, isConfigReviewDue(now.Add(-time.Hour), now, 60, 3600))
+}
+
+// Test that the daemon with a longer interval override is reviewed less
+// frequently than the daemon using the global interval.
+func TestIsConfigReviewDueLongerOverride(t *testing.T) {
+	const pullerInterval = 3600
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	intervals := []int64{pullerInterval, 6 * pullerInterval}
+	lastReviews := make([]time.Time, len(intervals))
+	reviewCounts := make([]int, len(intervals))
+
+	// Simulate the puller runs for one day. The reviews complete a couple
+	// of seconds after the puller runs.
+	for i := 0; i < 24; i++ {
+		now := start.Add(time.Duration(i) * pullerInterval * time.Second)
+		for j, interval := range intervals {
+			if isConfigReviewDue(lastReviews[j], now, interval, pullerInterval) {
+				lastReviews[j] = now.Add(2 * time.Second)
+				reviewCounts[j]++
+			}
+		}
+	}
+
+	require.Equal(t, 24, reviewCounts[0])
+	require.Equal(t, 4, reviewCounts[1])
+}
+
+// Test that the config review puller schedules the reviews of the daemons
+// according to the global interval and the per-daemon overrides.
+func TestConfigReviewPullerPull(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+	require.NoError(t, dbmodel.SetSettingInt(db, "config_review_puller_interval", 3600))
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	require.NoError(t, dbmodel.AddMachine(db, m))
+
+	app := &dbmodel.App{
+		MachineID: m.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true),
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	for _, daemon := range app.Daemons {
+		daemon.KeaDaemon.Config = getTestConfigWithIPv4Subnets(t, false)
+	}
+	daemons, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+	require.Len(t, daemons, 3)
+
+	// All daemons have been reviewed two hours ago.
+	for _, daemon := range daemons {
+		require.NoError(t, dbmodel.AddConfigReview(db, &dbmodel.ConfigReview{
+			CreatedAt: time.Now().UTC().Add(-2 * time.Hour),
+			DaemonID:  daemon.ID,
+		}))
+	}
+	// The second daemon is stable and should be reviewed once a day.
+	require.NoError(t, dbmodel.SetConfigReviewInterval(db, daemons[1].ID, 86400))
+	// The periodic reviews of the third daemon are disabled.
+	require.NoError(t, dbmodel.SetConfigReviewInterval(db, daemons[2].ID, 0))
+
+	fd := &storktest.FakeDispatcher{}
+	puller, err := NewConfigReviewPuller(db, fd)
+	require.NoError(t, err)
+	defer puller.Shutdown()
+
+	// Act
+	err = puller.pull()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, fd.CallLog, 1)
+	require.Equal(t, "BeginReview", fd.CallLog[0].CallName)
+	require.Equal(t, daemons[0].ID, fd.CallLog[0].DaemonID)
+	require.Equal(t, configreview.ManualRun, fd.CallLog[0].Trigger)
+
+	// Arrange
+	require.NoError(t, dbmodel.SetConfigReviewInterval(db, daemons[1].ID, 3600))
+	fd.CallLog = nil
+
+	// Act
+	err = puller.pull()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, fd.CallLog, 2)
+	require.Equal(t, daemons[0].ID, fd.CallLog[0].DaemonID)
+	require.Equal(t, daemons[1].ID, fd.CallLog[1].DaemonID)
+}
+
+// Test that the config review puller schedules no reviews when the
+// global interval is zero and the daemons have no overrides.
+func TestConfigReviewPullerPullDisabled(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	require.NoError(t, dbmodel.AddMachine(db, m))
+
+	app := &dbmodel.App{
+		MachineID: m.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	app.Daemons[0].KeaDaemon.Config = getTestConfigWithIPv4Subnets(t, false)
+	_, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	fd := &storktest.FakeDispatcher{}
+	puller, err := NewConfigReviewPuller(db, fd)
+	require.NoError(t, err)
+	defer puller.Shutdown()
+
+	// Act
+	err = puller.pull()
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, fd.CallLog)
+}
@@ -10,12 +10,16 @@ import (
 	log "github.com/sirupsen/logrus"
 	keactrl "isc.org/stork/appctrl/kea"
 	"isc.org/stork/server/agentcomm"
+	dbops "isc.org/stork/server/database"
 	dbmodel "isc.org/stork/server/database/model"
 )
 
 type StatsPuller struct {
 	*agentcomm.PeriodicPuller
 	*RpsWorker
+	// ID of the last app processed in the previous cycle when the apps
+	// are processed in batches.
+	lastBatchAppID int64
 }
 
 // Create a StatsPuller object that in background pulls Kea stats about leases.
@@ -44,8 +48,35 @@ func (statsPuller *StatsPuller) Shutdown() {
 	statsPuller.PeriodicPuller.Shutdown()
 }
 
+// Selects the batch of the apps to be processed in the current cycle.
+// The apps must be sorted by ID. The batch begins with the first app
+// having the ID greater than lastAppID and wraps around to the beginning
+// of the list, so the consecutive cycles cover all apps in a round-robin
+// fashion. All apps are returned when the batch size is not positive or
+// it is not lower than the number of apps.
+func selectAppsBatch(apps []dbmodel.App, lastAppID int64, batchSize int) []dbmodel.App {
+	if batchSize <= 0 || batchSize >= len(apps) {
+		return apps
+	}
+	start := 0
+	for i := range apps {
+		if apps[i].ID > lastAppID {
+			start = i
+			break
+		}
+	}
+	batch := make([]dbmodel.App, 0, batchSize)
+	for i := 0; i < batchSize; i++ {
+		batch = append(batch, apps[(start+i)%len(apps)])
+	}
+	return batch
+}
+
 // Pull stats periodically for all Kea apps which Stork is monitoring. The function returns
-// last encountered error.
+// last encountered error. If the kea_stats_puller_batch_size setting is positive, the
+// stats are pulled from at most that many apps in a single cycle, and the utilization
+// is updated only in the subnets served by these apps. The following cycles process
+// the next batches of the apps.
 func (statsPuller *StatsPuller) pullStats() error {
 	// get list of all kea apps from database
 	dbApps, err := dbmodel.GetAppsByType(statsPuller.DB, dbmodel.AppTypeKea)
@@ -53,6 +84,25 @@ func (statsPuller *StatsPuller) pullStats() error {
 		return err
 	}
 
+	batchSize, err := dbmodel.GetSettingInt(statsPuller.DB, "kea_stats_puller_batch_size")
+	if err != nil {
+		return err
+	}
+	allAppsCnt := len(dbApps)
+	dbApps = selectAppsBatch(dbApps, statsPuller.lastBatchAppID, int(batchSize))
+	// The IDs of the daemons belonging to the batch. It is nil when all
+	// apps are processed.
+	var batchDaemonIDs map[int64]bool
+	if len(dbApps) < allAppsCnt {
+		statsPuller.lastBatchAppID = dbApps[len(dbApps)-1].ID
+		batchDaemonIDs = make(map[int64]bool)
+		for _, dbApp := range dbApps {
+			for _, daemon := range dbApp.Daemons {
+				batchDaemonIDs[daemon.ID] = true
+			}
+		}
+	}
+
 	// get lease stats from each kea app
 	var lastErr error
 	appsOkCnt := 0
@@ -67,6 +117,9 @@ func (statsPuller *StatsPuller) pullStats() error {
 		}
 	}
 	log.Printf("Completed pulling lease stats from Kea apps: %d/%d succeeded", appsOkCnt, len(dbApps))
+	if batchDaemonIDs != nil {
+		log.Printf("Pulled lease stats from the batch of %d out of %d Kea apps", len(dbApps), allAppsCnt)
+	}
 
 	// estimate addresses utilization for subnets
 	subnets, err := dbmodel.GetSubnetsWithLocalSubnets(statsPuller.DB)
@@ -122,6 +175,11 @@ func (statsPuller *StatsPuller) pullStats() error {
 	// 2) estimate global stats
 	for _, sn := range subnets {
 		su := counter.add(sn)
+		if batchDaemonIDs != nil && !isSubnetServedByDaemons(sn, batchDaemonIDs) {
+			// The statistics of this subnet were not pulled in this
+			// cycle, so its utilization is unchanged.
+			continue
+		}
 		err = sn.UpdateStatistics(
 			statsPuller.DB,
 			su,
@@ -170,6 +228,57 @@ func (statsPuller *StatsPuller) pullStats() error {
 	return lastErr
 }
 
+// Checks if any of the local subnets is served by the specified daemons.
+func isSubnetServedByDaemons(subnet *dbmodel.Subnet, daemonIDs map[int64]bool) bool {
+	for _, ls := range subnet.LocalSubnets {
+		if daemonIDs[ls.DaemonID] {
+			return true
+		}
+	}
+	return false
+}
+
+// Utilization and statistics of a single subnet computed on demand.
+type SubnetUtilization struct {
+	AddressUtilization         float64
+	DelegatedPrefixUtilization float64
+	Statistics                 dbmodel.SubnetStats
+}
+
+// Computes the utilization of a single subnet from the latest statistics of
+// its local subnets. It follows the same rules as the statistics puller, i.e.,
+// it includes the out-of-pool reservations in the total counters and excludes
+// the statistics returned by the passive HA servers. The subnet must be
+// fetched with its local subnets. The result is not stored in the database.
+func CalculateSubnetUtilization(dbi dbops.DBI, subnet *dbmodel.Subnet) (*SubnetUtilization, error) {
+	counter := newStatisticsCounter()
+
+	outOfPoolCounters, err := dbmodel.CountOutOfPoolAddressReservations(dbi)
+	if err != nil {
+		return nil, err
+	}
+	counter.setOutOfPoolAddresses(outOfPoolCounters)
+
+	outOfPoolCounters, err = dbmodel.CountOutOfPoolPrefixReservations(dbi)
+	if err != nil {
+		return nil, err
+	}
+	counter.setOutOfPoolPrefixes(outOfPoolCounters)
+
+	excludedDaemons, err := dbmodel.GetPassiveHADaemonIDs(dbi)
+	if err != nil {
+		return nil, err
+	}
+	counter.setExcludedDaemons(excludedDaemons)
+
+	stats := counter.add(subnet)
+	return &SubnetUtilization{
+		AddressUtilization:         stats.GetAddressUtilization(),
+		DelegatedPrefixUtilization: stats.GetDelegatedPrefixUtilization(),
+		Statistics:                 stats.GetStatistics(),
+	}, nil
+}
+
 // Part of response for stat-lease4-get and stat-lease6-get commands.
 type ResultSetInStatLeaseGet struct {
 	Columns []string
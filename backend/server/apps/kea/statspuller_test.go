@@ -525,6 +525,131 @@ func TestStatsPullerPullStatsKea18Format(t *testing.T) {
 	checkStatsPullerPullStats(t, "1.8")
 }
 
+// Test that the apps are selected for pulling the statistics in batches
+// which cover all apps in a round-robin fashion.
+func TestSelectAppsBatch(t *testing.T) {
+	// Arrange
+	apps := []dbmodel.App{{ID: 1}, {ID: 2}, {ID: 4}, {ID: 5}, {ID: 7}}
+	getIDs := func(batch []dbmodel.App) (ids []int64) {
+		for _, app := range batch {
+			ids = append(ids, app.ID)
+		}
+		return
+	}
+
+	// Act
+	var batches [][]int64
+	lastAppID := int64(0)
+	for i := 0; i < 4; i++ {
+		batch := selectAppsBatch(apps, lastAppID, 2)
+		lastAppID = batch[len(batch)-1].ID
+		batches = append(batches, getIDs(batch))
+	}
+
+	// Assert
+	require.Equal(t, [][]int64{{1, 2}, {4, 5}, {7, 1}, {2, 4}}, batches)
+}
+
+// Test that the batch begins with the next app when the last processed
+// app was deleted, and that all apps are selected when batching is
+// disabled or the batch covers all apps.
+func TestSelectAppsBatchEdgeCases(t *testing.T) {
+	// Arrange
+	apps := []dbmodel.App{{ID: 1}, {ID: 2}, {ID: 4}}
+
+	// Act & Assert
+	require.Len(t, selectAppsBatch(apps, 0, 0), 3)
+	require.Len(t, selectAppsBatch(apps, 2, -1), 3)
+	require.Len(t, selectAppsBatch(apps, 2, 3), 3)
+	require.Len(t, selectAppsBatch(apps, 2, 10), 3)
+	require.Empty(t, selectAppsBatch([]dbmodel.App{}, 0, 2))
+
+	batch := selectAppsBatch(apps, 3, 1)
+	require.Len(t, batch, 1)
+	require.EqualValues(t, 4, batch[0].ID)
+
+	batch = selectAppsBatch(apps, 4, 2)
+	require.Len(t, batch, 2)
+	require.EqualValues(t, 1, batch[0].ID)
+	require.EqualValues(t, 2, batch[1].ID)
+}
+
+// Test that the subnet is recognized as served by the daemons when any
+// of its local subnets is associated with them.
+func TestIsSubnetServedByDaemons(t *testing.T) {
+	subnet := &dbmodel.Subnet{
+		LocalSubnets: []*dbmodel.LocalSubnet{
+			{DaemonID: 1},
+			{DaemonID: 3},
+		},
+	}
+	require.True(t, isSubnetServedByDaemons(subnet, map[int64]bool{3: true}))
+	require.False(t, isSubnetServedByDaemons(subnet, map[int64]bool{2: true}))
+	require.False(t, isSubnetServedByDaemons(&dbmodel.Subnet{}, map[int64]bool{1: true}))
+}
+
+// Test that the subnet utilization computed on demand matches the output
+// of the statistics counter and the utilization stored by the puller.
+func TestCalculateSubnetUtilization(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+	_ = dbmodel.InitializeStats(db)
+
+	v4Config, v6Config := createDhcpConfigs()
+	app := createAppWithSubnets(t, db, 0, v4Config, v6Config)
+
+	keaMock := createStandardKeaMock(false)
+
+	fa := agentcommtest.NewFakeAgents(keaMock, nil)
+	lookup := dbmodel.NewDHCPOptionDefinitionLookup()
+	for i := range app.Daemons {
+		nets, snets, err := detectDaemonNetworks(db, app.Daemons[i], lookup)
+		require.NoError(t, err)
+		_, err = dbmodel.CommitNetworksIntoDB(db, nets, snets, app.Daemons[i])
+		require.NoError(t, err)
+	}
+
+	sp, _ := NewStatsPuller(db, fa)
+	defer sp.Shutdown()
+	err := sp.pullStats()
+	require.NoError(t, err)
+
+	outOfPoolAddresses, err := dbmodel.CountOutOfPoolAddressReservations(db)
+	require.NoError(t, err)
+	outOfPoolPrefixes, err := dbmodel.CountOutOfPoolPrefixReservations(db)
+	require.NoError(t, err)
+
+	subnets, err := dbmodel.GetAllSubnets(db, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, subnets)
+
+	for i := range subnets {
+		subnet := &subnets[i]
+
+		counter := newStatisticsCounter()
+		counter.setOutOfPoolAddresses(outOfPoolAddresses)
+		counter.setOutOfPoolPrefixes(outOfPoolPrefixes)
+		expected := counter.add(subnet)
+
+		// Act
+		utilization, err := CalculateSubnetUtilization(db, subnet)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, utilization)
+		require.Equal(t, expected.GetAddressUtilization(), utilization.AddressUtilization)
+		require.Equal(t, expected.GetDelegatedPrefixUtilization(), utilization.DelegatedPrefixUtilization)
+		require.Equal(t, expected.GetStatistics(), utilization.Statistics)
+
+		// The statistics haven't changed since the last pull, so the
+		// computed utilization should match the stored one.
+		require.InDelta(t, float64(subnet.AddrUtilization)/1000.0, utilization.AddressUtilization, 0.001)
+		require.InDelta(t, float64(subnet.PdUtilization)/1000.0, utilization.DelegatedPrefixUtilization, 0.001)
+	}
+}
+
 // Stork should not attempt to get statistics from  the Kea application without the
 // stat_cmds hook library.
 func TestGetStatsFromAppWithoutStatCmd(t *testing.T) {
@@ -111,6 +111,7 @@ func detectSharedNetworks(dbi dbops.DBI, config *dbmodel.KeaConfig, family int,
 						continue
 					}
 					existingSubnet.Hosts = hosts
+					existingSubnet.OriginalPrefix = subnet.OriginalPrefix
 					networkForUpdate.Subnets = append(networkForUpdate.Subnets, *existingSubnet)
 				}
 			}
@@ -164,6 +165,7 @@ func detectSubnets(dbi dbops.DBI, config *dbmodel.KeaConfig, family int, daemon
 			}
 			existingSubnet := findMatchingSubnet(subnet, indexedSubnets)
 			if existingSubnet != nil {
+				existingSubnet.OriginalPrefix = subnet.OriginalPrefix
 				subnets = append(subnets, *existingSubnet)
 
 				// Subnet already exists and may contain some hosts. Let's
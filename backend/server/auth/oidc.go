@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/pkg/errors"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Tolerated clock difference between Stork server and the identity
+// provider when checking the token validity period.
+const oidcClockSkew = time.Minute
+
+// Minimal interval between two consecutive fetches of the JWKS document.
+// It protects the identity provider against being flooded with the
+// requests caused by the tokens with unknown key identifiers.
+const jwksRefetchInterval = time.Minute
+
+// Settings of the OIDC bearer token verification and mapping of the
+// token claims to the Stork groups.
+type OIDCSettings struct {
+	// Expected token issuer (iss claim).
+	Issuer string
+	// Expected token audience (aud claim).
+	Audience string
+	// URL of the JSON Web Key Set holding the issuer's public keys.
+	JWKSURL string
+	// Name of the claim holding the list of user roles.
+	RolesClaim string
+	// Role mapped to the Stork super-admin group.
+	SuperAdminRole string
+	// Role mapped to the Stork admin group.
+	AdminRole string
+}
+
+// Claims extracted from a verified OIDC token.
+type OIDCClaims struct {
+	Subject    string
+	Email      string
+	Name       string
+	FamilyName string
+	Roles      []string
+}
+
+// Verifies the bearer tokens (JWT) issued by an OIDC identity provider.
+// The public keys used to verify the token signatures are fetched from
+// the JWKS endpoint and cached. The cache is refreshed when a token
+// signed with an unknown key arrives, e.g., after the key rotation.
+type OIDCVerifier struct {
+	settings  OIDCSettings
+	client    *http.Client
+	mutex     sync.Mutex
+	keys      map[string]jose.JSONWebKey
+	lastFetch time.Time
+}
+
+// Asymmetric signing algorithms accepted in the tokens. The symmetric
+// algorithms and the unsigned tokens are rejected.
+var oidcSigningAlgorithms = map[string]bool{
+	string(jose.RS256): true,
+	string(jose.RS384): true,
+	string(jose.RS512): true,
+	string(jose.PS256): true,
+	string(jose.PS384): true,
+	string(jose.PS512): true,
+	string(jose.ES256): true,
+	string(jose.ES384): true,
+	string(jose.ES512): true,
+}
+
+// Claims specific to OIDC and Stork, complementing the registered claims.
+type oidcPayload struct {
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	GivenName  string `json:"given_name"`
+	FamilyName string `json:"family_name"`
+}
+
+// Creates new OIDC token verifier. The issuer, audience and JWKS URL are
+// mandatory. The roles claim defaults to "groups".
+func NewOIDCVerifier(settings OIDCSettings) (*OIDCVerifier, error) {
+	if settings.Issuer == "" {
+		return nil, errors.New("OIDC issuer must be specified")
+	}
+	if settings.Audience == "" {
+		return nil, errors.New("OIDC audience must be specified")
+	}
+	if settings.JWKSURL == "" {
+		return nil, errors.New("OIDC JWKS URL must be specified")
+	}
+	if settings.RolesClaim == "" {
+		settings.RolesClaim = "groups"
+	}
+	return &OIDCVerifier{
+		settings: settings,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		keys: make(map[string]jose.JSONWebKey),
+	}, nil
+}
+
+// Verifies the token signature and its standard claims: issuer, audience,
+// expiration and not-before time. It returns the claims relevant for
+// Stork if the token is valid.
+func (v *OIDCVerifier) Verify(token string, now time.Time) (*OIDCClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed token")
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, errors.New("token must have exactly one signature")
+	}
+	header := parsed.Headers[0]
+	if !oidcSigningAlgorithms[header.Algorithm] {
+		return nil, errors.Errorf("unsupported token signing algorithm %s", header.Algorithm)
+	}
+
+	key, err := v.getKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+		return nil, errors.New("token signing key does not match the algorithm")
+	}
+
+	var (
+		registered jwt.Claims
+		payload    oidcPayload
+		custom     map[string]interface{}
+	)
+	if err = parsed.Claims(key.Key, &registered, &payload, &custom); err != nil {
+		return nil, errors.Wrap(err, "invalid token signature")
+	}
+
+	if registered.Expiry == nil {
+		return nil, errors.New("token has no expiration time")
+	}
+	err = registered.ValidateWithLeeway(jwt.Expected{
+		Issuer:   v.settings.Issuer,
+		Audience: jwt.Audience{v.settings.Audience},
+		Time:     now,
+	}, oidcClockSkew)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid token claims")
+	}
+
+	claims := &OIDCClaims{
+		Subject:    registered.Subject,
+		Email:      payload.Email,
+		Name:       payload.GivenName,
+		FamilyName: payload.FamilyName,
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	if claims.Name == "" {
+		claims.Name = payload.Name
+	}
+
+	switch roles := custom[v.settings.RolesClaim].(type) {
+	case string:
+		claims.Roles = strings.Fields(roles)
+	case []interface{}:
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				claims.Roles = append(claims.Roles, role)
+			}
+		}
+	}
+
+	return claims, nil
+}
+
+// Maps the roles from the token to the Stork groups. It returns an empty
+// list if none of the roles is recognized.
+func (v *OIDCVerifier) Groups(claims *OIDCClaims) (groups []*dbmodel.SystemGroup) {
+	for _, role := range claims.Roles {
+		switch {
+		case v.settings.SuperAdminRole != "" && role == v.settings.SuperAdminRole:
+			groups = append(groups, &dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID})
+		case v.settings.AdminRole != "" && role == v.settings.AdminRole:
+			groups = append(groups, &dbmodel.SystemGroup{ID: dbmodel.AdminGroupID})
+		}
+	}
+	return groups
+}
+
+// Returns the cached key with the given identifier. If the key is not
+// cached, the JWKS document is fetched again.
+func (v *OIDCVerifier) getKey(kid string) (*jose.JSONWebKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return &key, nil
+	}
+	if !v.lastFetch.IsZero() && time.Since(v.lastFetch) < jwksRefetchInterval {
+		return nil, errors.Errorf("unknown token signing key %s", kid)
+	}
+	v.lastFetch = time.Now()
+
+	keys, err := v.fetchKeys()
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+
+	if key, ok := v.keys[kid]; ok {
+		return &key, nil
+	}
+	return nil, errors.Errorf("unknown token signing key %s", kid)
+}
+
+// Fetches and parses the JWKS document. The keys of the unsupported
+// types, the private keys and the keys not intended for signing are
+// skipped.
+func (v *OIDCVerifier) fetchKeys() (map[string]jose.JSONWebKey, error) {
+	response, err := v.client.Get(v.settings.JWKSURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching JWKS from %s", v.settings.JWKSURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem fetching JWKS from %s: status %d", v.settings.JWKSURL, response.StatusCode)
+	}
+
+	// The keys are parsed one by one because a single unsupported key
+	// would cause the whole set to be rejected.
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&jwks); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing JWKS from %s", v.settings.JWKSURL)
+	}
+
+	keys := make(map[string]jose.JSONWebKey)
+	for _, rawKey := range jwks.Keys {
+		var key jose.JSONWebKey
+		if err := json.Unmarshal(rawKey, &key); err != nil {
+			continue
+		}
+		if !key.Valid() || !key.IsPublic() || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		keys[key.KeyID] = key
+	}
+	return keys, nil
+}
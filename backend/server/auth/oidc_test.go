@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Stub of the OIDC identity provider serving the JWKS document and
+// signing the tokens.
+type testIdentityProvider struct {
+	server   *httptest.Server
+	rsaKey   *rsa.PrivateKey
+	ecKey    *ecdsa.PrivateKey
+	requests int32
+}
+
+// Creates the identity provider stub with one RSA and one EC key.
+func newTestIdentityProvider(t *testing.T) *testIdentityProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	idp := &testIdentityProvider{
+		rsaKey: rsaKey,
+		ecKey:  ecKey,
+	}
+	// The unsupported key must not cause the other keys to be rejected.
+	jwks := map[string]interface{}{
+		"keys": []interface{}{
+			jose.JSONWebKey{Key: &rsaKey.PublicKey, KeyID: "rsa-key", Use: "sig"},
+			jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "ec-key", Use: "sig"},
+			map[string]string{"kty": "unknown", "kid": "unknown-key"},
+		},
+	}
+	idp.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&idp.requests, 1)
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// Creates the verifier accepting the tokens from the stub.
+func (idp *testIdentityProvider) newVerifier(t *testing.T) *OIDCVerifier {
+	verifier, err := NewOIDCVerifier(OIDCSettings{
+		Issuer:         "https://idp.example.org",
+		Audience:       "stork",
+		JWKSURL:        idp.server.URL,
+		SuperAdminRole: "stork-super-admin",
+		AdminRole:      "stork-admin",
+	})
+	require.NoError(t, err)
+	return verifier
+}
+
+// Returns the default valid claims.
+func validClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":         "https://idp.example.org",
+		"aud":         "stork",
+		"sub":         "1234",
+		"email":       "jdoe@example.org",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"groups":      []string{"stork-admin", "other"},
+		"iat":         now.Unix(),
+		"exp":         now.Add(time.Hour).Unix(),
+	}
+}
+
+// Signs the claims with the specified algorithm and key identifier.
+func (idp *testIdentityProvider) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, idp.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, idp.ecKey, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Test that the verifier requires the issuer, audience and JWKS URL.
+func TestNewOIDCVerifierMissingSettings(t *testing.T) {
+	_, err := NewOIDCVerifier(OIDCSettings{Audience: "stork", JWKSURL: "http://localhost"})
+	require.Error(t, err)
+	_, err = NewOIDCVerifier(OIDCSettings{Issuer: "https://idp.example.org", JWKSURL: "http://localhost"})
+	require.Error(t, err)
+	_, err = NewOIDCVerifier(OIDCSettings{Issuer: "https://idp.example.org", Audience: "stork"})
+	require.Error(t, err)
+}
+
+// Test that the valid RS256 and ES256 tokens are accepted and their
+// claims are returned.
+func TestOIDCVerifyValidToken(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		kid := "rsa-key"
+		if alg == "ES256" {
+			kid = "ec-key"
+		}
+		t.Run(alg, func(t *testing.T) {
+			claims, err := verifier.Verify(idp.sign(t, alg, kid, validClaims()), time.Now())
+			require.NoError(t, err)
+			require.NotNil(t, claims)
+			require.Equal(t, "1234", claims.Subject)
+			require.Equal(t, "jdoe@example.org", claims.Email)
+			require.Equal(t, "John", claims.Name)
+			require.Equal(t, "Doe", claims.FamilyName)
+			require.Equal(t, []string{"stork-admin", "other"}, claims.Roles)
+		})
+	}
+
+	// The keys should be fetched only once.
+	require.EqualValues(t, 1, atomic.LoadInt32(&idp.requests))
+}
+
+// Test that the audience claim may be an array.
+func TestOIDCVerifyAudienceArray(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	claims := validClaims()
+	claims["aud"] = []string{"other", "stork"}
+	_, err := verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.NoError(t, err)
+}
+
+// Test that the expired token is rejected.
+func TestOIDCVerifyExpiredToken(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err := verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.ErrorContains(t, err, "expired")
+
+	// The token without the expiration time is rejected too.
+	delete(claims, "exp")
+	_, err = verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.Error(t, err)
+}
+
+// Test that the token which is not valid yet is rejected.
+func TestOIDCVerifyNotYetValidToken(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	claims := validClaims()
+	claims["nbf"] = time.Now().Add(time.Hour).Unix()
+	_, err := verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.ErrorContains(t, err, "not valid yet")
+}
+
+// Test that the tokens with the wrong issuer or audience are rejected.
+func TestOIDCVerifyWrongIssuerOrAudience(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	claims := validClaims()
+	claims["iss"] = "https://evil.example.org"
+	_, err := verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.ErrorContains(t, err, "issuer")
+
+	claims = validClaims()
+	claims["aud"] = "other"
+	_, err = verifier.Verify(idp.sign(t, "RS256", "rsa-key", claims), time.Now())
+	require.ErrorContains(t, err, "audience")
+}
+
+// Test that the tokens with invalid signatures or malformed tokens are
+// rejected.
+func TestOIDCVerifyInvalidToken(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	token := idp.sign(t, "RS256", "rsa-key", validClaims())
+
+	// Tampered payload.
+	claims := validClaims()
+	claims["groups"] = []string{"stork-super-admin"}
+	tampered := idp.sign(t, "RS256", "rsa-key", claims)
+	_, err := verifier.Verify(tampered[:len(tampered)-10]+token[len(token)-10:], time.Now())
+	require.Error(t, err)
+
+	// Signature with a key not matching the algorithm.
+	_, err = verifier.Verify(idp.sign(t, "ES256", "rsa-key", validClaims()), time.Now())
+	require.Error(t, err)
+
+	// Symmetric signing algorithm.
+	_, err = verifier.Verify(idp.sign(t, "HS256", "rsa-key", validClaims()), time.Now())
+	require.ErrorContains(t, err, "unsupported token signing algorithm")
+
+	// Unsigned token.
+	_, err = verifier.Verify(idp.sign(t, "none", "rsa-key", validClaims()), time.Now())
+	require.ErrorContains(t, err, "unsupported token signing algorithm")
+
+	// Unknown key.
+	_, err = verifier.Verify(idp.sign(t, "RS256", "unknown", validClaims()), time.Now())
+	require.ErrorContains(t, err, "unknown token signing key")
+
+	// Malformed tokens.
+	_, err = verifier.Verify("", time.Now())
+	require.Error(t, err)
+	_, err = verifier.Verify("a.b.c", time.Now())
+	require.Error(t, err)
+}
+
+// Test that the token signed by another identity provider using the same
+// key identifier is rejected.
+func TestOIDCVerifyForeignSignature(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	other := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	_, err := verifier.Verify(other.sign(t, "RS256", "rsa-key", validClaims()), time.Now())
+	require.ErrorContains(t, err, "invalid token signature")
+}
+
+// Test that the roles are mapped to the Stork groups.
+func TestOIDCGroups(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	verifier := idp.newVerifier(t)
+
+	groups := verifier.Groups(&OIDCClaims{Roles: []string{"stork-super-admin", "stork-admin", "other"}})
+	require.Len(t, groups, 2)
+	require.Equal(t, dbmodel.SuperAdminGroupID, groups[0].ID)
+	require.Equal(t, dbmodel.AdminGroupID, groups[1].ID)
+
+	require.Empty(t, verifier.Groups(&OIDCClaims{Roles: []string{"other"}}))
+	require.Empty(t, verifier.Groups(&OIDCClaims{}))
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
+	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"net"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/go-pg/pg/v10"
@@ -27,6 +33,20 @@ const (
 	SecretTypeSrvToken = "server token"
 )
 
+// Encodings of the exported secrets.
+const (
+	// Secret is exported as stored in the database, i.e., the keys and
+	// certificates in PEM format, and the token as plain text.
+	ExportEncodingRaw = "raw"
+	// Keys and certificates are exported in PEM format.
+	ExportEncodingPEM = "pem"
+	// Keys and certificates are exported in binary DER format.
+	ExportEncodingDER = "der"
+	// Keys and certificates are exported as base64-encoded DER. The
+	// token is exported as base64-encoded plain text.
+	ExportEncodingBase64 = "base64"
+)
+
 // Generate server token and store it in database.  It is used during
 // manual agent registration. This function uses crypto random numbers
 // generator.
@@ -103,6 +123,39 @@ func setupRootKeyAndCert(db *pg.DB) (*ecdsa.PrivateKey, *x509.Certificate, []byt
 	return rootKey, rootCert, rootCertPEM, nil
 }
 
+// Returns the list of all host names and IP addresses that will be put
+// to the server cert.
+func getServerNamesAndIPs() ([]string, []net.IP, error) {
+	// get list of all host IP addresses that will be put to server cert
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot get interface addresses")
+	}
+	var srvIPs []net.IP
+	var srvNames []string
+	var resolver net.Resolver
+	for _, addr := range addrs {
+		ipAddr, _, err := net.ParseCIDR(addr.String())
+		if err != nil {
+			continue
+		}
+		srvIPs = append(srvIPs, ipAddr)
+
+		// Lookup sometimes blocks on IPv6 loopback address on Debian 10.
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+		names, err := resolver.LookupAddr(ctx, ipAddr.String())
+
+		if err == nil {
+			srvNames = append(srvNames, names...)
+		}
+	}
+	if len(srvIPs) == 0 || len(srvNames) == 0 {
+		return nil, nil, errors.Errorf("cannot find IP addresses on this host")
+	}
+	return srvNames, srvIPs, nil
+}
+
 // Check if a server key and a server cert are present in db. If not generate them
 // and store in database.
 func setupServerKeyAndCert(db *pg.DB, rootKey *ecdsa.PrivateKey, rootCert *x509.Certificate) ([]byte, []byte, error) {
@@ -118,32 +171,9 @@ func setupServerKeyAndCert(db *pg.DB, rootKey *ecdsa.PrivateKey, rootCert *x509.
 
 	// no server key or no server cert so generate
 	if serverKeyPEM == nil || serverCertPEM == nil {
-		// get list of all host IP addresses that will be put to server cert
-		addrs, err := net.InterfaceAddrs()
+		srvNames, srvIPs, err := getServerNamesAndIPs()
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "cannot get interface addresses")
-		}
-		var srvIPs []net.IP
-		var srvNames []string
-		var resolver net.Resolver
-		for _, addr := range addrs {
-			ipAddr, _, err := net.ParseCIDR(addr.String())
-			if err != nil {
-				continue
-			}
-			srvIPs = append(srvIPs, ipAddr)
-
-			// Lookup sometimes blocks on IPv6 loopback address on Debian 10.
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
-			names, err := resolver.LookupAddr(ctx, ipAddr.String())
-
-			if err == nil {
-				srvNames = append(srvNames, names...)
-			}
-		}
-		if len(srvIPs) == 0 || len(srvNames) == 0 {
-			return nil, nil, errors.Errorf("cannot find IP addresses on this host")
+			return nil, nil, err
 		}
 
 		certSerialNumber, err := dbmodel.GetNewCertSerialNumber(db)
@@ -216,8 +246,174 @@ func SetupServerCerts(db *pg.DB) ([]byte, []byte, []byte, error) {
 	return rootCertPEM, serverCertPEM, serverKeyPEM, nil
 }
 
-// Export a secret e.g. certificate or server token to stdout or to indicated file.
-func ExportSecret(db *pg.DB, object string, filename string) error {
+// Default validity period of the rotated server cert in days.
+const DefaultRotatedCertValidityDays = 365
+
+// Describes the rotation of the server key and cert.
+type ServerCertRotation struct {
+	// Indicates if the rotation was only simulated and nothing was
+	// stored in the database.
+	DryRun bool
+	// Host names and IP addresses the new server cert is issued for.
+	DNSNames    []string
+	IPAddresses []net.IP
+	// Expiration time of the current server cert. It is nil if the
+	// server cert is missing.
+	OldNotAfter *time.Time
+	// Expiration time of the new server cert.
+	NotAfter time.Time
+	// Indicates if the server token is regenerated.
+	TokenBumped bool
+}
+
+// Regenerates the server key and cert signed by the root CA key and cert
+// held in the database, and stores them in the database. The new cert is
+// valid for the specified number of days. If bumpToken is true, the server
+// token is regenerated too. It returns an error if the root CA key or cert
+// is missing because the server key and cert would not be trusted by the
+// agents. In the dry-run mode, it only returns what would change without
+// modifying the database. The running server must be restarted to use the
+// new server key and cert.
+func RotateServerCert(db *pg.DB, validityDays int, bumpToken, dryRun bool) (*ServerCertRotation, error) {
+	if validityDays <= 0 {
+		return nil, errors.Errorf("cert validity must be a positive number of days: %d", validityDays)
+	}
+
+	rootKeyPEM, err := dbmodel.GetSecret(db, dbmodel.SecretCAKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting CA key from database")
+	}
+	if rootKeyPEM == nil {
+		return nil, errors.Errorf("%s is missing in the database; cannot sign the new server cert", SecretTypeCAKey)
+	}
+	rootCertPEM, err := dbmodel.GetSecret(db, dbmodel.SecretCACert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting CA cert from database")
+	}
+	if rootCertPEM == nil {
+		return nil, errors.Errorf("%s is missing in the database; cannot sign the new server cert", SecretTypeCACert)
+	}
+	rootKey, err := pki.ParsePrivateKey(rootKeyPEM)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "cannot parse %s", SecretTypeCAKey)
+	}
+	rootCert, err := pki.ParseCert(rootCertPEM)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "cannot parse %s", SecretTypeCACert)
+	}
+
+	srvNames, srvIPs, err := getServerNamesAndIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &ServerCertRotation{
+		DryRun:      dryRun,
+		DNSNames:    srvNames,
+		IPAddresses: srvIPs,
+		NotAfter:    time.Now().UTC().AddDate(0, 0, validityDays).Truncate(time.Second),
+		TokenBumped: bumpToken,
+	}
+	if rotation.NotAfter.After(rootCert.NotAfter) {
+		return nil, errors.Errorf("new server cert would expire after the %s on %s",
+			SecretTypeCACert, rootCert.NotAfter.Format(time.RFC3339))
+	}
+
+	oldCert, err := getServerCertExpiry(db, dbmodel.SecretServerCert, SecretTypeSrvCert)
+	if err != nil {
+		return nil, err
+	}
+	if oldCert != nil {
+		rotation.OldNotAfter = &oldCert.NotAfter
+	}
+
+	if dryRun {
+		return rotation, nil
+	}
+
+	certSerialNumber, err := dbmodel.GetNewCertSerialNumber(db)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get new cert S/N")
+	}
+	serverCertPEM, serverKeyPEM, err := pki.GenKeyCertWithExpiry("server", srvNames, srvIPs, certSerialNumber, rotation.NotAfter, rootCert, rootKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot generate key and cert for server")
+	}
+	err = dbmodel.SetSecret(db, dbmodel.SecretServerKey, serverKeyPEM)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot store server key in database")
+	}
+	err = dbmodel.SetSecret(db, dbmodel.SecretServerCert, serverCertPEM)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot store server cert in database")
+	}
+	log.Printf("Rotated server key and cert")
+
+	if bumpToken {
+		if _, err = GenerateServerToken(db); err != nil {
+			return nil, errors.Wrapf(err, "cannot generate server token")
+		}
+		log.Printf("Regenerated server token")
+	}
+	return rotation, nil
+}
+
+// Converts the secret to the specified encoding. The content is the secret
+// as stored in the database. The keys and certificates are stored in PEM
+// format and can be converted to all supported encodings. The server token
+// is a plain text and it has no PEM nor DER form.
+func EncodeSecret(object string, content []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case ExportEncodingRaw, "":
+		return content, nil
+	case ExportEncodingPEM, ExportEncodingDER, ExportEncodingBase64:
+	default:
+		return nil, errors.Errorf("unsupported encoding '%s'", encoding)
+	}
+
+	if object == dbmodel.SecretServerToken {
+		if encoding != ExportEncodingBase64 {
+			return nil, errors.Errorf("%s cannot be exported in '%s' encoding", SecretTypeSrvToken, encoding)
+		}
+		return []byte(base64.StdEncoding.EncodeToString(content)), nil
+	}
+
+	// The keys and certificates are stored in PEM format.
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.Errorf("problem decoding PEM block of the '%s' object", object)
+	}
+
+	switch encoding {
+	case ExportEncodingDER:
+		return block.Bytes, nil
+	case ExportEncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(block.Bytes)), nil
+	default:
+		return pem.EncodeToMemory(block), nil
+	}
+}
+
+// Metadata of the exported secret. It describes the secret without
+// revealing its content.
+type ExportedSecretMetadata struct {
+	Object   string `json:"object"`
+	Name     string `json:"name"`
+	Encoding string `json:"encoding"`
+	File     string `json:"file,omitempty"`
+	Size     int    `json:"size"`
+	SHA256   string `json:"sha256"`
+	// Details set only for the certificates.
+	Subject   string     `json:"subject,omitempty"`
+	Issuer    string     `json:"issuer,omitempty"`
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+}
+
+// Fetches a secret from the database and converts it to the specified
+// encoding. It returns the secret display name, the secret as stored in
+// the database and the encoded secret.
+func getEncodedSecret(db *pg.DB, object string, encoding string, filename string) (string, []byte, []byte, error) {
 	var objDisplayName string
 	switch object {
 	case dbmodel.SecretCAKey:
@@ -231,12 +427,30 @@ func ExportSecret(db *pg.DB, object string, filename string) error {
 	case dbmodel.SecretServerToken:
 		objDisplayName = SecretTypeSrvToken
 	default:
-		return errors.Errorf("requested unknown object '%s'", object)
+		return "", nil, nil, errors.Errorf("requested unknown object '%s'", object)
+	}
+
+	if encoding == ExportEncodingDER && filename == "" {
+		return "", nil, nil, errors.Errorf("%s in the binary '%s' encoding can be exported only to a file", objDisplayName, encoding)
+	}
+
+	raw, err := dbmodel.GetSecret(db, object)
+	if err != nil {
+		return "", nil, nil, errors.Wrapf(err, "problem getting '%s' from database", objDisplayName)
 	}
+	content, err := EncodeSecret(object, raw, encoding)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return objDisplayName, raw, content, nil
+}
 
-	content, err := dbmodel.GetSecret(db, object)
+// Export a secret e.g. certificate or server token to stdout or to indicated file.
+// The secret is converted to the specified encoding before exporting.
+func ExportSecret(db *pg.DB, object string, encoding string, filename string) error {
+	objDisplayName, _, content, err := getEncodedSecret(db, object, encoding, filename)
 	if err != nil {
-		return errors.Wrapf(err, "problem getting '%s' from database", objDisplayName)
+		return err
 	}
 	if filename != "" {
 		err := os.WriteFile(filename, content, 0o600)
@@ -251,6 +465,46 @@ func ExportSecret(db *pg.DB, object string, filename string) error {
 	return nil
 }
 
+// Export a secret to the indicated file and return its metadata. If the
+// file is not specified the secret is not exported anywhere and only its
+// metadata are returned. The metadata never include the secret content.
+func ExportSecretMetadata(db *pg.DB, object string, encoding string, filename string) (*ExportedSecretMetadata, error) {
+	objDisplayName, raw, content, err := getEncodedSecret(db, object, encoding, filename)
+	if err != nil {
+		return nil, err
+	}
+	if filename != "" {
+		if err := os.WriteFile(filename, content, 0o600); err != nil {
+			return nil, err
+		}
+	}
+	if encoding == "" {
+		encoding = ExportEncodingRaw
+	}
+
+	checksum := sha256.Sum256(content)
+	metadata := &ExportedSecretMetadata{
+		Object:   object,
+		Name:     objDisplayName,
+		Encoding: encoding,
+		File:     filename,
+		Size:     len(content),
+		SHA256:   hex.EncodeToString(checksum[:]),
+	}
+
+	if object == dbmodel.SecretCACert || object == dbmodel.SecretServerCert {
+		cert, err := pki.ParseCert(raw)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "cannot parse %s", objDisplayName)
+		}
+		metadata.Subject = cert.Subject.String()
+		metadata.Issuer = cert.Issuer.String()
+		metadata.NotBefore = &cert.NotBefore
+		metadata.NotAfter = &cert.NotAfter
+	}
+	return metadata, nil
+}
+
 // Import a secret e.g. certificate or server token from stdin or from indicated file.
 func ImportSecret(db *pg.DB, object string, filename string) error {
 	var objDisplayName string
@@ -321,3 +575,115 @@ func ImportSecret(db *pg.DB, object string, filename string) error {
 
 	return nil
 }
+
+// Default period before the certificate expiration in which the
+// certificate is reported as expiring.
+const DefaultCertExpiryWindow = 30 * 24 * time.Hour
+
+// Describes the certificate which expires within the specified window
+// or has already expired.
+type ExpiringCert struct {
+	// Human-readable name of the certificate.
+	Name string
+	// ID of the machine for which the agent certificate was issued.
+	// It is zero for the server certificates.
+	MachineID int64
+	// Expiration time of the certificate.
+	NotAfter time.Time
+}
+
+// Checks if the certificate has expired at the specified time.
+func (c ExpiringCert) IsExpired(now time.Time) bool {
+	return !c.NotAfter.After(now)
+}
+
+// The server certificates held in the database with their display names.
+var serverCertSecrets = []struct {
+	object      string
+	displayName string
+}{
+	{dbmodel.SecretCACert, SecretTypeCACert},
+	{dbmodel.SecretServerCert, SecretTypeSrvCert},
+}
+
+// Returns the number of whole days left until the certificate expiration.
+// It is negative if the certificate has expired.
+func (c ExpiringCert) DaysLeft(now time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(now).Hours() / 24))
+}
+
+// Reads the server certificate from the database and returns its expiration
+// time. It returns nil if the certificate is missing.
+func getServerCertExpiry(db *pg.DB, object, displayName string) (*ExpiringCert, error) {
+	certPEM, err := dbmodel.GetSecret(db, object)
+	if err != nil {
+		return nil, err
+	}
+	if certPEM == nil {
+		return nil, nil
+	}
+	cert, err := pki.ParseCert(certPEM)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "cannot parse %s", displayName)
+	}
+	return &ExpiringCert{
+		Name:     displayName,
+		NotAfter: cert.NotAfter,
+	}, nil
+}
+
+// Returns the expiration times of the CA and server certificates held in
+// the database. It returns an error if any of the certificates is missing,
+// e.g., when the server has not been started yet.
+func GetServerCertsExpiry(db *pg.DB) ([]ExpiringCert, error) {
+	var certs []ExpiringCert
+	for _, secret := range serverCertSecrets {
+		cert, err := getServerCertExpiry(db, secret.object, secret.displayName)
+		if err != nil {
+			return nil, err
+		}
+		if cert == nil {
+			return nil, errors.Errorf("%s is missing in the database; it is generated on the first Stork server startup", secret.displayName)
+		}
+		certs = append(certs, *cert)
+	}
+	return certs, nil
+}
+
+// Finds the server and agent certificates which expire within the
+// specified window from now or have already expired. The server
+// certificates are read from the database. The agent certificates are
+// not stored, so their expiration times recorded during the agent
+// registration are used. The returned certificates are ordered by the
+// expiration time.
+func FindExpiringCerts(db *pg.DB, now time.Time, window time.Duration) ([]ExpiringCert, error) {
+	deadline := now.Add(window)
+
+	var expiring []ExpiringCert
+	for _, secret := range serverCertSecrets {
+		cert, err := getServerCertExpiry(db, secret.object, secret.displayName)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil && cert.NotAfter.Before(deadline) {
+			expiring = append(expiring, *cert)
+		}
+	}
+
+	machines, err := dbmodel.GetMachinesWithCertsExpiringBefore(db, deadline)
+	if err != nil {
+		return nil, err
+	}
+	for _, machine := range machines {
+		expiring = append(expiring, ExpiringCert{
+			Name:      fmt.Sprintf("agent cert of machine %s:%d", machine.Address, machine.AgentPort),
+			MachineID: machine.ID,
+			NotAfter:  machine.CertExpiresAt,
+		})
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+	})
+	return expiring, nil
+}
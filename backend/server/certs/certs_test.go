@@ -1,11 +1,18 @@
 package certs
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"isc.org/stork/pki"
 	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
 	"isc.org/stork/testutil"
@@ -78,48 +85,48 @@ func TestExportSecret(t *testing.T) {
 	require.NoError(t, err)
 
 	// check exporting Server Cert
-	err = ExportSecret(db, dbmodel.SecretServerCert, "")
+	err = ExportSecret(db, dbmodel.SecretServerCert, ExportEncodingRaw, "")
 	require.NoError(t, err)
 
 	serverCertPEMFile, err := sb.Join("server-cert.pem")
 	require.NoError(t, err)
-	err = ExportSecret(db, dbmodel.SecretServerCert, serverCertPEMFile)
+	err = ExportSecret(db, dbmodel.SecretServerCert, ExportEncodingRaw, serverCertPEMFile)
 	require.NoError(t, err)
 	serverCertPEM2, err := os.ReadFile(serverCertPEMFile)
 	require.NoError(t, err)
 	require.EqualValues(t, serverCertPEM, serverCertPEM2)
 
 	// check exporting Server Key
-	err = ExportSecret(db, dbmodel.SecretServerKey, "")
+	err = ExportSecret(db, dbmodel.SecretServerKey, ExportEncodingRaw, "")
 	require.NoError(t, err)
 
 	serverKeyPEMFile, err := sb.Join("server-key.pem")
 	require.NoError(t, err)
-	err = ExportSecret(db, dbmodel.SecretServerKey, serverKeyPEMFile)
+	err = ExportSecret(db, dbmodel.SecretServerKey, ExportEncodingRaw, serverKeyPEMFile)
 	require.NoError(t, err)
 	serverKeyPEM2, err := os.ReadFile(serverKeyPEMFile)
 	require.NoError(t, err)
 	require.EqualValues(t, serverKeyPEM, serverKeyPEM2)
 
 	// check exporting CA Cert
-	err = ExportSecret(db, dbmodel.SecretCACert, "")
+	err = ExportSecret(db, dbmodel.SecretCACert, ExportEncodingRaw, "")
 	require.NoError(t, err)
 
 	rootCertPEMFile, err := sb.Join("root-cert.pem")
 	require.NoError(t, err)
-	err = ExportSecret(db, dbmodel.SecretCACert, rootCertPEMFile)
+	err = ExportSecret(db, dbmodel.SecretCACert, ExportEncodingRaw, rootCertPEMFile)
 	require.NoError(t, err)
 	rootCertPEM2, err := os.ReadFile(rootCertPEMFile)
 	require.NoError(t, err)
 	require.EqualValues(t, rootCertPEM, rootCertPEM2)
 
 	// check exporting CA Key
-	err = ExportSecret(db, dbmodel.SecretCAKey, "")
+	err = ExportSecret(db, dbmodel.SecretCAKey, ExportEncodingRaw, "")
 	require.NoError(t, err)
 
 	rootKeyPEMFile, err := sb.Join("root-key.pem")
 	require.NoError(t, err)
-	err = ExportSecret(db, dbmodel.SecretCAKey, rootKeyPEMFile)
+	err = ExportSecret(db, dbmodel.SecretCAKey, ExportEncodingRaw, rootKeyPEMFile)
 	require.NoError(t, err)
 	rootKeyPEM2, err := os.ReadFile(rootKeyPEMFile)
 	require.NoError(t, err)
@@ -128,12 +135,12 @@ func TestExportSecret(t *testing.T) {
 	require.EqualValues(t, rootKeyPEM, rootKeyPEM2)
 
 	// check exporting Server Token
-	err = ExportSecret(db, dbmodel.SecretServerToken, "")
+	err = ExportSecret(db, dbmodel.SecretServerToken, ExportEncodingRaw, "")
 	require.NoError(t, err)
 
 	serverTokenFile, err := sb.Join("server-token.txt")
 	require.NoError(t, err)
-	err = ExportSecret(db, dbmodel.SecretServerToken, serverTokenFile)
+	err = ExportSecret(db, dbmodel.SecretServerToken, ExportEncodingRaw, serverTokenFile)
 	require.NoError(t, err)
 	serverToken2, err := os.ReadFile(serverTokenFile)
 	require.NoError(t, err)
@@ -142,6 +149,149 @@ func TestExportSecret(t *testing.T) {
 	require.EqualValues(t, serverToken, serverToken2)
 }
 
+// Check that the metadata of the exported secrets are returned and the
+// secrets are written to the file when it is specified.
+func TestExportSecretMetadata(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	sb := testutil.NewSandbox()
+	defer sb.Close()
+
+	_, serverCertPEM, _, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	serverCert, err := pki.ParseCert(serverCertPEM)
+	require.NoError(t, err)
+
+	// Certificate exported to a file.
+	serverCertFile, err := sb.Join("server-cert.der")
+	require.NoError(t, err)
+	metadata, err := ExportSecretMetadata(db, dbmodel.SecretServerCert, ExportEncodingDER, serverCertFile)
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	require.Equal(t, dbmodel.SecretServerCert, metadata.Object)
+	require.Equal(t, SecretTypeSrvCert, metadata.Name)
+	require.Equal(t, ExportEncodingDER, metadata.Encoding)
+	require.Equal(t, serverCertFile, metadata.File)
+	require.Equal(t, len(serverCert.Raw), metadata.Size)
+	checksum := sha256.Sum256(serverCert.Raw)
+	require.Equal(t, hex.EncodeToString(checksum[:]), metadata.SHA256)
+	require.Equal(t, serverCert.Subject.String(), metadata.Subject)
+	require.Equal(t, serverCert.Issuer.String(), metadata.Issuer)
+	require.NotNil(t, metadata.NotAfter)
+	require.Equal(t, serverCert.NotAfter, *metadata.NotAfter)
+
+	content, err := os.ReadFile(serverCertFile)
+	require.NoError(t, err)
+	require.Equal(t, serverCert.Raw, content)
+
+	// Token without a file. The certificate details are not set.
+	metadata, err = ExportSecretMetadata(db, dbmodel.SecretServerToken, "", "")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	require.Equal(t, ExportEncodingRaw, metadata.Encoding)
+	require.Empty(t, metadata.File)
+	require.NotZero(t, metadata.Size)
+	require.Empty(t, metadata.Subject)
+	require.Nil(t, metadata.NotAfter)
+
+	// Unknown object.
+	_, err = ExportSecretMetadata(db, "unknown", "", "")
+	require.Error(t, err)
+}
+
+// Check if the certificate is exported in the raw encoding.
+func TestEncodeSecretCertRaw(t *testing.T) {
+	_, _, _, certPEM, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	encoded, err := EncodeSecret(dbmodel.SecretCACert, certPEM, ExportEncodingRaw)
+	require.NoError(t, err)
+	require.Equal(t, certPEM, encoded)
+}
+
+// Check if the certificate is exported in the PEM encoding.
+func TestEncodeSecretCertPEM(t *testing.T) {
+	_, _, _, certPEM, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	encoded, err := EncodeSecret(dbmodel.SecretCACert, certPEM, ExportEncodingPEM)
+	require.NoError(t, err)
+	require.Equal(t, certPEM, encoded)
+
+	_, err = pki.ParseCert(encoded)
+	require.NoError(t, err)
+}
+
+// Check if the certificate is exported in the DER encoding.
+func TestEncodeSecretCertDER(t *testing.T) {
+	_, _, cert, certPEM, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	encoded, err := EncodeSecret(dbmodel.SecretCACert, certPEM, ExportEncodingDER)
+	require.NoError(t, err)
+	require.Equal(t, cert.Raw, encoded)
+}
+
+// Check if the certificate is exported in the base64 encoding.
+func TestEncodeSecretCertBase64(t *testing.T) {
+	_, _, cert, certPEM, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	encoded, err := EncodeSecret(dbmodel.SecretCACert, certPEM, ExportEncodingBase64)
+	require.NoError(t, err)
+
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	require.NoError(t, err)
+	require.Equal(t, cert.Raw, decoded)
+}
+
+// Check if the private key is exported in the DER encoding.
+func TestEncodeSecretKeyDER(t *testing.T) {
+	_, keyPEM, _, _, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	encoded, err := EncodeSecret(dbmodel.SecretCAKey, keyPEM, ExportEncodingDER)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(keyPEM)
+	require.NotNil(t, block)
+	require.Equal(t, block.Bytes, encoded)
+}
+
+// Check if the server token can only be exported in the raw and base64
+// encodings.
+func TestEncodeSecretToken(t *testing.T) {
+	token := []byte("01234567890123456789001234567890")
+
+	encoded, err := EncodeSecret(dbmodel.SecretServerToken, token, ExportEncodingRaw)
+	require.NoError(t, err)
+	require.Equal(t, token, encoded)
+
+	encoded, err = EncodeSecret(dbmodel.SecretServerToken, token, ExportEncodingBase64)
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString(token), string(encoded))
+
+	_, err = EncodeSecret(dbmodel.SecretServerToken, token, ExportEncodingPEM)
+	require.Error(t, err)
+
+	_, err = EncodeSecret(dbmodel.SecretServerToken, token, ExportEncodingDER)
+	require.Error(t, err)
+}
+
+// Check if an error is returned for an unsupported encoding or a malformed
+// certificate.
+func TestEncodeSecretErrors(t *testing.T) {
+	_, _, _, certPEM, err := pki.GenCAKeyCert(1)
+	require.NoError(t, err)
+
+	_, err = EncodeSecret(dbmodel.SecretCACert, certPEM, "hex")
+	require.Error(t, err)
+
+	_, err = EncodeSecret(dbmodel.SecretCACert, bytes.Repeat([]byte{1}, 10), ExportEncodingDER)
+	require.Error(t, err)
+}
+
 // Check if ImportSecret correctly imports various keys and
 // certificates from a file to a database.
 func TestImportSecret(t *testing.T) {
@@ -267,3 +417,239 @@ hbkkHg8CIQDEfP1HGySpXF5AhAK5RSIxSJTvVhzSSMKtAEmqG2BgYw==
 	err = ImportSecret(db, dbmodel.SecretServerToken, nonsenseFile)
 	require.Error(t, err)
 }
+
+// Test that the server and agent certificates expiring within the
+// window are found.
+func TestFindExpiringCerts(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rootCertPEM, _, _, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	rootCert, err := pki.ParseCert(rootCertPEM)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	machines := []*dbmodel.Machine{
+		{
+			Address:       "agent1",
+			AgentPort:     8080,
+			CertExpiresAt: now.AddDate(0, 0, 10),
+		},
+		{
+			Address:       "agent2",
+			AgentPort:     8080,
+			CertExpiresAt: now.AddDate(0, 0, 100),
+		},
+		{
+			Address:   "agent3",
+			AgentPort: 8080,
+		},
+	}
+	for _, machine := range machines {
+		err = dbmodel.AddMachine(db, machine)
+		require.NoError(t, err)
+	}
+
+	// Only the first agent certificate expires within the default window.
+	expiring, err := FindExpiringCerts(db, now, DefaultCertExpiryWindow)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	require.Equal(t, "agent cert of machine agent1:8080", expiring[0].Name)
+	require.Equal(t, machines[0].ID, expiring[0].MachineID)
+	require.False(t, expiring[0].IsExpired(now))
+
+	// Check shortly before the server certificates expire. All agent
+	// certificates with the known expiration time have expired.
+	later := rootCert.NotAfter.Add(-24 * time.Hour)
+	expiring, err = FindExpiringCerts(db, later, DefaultCertExpiryWindow)
+	require.NoError(t, err)
+	require.Len(t, expiring, 4)
+	require.Equal(t, "agent cert of machine agent1:8080", expiring[0].Name)
+	require.True(t, expiring[0].IsExpired(later))
+	require.Equal(t, "agent cert of machine agent2:8080", expiring[1].Name)
+	require.True(t, expiring[1].IsExpired(later))
+	require.ElementsMatch(t, []string{SecretTypeCACert, SecretTypeSrvCert},
+		[]string{expiring[2].Name, expiring[3].Name})
+	require.Zero(t, expiring[2].MachineID)
+	require.False(t, expiring[2].IsExpired(later))
+}
+
+// Test that no certificates are returned when there are no server
+// certificates and no agents.
+func TestFindExpiringCertsEmpty(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	expiring, err := FindExpiringCerts(db, time.Now(), DefaultCertExpiryWindow)
+	require.NoError(t, err)
+	require.Empty(t, expiring)
+}
+
+// Test that the expiration times of the CA and server certificates are
+// returned.
+func TestGetServerCertsExpiry(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rootCertPEM, _, serverCertPEM, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	rootCert, err := pki.ParseCert(rootCertPEM)
+	require.NoError(t, err)
+	serverCert, err := pki.ParseCert(serverCertPEM)
+	require.NoError(t, err)
+
+	certs, err := GetServerCertsExpiry(db)
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	require.Equal(t, SecretTypeCACert, certs[0].Name)
+	require.True(t, rootCert.NotAfter.Equal(certs[0].NotAfter))
+	require.Equal(t, SecretTypeSrvCert, certs[1].Name)
+	require.True(t, serverCert.NotAfter.Equal(certs[1].NotAfter))
+}
+
+// Test that an error is returned when the server certificates are
+// missing in the database.
+func TestGetServerCertsExpiryMissing(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	certs, err := GetServerCertsExpiry(db)
+	require.ErrorContains(t, err, "CA cert is missing in the database")
+	require.Nil(t, certs)
+}
+
+// Test that the number of days left until the certificate expiration
+// is computed correctly.
+func TestExpiringCertDaysLeft(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	cert := ExpiringCert{NotAfter: now.Add(30*24*time.Hour + time.Hour)}
+	require.Equal(t, 30, cert.DaysLeft(now))
+
+	cert = ExpiringCert{NotAfter: now.Add(time.Hour)}
+	require.Zero(t, cert.DaysLeft(now))
+
+	cert = ExpiringCert{NotAfter: now.Add(-time.Hour)}
+	require.Equal(t, -1, cert.DaysLeft(now))
+}
+
+// Test that the server key and cert are regenerated and signed by the
+// existing root CA, and the server token is optionally regenerated.
+func TestRotateServerCert(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rootCertPEM, oldServerCertPEM, oldServerKeyPEM, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	oldToken, err := dbmodel.GetSecret(db, dbmodel.SecretServerToken)
+	require.NoError(t, err)
+
+	// Act
+	rotation, err := RotateServerCert(db, 90, false, false)
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, rotation.DryRun)
+	require.False(t, rotation.TokenBumped)
+	require.NotEmpty(t, rotation.DNSNames)
+	require.NotEmpty(t, rotation.IPAddresses)
+	require.NotNil(t, rotation.OldNotAfter)
+	require.WithinDuration(t, time.Now().AddDate(0, 0, 90), rotation.NotAfter, time.Minute)
+
+	serverCertPEM, err := dbmodel.GetSecret(db, dbmodel.SecretServerCert)
+	require.NoError(t, err)
+	require.NotEqualValues(t, oldServerCertPEM, serverCertPEM)
+	serverKeyPEM, err := dbmodel.GetSecret(db, dbmodel.SecretServerKey)
+	require.NoError(t, err)
+	require.NotEqualValues(t, oldServerKeyPEM, serverKeyPEM)
+
+	rootCert, err := pki.ParseCert(rootCertPEM)
+	require.NoError(t, err)
+	serverCert, err := pki.ParseCert(serverCertPEM)
+	require.NoError(t, err)
+	require.NoError(t, serverCert.CheckSignatureFrom(rootCert))
+	require.True(t, rotation.NotAfter.Equal(serverCert.NotAfter))
+
+	token, err := dbmodel.GetSecret(db, dbmodel.SecretServerToken)
+	require.NoError(t, err)
+	require.EqualValues(t, oldToken, token)
+
+	// Act
+	rotation, err = RotateServerCert(db, 30, true, false)
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, rotation.TokenBumped)
+	token, err = dbmodel.GetSecret(db, dbmodel.SecretServerToken)
+	require.NoError(t, err)
+	require.NotEqualValues(t, oldToken, token)
+}
+
+// Test that the server key, cert and token are not modified in the
+// dry-run mode.
+func TestRotateServerCertDryRun(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	_, serverCertPEM, serverKeyPEM, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	token, err := dbmodel.GetSecret(db, dbmodel.SecretServerToken)
+	require.NoError(t, err)
+
+	// Act
+	rotation, err := RotateServerCert(db, 90, true, true)
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, rotation.DryRun)
+	require.True(t, rotation.TokenBumped)
+
+	secret, err := dbmodel.GetSecret(db, dbmodel.SecretServerCert)
+	require.NoError(t, err)
+	require.EqualValues(t, serverCertPEM, secret)
+	secret, err = dbmodel.GetSecret(db, dbmodel.SecretServerKey)
+	require.NoError(t, err)
+	require.EqualValues(t, serverKeyPEM, secret)
+	secret, err = dbmodel.GetSecret(db, dbmodel.SecretServerToken)
+	require.NoError(t, err)
+	require.EqualValues(t, token, secret)
+}
+
+// Test that the server cert is not rotated when the root CA key is missing.
+func TestRotateServerCertMissingCAKey(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	_, serverCertPEM, _, err := SetupServerCerts(db)
+	require.NoError(t, err)
+	_, err = db.Model(&dbmodel.Secret{}).Where("name = ?", dbmodel.SecretCAKey).Delete()
+	require.NoError(t, err)
+
+	// Act
+	rotation, err := RotateServerCert(db, 90, false, false)
+
+	// Assert
+	require.ErrorContains(t, err, "CA key is missing in the database")
+	require.Nil(t, rotation)
+	secret, err := dbmodel.GetSecret(db, dbmodel.SecretServerCert)
+	require.NoError(t, err)
+	require.EqualValues(t, serverCertPEM, secret)
+}
+
+// Test that the non-positive validity period is rejected.
+func TestRotateServerCertInvalidValidity(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// Act
+	rotation, err := RotateServerCert(db, 0, false, false)
+
+	// Assert
+	require.ErrorContains(t, err, "must be a positive number of days")
+	require.Nil(t, rotation)
+}
@@ -1,16 +1,36 @@
 package configreview
 
+// Default severity of the issues found by a configuration checker.
+type CheckerSeverity string
+
+// Configuration checker severities.
+const (
+	CheckerSeverityInfo    CheckerSeverity = "info"
+	CheckerSeverityWarning CheckerSeverity = "warning"
+	CheckerSeverityError   CheckerSeverity = "error"
+)
+
+// Human-readable information about a configuration checker. It is
+// attached to the checker during its registration and presented to
+// the users.
+type CheckerInfo struct {
+	Description string
+	Severity    CheckerSeverity
+}
+
 // Represents a configuration checker. It includes a checker name,
-// triggers which can activate this checker and the pointer to the
-// function implementing the checker.
+// triggers which can activate this checker, the pointer to the
+// function implementing the checker and the checker information.
 type checker struct {
 	name     string
 	triggers Triggers
 	checkFn  func(*ReviewContext) (*Report, error)
+	info     CheckerInfo
 }
 
 // Represents current metadata of the configuration checker. It includes a name,
-// triggers, selectors on which the checker was registered, and enable state.
+// triggers, selectors on which the checker was registered, enable state, and
+// the checker information (description and default severity).
 // The checker metadata is valid only for a specific daemon (or globally).
 // It affects the selector list and the state. The enabled property combines
 // the daemon state and the global one. It means that for CheckerStateEnabled,
@@ -22,15 +42,19 @@ type CheckerMetadata struct {
 	Selectors       DispatchGroupSelectors
 	GloballyEnabled bool
 	State           CheckerState
+	Description     string
+	Severity        CheckerSeverity
 }
 
 // Constructs the checker metadata.
-func newCheckerMetadata(name string, triggers Triggers, selectors DispatchGroupSelectors, globallyEnabled bool, state CheckerState) *CheckerMetadata {
+func newCheckerMetadata(name string, triggers Triggers, selectors DispatchGroupSelectors, globallyEnabled bool, state CheckerState, info CheckerInfo) *CheckerMetadata {
 	return &CheckerMetadata{
 		Name:            name,
 		Triggers:        triggers,
 		Selectors:       selectors,
 		GloballyEnabled: globallyEnabled,
 		State:           state,
+		Description:     info.Description,
+		Severity:        info.Severity,
 	}
 }
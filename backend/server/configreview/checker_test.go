@@ -10,7 +10,8 @@ import (
 func TestNewCheckerMetadata(t *testing.T) {
 	// Act
 	metadata := newCheckerMetadata("foo", Triggers{ManualRun, ConfigModified},
-		DispatchGroupSelectors{Bind9Daemon, KeaDHCPv4Daemon}, true, CheckerStateInherit)
+		DispatchGroupSelectors{Bind9Daemon, KeaDHCPv4Daemon}, true, CheckerStateInherit,
+		CheckerInfo{Description: "bar", Severity: CheckerSeverityWarning})
 
 	// Assert
 	require.EqualValues(t, "foo", metadata.Name)
@@ -22,4 +23,6 @@ func TestNewCheckerMetadata(t *testing.T) {
 	require.Len(t, metadata.Selectors, 2)
 	require.True(t, metadata.GloballyEnabled)
 	require.EqualValues(t, CheckerStateInherit, metadata.State)
+	require.Equal(t, "bar", metadata.Description)
+	require.Equal(t, CheckerSeverityWarning, metadata.Severity)
 }
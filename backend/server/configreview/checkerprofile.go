@@ -0,0 +1,215 @@
+package configreview
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Checker profile is a policy specifying the desired global and per-daemon
+// config checker states. It allows for applying the consistent checker
+// states across many Stork servers in one call. The checkers not mentioned
+// in the profile remain unchanged.
+type CheckerProfile struct {
+	// Desired global checker states indexed by checker names. The global
+	// state can be enabled or disabled.
+	Global map[string]CheckerState `json:"global,omitempty"`
+	// Desired checker states indexed by daemon IDs and checker names. The
+	// daemon state can be enabled, disabled or inherit.
+	Daemons map[int64]map[string]CheckerState `json:"daemons,omitempty"`
+}
+
+// Describes a single checker state change made while applying the checker
+// profile.
+type CheckerStateChange struct {
+	CheckerName string `json:"checker"`
+	// ID of the daemon for which the state was changed. It is zero for
+	// the global state.
+	DaemonID int64        `json:"daemonId,omitempty"`
+	OldState CheckerState `json:"oldState"`
+	NewState CheckerState `json:"newState"`
+}
+
+// Parses the checker profile from the JSON document. It returns an error if
+// the document contains unknown fields.
+func ParseCheckerProfile(data []byte) (*CheckerProfile, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	profile := &CheckerProfile{}
+	if err := decoder.Decode(profile); err != nil {
+		return nil, pkgerrors.Wrap(err, "problem parsing the checker profile")
+	}
+	return profile, nil
+}
+
+// Checks if the state is a valid checker state.
+func isValidCheckerState(state CheckerState) bool {
+	switch state {
+	case CheckerStateEnabled, CheckerStateDisabled, CheckerStateInherit:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns the checker state indexed by the checker names.
+func getCheckerStates(metadata []*CheckerMetadata) map[string]CheckerState {
+	states := make(map[string]CheckerState, len(metadata))
+	for _, m := range metadata {
+		states[m.Name] = m.State
+	}
+	return states
+}
+
+// Returns the sorted names of the checkers in the profile section.
+func getSortedCheckerNames(states map[string]CheckerState) []string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Applies the checker profile to the review dispatcher and persists the
+// changed states in the database in one transaction. The profile is
+// validated before applying any changes. It returns an error if the profile
+// contains unknown checkers, unknown daemons, invalid states, or the
+// checkers not registered for a given daemon. It returns the list of the
+// changes. The states which already match the profile are not reported.
+// The global changes are returned first, followed by the changes for the
+// daemons ordered by the daemon IDs. The changes are ordered by the checker
+// names within each group.
+func ApplyCheckerProfile(db dbops.DBI, dispatcher Dispatcher, profile *CheckerProfile) ([]CheckerStateChange, error) {
+	globalMetadata, err := dispatcher.GetCheckersMetadata(nil)
+	if err != nil {
+		return nil, err
+	}
+	globalStates := getCheckerStates(globalMetadata)
+
+	// Validate the checker names first, so the operator gets all unknown
+	// names at once.
+	unknown := make(map[string]bool)
+	for name := range profile.Global {
+		if _, ok := globalStates[name]; !ok {
+			unknown[name] = true
+		}
+	}
+	for _, daemonStates := range profile.Daemons {
+		for name := range daemonStates {
+			if _, ok := globalStates[name]; !ok {
+				unknown[name] = true
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(unknown))
+		for name := range unknown {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, pkgerrors.Errorf("unknown config checkers in the profile: %s", strings.Join(names, ", "))
+	}
+
+	var (
+		changes      []CheckerStateChange
+		daemons      = make(map[int64]*dbmodel.Daemon)
+		updatedPrefs []*dbmodel.ConfigCheckerPreference
+		deletedPrefs []*dbmodel.ConfigCheckerPreference
+	)
+
+	for _, name := range getSortedCheckerNames(profile.Global) {
+		state := profile.Global[name]
+		if state != CheckerStateEnabled && state != CheckerStateDisabled {
+			return nil, pkgerrors.Errorf("invalid global state '%s' of the %s checker; it must be enabled or disabled", state, name)
+		}
+		if globalStates[name] == state {
+			continue
+		}
+		changes = append(changes, CheckerStateChange{
+			CheckerName: name,
+			OldState:    globalStates[name],
+			NewState:    state,
+		})
+		if state == CheckerStateDisabled {
+			updatedPrefs = append(updatedPrefs, dbmodel.NewGlobalConfigCheckerPreference(name))
+		} else {
+			deletedPrefs = append(deletedPrefs, dbmodel.NewGlobalConfigCheckerPreference(name))
+		}
+	}
+
+	daemonIDs := make([]int64, 0, len(profile.Daemons))
+	for daemonID := range profile.Daemons {
+		daemonIDs = append(daemonIDs, daemonID)
+	}
+	sort.Slice(daemonIDs, func(i, j int) bool {
+		return daemonIDs[i] < daemonIDs[j]
+	})
+
+	for _, daemonID := range daemonIDs {
+		daemon, err := dbmodel.GetDaemonByID(db, daemonID)
+		if err != nil {
+			return nil, err
+		}
+		if daemon == nil {
+			return nil, pkgerrors.Errorf("unknown daemon with ID %d in the checker profile", daemonID)
+		}
+		daemons[daemonID] = daemon
+
+		metadata, err := dispatcher.GetCheckersMetadata(daemon)
+		if err != nil {
+			return nil, err
+		}
+		daemonStates := getCheckerStates(metadata)
+
+		for _, name := range getSortedCheckerNames(profile.Daemons[daemonID]) {
+			state := profile.Daemons[daemonID][name]
+			if !isValidCheckerState(state) {
+				return nil, pkgerrors.Errorf("invalid state '%s' of the %s checker for daemon %d", state, name, daemonID)
+			}
+			oldState, ok := daemonStates[name]
+			if !ok {
+				return nil, pkgerrors.Errorf("the %s checker isn't registered to use with the %s daemon %d", name, daemon.Name, daemonID)
+			}
+			if oldState == state {
+				continue
+			}
+			changes = append(changes, CheckerStateChange{
+				CheckerName: name,
+				DaemonID:    daemonID,
+				OldState:    oldState,
+				NewState:    state,
+			})
+			if state == CheckerStateInherit {
+				deletedPrefs = append(deletedPrefs, dbmodel.NewDaemonConfigCheckerPreference(daemonID, name, true))
+			} else {
+				updatedPrefs = append(updatedPrefs, dbmodel.NewDaemonConfigCheckerPreference(daemonID, name, state == CheckerStateEnabled))
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err = dbmodel.CommitCheckerPreferences(db, updatedPrefs, deletedPrefs); err != nil {
+		return nil, err
+	}
+
+	// The profile has been validated, so setting the states should not fail.
+	for _, change := range changes {
+		var daemon *dbmodel.Daemon
+		if change.DaemonID != 0 {
+			daemon = daemons[change.DaemonID]
+		}
+		if err = dispatcher.SetCheckerState(daemon, change.CheckerName, change.NewState); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,190 @@
+package configreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the checker profile is parsed from JSON.
+func TestParseCheckerProfile(t *testing.T) {
+	// Arrange
+	data := []byte(`{
+		"global": { "foo": "disabled" },
+		"daemons": { "5": { "bar": "enabled", "baz": "inherit" } }
+	}`)
+
+	// Act
+	profile, err := ParseCheckerProfile(data)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, profile.Global, 1)
+	require.Equal(t, CheckerStateDisabled, profile.Global["foo"])
+	require.Len(t, profile.Daemons, 1)
+	require.Len(t, profile.Daemons[5], 2)
+	require.Equal(t, CheckerStateEnabled, profile.Daemons[5]["bar"])
+	require.Equal(t, CheckerStateInherit, profile.Daemons[5]["baz"])
+}
+
+// Test that the checker profile with unknown fields is rejected.
+func TestParseCheckerProfileUnknownField(t *testing.T) {
+	// Act
+	profile, err := ParseCheckerProfile([]byte(`{ "checkers": { "foo": "disabled" } }`))
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, profile)
+}
+
+// Creates a Kea DHCPv4 daemon and the dispatcher with test checkers.
+func setupCheckerProfileTest(t *testing.T, db *dbops.PgDB) (*dbmodel.Daemon, Dispatcher) {
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	require.NoError(t, dbmodel.AddMachine(db, machine))
+	app := &dbmodel.App{
+		Type:      dbmodel.AppTypeKea,
+		MachineID: machine.ID,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon("dhcp4", true),
+		},
+	}
+	daemons, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	dispatcher := NewDispatcher(nil)
+	dispatcher.RegisterChecker(KeaDHCPDaemon, "foo", Triggers{ManualRun}, nil)
+	dispatcher.RegisterChecker(KeaDHCPDaemon, "bar", Triggers{ManualRun}, nil)
+	dispatcher.RegisterChecker(KeaDHCPDaemon, "baz", Triggers{ManualRun}, nil)
+	dispatcher.RegisterChecker(Bind9Daemon, "qux", Triggers{ManualRun}, nil)
+	return daemons[0], dispatcher
+}
+
+// Test that the checker profile is applied to the dispatcher and persisted
+// in the database, and only the actual changes are returned.
+func TestApplyCheckerProfile(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon, dispatcher := setupCheckerProfileTest(t, db)
+	require.NoError(t, dispatcher.SetCheckerState(daemon, "baz", CheckerStateDisabled))
+
+	profile := &CheckerProfile{
+		Global: map[string]CheckerState{
+			"foo": CheckerStateDisabled,
+			"bar": CheckerStateEnabled,
+		},
+		Daemons: map[int64]map[string]CheckerState{
+			daemon.ID: {
+				"foo": CheckerStateEnabled,
+				"bar": CheckerStateInherit,
+				"baz": CheckerStateInherit,
+			},
+		},
+	}
+
+	// Act
+	changes, err := ApplyCheckerProfile(db, dispatcher, profile)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []CheckerStateChange{
+		{CheckerName: "foo", OldState: CheckerStateEnabled, NewState: CheckerStateDisabled},
+		{CheckerName: "baz", DaemonID: daemon.ID, OldState: CheckerStateDisabled, NewState: CheckerStateInherit},
+		{CheckerName: "foo", DaemonID: daemon.ID, OldState: CheckerStateInherit, NewState: CheckerStateEnabled},
+	}, changes)
+
+	metadata, _ := dispatcher.GetCheckersMetadata(daemon)
+	states := getCheckerStates(metadata)
+	require.Equal(t, CheckerStateInherit, states["bar"])
+	require.Equal(t, CheckerStateInherit, states["baz"])
+	require.Equal(t, CheckerStateEnabled, states["foo"])
+
+	preferences, err := dbmodel.GetAllCheckerPreferences(db)
+	require.NoError(t, err)
+	require.Len(t, preferences, 2)
+
+	// Act
+	changes, err = ApplyCheckerProfile(db, dispatcher, profile)
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+// Test that the checker profile with unknown checkers is rejected and no
+// changes are applied.
+func TestApplyCheckerProfileUnknownCheckers(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon, dispatcher := setupCheckerProfileTest(t, db)
+
+	profile := &CheckerProfile{
+		Global: map[string]CheckerState{
+			"foo":  CheckerStateDisabled,
+			"oof":  CheckerStateDisabled,
+			"rab1": CheckerStateEnabled,
+		},
+		Daemons: map[int64]map[string]CheckerState{
+			daemon.ID: {
+				"zab": CheckerStateEnabled,
+			},
+		},
+	}
+
+	// Act
+	changes, err := ApplyCheckerProfile(db, dispatcher, profile)
+
+	// Assert
+	require.EqualError(t, err, "unknown config checkers in the profile: oof, rab1, zab")
+	require.Nil(t, changes)
+
+	metadata, _ := dispatcher.GetCheckersMetadata(nil)
+	require.Equal(t, CheckerStateEnabled, getCheckerStates(metadata)["foo"])
+	preferences, _ := dbmodel.GetAllCheckerPreferences(db)
+	require.Empty(t, preferences)
+}
+
+// Test that the checker profile with invalid states, unknown daemons or
+// checkers not registered for a daemon is rejected.
+func TestApplyCheckerProfileInvalid(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon, dispatcher := setupCheckerProfileTest(t, db)
+
+	profiles := map[string]*CheckerProfile{
+		"inherit global state": {
+			Global: map[string]CheckerState{"foo": CheckerStateInherit},
+		},
+		"invalid daemon state": {
+			Daemons: map[int64]map[string]CheckerState{daemon.ID: {"foo": "on"}},
+		},
+		"unknown daemon": {
+			Daemons: map[int64]map[string]CheckerState{daemon.ID + 1: {"foo": CheckerStateEnabled}},
+		},
+		"checker not registered for daemon": {
+			Daemons: map[int64]map[string]CheckerState{daemon.ID: {"qux": CheckerStateEnabled}},
+		},
+	}
+
+	for name, profile := range profiles {
+		profile := profile
+		t.Run(name, func(t *testing.T) {
+			// Act
+			changes, err := ApplyCheckerProfile(db, dispatcher, profile)
+
+			// Assert
+			require.Error(t, err)
+			require.Nil(t, changes)
+		})
+	}
+	preferences, _ := dbmodel.GetAllCheckerPreferences(db)
+	require.Empty(t, preferences)
+}
@@ -21,6 +21,14 @@ import (
 // dispatch groups were not changed.
 const enforceDispatchSeq = 1
 
+// Default maximum number of the configuration reviews performed
+// concurrently by the dispatcher.
+const DefaultReviewWorkers = 4
+
+// Default maximum number of the checkers run concurrently during
+// a review of a single daemon.
+const DefaultCheckerWorkers = 4
+
 // Callback function invoked when configuration review is completed
 // for a daemon. The first argument holds an ID of a daemon for
 // which the review has been performed. The second argument holds an
@@ -74,31 +82,47 @@ type taggedReport struct {
 	report      *Report
 }
 
+// Returns the severity of the report generated by the checker. It is the
+// severity specified by the checker while creating the report or the
+// checker's default severity. The warning severity is returned when
+// neither is specified.
+func getReportSeverity(report *Report, info CheckerInfo) dbmodel.ConfigReportSeverity {
+	if report.severity != "" {
+		return report.severity
+	}
+	if severity := dbmodel.ConfigReportSeverity(info.Severity); severity.IsValid() {
+		return severity
+	}
+	return dbmodel.ConfigReportSeverityWarning
+}
+
 // Review context is valid throughout a review of a daemon configuration.
 // It holds information about the daemons which configurations are
 // reviewed, and the review results output by the review report
 // checkers. Checkers can modify the context information (e.g. fetch
 // additional daemon configurations as necessary). The following
 // fields are available in the context:
-// - db: pointer to the database instance
+// - db: database connection dedicated to the review
 // - subjectDaemon: a daemon for which review is conducted
 // - refDaemons: daemons fetched by the checkers during the review
 // (e.g. daemons which configurations are associated with the subject
 // daemon configuration),
 // - reports: configuration reports produced so far,
+// - checkerNames: names of the checkers run so far,
 // - callback: user callback to invoke after the review,
 // - trigger: a trigger that started the current review.
 type ReviewContext struct {
-	db            *dbops.PgDB
+	db            dbops.DBI
 	subjectDaemon *dbmodel.Daemon
 	refDaemons    []*dbmodel.Daemon
 	reports       []taggedReport
+	checkerNames  []string
 	callback      CallbackFunc
 	trigger       Trigger
 }
 
 // Creates new review context instance.
-func newReviewContext(db *dbops.PgDB, daemon *dbmodel.Daemon, trigger Trigger, callback CallbackFunc) *ReviewContext {
+func newReviewContext(db dbops.DBI, daemon *dbmodel.Daemon, trigger Trigger, callback CallbackFunc) *ReviewContext {
 	ctx := &ReviewContext{
 		db:            db,
 		subjectDaemon: daemon,
@@ -180,6 +204,27 @@ func getDispatchGroupSelectors(daemonName string) DispatchGroupSelectors {
 	return DispatchGroupSelectors{}
 }
 
+// Returns the names of the daemons which configurations are reviewed by
+// the checkers registered for the selectors. The names are ordered.
+func (s DispatchGroupSelectors) GetDaemonNames() (names []string) {
+	selectors := make(map[DispatchGroupSelector]bool, len(s))
+	for _, selector := range s {
+		selectors[selector] = true
+	}
+	for _, daemonName := range []string{
+		dbmodel.DaemonNameDHCPv4, dbmodel.DaemonNameDHCPv6, dbmodel.DaemonNameD2,
+		dbmodel.DaemonNameCA, dbmodel.DaemonNameBind9,
+	} {
+		for _, selector := range getDispatchGroupSelectors(daemonName) {
+			if selectors[selector] {
+				names = append(names, daemonName)
+				break
+			}
+		}
+	}
+	return names
+}
+
 // Dispatch group is a group of checkers registered for the particular
 // dispatch group selector, e.g. for the KeaDHCPv4Daemon. Typically,
 // checkers from multiple dispatch groups are used for reviewing
@@ -246,6 +291,15 @@ func (g *dispatchGroup) hasCheckersForTrigger(trigger Trigger) bool {
 // existing reports for the daemon. More advanced checkers can look
 // into more than one daemon's configuration (e.g., to verify the
 // consistency of the HA configuration between two partners).
+// The reviews of different daemons run in parallel, but the number of
+// concurrent reviews is limited by the number of workers. The checkers
+// of a single review also run in parallel, and their number is limited
+// by the number of checker workers. Each checker worker uses its own
+// database connection. The reports are ordered by the checker
+// registration order regardless of which checkers complete first. The
+// reports are inserted into the database by a single goroutine, and at
+// most one review for a daemon can be in progress, so the reports never
+// conflict.
 type dispatcherImpl struct {
 	// Database instance where configuration reports are stored.
 	db *dbops.PgDB
@@ -257,6 +311,13 @@ type dispatcherImpl struct {
 	shutdownWg *sync.WaitGroup
 	// Wait group used to synchronize the ongoing reviews.
 	reviewWg *sync.WaitGroup
+	// Buffered channel limiting the number of concurrent reviews. Its
+	// capacity is equal to the number of workers. A review occupies
+	// a slot in the channel while it runs the checkers.
+	workerSlots chan struct{}
+	// Maximum number of the checkers run concurrently during a review
+	// of a single daemon.
+	checkerWorkers int
 	// Dispatcher main mutex.
 	mutex *sync.RWMutex
 	// Channel for passing ready review reports to the worker
@@ -279,6 +340,7 @@ type dispatcherImpl struct {
 // require replacing the default implementation with a mock dispatcher.
 type Dispatcher interface {
 	RegisterChecker(selector DispatchGroupSelector, checkerName string, triggers Triggers, checkFn func(*ReviewContext) (*Report, error))
+	RegisterCheckerWithInfo(selector DispatchGroupSelector, checkerName string, triggers Triggers, checkFn func(*ReviewContext) (*Report, error), info CheckerInfo)
 	UnregisterChecker(selector DispatchGroupSelector, checkerName string) bool
 	GetCheckersMetadata(daemon *dbmodel.Daemon) ([]*CheckerMetadata, error)
 	SetCheckerState(daemon *dbmodel.Daemon, checkerName string, state CheckerState) error
@@ -294,7 +356,7 @@ type Dispatcher interface {
 // performed. The trigger as a trigger that started the current
 // review. The callback is a callback function invoked after the
 // review is completed.
-func (d *dispatcherImpl) newContext(db *dbops.PgDB, daemon *dbmodel.Daemon, trigger Trigger, callback CallbackFunc) *ReviewContext {
+func (d *dispatcherImpl) newContext(db dbops.DBI, daemon *dbmodel.Daemon, trigger Trigger, callback CallbackFunc) *ReviewContext {
 	ctx := newReviewContext(db, daemon, trigger, callback)
 	return ctx
 }
@@ -389,7 +451,33 @@ func (d *dispatcherImpl) awaitReports() {
 func (d *dispatcherImpl) runForDaemon(daemon *dbmodel.Daemon, trigger Trigger, dispatchGroupSelectors DispatchGroupSelectors, callback CallbackFunc) {
 	defer d.reviewWg.Done()
 
-	ctx := d.newContext(d.db, daemon, trigger, callback)
+	ctx := d.runCheckers(daemon, trigger, dispatchGroupSelectors, callback)
+
+	// The worker slot and the database connection have been released,
+	// so the next review can start while the reports are inserted.
+	d.reviewDoneChan <- ctx
+}
+
+// Holds the outcome of a single checker run during a review.
+type checkerResult struct {
+	report     *Report
+	refDaemons []*dbmodel.Daemon
+}
+
+// Runs the enabled checkers for a daemon and returns the review context
+// holding the reports. It blocks until a worker slot is available. The
+// checkers are run concurrently by up to checkerWorkers goroutines. Each
+// goroutine acquires a dedicated database connection because a connection
+// must not be shared between the goroutines. The worker slot and the
+// connections are released when the function returns. The reports are
+// appended to the context in the order of the checker registration.
+func (d *dispatcherImpl) runCheckers(daemon *dbmodel.Daemon, trigger Trigger, dispatchGroupSelectors DispatchGroupSelectors, callback CallbackFunc) *ReviewContext {
+	d.workerSlots <- struct{}{}
+	defer func() {
+		<-d.workerSlots
+	}()
+
+	ctx := d.newContext(nil, daemon, trigger, callback)
 
 	// If this is an internal run, the dispatch group selectors haven't
 	// been determined in the beginReview function.
@@ -400,6 +488,7 @@ func (d *dispatcherImpl) runForDaemon(daemon *dbmodel.Daemon, trigger Trigger, d
 		selectors = dispatchGroupSelectors
 	}
 
+	var checkers []*checker
 	for _, selector := range selectors {
 		if group := d.getGroup(selector); group != nil {
 			for _, checker := range group.checkers {
@@ -407,21 +496,72 @@ func (d *dispatcherImpl) runForDaemon(daemon *dbmodel.Daemon, trigger Trigger, d
 					// Skip disabled checker.
 					continue
 				}
-				report, err := checker.checkFn(ctx)
+				ctx.checkerNames = append(ctx.checkerNames, checker.name)
+				checkers = append(checkers, checker)
+			}
+		}
+	}
+
+	// The workers take the indexes of the checkers to run from the channel
+	// and store the results under the same indexes. It preserves the order
+	// of the reports.
+	results := make([]checkerResult, len(checkers))
+	indexes := make(chan int, len(checkers))
+	for i := range checkers {
+		indexes <- i
+	}
+	close(indexes)
+
+	workers := d.checkerWorkers
+	if workers > len(checkers) {
+		workers = len(checkers)
+	}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The database can be nil in the unit tests. Make sure that the
+			// checkers see a nil interface in this case.
+			var db dbops.DBI
+			if d.db != nil {
+				conn := d.db.Conn()
+				defer conn.Close()
+				db = conn
+			}
+			for index := range indexes {
+				// Each checker gets its own copy of the context, so the
+				// checkers can safely reference the daemons.
+				checkerCtx := *ctx
+				checkerCtx.db = db
+				checkerCtx.refDaemons = nil
+
+				checker := checkers[index]
+				report, err := checker.checkFn(&checkerCtx)
 				if err != nil {
 					log.Errorf("Malformed report created by the config review checker %s: %+v",
 						checker.name, err)
 				}
-				if report != nil {
-					ctx.reports = append(ctx.reports, taggedReport{
-						checkerName: checker.name,
-						report:      report,
-					})
+				results[index] = checkerResult{
+					report:     report,
+					refDaemons: checkerCtx.refDaemons,
 				}
 			}
+		}()
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		ctx.refDaemons = append(ctx.refDaemons, result.refDaemons...)
+		if result.report != nil {
+			result.report.severity = getReportSeverity(result.report, checkers[i].info)
+			ctx.reports = append(ctx.reports, taggedReport{
+				checkerName: checkers[i].name,
+				report:      result.report,
+			})
 		}
 	}
-	d.reviewDoneChan <- ctx
+	return ctx
 }
 
 // Checks if the dispatch group has checkers enabled for a specific daemon.
@@ -585,6 +725,7 @@ func (d *dispatcherImpl) populateReports(ctx *ReviewContext) (err error) {
 		cr := &dbmodel.ConfigReport{
 			CheckerName: r.checkerName,
 			Content:     r.report.content,
+			Severity:    r.report.severity,
 			DaemonID:    r.report.daemonID,
 			RefDaemons:  assoc,
 		}
@@ -594,6 +735,29 @@ func (d *dispatcherImpl) populateReports(ctx *ReviewContext) (err error) {
 		}
 	}
 
+	// Record the number of reports generated by each checker. The
+	// summaries accumulate over time to present the configuration
+	// health trends.
+	createdAt := time.Now().UTC()
+	var summaries []*dbmodel.ConfigReportSummary
+	for _, checkerName := range ctx.checkerNames {
+		summary := &dbmodel.ConfigReportSummary{
+			CreatedAt:   createdAt,
+			DaemonID:    ctx.subjectDaemon.ID,
+			CheckerName: checkerName,
+		}
+		for _, r := range ctx.reports {
+			if r.checkerName == checkerName {
+				summary.AddReport(r.report.severity)
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	err = dbmodel.AddConfigReportSummaries(tx, summaries)
+	if err != nil {
+		return
+	}
+
 	// Add configuration review summary.
 	// todo: add config review summary for BIND9. Currently we don't because
 	// BIND 9 does not include a config hash.
@@ -639,14 +803,33 @@ func (d *dispatcherImpl) getGroup(selector DispatchGroupSelector) *dispatchGroup
 	return nil
 }
 
-// Creates new dispatcher instance.
+// Creates new dispatcher instance performing up to DefaultReviewWorkers
+// reviews concurrently and running up to DefaultCheckerWorkers checkers
+// concurrently within each review.
 func NewDispatcher(db *dbops.PgDB) Dispatcher {
+	return NewDispatcherWithWorkers(db, DefaultReviewWorkers, DefaultCheckerWorkers)
+}
+
+// Creates new dispatcher instance performing up to the specified number
+// of reviews concurrently and running up to the specified number of
+// checkers concurrently within each review. The numbers of workers lower
+// than 1 are replaced with 1. The dispatcher uses up to the product of
+// these numbers of database connections.
+func NewDispatcherWithWorkers(db *dbops.PgDB, workers, checkerWorkers int) Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if checkerWorkers < 1 {
+		checkerWorkers = 1
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	dispatcher := &dispatcherImpl{
 		db:                db,
 		groups:            make(map[DispatchGroupSelector]*dispatchGroup),
 		shutdownWg:        &sync.WaitGroup{},
 		reviewWg:          &sync.WaitGroup{},
+		workerSlots:       make(chan struct{}, workers),
+		checkerWorkers:    checkerWorkers,
 		mutex:             &sync.RWMutex{},
 		reviewDoneChan:    make(chan *ReviewContext),
 		dispatchCtx:       ctx,
@@ -662,8 +845,18 @@ func NewDispatcher(db *dbops.PgDB) Dispatcher {
 // a single configuration piece (or aspect) and output a suitable report
 // if it finds issues. It should return nil when no issues were found.
 // Each checker is assigned a unique name so it will be possible to
-// list available checkers and/or selectively disable them.
+// list available checkers and/or selectively disable them. The checker
+// has no description and its default severity is the warning.
 func (d *dispatcherImpl) RegisterChecker(selector DispatchGroupSelector, checkerName string, triggers Triggers, checkFn func(*ReviewContext) (*Report, error)) {
+	d.RegisterCheckerWithInfo(selector, checkerName, triggers, checkFn, CheckerInfo{
+		Severity: CheckerSeverityWarning,
+	})
+}
+
+// Registers new checker with the human-readable information, i.e., the
+// description and default severity. The information is returned in the
+// checker metadata. See RegisterChecker for details.
+func (d *dispatcherImpl) RegisterCheckerWithInfo(selector DispatchGroupSelector, checkerName string, triggers Triggers, checkFn func(*ReviewContext) (*Report, error), info CheckerInfo) {
 	group := d.getGroup(selector)
 	if group == nil {
 		group = newDispatchGroup()
@@ -675,6 +868,7 @@ func (d *dispatcherImpl) RegisterChecker(selector DispatchGroupSelector, checker
 			name:     checkerName,
 			triggers: triggers,
 			checkFn:  checkFn,
+			info:     info,
 		},
 	)
 }
@@ -766,7 +960,7 @@ func (d *dispatcherImpl) GetCheckersMetadata(daemon *dbmodel.Daemon) ([]*Checker
 			state = d.checkerController.getStateForDaemon(daemonID, checker.name)
 		}
 
-		m := newCheckerMetadata(checker.name, checker.triggers, selectors[checker.name], isGloballyEnabled, state)
+		m := newCheckerMetadata(checker.name, checker.triggers, selectors[checker.name], isGloballyEnabled, state, checker.info)
 		metadata[i] = m
 		i++
 	}
@@ -864,15 +1058,157 @@ func (d *dispatcherImpl) ReviewInProgress(daemonID int64) bool {
 }
 
 // Registers default checkers in this package. When new checker is
-// implemented it should be included in this function.
+// implemented it should be included in this function along with its
+// description and default severity.
 func RegisterDefaultCheckers(dispatcher Dispatcher) {
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "stat_cmds_presence", GetDefaultTriggers(), statCmdsPresence)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "host_cmds_presence", GetDefaultTriggers(), hostCmdsPresence)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "dispensable_shared_network", GetDefaultTriggers(), sharedNetworkDispensable)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "dispensable_subnet", ExtendDefaultTriggers(DBHostsModified), subnetDispensable)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "out_of_pool_reservation", ExtendDefaultTriggers(DBHostsModified), reservationsOutOfPool)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "overlapping_subnet", GetDefaultTriggers(), subnetsOverlapping)
-	dispatcher.RegisterChecker(KeaDHCPDaemon, "canonical_prefix", GetDefaultTriggers(), canonicalPrefixes)
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "stat_cmds_presence", GetDefaultTriggers(), statCmdsPresence, CheckerInfo{
+		Description: "The checker verifying if the stat_cmds hooks library is loaded.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "host_cmds_presence", GetDefaultTriggers(), hostCmdsPresence, CheckerInfo{
+		Description: "The checker verifying if the host_cmds hooks library is loaded when host backend is in use.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "lease_cmds_backend_presence", GetDefaultTriggers(), leaseCmdsBackendPresence, CheckerInfo{
+		Description: "The checker verifying if the lease database backend is configured when the lease_cmds hooks library is loaded.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "dispensable_shared_network", GetDefaultTriggers(), sharedNetworkDispensable, CheckerInfo{
+		Description: "The checker verifying if a shared network can be removed because it is empty or contains only one subnet.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "dispensable_subnet", ExtendDefaultTriggers(DBHostsModified), subnetDispensable, CheckerInfo{
+		Description: "The checker verifying if a subnet can be removed because it includes no pools and no reservations.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "out_of_pool_reservation", ExtendDefaultTriggers(DBHostsModified), reservationsOutOfPool, CheckerInfo{
+		Description: "The checker suggesting the use of out-of-pool host reservation mode when there are subnets with all host reservations outside of the dynamic pools.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "overlapping_subnet", GetDefaultTriggers(), subnetsOverlapping, CheckerInfo{
+		Description: "The checker verifying if subnet prefixes do not overlap.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "duplicate_subnet_id", GetDefaultTriggers(), subnetsWithDuplicateIDs, CheckerInfo{
+		Description: "The checker verifying if the subnet IDs are unique.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "canonical_prefix", GetDefaultTriggers(), canonicalPrefixes, CheckerInfo{
+		Description: "The checker verifying if subnet prefixes are in the canonical form.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "overlapping_shared_network_pool", GetDefaultTriggers(), sharedNetworkPoolsOverlapping, CheckerInfo{
+		Description: "The checker verifying if the address pools of the subnets belonging to the same shared network do not overlap.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "overlapping_subnet_pool", GetDefaultTriggers(), subnetPoolsOverlapping, CheckerInfo{
+		Description: "The checker verifying if the address pools within the same subnet do not overlap.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "pool_size_below_minimum", GetDefaultTriggers(), poolSizeBelowMinimum, CheckerInfo{
+		Description: "The checker verifying if the address pools contain at least the number of addresses specified in the min_pool_size setting.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "pool_options_conflict", GetDefaultTriggers(), poolOptionsConflict, CheckerInfo{
+		Description: "The checker verifying if the DHCP options specified for the address pools do not override the subnet-level options with different values.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "shared_network_prefix_length", GetDefaultTriggers(), sharedNetworkPrefixLength, CheckerInfo{
+		Description: "The checker verifying if the subnets belonging to the same shared network have the same prefix length.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "shared_network_daemons_consistency", GetDefaultTriggers(), sharedNetworkDaemonsConsistency, CheckerInfo{
+		Description: "The checker verifying if all subnets belonging to a shared network are served by the same set of daemons.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "subnet_daemons_consistency", GetDefaultTriggers(), subnetDaemonsConsistency, CheckerInfo{
+		Description: "The checker verifying if the subnets served by multiple daemons have the same pools and options on all of them.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "interfaces_config", GetDefaultTriggers(), interfacesConfig, CheckerInfo{
+		Description: "The checker verifying if the DHCP server listens on any interfaces and if it does not listen on all interfaces using the wildcard.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "multi_threading", GetDefaultTriggers(), multiThreading, CheckerInfo{
+		Description: "The checker verifying if the multi-threading settings of the DHCP server are consistent and effective.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "lease_sanity_checks", GetDefaultTriggers(), leaseSanityChecks, CheckerInfo{
+		Description: "The checker verifying if the lease sanity checks are not disabled in the DHCP server configuration.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "in_pool_reservation_mode", GetDefaultTriggers(), reservationsOutsideOfPools, CheckerInfo{
+		Description: "The checker finding the subnets with the host reservations outside of the pools while the in-subnet reservation mode is enabled and the out-of-pool reservation mode is disabled.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "ddns_flags", GetDefaultTriggers(), ddnsFlags, CheckerInfo{
+		Description: "The checker verifying if the combinations of the DDNS flags at the global, shared network and subnet levels are consistent.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "reservation_family_mismatch", GetDefaultTriggers(), reservationsFamilyMismatch, CheckerInfo{
+		Description: "The checker verifying if the host reservations specify the option spaces and the address fields appropriate for the server's family.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv4Daemon, "dns_servers_option", GetDefaultTriggers(), dnsServersOptionPresence, CheckerInfo{
+		Description: "The checker verifying if the DHCPv4 subnets with the address pools have the domain-name-servers option specified at any configuration level.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv4Daemon, "address_space_exhaustion", GetDefaultTriggers(), addressSpaceExhaustion, CheckerInfo{
+		Description: "The checker verifying if the address pools and host reservations of the small DHCPv4 subnets leave at least one address for the router.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv4Daemon, "pool_covers_entire_subnet", GetDefaultTriggers(), poolCoversEntireSubnet, CheckerInfo{
+		Description: "The checker verifying if the address pools of the DHCPv4 subnets exclude the network and broadcast addresses.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv4Daemon, "shared_network_match_client_id", GetDefaultTriggers(), sharedNetworkMatchClientIDConsistency, CheckerInfo{
+		Description: "The checker verifying if the subnets belonging to the same shared network use the same effective match-client-id value.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv6Daemon, "preferred_lifetime", GetDefaultTriggers(), preferredLifetime, CheckerInfo{
+		Description: "The checker verifying if the preferred lifetime of the DHCPv6 subnets is lower than the valid lifetime.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv6Daemon, "dhcp6_subnet_selectors", GetDefaultTriggers(), dhcp6SubnetSelectors, CheckerInfo{
+		Description: "The checker verifying if the DHCPv6 subnets specify the interface, the relay addresses or the interface-id used to select the subnet for the clients.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv6Daemon, "prefix_aggregation", GetDefaultTriggers(), prefixAggregation, CheckerInfo{
+		Description: "The checker suggesting the aggregation of the adjacent DHCPv6 subnets with the same prefix length which form complete larger prefixes.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPv6Daemon, "pd_reservation_delegated_len", ExtendDefaultTriggers(DBHostsModified), prefixReservationsDelegatedLength, CheckerInfo{
+		Description: "The checker verifying if the lengths of the reserved delegated prefixes match the delegated lengths of the prefix delegation pools in their subnets.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "reservation_identifiers_not_listed", ExtendDefaultTriggers(DBHostsModified), reservationIdentifiersNotListed, CheckerInfo{
+		Description: "The checker verifying if the host reservations use the identifier types listed in the host-reservation-identifiers parameter.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "reservation_hostname", ExtendDefaultTriggers(DBHostsModified), reservationHostname, CheckerInfo{
+		Description: "The checker verifying if the hostnames specified in the host reservations are not blank and follow the basic hostname rules.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "reservation_unknown_subnet", ExtendDefaultTriggers(DBHostsModified), reservationsUnknownSubnet, CheckerInfo{
+		Description: "The checker verifying if the host reservations in the host database reference the subnets configured in the DHCP server.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "option_def_standard_conflict", GetDefaultTriggers(), optionDefinitionsStandardConflict, CheckerInfo{
+		Description: "The checker verifying if the option definitions do not redefine the standard DHCP options with different types.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "valid_lifetime_unspecified", GetDefaultTriggers(), validLifetimeUnspecified, CheckerInfo{
+		Description: "The checker verifying if the valid lifetime is specified for the subnets at the subnet, shared network or global level.",
+		Severity:    CheckerSeverityInfo,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaCADaemon, "ca_basic_auth_realm", GetDefaultTriggers(), basicAuthRealm, CheckerInfo{
+		Description: "The checker verifying if the Kea Control Agent enabling the basic HTTP authentication specifies the authentication realm.",
+		Severity:    CheckerSeverityWarning,
+	})
+	dispatcher.RegisterCheckerWithInfo(KeaDaemon, "logger_file_output_systemd", GetDefaultTriggers(), loggersFileOutputUnderSystemd, CheckerInfo{
+		Description: "The checker verifying if the Kea loggers write to the files while the daemon appears to run under systemd, which captures the output in the journal.",
+		Severity:    CheckerSeverityInfo,
+	})
 }
 
 // Fetches all checker preferences from the database and loads them into
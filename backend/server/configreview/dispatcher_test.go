@@ -2,15 +2,128 @@ package configreview
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	dbops "isc.org/stork/server/database"
 	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
 )
 
+// Test that the report severity specified by the checker takes precedence
+// over the default checker severity.
+func TestGetReportSeverity(t *testing.T) {
+	report := &Report{}
+	require.Equal(t, dbmodel.ConfigReportSeverityInfo,
+		getReportSeverity(report, CheckerInfo{Severity: CheckerSeverityInfo}))
+	require.Equal(t, dbmodel.ConfigReportSeverityWarning,
+		getReportSeverity(report, CheckerInfo{}))
+
+	report.severity = dbmodel.ConfigReportSeverityError
+	require.Equal(t, dbmodel.ConfigReportSeverityError,
+		getReportSeverity(report, CheckerInfo{Severity: CheckerSeverityInfo}))
+}
+
+// Test that the reports generated during the review have the default
+// severities of the checkers unless the checkers specify the severities.
+func TestRunCheckersReportSeverity(t *testing.T) {
+	// Arrange
+	dispatcher := NewDispatcher(nil).(*dispatcherImpl)
+	dispatcher.RegisterCheckerWithInfo(EachDaemon, "info", Triggers{ManualRun}, func(ctx *ReviewContext) (*Report, error) {
+		return NewReport(ctx, "info report").create()
+	}, CheckerInfo{Severity: CheckerSeverityInfo})
+	dispatcher.RegisterCheckerWithInfo(EachDaemon, "escalated", Triggers{ManualRun}, func(ctx *ReviewContext) (*Report, error) {
+		return NewReport(ctx, "error report").withSeverity(dbmodel.ConfigReportSeverityError).create()
+	}, CheckerInfo{Severity: CheckerSeverityInfo})
+	dispatcher.RegisterChecker(EachDaemon, "default", Triggers{ManualRun}, func(ctx *ReviewContext) (*Report, error) {
+		return NewReport(ctx, "warning report").create()
+	})
+	daemon := &dbmodel.Daemon{ID: 1, Name: dbmodel.DaemonNameDHCPv4}
+
+	// Act
+	ctx := dispatcher.runCheckers(daemon, ManualRun, getDispatchGroupSelectors(daemon.Name), nil)
+
+	// Assert
+	require.Len(t, ctx.reports, 3)
+	require.Equal(t, dbmodel.ConfigReportSeverityInfo, ctx.reports[0].report.severity)
+	require.Equal(t, dbmodel.ConfigReportSeverityError, ctx.reports[1].report.severity)
+	require.Equal(t, dbmodel.ConfigReportSeverityWarning, ctx.reports[2].report.severity)
+}
+
+// Test that the checkers of a single review run concurrently, their number
+// does not exceed the number of checker workers, and the reports are
+// ordered by the checker registration order.
+func TestRunCheckersConcurrently(t *testing.T) {
+	// Arrange
+	dispatcher := NewDispatcherWithWorkers(nil, 1, 3).(*dispatcherImpl)
+
+	// The checkers record the maximum number of the concurrently running
+	// checkers. The first checkers take the longest, so they complete last.
+	var running, maxRunning int32
+	for i := 0; i < 10; i++ {
+		delay := time.Duration(10-i) * 2 * time.Millisecond
+		content := fmt.Sprintf("report %d", i)
+		dispatcher.RegisterChecker(EachDaemon, fmt.Sprintf("checker_%d", i), Triggers{ManualRun}, func(ctx *ReviewContext) (*Report, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prevMax := atomic.LoadInt32(&maxRunning)
+				if current <= prevMax || atomic.CompareAndSwapInt32(&maxRunning, prevMax, current) {
+					break
+				}
+			}
+			time.Sleep(delay)
+			return NewReport(ctx, content).referencingDaemon(ctx.subjectDaemon).create()
+		})
+	}
+	daemon := &dbmodel.Daemon{ID: 1, Name: dbmodel.DaemonNameDHCPv4}
+
+	// Act
+	ctx := dispatcher.runCheckers(daemon, ManualRun, getDispatchGroupSelectors(daemon.Name), nil)
+
+	// Assert
+	require.Greater(t, atomic.LoadInt32(&maxRunning), int32(1))
+	require.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+	require.Len(t, ctx.checkerNames, 10)
+	require.Len(t, ctx.reports, 10)
+	for i, report := range ctx.reports {
+		require.Equal(t, fmt.Sprintf("checker_%d", i), ctx.checkerNames[i])
+		require.Equal(t, fmt.Sprintf("checker_%d", i), report.checkerName)
+		require.Equal(t, fmt.Sprintf("report %d", i), report.report.content)
+	}
+}
+
+// Test that the daemons referenced by the concurrently running checkers
+// are collected in the checker registration order.
+func TestRunCheckersConcurrentlyRefDaemons(t *testing.T) {
+	// Arrange
+	dispatcher := NewDispatcherWithWorkers(nil, 1, 4).(*dispatcherImpl)
+	for i := 0; i < 4; i++ {
+		refDaemon := &dbmodel.Daemon{ID: int64(i + 2)}
+		delay := time.Duration(4-i) * 2 * time.Millisecond
+		dispatcher.RegisterChecker(EachDaemon, fmt.Sprintf("checker_%d", i), Triggers{ManualRun}, func(ctx *ReviewContext) (*Report, error) {
+			time.Sleep(delay)
+			ctx.refDaemons = append(ctx.refDaemons, refDaemon)
+			return nil, nil
+		})
+	}
+	daemon := &dbmodel.Daemon{ID: 1, Name: dbmodel.DaemonNameDHCPv4}
+
+	// Act
+	ctx := dispatcher.runCheckers(daemon, ManualRun, getDispatchGroupSelectors(daemon.Name), nil)
+
+	// Assert
+	require.Empty(t, ctx.reports)
+	require.Len(t, ctx.refDaemons, 4)
+	for i, refDaemon := range ctx.refDaemons {
+		require.EqualValues(t, i+2, refDaemon.ID)
+	}
+}
+
 // Tests creating new dispatcher instance.
 func TestNewDispatcher(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -22,6 +135,8 @@ func TestNewDispatcher(t *testing.T) {
 	require.NotNil(t, dispatcher.groups)
 	require.NotNil(t, dispatcher.shutdownWg)
 	require.NotNil(t, dispatcher.reviewWg)
+	require.Equal(t, DefaultReviewWorkers, cap(dispatcher.workerSlots))
+	require.Equal(t, DefaultCheckerWorkers, dispatcher.checkerWorkers)
 	require.NotNil(t, dispatcher.mutex)
 	require.NotNil(t, dispatcher.reviewDoneChan)
 	require.NotNil(t, dispatcher.dispatchCtx)
@@ -30,6 +145,21 @@ func TestNewDispatcher(t *testing.T) {
 	require.NotNil(t, dispatcher.checkerController)
 }
 
+// Tests creating new dispatcher instance with the specified number of
+// workers.
+func TestNewDispatcherWithWorkers(t *testing.T) {
+	dispatcher := NewDispatcherWithWorkers(nil, 7, 5).(*dispatcherImpl)
+	require.NotNil(t, dispatcher)
+	require.Equal(t, 7, cap(dispatcher.workerSlots))
+	require.Equal(t, 5, dispatcher.checkerWorkers)
+
+	// At least one worker is required.
+	dispatcher = NewDispatcherWithWorkers(nil, 0, 0).(*dispatcherImpl)
+	require.NotNil(t, dispatcher)
+	require.Equal(t, 1, cap(dispatcher.workerSlots))
+	require.Equal(t, 1, dispatcher.checkerWorkers)
+}
+
 // Tests the whole lifecycle of the dispatcher. In particular, it verifies that
 // scheduled reviews are completed after stopping the dispatcher, and that the
 // stop function waits for them.
@@ -37,12 +167,14 @@ func TestGracefulShutdown(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
 	defer teardown()
 
-	// Create new dispatcher.
-	dispatcher := NewDispatcher(db)
-	require.NotNil(t, dispatcher)
-
 	// We will simulate reviews for all daemon types.
 	daemonNames := []string{"dhcp4", "dhcp6", "ca", "d2", "named"}
+
+	// Create new dispatcher. All reviews must run concurrently because
+	// the test controls the order in which they complete.
+	dispatcher := NewDispatcherWithWorkers(db, len(daemonNames), DefaultCheckerWorkers)
+	require.NotNil(t, dispatcher)
+
 	// Selectors must correspond to the daemons above.
 	selectors := []DispatchGroupSelector{
 		KeaDHCPv4Daemon,
@@ -229,6 +361,16 @@ func TestPopulateKeaReports(t *testing.T) {
 	require.NotEmpty(t, review.ConfigHash)
 	require.NotEmpty(t, review.Signature)
 
+	// Ensure that the report summary for the first daemon has been inserted.
+	summaries, err := dbmodel.GetReportSummaryHistory(db, daemons[0].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Equal(t, "dhcp4_test_checker", summaries[0].CheckerName)
+	require.EqualValues(t, 1, summaries[0].ReportCount)
+	require.EqualValues(t, 1, summaries[0].WarningCount)
+	require.Zero(t, summaries[0].InfoCount)
+	require.Zero(t, summaries[0].ErrorCount)
+
 	// Ensure that the reports for the second daemon have not been inserted.
 	reports, total, err = dbmodel.GetConfigReportsByDaemonID(db, 0, 0, daemons[1].ID)
 	require.NoError(t, err)
@@ -238,6 +380,30 @@ func TestPopulateKeaReports(t *testing.T) {
 	review, err = dbmodel.GetConfigReviewByDaemonID(db, daemons[1].ID)
 	require.NoError(t, err)
 	require.Nil(t, review)
+
+	summaries, err = dbmodel.GetReportSummaryHistory(db, daemons[1].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+
+	// Run another review for the first daemon. The summaries should
+	// accumulate.
+	wg.Add(1)
+	ok = dispatcher.BeginReview(daemons[0], ManualRun, func(daemonID int64, err error) {
+		defer wg.Done()
+		innerErrors[0] = err
+	})
+	require.True(t, ok)
+	wg.Wait()
+	require.NoError(t, innerErrors[0])
+
+	summaries, err = dbmodel.GetReportSummaryHistory(db, daemons[0].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	for _, summary := range summaries {
+		require.Equal(t, "dhcp4_test_checker", summary.CheckerName)
+		require.EqualValues(t, 1, summary.ReportCount)
+	}
+	require.False(t, summaries[0].CreatedAt.After(summaries[1].CreatedAt))
 }
 
 // Tests that the configuration reviews for the BIND9 daemon are populated
@@ -651,9 +817,29 @@ func TestRegisterDefaultCheckers(t *testing.T) {
 	}
 	require.Contains(t, checkerNames, "stat_cmds_presence")
 	require.Contains(t, checkerNames, "host_cmds_presence")
+	require.Contains(t, checkerNames, "lease_cmds_backend_presence")
 	require.Contains(t, checkerNames, "dispensable_shared_network")
 	require.Contains(t, checkerNames, "dispensable_subnet")
 	require.Contains(t, checkerNames, "out_of_pool_reservation")
+	require.Contains(t, checkerNames, "overlapping_shared_network_pool")
+	require.Contains(t, checkerNames, "overlapping_subnet_pool")
+	require.Contains(t, checkerNames, "pool_size_below_minimum")
+	require.Contains(t, checkerNames, "pool_options_conflict")
+	require.Contains(t, checkerNames, "shared_network_prefix_length")
+	require.Contains(t, checkerNames, "shared_network_daemons_consistency")
+	require.Contains(t, checkerNames, "subnet_daemons_consistency")
+	require.Contains(t, checkerNames, "interfaces_config")
+	require.Contains(t, checkerNames, "multi_threading")
+	require.Contains(t, checkerNames, "lease_sanity_checks")
+	require.Contains(t, checkerNames, "in_pool_reservation_mode")
+	require.Contains(t, checkerNames, "ddns_flags")
+	require.Contains(t, checkerNames, "reservation_family_mismatch")
+	require.Contains(t, checkerNames, "duplicate_subnet_id")
+	require.Contains(t, checkerNames, "reservation_identifiers_not_listed")
+	require.Contains(t, checkerNames, "option_def_standard_conflict")
+	require.Contains(t, checkerNames, "valid_lifetime_unspecified")
+	require.Contains(t, checkerNames, "reservation_hostname")
+	require.Contains(t, checkerNames, "reservation_unknown_subnet")
 
 	// Ensure that the appropriate triggers were registered for the
 	// default checkers.
@@ -661,9 +847,51 @@ func TestRegisterDefaultCheckers(t *testing.T) {
 	require.Contains(t, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts, ConfigModified)
 	require.Contains(t, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts, DBHostsModified)
 
-	require.EqualValues(t, 7, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[ManualRun])
-	require.EqualValues(t, 7, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[ConfigModified])
-	require.EqualValues(t, 2, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[DBHostsModified])
+	require.EqualValues(t, 27, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[ManualRun])
+	require.EqualValues(t, 27, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[ConfigModified])
+	require.EqualValues(t, 5, dispatcher.groups[KeaDHCPDaemon].triggerRefCounts[DBHostsModified])
+
+	// KeaDHCPv4Daemon group.
+	require.Contains(t, dispatcher.groups, KeaDHCPv4Daemon)
+	checkerNames = []string{}
+	for _, p := range dispatcher.groups[KeaDHCPv4Daemon].checkers {
+		checkerNames = append(checkerNames, p.name)
+	}
+	require.Contains(t, checkerNames, "dns_servers_option")
+	require.Contains(t, checkerNames, "address_space_exhaustion")
+	require.Contains(t, checkerNames, "pool_covers_entire_subnet")
+	require.Contains(t, checkerNames, "shared_network_match_client_id")
+
+	// KeaDHCPv6Daemon group.
+	require.Contains(t, dispatcher.groups, KeaDHCPv6Daemon)
+	checkerNames = []string{}
+	for _, p := range dispatcher.groups[KeaDHCPv6Daemon].checkers {
+		checkerNames = append(checkerNames, p.name)
+	}
+	require.Contains(t, checkerNames, "preferred_lifetime")
+	require.Contains(t, checkerNames, "dhcp6_subnet_selectors")
+	require.Contains(t, checkerNames, "pd_reservation_delegated_len")
+	require.Contains(t, checkerNames, "prefix_aggregation")
+
+	require.EqualValues(t, 4, dispatcher.groups[KeaDHCPv6Daemon].triggerRefCounts[ManualRun])
+	require.EqualValues(t, 4, dispatcher.groups[KeaDHCPv6Daemon].triggerRefCounts[ConfigModified])
+	require.EqualValues(t, 1, dispatcher.groups[KeaDHCPv6Daemon].triggerRefCounts[DBHostsModified])
+
+	// KeaCADaemon group.
+	require.Contains(t, dispatcher.groups, KeaCADaemon)
+	checkerNames = []string{}
+	for _, p := range dispatcher.groups[KeaCADaemon].checkers {
+		checkerNames = append(checkerNames, p.name)
+	}
+	require.Contains(t, checkerNames, "ca_basic_auth_realm")
+
+	// KeaDaemon group.
+	require.Contains(t, dispatcher.groups, KeaDaemon)
+	checkerNames = []string{}
+	for _, p := range dispatcher.groups[KeaDaemon].checkers {
+		checkerNames = append(checkerNames, p.name)
+	}
+	require.Contains(t, checkerNames, "logger_file_output_systemd")
 }
 
 // Verifies that registering new checkers and bumping up the
@@ -816,6 +1044,66 @@ func TestGetCheckersMetadata(t *testing.T) {
 	require.Nil(t, metadataUnknown)
 }
 
+// Test that the checker metadata include the information attached to
+// the checkers during the registration.
+func TestGetCheckersMetadataInfo(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dispatcher := NewDispatcher(db)
+	dispatcher.RegisterCheckerWithInfo(KeaDHCPDaemon, "foo", GetDefaultTriggers(), nil, CheckerInfo{
+		Description: "The foo checker.",
+		Severity:    CheckerSeverityError,
+	})
+	dispatcher.RegisterChecker(KeaDHCPDaemon, "bar", GetDefaultTriggers(), nil)
+
+	// Act
+	metadata, err := dispatcher.GetCheckersMetadata(nil)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, metadata, 2)
+	require.EqualValues(t, "bar", metadata[0].Name)
+	require.Empty(t, metadata[0].Description)
+	require.Equal(t, CheckerSeverityWarning, metadata[0].Severity)
+	require.EqualValues(t, "foo", metadata[1].Name)
+	require.Equal(t, "The foo checker.", metadata[1].Description)
+	require.Equal(t, CheckerSeverityError, metadata[1].Severity)
+}
+
+// Test that all default checkers are registered with the description
+// and severity.
+func TestRegisterDefaultCheckersInfo(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dispatcher := NewDispatcher(db)
+
+	// Act
+	RegisterDefaultCheckers(dispatcher)
+
+	// Assert
+	metadata, err := dispatcher.GetCheckersMetadata(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, metadata)
+	for _, m := range metadata {
+		require.NotEmpty(t, m.Description, m.Name)
+		require.Contains(t, []CheckerSeverity{
+			CheckerSeverityInfo, CheckerSeverityWarning, CheckerSeverityError,
+		}, m.Severity, m.Name)
+	}
+}
+
+// Test that the daemon names are returned for the dispatch group selectors.
+func TestDispatchGroupSelectorsGetDaemonNames(t *testing.T) {
+	require.Equal(t, []string{"dhcp4", "dhcp6"}, DispatchGroupSelectors{KeaDHCPDaemon}.GetDaemonNames())
+	require.Equal(t, []string{"dhcp4"}, DispatchGroupSelectors{KeaDHCPv4Daemon}.GetDaemonNames())
+	require.Equal(t, []string{"dhcp4", "dhcp6", "d2", "ca"}, DispatchGroupSelectors{KeaDaemon}.GetDaemonNames())
+	require.Equal(t, []string{"dhcp4", "dhcp6", "d2", "ca", "named"}, DispatchGroupSelectors{EachDaemon}.GetDaemonNames())
+	require.Equal(t, []string{"ca", "named"}, DispatchGroupSelectors{Bind9Daemon, KeaCADaemon}.GetDaemonNames())
+	require.Empty(t, DispatchGroupSelectors{}.GetDaemonNames())
+}
+
 // Test that the checker state are loaded and validated properly.
 func TestLoadAndValidateCheckerState(t *testing.T) {
 	// Arrange
@@ -983,3 +1271,152 @@ func TestSetCheckerStateToInvalidValue(t *testing.T) {
 	require.Error(t, err1)
 	require.Error(t, err2)
 }
+
+// Adds an app with the specified number of DHCPv4 daemons to the database
+// and returns the daemons.
+func addDaemonsForConcurrentReviews(tb testing.TB, db *dbops.PgDB, count int) []*dbmodel.Daemon {
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := dbmodel.AddMachine(db, machine)
+	require.NoError(tb, err)
+
+	var daemons []*dbmodel.Daemon
+	for i := 0; i < count; i++ {
+		config, err := dbmodel.NewKeaConfigFromJSON(`{"Dhcp4": { }}`)
+		require.NoError(tb, err)
+		app := &dbmodel.App{
+			Type:      dbmodel.AppTypeKea,
+			MachineID: machine.ID,
+			Name:      fmt.Sprintf("kea%d", i),
+			Daemons: []*dbmodel.Daemon{
+				{
+					Name:   "dhcp4",
+					Active: true,
+					KeaDaemon: &dbmodel.KeaDaemon{
+						Config:     config,
+						ConfigHash: fmt.Sprintf("hash%d", i),
+					},
+				},
+			},
+		}
+		addedDaemons, err := dbmodel.AddApp(db, app)
+		require.NoError(tb, err)
+		daemons = append(daemons, addedDaemons...)
+	}
+	return daemons
+}
+
+// Test that the reviews of many daemons run concurrently, their number
+// does not exceed the number of workers, and all daemons are reviewed.
+func TestConcurrentReviews(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	daemons := addDaemonsForConcurrentReviews(t, db, 20)
+
+	dispatcher := NewDispatcherWithWorkers(db, 3, DefaultCheckerWorkers)
+	require.NotNil(t, dispatcher)
+
+	// The checker records the maximum number of the concurrently running
+	// reviews and the reviewed daemons. It also queries the database to
+	// make sure that each review can use its connection.
+	var running, maxRunning int32
+	mutex := &sync.Mutex{}
+	reviewed := make(map[int64]int)
+	dispatcher.RegisterChecker(KeaDHCPv4Daemon, "test_checker", GetDefaultTriggers(), func(ctx *ReviewContext) (*Report, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			prevMax := atomic.LoadInt32(&maxRunning)
+			if current <= prevMax || atomic.CompareAndSwapInt32(&maxRunning, prevMax, current) {
+				break
+			}
+		}
+
+		_, err := dbmodel.GetDaemonByID(ctx.db, ctx.subjectDaemon.ID)
+		if err != nil {
+			return nil, err
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		reviewed[ctx.subjectDaemon.ID]++
+		mutex.Unlock()
+		return NewReport(ctx, "test output").create()
+	})
+
+	dispatcher.Start()
+	defer dispatcher.Shutdown()
+
+	wg := &sync.WaitGroup{}
+	var callbackErrors []error
+	for _, daemon := range daemons {
+		wg.Add(1)
+		ok := dispatcher.BeginReview(daemon, ConfigModified, func(daemonID int64, err error) {
+			defer wg.Done()
+			if err != nil {
+				mutex.Lock()
+				callbackErrors = append(callbackErrors, err)
+				mutex.Unlock()
+			}
+		})
+		require.True(t, ok)
+	}
+	wg.Wait()
+
+	require.Empty(t, callbackErrors)
+	require.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+
+	// Each daemon should have been reviewed exactly once and have its
+	// report in the database.
+	require.Len(t, reviewed, len(daemons))
+	for _, daemon := range daemons {
+		require.Equal(t, 1, reviewed[daemon.ID])
+		reports, total, err := dbmodel.GetConfigReportsByDaemonID(db, 0, 0, daemon.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, total)
+		require.Equal(t, "test output", reports[0].Content)
+	}
+}
+
+// Benchmark measuring the throughput of the configuration reviews of
+// many daemons depending on the number of workers. The checker simulates
+// a database-backed checker that spends some time waiting for the
+// database.
+func BenchmarkConcurrentReviews(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			db, _, teardown := dbtest.SetupDatabaseTestCase(b)
+			defer teardown()
+
+			daemons := addDaemonsForConcurrentReviews(b, db, 16)
+
+			dispatcher := NewDispatcherWithWorkers(db, workers, DefaultCheckerWorkers)
+			dispatcher.RegisterChecker(KeaDHCPv4Daemon, "test_checker", GetDefaultTriggers(), func(ctx *ReviewContext) (*Report, error) {
+				_, err := ctx.db.Exec("SELECT pg_sleep(0.005)")
+				if err != nil {
+					return nil, err
+				}
+				return NewReport(ctx, "test output").create()
+			})
+			dispatcher.Start()
+			defer dispatcher.Shutdown()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				wg := &sync.WaitGroup{}
+				for _, daemon := range daemons {
+					wg.Add(1)
+					if !dispatcher.BeginReview(daemon, ConfigModified, func(daemonID int64, err error) {
+						defer wg.Done()
+					}) {
+						wg.Done()
+					}
+				}
+				wg.Wait()
+			}
+		})
+	}
+}
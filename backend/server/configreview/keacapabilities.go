@@ -0,0 +1,129 @@
+package configreview
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Pattern matching the Kea version, e.g., 2.2.0 or 2.3.1-git. The
+// suffix following the patch number is ignored.
+var keaVersionPattern = regexp.MustCompile(`^\s*(\d+)\.(\d+)\.(\d+)`)
+
+// Kea software version.
+type keaVersion struct {
+	major int
+	minor int
+	patch int
+}
+
+// Parses the Kea version reported by the Kea daemon.
+func parseKeaVersion(version string) (keaVersion, error) {
+	match := keaVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return keaVersion{}, errors.Errorf("invalid Kea version %s", version)
+	}
+	var numbers [3]int
+	for i := range numbers {
+		number, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return keaVersion{}, errors.Wrapf(err, "invalid Kea version %s", version)
+		}
+		numbers[i] = number
+	}
+	return keaVersion{numbers[0], numbers[1], numbers[2]}, nil
+}
+
+// Checks if the version is equal to or later than the other version.
+func (v keaVersion) isAtLeast(other keaVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+	if v.minor != other.minor {
+		return v.minor > other.minor
+	}
+	return v.patch >= other.patch
+}
+
+// Features of the Kea DHCP servers whose availability depends on the
+// Kea version. The checkers use them to avoid the reports that are not
+// applicable to the reviewed Kea version.
+type KeaCapabilities struct {
+	// The reservations-global, reservations-in-subnet and
+	// reservations-out-of-pool flags superseding the reservation-mode
+	// are supported since Kea 1.9.1.
+	ReservationFlags bool
+	// The multi-threading is supported since Kea 1.8.0.
+	MultiThreading bool
+	// The multi-threading is enabled by default since Kea 2.3.5.
+	MultiThreadingEnabledByDefault bool
+	// The lease caching controlled by the cache-threshold and the
+	// cache-max-age parameters is supported since Kea 1.9.2.
+	LeaseCaching bool
+}
+
+// Returns the capabilities of the specified Kea version.
+func newKeaCapabilities(version keaVersion) *KeaCapabilities {
+	return &KeaCapabilities{
+		ReservationFlags:               version.isAtLeast(keaVersion{1, 9, 1}),
+		MultiThreading:                 version.isAtLeast(keaVersion{1, 8, 0}),
+		MultiThreadingEnabledByDefault: version.isAtLeast(keaVersion{2, 3, 5}),
+		LeaseCaching:                   version.isAtLeast(keaVersion{1, 9, 2}),
+	}
+}
+
+// Registry of the Kea capabilities indexed by the Kea versions. The
+// capabilities are computed once per version and cached because the
+// checkers query them for each reviewed daemon. The registry is safe
+// for concurrent use.
+type keaCapabilitiesRegistry struct {
+	mutex        sync.Mutex
+	capabilities map[keaVersion]*KeaCapabilities
+}
+
+// Creates new empty registry.
+func newKeaCapabilitiesRegistry() *keaCapabilitiesRegistry {
+	return &keaCapabilitiesRegistry{
+		capabilities: make(map[keaVersion]*KeaCapabilities),
+	}
+}
+
+// Returns the capabilities of the specified Kea version. It returns an
+// error if the version cannot be parsed. The returned structure is a
+// copy, so the caller may modify it.
+func (r *keaCapabilitiesRegistry) get(version string) (*KeaCapabilities, error) {
+	parsedVersion, err := parseKeaVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	capabilities, ok := r.capabilities[parsedVersion]
+	if !ok {
+		capabilities = newKeaCapabilities(parsedVersion)
+		r.capabilities[parsedVersion] = capabilities
+	}
+	capabilitiesCopy := *capabilities
+	return &capabilitiesCopy, nil
+}
+
+// Registry of the Kea capabilities shared by all checkers.
+var keaCapabilities = newKeaCapabilitiesRegistry()
+
+// Returns the capabilities of the Kea version reported by the reviewed
+// daemon. It returns an error if the daemon is not a Kea daemon or its
+// version is unknown.
+func (ctx *ReviewContext) getKeaCapabilities() (*KeaCapabilities, error) {
+	if ctx.subjectDaemon.KeaDaemon == nil {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+	capabilities, err := keaCapabilities.get(ctx.subjectDaemon.Version)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "problem getting capabilities of the Kea %s daemon", ctx.subjectDaemon.Name)
+	}
+	return capabilities, nil
+}
@@ -0,0 +1,165 @@
+package configreview
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Test that the Kea versions are parsed correctly.
+func TestParseKeaVersion(t *testing.T) {
+	testCases := map[string]keaVersion{
+		"2.2.0":            {2, 2, 0},
+		"1.9.10":           {1, 9, 10},
+		"2.3.1-git":        {2, 3, 1},
+		" 2.5.0-isc202311": {2, 5, 0},
+		"2.4.1 (tarball)":  {2, 4, 1},
+	}
+	for version, expected := range testCases {
+		parsed, err := parseKeaVersion(version)
+		require.NoError(t, err, version)
+		require.Equal(t, expected, parsed, version)
+	}
+}
+
+// Test that an error is returned for invalid Kea versions.
+func TestParseKeaVersionInvalid(t *testing.T) {
+	for _, version := range []string{"", "2", "2.2", "foo", "v2.2.0", "2.x.0"} {
+		_, err := parseKeaVersion(version)
+		require.Error(t, err, version)
+	}
+}
+
+// Test that the Kea versions are compared correctly.
+func TestKeaVersionIsAtLeast(t *testing.T) {
+	require.True(t, keaVersion{1, 9, 1}.isAtLeast(keaVersion{1, 9, 1}))
+	require.True(t, keaVersion{1, 9, 10}.isAtLeast(keaVersion{1, 9, 2}))
+	require.True(t, keaVersion{1, 10, 0}.isAtLeast(keaVersion{1, 9, 10}))
+	require.True(t, keaVersion{2, 0, 0}.isAtLeast(keaVersion{1, 10, 10}))
+	require.False(t, keaVersion{1, 9, 0}.isAtLeast(keaVersion{1, 9, 1}))
+	require.False(t, keaVersion{1, 8, 10}.isAtLeast(keaVersion{1, 9, 0}))
+	require.False(t, keaVersion{1, 10, 10}.isAtLeast(keaVersion{2, 0, 0}))
+}
+
+// Test that the sample Kea versions are mapped to the expected
+// capabilities.
+func TestKeaCapabilitiesRegistry(t *testing.T) {
+	registry := newKeaCapabilitiesRegistry()
+
+	testCases := map[string]KeaCapabilities{
+		"1.6.3":  {},
+		"1.7.10": {},
+		"1.8.2": {
+			MultiThreading: true,
+		},
+		"1.9.1": {
+			ReservationFlags: true,
+			MultiThreading:   true,
+		},
+		"1.9.2": {
+			ReservationFlags: true,
+			MultiThreading:   true,
+			LeaseCaching:     true,
+		},
+		"2.2.0": {
+			ReservationFlags: true,
+			MultiThreading:   true,
+			LeaseCaching:     true,
+		},
+		"2.3.5-git": {
+			ReservationFlags:               true,
+			MultiThreading:                 true,
+			MultiThreadingEnabledByDefault: true,
+			LeaseCaching:                   true,
+		},
+		"2.4.0": {
+			ReservationFlags:               true,
+			MultiThreading:                 true,
+			MultiThreadingEnabledByDefault: true,
+			LeaseCaching:                   true,
+		},
+	}
+	for version, expected := range testCases {
+		capabilities, err := registry.get(version)
+		require.NoError(t, err, version)
+		require.NotNil(t, capabilities, version)
+		require.Equal(t, expected, *capabilities, version)
+	}
+	require.Len(t, registry.capabilities, len(testCases))
+}
+
+// Test that the capabilities are cached per version and that the
+// returned capabilities can be modified without affecting the cache.
+func TestKeaCapabilitiesRegistryCache(t *testing.T) {
+	registry := newKeaCapabilitiesRegistry()
+
+	capabilities, err := registry.get("2.2.0")
+	require.NoError(t, err)
+	require.True(t, capabilities.ReservationFlags)
+	capabilities.ReservationFlags = false
+
+	// The same version with a different suffix shares the cache entry.
+	capabilities, err = registry.get("2.2.0-git")
+	require.NoError(t, err)
+	require.True(t, capabilities.ReservationFlags)
+	require.Len(t, registry.capabilities, 1)
+
+	_, err = registry.get("foo")
+	require.Error(t, err)
+	require.Len(t, registry.capabilities, 1)
+}
+
+// Test that the registry can be queried concurrently.
+func TestKeaCapabilitiesRegistryConcurrentAccess(t *testing.T) {
+	registry := newKeaCapabilitiesRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			capabilities, err := registry.get("2.4.0")
+			require.NoError(t, err)
+			require.True(t, capabilities.MultiThreadingEnabledByDefault)
+		}()
+	}
+	wg.Wait()
+	require.Len(t, registry.capabilities, 1)
+}
+
+// Test that the capabilities of the reviewed daemon are returned
+// according to its version.
+func TestReviewContextGetKeaCapabilities(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.Version = "1.8.2"
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	capabilities, err := ctx.getKeaCapabilities()
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, capabilities)
+	require.True(t, capabilities.MultiThreading)
+	require.False(t, capabilities.ReservationFlags)
+}
+
+// Test that an error is returned when the reviewed daemon's version is
+// unknown or the daemon is not a Kea daemon.
+func TestReviewContextGetKeaCapabilitiesError(t *testing.T) {
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+	capabilities, err := ctx.getKeaCapabilities()
+	require.Error(t, err)
+	require.Nil(t, capabilities)
+
+	daemon = dbmodel.NewBind9Daemon(true)
+	daemon.Version = "9.18.0"
+	ctx = newReviewContext(nil, daemon, ManualRun, nil)
+	capabilities, err = ctx.getKeaCapabilities()
+	require.Error(t, err)
+	require.Nil(t, capabilities)
+}
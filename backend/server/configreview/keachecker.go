@@ -1,9 +1,14 @@
 package configreview
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"math/big"
+	"net"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	keaconfig "isc.org/stork/appcfg/kea"
@@ -39,6 +44,24 @@ func hostCmdsPresence(ctx *ReviewContext) (*Report, error) {
 	return nil, nil
 }
 
+// The checker verifying if the lease database backend is configured when
+// the lease_cmds hooks library is loaded. The leases managed with the lease
+// commands may not survive the server restart when the memfile backend is
+// in use.
+func leaseCmdsBackendPresence(ctx *ReviewContext) (*Report, error) {
+	config := ctx.subjectDaemon.KeaDaemon.Config
+	if _, _, present := config.GetHooksLibrary("libdhcp_lease_cmds"); present {
+		database := config.GetAllDatabases().Lease
+		if database == nil || database.Type == "" || database.Type == "memfile" {
+			r, err := NewReport(ctx, "The Lease Commands hook library (libdhcp_lease_cmds) is loaded on {daemon}, but no database lease backend is configured in the lease-database parameter. Kea stores the leases using the memfile backend in this case. The leases added or modified using the lease commands are lost after the server restart when the memfile backend is configured not to persist the leases, which often surprises the users. Please refer to the Lease Storage section of the Kea Administrator Reference Manual and consider configuring a persistent lease database backend.").
+				referencingDaemon(ctx.subjectDaemon).
+				create()
+			return r, err
+		}
+	}
+	return nil, nil
+}
+
 // The checker verifying if a shared network can be removed because it
 // is empty or contains only one subnet.
 func sharedNetworkDispensable(ctx *ReviewContext) (*Report, error) {
@@ -107,13 +130,63 @@ func sharedNetworkDispensable(ctx *ReviewContext) (*Report, error) {
 	return nil, nil
 }
 
+// Default maximum number of findings listed in a single report used
+// when the server database is unavailable.
+const defaultMaxFindings = 10
+
+// Returns the maximum number of findings listed in a single report. The
+// value is held in the max_checker_findings setting. The non-positive
+// values are replaced with the default.
+func getMaxFindings(ctx *ReviewContext) (int, error) {
+	if ctx.db == nil {
+		return defaultMaxFindings, nil
+	}
+	maxFindings, err := dbmodel.GetSettingInt(ctx.db, "max_checker_findings")
+	if err != nil {
+		return 0, err
+	}
+	if maxFindings <= 0 {
+		return defaultMaxFindings, nil
+	}
+	return int(maxFindings), nil
+}
+
+// Joins the findings listed in a report. The findings beyond the maximum
+// number are not listed but summarized with their count.
+func joinFindings(findings []string, maxFindings int) string {
+	if len(findings) <= maxFindings {
+		return strings.Join(findings, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(findings[:maxFindings], "; "),
+		len(findings)-maxFindings)
+}
+
+// Returns the subnet description used in the reports. It includes the
+// subnet ID if it is specified.
+func formatSubnetFinding(id int64, prefix string) string {
+	if id != 0 {
+		return fmt.Sprintf("[%d] %s", id, prefix)
+	}
+	return prefix
+}
+
 // Creates a report for a checker verifying if a subnet can be removed
-// because it contains no pools and no reservations.
-func createSubnetDispensableReport(ctx *ReviewContext, dispensableCount int64) (*Report, error) {
-	if dispensableCount == 0 {
+// because it contains no pools and no reservations. The dispensable
+// subnets are listed up to the maximum number of findings.
+func createSubnetDispensableReport(ctx *ReviewContext, dispensableSubnets []string) (*Report, error) {
+	if len(dispensableSubnets) == 0 {
 		return nil, nil
 	}
-	r, err := NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s without pools and host reservations. The DHCP server will not assign any addresses to the devices within this subnet. It is recommended to add some pools or host reservations to this subnet or remove the subnet from the configuration.", storkutil.FormatNoun(dispensableCount, "subnet", "s"))).
+	maxFindings, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(dispensableSubnets) && i < maxFindings; i++ {
+		dispensableSubnets[i] = fmt.Sprintf("%d. %s", i+1, dispensableSubnets[i])
+	}
+	r, err := NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s without pools and host reservations. The DHCP server will not assign any addresses to the devices within this subnet. It is recommended to add some pools or host reservations to this subnet or remove the subnet from the configuration.\n%s",
+		storkutil.FormatNoun(int64(len(dispensableSubnets)), "subnet", "s"),
+		joinFindings(dispensableSubnets, maxFindings))).
 		referencingDaemon(ctx.subjectDaemon).
 		create()
 	return r, err
@@ -165,16 +238,16 @@ func checkSubnet4Dispensable(ctx *ReviewContext) (*Report, error) {
 	}
 	// Iterate over the shared networks and check if they contain any
 	// subnets that can be removed.
-	dispensableCount := int64(0)
+	var dispensableSubnets []string
 	for _, net := range *decodedSharedNetworks {
 		for _, subnet := range net.Subnet4 {
 			if len(subnet.Pools) == 0 && len(subnet.Reservations) == 0 &&
 				(!hostCmds || len(dbHosts[subnet.ID]) == 0) {
-				dispensableCount++
+				dispensableSubnets = append(dispensableSubnets, formatSubnetFinding(subnet.ID, subnet.Subnet))
 			}
 		}
 	}
-	return createSubnetDispensableReport(ctx, dispensableCount)
+	return createSubnetDispensableReport(ctx, dispensableSubnets)
 }
 
 // Implementation of a checker verifying if an IPv6 subnet can be removed
@@ -228,16 +301,16 @@ func checkSubnet6Dispensable(ctx *ReviewContext) (*Report, error) {
 	}
 	// Iterate over the shared networks and check if they contain any
 	// subnets that can be removed.
-	dispensableCount := int64(0)
+	var dispensableSubnets []string
 	for _, net := range *decodedSharedNetworks {
 		for _, subnet := range net.Subnet6 {
 			if len(subnet.Pools) == 0 && len(subnet.PDPools) == 0 && len(subnet.Reservations) == 0 &&
 				(!hostCmds || len(dbHosts[subnet.ID]) == 0) {
-				dispensableCount++
+				dispensableSubnets = append(dispensableSubnets, formatSubnetFinding(subnet.ID, subnet.Subnet))
 			}
 		}
 	}
-	return createSubnetDispensableReport(ctx, dispensableCount)
+	return createSubnetDispensableReport(ctx, dispensableSubnets)
 }
 
 // The checker verifying if a subnet can be removed because it includes
@@ -601,9 +674,29 @@ type minimalSubnet struct {
 	Subnet string
 }
 
-type minimalSubnetPair struct {
-	parent minimalSubnet
-	child  minimalSubnet
+// The default time budget for the overlapping subnets detection used when
+// the server database is unavailable. The detection is stopped when it is
+// exceeded, and the partial results are reported. It prevents the checker
+// from blocking the review for the configurations with a huge number of
+// subnets. The zero value disables the limit.
+var overlapsDetectionTimeBudget = 5 * time.Second
+
+// Returns the time budget for the overlapping subnets detection. The value
+// is held in the overlaps_detection_time_budget setting in seconds. The
+// zero value disables the limit. The negative values are replaced with the
+// default.
+func getOverlapsDetectionTimeBudget(ctx *ReviewContext) (time.Duration, error) {
+	if ctx.db == nil {
+		return overlapsDetectionTimeBudget, nil
+	}
+	seconds, err := dbmodel.GetSettingInt(ctx.db, "overlaps_detection_time_budget")
+	if err != nil {
+		return 0, err
+	}
+	if seconds < 0 {
+		return overlapsDetectionTimeBudget, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
 }
 
 // The checker validates that subnets (global or from shared networks) don't overlap.
@@ -638,103 +731,158 @@ func subnetsOverlapping(ctx *ReviewContext) (*Report, error) {
 		decodedSubnets = append(decodedSubnets, sharedNetwork.Subnet6...)
 	}
 
-	// Limits the overlaps count to avoid producing too huge review message.
-	maxOverlaps := 10
-	overlaps := findOverlaps(decodedSubnets, maxOverlaps)
+	// The subnet IDs from the configuration are carried in the local subnets
+	// because the subnet ID is reserved for the database identifier.
+	subnets := make([]dbmodel.Subnet, len(decodedSubnets))
+	for i, subnet := range decodedSubnets {
+		subnets[i] = dbmodel.Subnet{
+			Prefix: subnet.Subnet,
+			LocalSubnets: []*dbmodel.LocalSubnet{{
+				DaemonID:      ctx.subjectDaemon.ID,
+				LocalSubnetID: subnet.ID,
+			}},
+		}
+	}
+
+	timeBudget, err := getOverlapsDetectionTimeBudget(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// All overlaps are counted but only some of them are listed to avoid
+	// producing too huge review message.
+	overlaps, truncated := dbmodel.FindSubnetOverlapsWithinTimeBudget(subnets, 0, timeBudget)
 	if len(overlaps) == 0 {
-		return nil, nil
+		if !truncated {
+			return nil, nil
+		}
+		// The detection may have been stopped before any overlaps were
+		// found. The user must be aware that the result is incomplete.
+		return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes no "+
+			"overlapping subnets found so far. The detection was truncated after %s "+
+			"due to the large number of subnets (%d), so there may be overlapping subnets.",
+			timeBudget, len(decodedSubnets))).
+			referencingDaemon(ctx.subjectDaemon).
+			create()
 	}
 
-	maxExceedMessage := ""
-	if len(overlaps) == maxOverlaps {
-		maxExceedMessage = " at least"
+	maxOverlaps, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	maxExceedMessage := ""
+
 	overlappingMessages := make([]string, len(overlaps))
 	for i, overlap := range overlaps {
+		if i == maxOverlaps {
+			break
+		}
 		parentID := ""
-		if overlap.parent.ID != 0 {
-			parentID = fmt.Sprintf(" (subnet-id %d)", overlap.parent.ID)
+		if id := overlap.Parent.LocalSubnets[0].LocalSubnetID; id != 0 {
+			parentID = fmt.Sprintf(" (subnet-id %d)", id)
 		}
 		childID := ""
-		if overlap.child.ID != 0 {
-			childID = fmt.Sprintf(" (subnet-id %d)", overlap.child.ID)
+		if id := overlap.Child.LocalSubnets[0].LocalSubnetID; id != 0 {
+			childID = fmt.Sprintf(" (subnet-id %d)", id)
 		}
 
 		message := fmt.Sprintf("%d. %s%s is overlapped by %s%s", i+1,
-			overlap.parent.Subnet, parentID,
-			overlap.child.Subnet, childID)
+			overlap.Parent.Prefix, parentID,
+			overlap.Child.Prefix, childID)
 		overlappingMessages[i] = message
 	}
-	overlapMessage := strings.Join(overlappingMessages, "; ")
+	overlapMessage := joinFindings(overlappingMessages, maxOverlaps)
+
+	truncatedMessage := ""
+	if truncated {
+		maxExceedMessage = " at least"
+		truncatedMessage = fmt.Sprintf(" The detection was truncated after %s due to the large "+
+			"number of subnets (%d), so there may be more overlapping subnets.",
+			timeBudget, len(decodedSubnets))
+	}
 
 	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes%s %s. "+
-		"It means that the DHCP clients in different subnets may be assigned the same IP addresses.\n%s",
-		maxExceedMessage, storkutil.FormatNoun(int64(len(overlaps)), "overlapping subnet pair", "s"), overlapMessage)).
+		"It means that the DHCP clients in different subnets may be assigned the same IP addresses.%s\n%s",
+		maxExceedMessage, storkutil.FormatNoun(int64(len(overlaps)), "overlapping subnet pair", "s"),
+		truncatedMessage, overlapMessage)).
 		referencingDaemon(ctx.subjectDaemon).
 		create()
 }
 
-// Search for prefix overlaps in the provided set of subnets.
-// The execution is stopped early if an expected name of founded overlaps is
-// reached.
-func findOverlaps(subnets []minimalSubnet, maxOverlaps int) (overlaps []minimalSubnetPair) {
-	// Pair of the subnet and its binary prefix.
-	type subnetWithPrefix struct {
-		subnet       minimalSubnet
-		binaryPrefix string
+// The checker validates that subnets (global or from shared networks) don't
+// share the same subnet ID. Kea rejects such a configuration or behaves
+// unpredictably. The subnets without explicit IDs are ignored.
+func subnetsWithDuplicateIDs(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
 	}
 
-	// Calculates the binary prefixes for all subnets.
-	subnetPrefixes := make([]subnetWithPrefix, len(subnets))
-
-	for i, subnet := range subnets {
-		cidr := storkutil.ParseIP(subnet.Subnet)
-		if cidr == nil || !cidr.Prefix {
-			continue
-		}
-		binaryPrefix := cidr.GetNetworkPrefixAsBinary()
+	config := ctx.subjectDaemon.KeaDaemon.Config
 
-		subnetPrefixes[i] = subnetWithPrefix{
-			subnet:       subnet,
-			binaryPrefix: binaryPrefix,
-		}
+	var decodedSubnets []minimalSubnet
+	// Global subnets.
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
 	}
 
-	// Sorts prefixes from the shortest (the most general masks) to the longest
-	// (the most specific masks).
-	sort.Slice(subnetPrefixes, func(i, j int) bool {
-		return len(subnetPrefixes[i].binaryPrefix) <= len(subnetPrefixes[j].binaryPrefix)
-	})
+	// Subnets belonging to the shared networks.
+	type minimalSharedNetwork struct {
+		Subnet4 []minimalSubnet
+		Subnet6 []minimalSubnet
+	}
+	var decodedSharedNetworks []minimalSharedNetwork
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
 
-	for outerIdx, outer := range subnetPrefixes {
-		for innerIdx, inner := range subnetPrefixes {
-			// The prefixes are sorted by length. The prefix length is equal to
-			// the subnet mask in bits. For a given prefix with length X, we
-			// need only check the prefixes with lengths equal to or greater
-			// than X. It means that we need to check only the following
-			// prefixes.
-			if outerIdx >= innerIdx {
-				continue
-			}
+	for _, sharedNetwork := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, sharedNetwork.Subnet4...)
+		decodedSubnets = append(decodedSubnets, sharedNetwork.Subnet6...)
+	}
 
-			// Checks if the outer prefix contains the inner prefix. It happens
-			// when the inner prefix's binary representation starts with the
-			// outer prefix's binary representation.
-			if strings.HasPrefix(inner.binaryPrefix, outer.binaryPrefix) {
-				overlaps = append(overlaps, minimalSubnetPair{
-					parent: outer.subnet,
-					child:  inner.subnet,
-				})
+	// Group the prefixes by subnet ID preserving the order in which the
+	// IDs appear in the configuration.
+	var ids []int64
+	prefixesByID := make(map[int64][]string)
+	for _, subnet := range decodedSubnets {
+		if subnet.ID == 0 {
+			continue
+		}
+		if _, ok := prefixesByID[subnet.ID]; !ok {
+			ids = append(ids, subnet.ID)
+		}
+		prefixesByID[subnet.ID] = append(prefixesByID[subnet.ID], subnet.Subnet)
+	}
 
-				// Checks if the overlap limit is exceed.
-				if len(overlaps) == maxOverlaps {
-					return
-				}
-			}
+	// Limits the listed duplicates to avoid producing too huge review message.
+	maxDuplicates, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var duplicateMessages []string
+	for _, id := range ids {
+		prefixes := prefixesByID[id]
+		if len(prefixes) < 2 {
+			continue
 		}
+		duplicateMessages = append(duplicateMessages, fmt.Sprintf("%d. subnet-id %d is used by %s",
+			len(duplicateMessages)+1, id, strings.Join(prefixes, ", ")))
+	}
+	if len(duplicateMessages) == 0 {
+		return nil, nil
 	}
-	return overlaps
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"shared by multiple subnets. Kea rejects such a configuration or may "+
+		"behave unpredictably because the subnet ID must be unique.\n%s",
+		storkutil.FormatNoun(int64(len(duplicateMessages)), "subnet ID", "s"),
+		joinFindings(duplicateMessages, maxDuplicates))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
 }
 
 // The checker validates that all subnet prefixes are in canonical form.
@@ -769,51 +917,49 @@ func canonicalPrefixes(ctx *ReviewContext) (*Report, error) {
 		decodedSubnets = append(decodedSubnets, sharedNetwork.Subnet6...)
 	}
 
-	maxIssues := 10
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var issues []string
+	// The non-canonical prefixes are accepted by Kea, so the report has the
+	// default checker severity. However, the prefixes that cannot be parsed
+	// at all are the configuration errors.
+	var severity dbmodel.ConfigReportSeverity
 
 	for _, decodedSubnet := range decodedSubnets {
 		prefix, ok := getCanonicalPrefix(decodedSubnet.Subnet)
 		if ok {
 			continue
 		}
-
-		subnetID := ""
-		if decodedSubnet.ID != 0 {
-			subnetID = fmt.Sprintf("[%d] ", decodedSubnet.ID)
+		if prefix == "" {
+			severity = dbmodel.ConfigReportSeverityError
 		}
 
-		issue := fmt.Sprintf("%d. %s%s is invalid prefix", len(issues)+1, subnetID, decodedSubnet.Subnet)
+		issue := fmt.Sprintf("%d. %s is invalid prefix", len(issues)+1,
+			formatSubnetFinding(decodedSubnet.ID, decodedSubnet.Subnet))
 
 		if prefix != "" {
 			issue = fmt.Sprintf("%s, expected: %s", issue, prefix)
 		}
 
 		issues = append(issues, issue)
-
-		if len(issues) == maxIssues {
-			break
-		}
 	}
 
 	if len(issues) == 0 {
 		return nil, nil
 	}
 
-	maxExceedMessage := ""
-	if len(issues) == maxIssues {
-		maxExceedMessage = " at least"
-	}
-
-	hintMessage := strings.Join(issues, "; ")
+	hintMessage := joinFindings(issues, maxIssues)
 
-	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration contains%s %s. "+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration contains %s. "+
 		"Kea accepts non-canonical prefix forms, which may lead to duplicates "+
 		"if two subnets have the same prefix specified in different forms. "+
 		"Use canonical forms to ensure that Kea properly identifies and "+
 		"validates subnet prefixes to avoid duplication or overlap.\n%s",
-		maxExceedMessage, storkutil.FormatNoun(int64(len(issues)), "non-canonical prefix", "es"), hintMessage)).
+		storkutil.FormatNoun(int64(len(issues)), "non-canonical prefix", "es"), hintMessage)).
 		referencingDaemon(ctx.subjectDaemon).
+		withSeverity(severity).
 		create()
 }
 
@@ -831,3 +977,3143 @@ func getCanonicalPrefix(prefix string) (string, bool) {
 	}
 	return candidate.GetNetworkPrefixWithLength(), true
 }
+
+// Returns the first non-nil value from the specified ones. The values
+// should be ordered from the most specific configuration level to the
+// global level, so the returned value is the one effective at the most
+// specific level. It returns nil if the value is not specified at any
+// level.
+func getEffectiveInt64(values ...*int64) *int64 {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// The checker verifying that the effective preferred-lifetime of each
+// DHCPv6 subnet is lower than the effective valid-lifetime. The lifetimes
+// are inherited from the shared network and the global level when they
+// are not specified at the subnet level. The checker also reports the
+// subnets for which the valid-lifetime is set but the preferred-lifetime
+// is not specified at any level.
+func preferredLifetime(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	// Lifetimes specified at a particular configuration level.
+	type lifetimes struct {
+		ValidLifetime     *int64
+		PreferredLifetime *int64
+	}
+	type subnet6 struct {
+		ID     int64
+		Subnet string
+		lifetimes
+	}
+	type sharedNetwork struct {
+		Name    string
+		Subnet6 []subnet6
+		lifetimes
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets6 []subnet6
+	err = config.DecodeTopLevelSubnets(&decodedSubnets6)
+	if err != nil {
+		return nil, err
+	}
+	// Create an artificial shared network comprising the top-level
+	// subnets. It will make the code below more readable.
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet6: decodedSubnets6,
+	})
+
+	// Parse global lifetimes.
+	var globalLifetimes lifetimes
+	if err = config.DecodeTopLevelParameters(&globalLifetimes); err != nil {
+		return nil, err
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, net := range decodedSharedNetworks {
+		for _, subnet := range net.Subnet6 {
+			valid := getEffectiveInt64(subnet.ValidLifetime, net.ValidLifetime, globalLifetimes.ValidLifetime)
+			if valid == nil {
+				// The valid lifetime is not specified so Kea uses defaults.
+				continue
+			}
+			preferred := getEffectiveInt64(subnet.PreferredLifetime, net.PreferredLifetime, globalLifetimes.PreferredLifetime)
+
+			var issue string
+			switch {
+			case preferred == nil:
+				issue = fmt.Sprintf("preferred-lifetime is not set while valid-lifetime is %d", *valid)
+			case *preferred >= *valid:
+				issue = fmt.Sprintf("preferred-lifetime %d is not lower than valid-lifetime %d", *preferred, *valid)
+			default:
+				continue
+			}
+
+			issues = append(issues, fmt.Sprintf("%d. %s: %s", len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet), issue))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the preferred-lifetime not lower than the valid-lifetime or with "+
+		"the preferred-lifetime unspecified. The DHCPv6 clients may treat the "+
+		"assigned addresses as preferred until they expire. It is recommended "+
+		"to set the preferred-lifetime to a value lower than the valid-lifetime.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the valid-lifetime is specified for each
+// subnet at the subnet, shared network or global level. Otherwise, Kea
+// uses the built-in default which is often not what the administrator
+// intends.
+func validLifetimeUnspecified(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 && ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet struct {
+		ID            int64
+		Subnet        string
+		ValidLifetime *int64
+	}
+	type sharedNetwork struct {
+		Name          string
+		ValidLifetime *int64
+		Subnet4       []subnet
+		Subnet6       []subnet
+	}
+	type globals struct {
+		ValidLifetime *int64
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse global valid-lifetime.
+	var decodedGlobals globals
+	if err := config.DecodeTopLevelParameters(&decodedGlobals); err != nil {
+		return nil, err
+	}
+	if decodedGlobals.ValidLifetime != nil {
+		// All subnets inherit the global value.
+		return nil, nil
+	}
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets []subnet
+	err = config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	// Create an artificial shared network comprising the top-level
+	// subnets.
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets,
+	})
+
+	var findings []string
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range append(network.Subnet4, network.Subnet6...) {
+			if getEffectiveInt64(subnet.ValidLifetime, network.ValidLifetime, decodedGlobals.ValidLifetime) != nil {
+				continue
+			}
+			findings = append(findings, fmt.Sprintf("%d. %s", len(findings)+1, formatSubnetFinding(subnet.ID, subnet.Subnet)))
+		}
+	}
+
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	maxFindings, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"without the valid-lifetime specified at the subnet, shared network or global "+
+		"level. The DHCP server assigns the leases in these subnets with the built-in "+
+		"default lifetime, which may not be what you intend. It is recommended to "+
+		"specify the valid-lifetime explicitly, e.g., at the global level.\n%s",
+		storkutil.FormatNoun(int64(len(findings)), "subnet", "s"),
+		joinFindings(findings, maxFindings))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the address pools of the IPv4 subnets do not
+// span the entire subnet prefix, i.e., from the network to the broadcast
+// address. Such pools contain the unusable addresses and leave no room for
+// the router addresses and the out-of-pool reservations. The /31 and /32
+// subnets are excluded because they have no network and broadcast addresses.
+func poolCoversEntireSubnet(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet4 struct {
+		ID     int64
+		Subnet string
+		Pools  []keaconfig.Pool
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet4
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets4 []subnet4
+	err = config.DecodeTopLevelSubnets(&decodedSubnets4)
+	if err != nil {
+		return nil, err
+	}
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets4,
+	})
+
+	// The /31 and /32 subnets have no network and broadcast addresses.
+	const maxPrefixLength = 30
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range network.Subnet4 {
+			prefix, _ := getCanonicalPrefix(subnet.Subnet)
+			parsedPrefix := storkutil.ParseIP(prefix)
+			if parsedPrefix == nil || parsedPrefix.Protocol != storkutil.IPv4 ||
+				parsedPrefix.PrefixLength > maxPrefixLength {
+				continue
+			}
+			first, last := getIPv4NetworkAndBroadcast(parsedPrefix)
+
+			for _, pool := range parsePools(subnet.Pools) {
+				lb, ub := pool.lb.To4(), pool.ub.To4()
+				if lb == nil || ub == nil ||
+					binary.BigEndian.Uint32(lb) != first || binary.BigEndian.Uint32(ub) != last {
+					continue
+				}
+				issues = append(issues, fmt.Sprintf("%d. %s: pool %s",
+					len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet), pool.pool))
+				break
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with an address pool spanning the entire subnet prefix. Such a pool "+
+		"includes the network and broadcast addresses which must not be offered "+
+		"to the clients, and leaves no room for the router address and the "+
+		"out-of-pool host reservations. Consider excluding at least the first "+
+		"and the last address of the subnet from the pool.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that each DHCPv6 subnet specifies at least one
+// of the parameters used by Kea to select the subnet for the client, i.e.,
+// the interface, the relay addresses or the interface-id. The parameters
+// may be inherited from the shared network. The subnets lacking these
+// parameters may not be selected for any client. Some deployments use
+// other selection mechanisms, so the issue is reported as a warning.
+func dhcp6SubnetSelectors(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	// Parameters used to select the subnet.
+	type selectors struct {
+		Interface   string
+		InterfaceID string
+		Relay       *struct {
+			IPAddress   string
+			IPAddresses []string
+		}
+	}
+	type subnet6 struct {
+		ID     int64
+		Subnet string
+		selectors
+	}
+	type sharedNetwork struct {
+		Subnet6 []subnet6
+		selectors
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets6 []subnet6
+	err = config.DecodeTopLevelSubnets(&decodedSubnets6)
+	if err != nil {
+		return nil, err
+	}
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet6: decodedSubnets6,
+	})
+
+	// Checks if any of the selectors is specified.
+	isSpecified := func(s selectors) bool {
+		return s.Interface != "" || s.InterfaceID != "" ||
+			(s.Relay != nil && (s.Relay.IPAddress != "" || len(s.Relay.IPAddresses) > 0))
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, net := range decodedSharedNetworks {
+		if isSpecified(net.selectors) {
+			continue
+		}
+		for _, subnet := range net.Subnet6 {
+			if isSpecified(subnet.selectors) {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. %s", len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet)))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"specifying neither the interface nor the relay addresses nor the "+
+		"interface-id. Depending on the network topology, the DHCPv6 clients may "+
+		"be unable to reach these subnets unless they are selected by other "+
+		"means, e.g., the client classes. Consider specifying the interface for "+
+		"the directly connected clients or the relay addresses for the relayed "+
+		"traffic.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Address pool with the parsed lower and upper bounds.
+type parsedPool struct {
+	pool string
+	lb   net.IP
+	ub   net.IP
+}
+
+// Parses the address pools and returns their bounds. The bounds are
+// converted to the 16-byte form to make them comparable regardless of
+// whether the pool was specified as a range or a prefix. The invalid
+// pools are skipped.
+func parsePools(pools []keaconfig.Pool) (parsedPools []parsedPool) {
+	for _, pool := range pools {
+		lb, ub, err := storkutil.ParseIPRange(pool.Pool)
+		if err != nil {
+			continue
+		}
+		parsedPools = append(parsedPools, parsedPool{
+			pool: pool.Pool,
+			lb:   lb.To16(),
+			ub:   ub.To16(),
+		})
+	}
+	return parsedPools
+}
+
+// Checks if two parsed address pools share at least one address.
+func (p parsedPool) overlaps(other parsedPool) bool {
+	return bytes.Compare(p.lb, other.ub) <= 0 && bytes.Compare(other.lb, p.ub) <= 0
+}
+
+// The checker verifying that the address pools of the subnets belonging to
+// the same shared network do not overlap. The subnets in a shared network
+// serve the same network segment, so the overlapping pools may cause
+// assigning the same address to different clients.
+func sharedNetworkPoolsOverlapping(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet struct {
+		ID     int64
+		Subnet string
+		Pools  []keaconfig.Pool
+	}
+	type sharedNetwork struct {
+		Name    string
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	config := ctx.subjectDaemon.KeaDaemon.Config
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		subnets := network.Subnet4
+		if ctx.subjectDaemon.Name == dbmodel.DaemonNameDHCPv6 {
+			subnets = network.Subnet6
+		}
+		pools := make([][]parsedPool, len(subnets))
+		for i := range subnets {
+			pools[i] = parsePools(subnets[i].Pools)
+		}
+		// Compare the pools of each pair of the sibling subnets.
+		for i := 0; i < len(subnets); i++ {
+			for j := i + 1; j < len(subnets); j++ {
+				for _, pool := range pools[i] {
+					for _, otherPool := range pools[j] {
+						if pool.overlaps(otherPool) {
+							issues = append(issues, fmt.Sprintf("%d. shared network %s: pool %s in subnet %s overlaps with pool %s in subnet %s",
+								len(issues)+1, network.Name, pool.pool, formatSubnetFinding(subnets[i].ID, subnets[i].Subnet),
+								otherPool.pool, formatSubnetFinding(subnets[j].ID, subnets[j].Subnet)))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"between the subnets belonging to the same shared network. The subnets "+
+		"in a shared network serve the same network segment, so the DHCP clients "+
+		"connected to this segment may be assigned the same IP addresses.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "overlapping address pool pair", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the address pools within a single subnet do
+// not overlap. Overlapping pools are not rejected by Kea but the addresses
+// belonging to more than one pool are wasted. The pools may be specified
+// as ranges (e.g., 192.0.2.10 - 192.0.2.100) or prefixes. The malformed
+// pools are skipped but their number is included in the report.
+func subnetPoolsOverlapping(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet struct {
+		ID     int64
+		Subnet string
+		Pools  []keaconfig.Pool
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Global subnets.
+	var decodedSubnets []subnet
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subnets belonging to the shared networks.
+	var decodedSharedNetworks []sharedNetwork
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, network.Subnet4...)
+		decodedSubnets = append(decodedSubnets, network.Subnet6...)
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+	malformedCount := 0
+
+	for _, s := range decodedSubnets {
+		pools := parsePools(s.Pools)
+		// The malformed pools are skipped by the parser.
+		malformedCount += len(s.Pools) - len(pools)
+		for i := 0; i < len(pools); i++ {
+			for j := i + 1; j < len(pools); j++ {
+				if pools[i].overlaps(pools[j]) {
+					issues = append(issues, fmt.Sprintf("%d. subnet %s: pool %s overlaps with pool %s",
+						len(issues)+1, formatSubnetFinding(s.ID, s.Subnet), pools[i].pool, pools[j].pool))
+				}
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	malformedMessage := ""
+	if malformedCount > 0 {
+		malformedMessage = fmt.Sprintf(" Skipped %s.",
+			storkutil.FormatNoun(int64(malformedCount), "malformed pool", "s"))
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"within the same subnet. The addresses belonging to more than one pool "+
+		"are wasted.%s\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "overlapping address pool pair", "s"),
+		malformedMessage, joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying whether the address pools override the DHCP
+// options specified at the subnet level with different values. Such an
+// override may be intentional, but it may also contradict the subnet's
+// intent (e.g., point the clients to a different router). The options
+// are matched by the option space and the code. The code of the option
+// specified by name is resolved using the option definitions.
+func poolOptionsConflict(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type pool struct {
+		Pool       string
+		OptionData []keaconfig.SingleOptionData
+	}
+	type subnet struct {
+		ID         int64
+		Subnet     string
+		OptionData []keaconfig.SingleOptionData
+		Pools      []pool
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	config := ctx.subjectDaemon.KeaDaemon.Config
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse top-level subnets.
+	var decodedSubnets []subnet
+	err = config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := newOptionCodeResolver(ctx.subjectDaemon.Name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalizes the option data for comparison. The whitespace
+	// surrounding the values and the character case are not relevant.
+	normalizeData := func(data string) string {
+		return strings.ToLower(strings.Join(strings.Fields(data), ""))
+	}
+
+	var subnets []subnet
+	for _, network := range decodedSharedNetworks {
+		subnets = append(subnets, network.Subnet4...)
+		subnets = append(subnets, network.Subnet6...)
+	}
+	subnets = append(subnets, decodedSubnets...)
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, s := range subnets {
+		subnetOptions := make(map[string]keaconfig.SingleOptionData)
+		for _, option := range s.OptionData {
+			key, _ := resolver.getOptionKey(option)
+			subnetOptions[key] = option
+		}
+		for _, p := range s.Pools {
+			for _, option := range p.OptionData {
+				key, label := resolver.getOptionKey(option)
+				subnetOption, ok := subnetOptions[key]
+				if !ok || normalizeData(subnetOption.Data) == normalizeData(option.Data) {
+					continue
+				}
+				issues = append(issues, fmt.Sprintf("%d. subnet %s, pool %s: option %s is %s in the subnet and %s in the pool",
+					len(issues)+1, formatSubnetFinding(s.ID, s.Subnet), p.Pool, label, subnetOption.Data, option.Data))
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"overriding the subnet-level DHCP options with different values. It may be "+
+		"intentional, but please make sure that the pool-level options do not "+
+		"contradict the subnet configuration.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "pool option", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the subnets belonging to the same shared
+// network have the same prefix length. The subnets in a shared network
+// usually follow the same addressing plan, so a subnet with a prefix
+// length different than the other subnets may be a typo. The expected
+// prefix length is the most common length in the shared network. If there
+// is no single most common length, the shared network is not checked.
+// It is a heuristic, so the report is merely a hint.
+func sharedNetworkPrefixLength(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet struct {
+		ID     int64
+		Subnet string
+	}
+	type sharedNetwork struct {
+		Name    string
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	config := ctx.subjectDaemon.KeaDaemon.Config
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		subnets := network.Subnet4
+		if ctx.subjectDaemon.Name == dbmodel.DaemonNameDHCPv6 {
+			subnets = network.Subnet6
+		}
+
+		// Count the subnets having each prefix length.
+		prefixLengths := make([]int, len(subnets))
+		counts := make(map[int]int)
+		for i, s := range subnets {
+			_, ipNet, err := net.ParseCIDR(s.Subnet)
+			if err != nil {
+				prefixLengths[i] = -1
+				continue
+			}
+			prefixLengths[i], _ = ipNet.Mask.Size()
+			counts[prefixLengths[i]]++
+		}
+
+		// Find the most common prefix length.
+		modalLength, modalCount, unique := 0, 0, false
+		for length, count := range counts {
+			switch {
+			case count > modalCount:
+				modalLength, modalCount, unique = length, count, true
+			case count == modalCount:
+				unique = false
+			}
+		}
+		if !unique || modalCount == len(subnets) {
+			continue
+		}
+
+		for i, s := range subnets {
+			if prefixLengths[i] < 0 || prefixLengths[i] == modalLength {
+				continue
+			}
+			subnetLabel := formatSubnetFinding(s.ID, s.Subnet)
+			issues = append(issues, fmt.Sprintf("%d. shared network %s: subnet %s has the prefix length /%d while /%d is expected",
+				len(issues)+1, network.Name, subnetLabel, prefixLengths[i], modalLength))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the prefix length different than the other subnets in the same shared "+
+		"network. It may be intentional, but it often indicates a typo in the subnet "+
+		"prefix. You can disable this checker if the subnets in your shared networks "+
+		"have different lengths by design.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the Kea Control Agent enabling the basic HTTP
+// authentication specifies a non-empty authentication realm. The realm is
+// presented to the users in the authentication prompt. Some HTTP clients
+// misbehave when the realm is empty.
+func basicAuthRealm(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameCA {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	authentication, ok := ctx.subjectDaemon.KeaDaemon.Config.GetAuthentication()
+	if !ok || authentication.Type != "basic" || strings.TrimSpace(authentication.Realm) != "" {
+		return nil, nil
+	}
+
+	return NewReport(ctx, "The basic HTTP authentication is enabled in the Kea {daemon} "+
+		"configuration but the authentication realm is not specified. The realm "+
+		"is presented to the users in the authentication prompt and some HTTP "+
+		"clients may fail to authenticate without it. Please specify a descriptive "+
+		"realm in the authentication configuration.").
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Checks if the machine appears to run the services under systemd. The
+// machine state doesn't include the init system, so the function guesses
+// it from the platform family. The containers typically don't run systemd.
+func isSystemdMachine(machine *dbmodel.Machine) bool {
+	if machine == nil || machine.State.Os != "linux" {
+		return false
+	}
+	if machine.State.VirtualizationRole == "guest" {
+		switch machine.State.VirtualizationSystem {
+		case "docker", "lxc", "podman", "openvz":
+			return false
+		}
+	}
+	switch machine.State.PlatformFamily {
+	case "debian", "rhel", "fedora", "suse", "arch":
+		return true
+	default:
+		return false
+	}
+}
+
+// The checker verifying that the Kea loggers don't write to the files when
+// the daemon runs under systemd. Systemd captures the daemon's output in
+// the journal, so the logs written to the files may be duplicated or not
+// rotated properly. The checker suggests using stdout or syslog instead.
+func loggersFileOutputUnderSystemd(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.KeaDaemon == nil || ctx.subjectDaemon.KeaDaemon.Config == nil {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	if ctx.subjectDaemon.App == nil || !isSystemdMachine(ctx.subjectDaemon.App.Machine) {
+		return nil, nil
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, logger := range ctx.subjectDaemon.KeaDaemon.Config.GetLoggers() {
+		for _, options := range logger.OutputOptions {
+			output := strings.TrimSpace(options.Output)
+			if output == "" || output == "stdout" || output == "stderr" ||
+				output == "syslog" || strings.HasPrefix(output, "syslog:") {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. logger %s writing to %s", len(issues)+1, logger.Name, output))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"to the files while the daemon appears to run under systemd. Systemd "+
+		"captures the daemon's output in the journal, so the logs may be "+
+		"duplicated or not rotated properly. Consider setting the output to "+
+		"stdout or syslog for proper integration with the journal.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "logger output", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the DHCPv4 subnets with pools have the
+// domain-name-servers option (code 6) in effect. The option may be specified
+// globally, in the shared network, in the subnet or in the pools. The DHCP
+// clients lacking this option may be unable to resolve names.
+func dnsServersOptionPresence(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	// Options specified at a particular configuration level.
+	type options struct {
+		OptionData []keaconfig.SingleOptionData
+	}
+	type pool struct {
+		options
+	}
+	type subnet4 struct {
+		ID     int64
+		Subnet string
+		Pools  []pool
+		options
+	}
+	type sharedNetwork struct {
+		Name    string
+		Subnet4 []subnet4
+		options
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets4 []subnet4
+	err = config.DecodeTopLevelSubnets(&decodedSubnets4)
+	if err != nil {
+		return nil, err
+	}
+	// Create an artificial shared network comprising the top-level
+	// subnets.
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets4,
+	})
+
+	// Parse global options.
+	var globalOptions options
+	if err = config.DecodeTopLevelParameters(&globalOptions); err != nil {
+		return nil, err
+	}
+
+	// The options specified by name are matched by the resolved codes.
+	resolver, err := newOptionCodeResolver(ctx.subjectDaemon.Name, config)
+	if err != nil {
+		return nil, err
+	}
+	// The key of the domain-name-servers option in the top-level space.
+	optionKey := fmt.Sprintf("%s/%d", keaconfig.DHCPv4OptionSpace, 6)
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range network.Subnet4 {
+			if len(subnet.Pools) == 0 {
+				// The clients are not assigned the dynamic addresses.
+				continue
+			}
+			// Collect the options in effect for the subnet and its pools.
+			var optionData []keaconfig.SingleOptionData
+			optionData = append(optionData, globalOptions.OptionData...)
+			optionData = append(optionData, network.OptionData...)
+			optionData = append(optionData, subnet.OptionData...)
+			for _, p := range subnet.Pools {
+				optionData = append(optionData, p.OptionData...)
+			}
+			specified := false
+			for _, option := range optionData {
+				if key, _ := resolver.getOptionKey(option); key == optionKey {
+					specified = true
+					break
+				}
+			}
+			if specified {
+				continue
+			}
+
+			issues = append(issues, fmt.Sprintf("%d. %s", len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet)))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with address pools lacking the domain-name-servers option (code 6). "+
+		"The option is not specified globally, in the shared networks, in the "+
+		"subnets nor in their pools. The DHCP clients may be unable to resolve "+
+		"the names without the DNS servers.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Returns the network and broadcast addresses of the parsed IPv4 prefix
+// as integers.
+func getIPv4NetworkAndBroadcast(parsedPrefix *storkutil.ParsedIP) (uint32, uint32) {
+	network := binary.BigEndian.Uint32(parsedPrefix.IPNet.IP.To4())
+	broadcast := network + (uint32(1) << (32 - parsedPrefix.PrefixLength)) - 1
+	return network, broadcast
+}
+
+// The checker verifying that the address pools and the out-of-pool host
+// reservations of the small IPv4 subnets leave at least one usable address
+// unallocated. The network and broadcast addresses are not usable. If the
+// pools and reservations consume all remaining addresses, there is no room
+// for a router (gateway) address, and the clients may fail to communicate.
+// The checker takes into account the subnets with the prefix length of 24
+// or longer, and only the reservations specified in the configuration file.
+func addressSpaceExhaustion(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet4 struct {
+		ID           int64
+		Subnet       string
+		Pools        []keaconfig.Pool
+		Reservations []struct {
+			IPAddress string
+		}
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet4
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets4 []subnet4
+	err = config.DecodeTopLevelSubnets(&decodedSubnets4)
+	if err != nil {
+		return nil, err
+	}
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets4,
+	})
+
+	// Larger subnets are unlikely to be fully allocated, and enumerating
+	// their addresses would be expensive.
+	const minPrefixLength = 24
+	// The /31 and /32 subnets have no network and broadcast addresses.
+	const maxPrefixLength = 30
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range network.Subnet4 {
+			prefix, _ := getCanonicalPrefix(subnet.Subnet)
+			parsedPrefix := storkutil.ParseIP(prefix)
+			if parsedPrefix == nil || parsedPrefix.Protocol != storkutil.IPv4 ||
+				parsedPrefix.PrefixLength < minPrefixLength ||
+				parsedPrefix.PrefixLength > maxPrefixLength {
+				continue
+			}
+			first, last := getIPv4NetworkAndBroadcast(parsedPrefix)
+			usable := int(last - first - 1)
+
+			// Collect the distinct addresses belonging to the pools. The
+			// pools may overlap or exceed the subnet boundaries.
+			allocated := make(map[uint32]bool)
+			for _, pool := range parsePools(subnet.Pools) {
+				lb, ub := pool.lb.To4(), pool.ub.To4()
+				if lb == nil || ub == nil {
+					continue
+				}
+				// Clamp the pool boundaries to the usable addresses of the
+				// subnet. It excludes the network and broadcast addresses
+				// and avoids iterating over the huge pools exceeding the
+				// subnet.
+				lower, upper := binary.BigEndian.Uint32(lb), binary.BigEndian.Uint32(ub)
+				if lower <= first {
+					lower = first + 1
+				}
+				if upper >= last {
+					upper = last - 1
+				}
+				for address := lower; address <= upper; address++ {
+					allocated[address] = true
+				}
+			}
+			poolAddresses := len(allocated)
+
+			// Count the reservations outside the pools.
+			outOfPoolReservations := 0
+			for _, reservation := range subnet.Reservations {
+				ip := net.ParseIP(reservation.IPAddress).To4()
+				if ip == nil {
+					continue
+				}
+				address := binary.BigEndian.Uint32(ip)
+				if address <= first || address >= last || allocated[address] {
+					continue
+				}
+				allocated[address] = true
+				outOfPoolReservations++
+			}
+
+			if len(allocated) < usable {
+				continue
+			}
+
+			issues = append(issues, fmt.Sprintf("%d. %s: %s in pools and %s while %d addresses are usable",
+				len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet),
+				storkutil.FormatNoun(int64(poolAddresses), "address", "es"),
+				storkutil.FormatNoun(int64(outOfPoolReservations), "out-of-pool reservation", "s"),
+				usable))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"in which the address pools and the host reservations consume all "+
+		"usable addresses. There are no addresses left for the routers, and "+
+		"the DHCP clients may be unable to communicate outside of the subnet.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that all subnets belonging to the shared networks
+// served by the reviewed daemon are served by the same set of daemons.
+// Stork aggregates the utilization of the subnets belonging to a shared
+// network. If some subnets are served by different daemons than the other
+// subnets, the aggregated utilization may be misleading. The checker uses
+// the subnet-to-daemon associations held in the database.
+func sharedNetworkDaemonsConsistency(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+	if ctx.db == nil {
+		return nil, nil
+	}
+
+	// Find the shared networks served by the reviewed daemon.
+	subnets, err := dbmodel.GetSubnetsByDaemonID(ctx.db, ctx.subjectDaemon.ID)
+	if err != nil {
+		return nil, err
+	}
+	var sharedNetworkIDs []int64
+	for _, subnet := range subnets {
+		if subnet.SharedNetworkID == 0 {
+			continue
+		}
+		found := false
+		for _, id := range sharedNetworkIDs {
+			if id == subnet.SharedNetworkID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sharedNetworkIDs = append(sharedNetworkIDs, subnet.SharedNetworkID)
+		}
+	}
+	sort.Slice(sharedNetworkIDs, func(i, j int) bool {
+		return sharedNetworkIDs[i] < sharedNetworkIDs[j]
+	})
+
+	// Returns the label of the daemon used in the report.
+	daemonLabel := func(daemon *dbmodel.Daemon) string {
+		if daemon.App != nil && daemon.App.Name != "" {
+			return fmt.Sprintf("%s/%s", daemon.App.Name, daemon.Name)
+		}
+		return fmt.Sprintf("daemon %d", daemon.ID)
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, id := range sharedNetworkIDs {
+		network, err := dbmodel.GetSharedNetworkWithSubnets(ctx.db, id)
+		if err != nil {
+			return nil, err
+		}
+		if network == nil {
+			continue
+		}
+		// Collect all daemons serving any of the subnets in the shared network.
+		allDaemons := make(map[int64]*dbmodel.Daemon)
+		var allDaemonIDs []int64
+		for _, subnet := range network.Subnets {
+			for _, ls := range subnet.LocalSubnets {
+				if _, ok := allDaemons[ls.DaemonID]; ok || ls.Daemon == nil {
+					continue
+				}
+				allDaemons[ls.DaemonID] = ls.Daemon
+				allDaemonIDs = append(allDaemonIDs, ls.DaemonID)
+			}
+		}
+		sort.Slice(allDaemonIDs, func(i, j int) bool {
+			return allDaemonIDs[i] < allDaemonIDs[j]
+		})
+		// Find the daemons not serving the particular subnets.
+		for _, subnet := range network.Subnets {
+			servingDaemons := make(map[int64]bool)
+			for _, ls := range subnet.LocalSubnets {
+				servingDaemons[ls.DaemonID] = true
+			}
+			var missingDaemons []string
+			for _, daemonID := range allDaemonIDs {
+				if !servingDaemons[daemonID] {
+					missingDaemons = append(missingDaemons, daemonLabel(allDaemons[daemonID]))
+				}
+			}
+			if len(missingDaemons) == 0 {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. shared network %s: subnet %s is not served by %s",
+				len(issues)+1, network.Name, subnet.Prefix, strings.Join(missingDaemons, ", ")))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes shared "+
+		"networks with %s not served by all daemons serving the other subnets "+
+		"in these shared networks. Stork aggregates the utilization of the subnets "+
+		"belonging to a shared network, so the utilization of these shared networks "+
+		"may be misleading. Please make sure that the shared networks are configured "+
+		"consistently on all servers.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the subnets served by the reviewed daemon
+// and by other daemons have the same address pools, prefix delegation
+// pools and options on all of them. Stork presents the subnets having the
+// same prefix as a single subnet. If their configurations differ, the
+// DHCP clients may get inconsistent leases depending on which server
+// responds. The checker finds the other daemons serving the subnets in
+// the database and compares their configurations. The options are matched
+// by the option space and the code. The code of the option specified by
+// name is resolved using the option definitions of the respective daemon.
+func subnetDaemonsConsistency(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+	if ctx.db == nil {
+		return nil, nil
+	}
+
+	type subnet struct {
+		ID         int64
+		Subnet     string
+		Pools      []keaconfig.Pool
+		PdPools    []keaconfig.PdPool
+		OptionData []keaconfig.SingleOptionData
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	// Decodes the subnets from the daemon configuration and indexes
+	// them by the canonical prefixes.
+	decodeSubnets := func(config *dbmodel.KeaConfig) (map[string]subnet, error) {
+		var decodedSharedNetworks []sharedNetwork
+		err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+		if err != nil {
+			return nil, err
+		}
+		var decodedSubnets []subnet
+		err = config.DecodeTopLevelSubnets(&decodedSubnets)
+		if err != nil {
+			return nil, err
+		}
+		for _, network := range decodedSharedNetworks {
+			decodedSubnets = append(decodedSubnets, network.Subnet4...)
+			decodedSubnets = append(decodedSubnets, network.Subnet6...)
+		}
+		subnets := make(map[string]subnet)
+		for _, s := range decodedSubnets {
+			if prefix, _ := getCanonicalPrefix(s.Subnet); prefix != "" {
+				subnets[prefix] = s
+			}
+		}
+		return subnets, nil
+	}
+
+	// Returns the sorted and comma separated list of the keys. It
+	// is used to compare the pools and options regardless of their
+	// order in the configurations.
+	joinSorted := func(keys []string) string {
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}
+	poolsKey := func(s subnet) string {
+		var keys []string
+		for _, pool := range parsePools(s.Pools) {
+			keys = append(keys, fmt.Sprintf("%s-%s", pool.lb, pool.ub))
+		}
+		return joinSorted(keys)
+	}
+	pdPoolsKey := func(s subnet) string {
+		var keys []string
+		for _, pool := range s.PdPools {
+			keys = append(keys, fmt.Sprintf("%s/%d/%d", pool.Prefix, pool.PrefixLen, pool.DelegatedLen))
+		}
+		return joinSorted(keys)
+	}
+	optionsKey := func(s subnet, resolver *optionCodeResolver) string {
+		var keys []string
+		for _, option := range s.OptionData {
+			key, _ := resolver.getOptionKey(option)
+			data := strings.ToLower(strings.Join(strings.Fields(option.Data), ""))
+			keys = append(keys, fmt.Sprintf("%s=%s", key, data))
+		}
+		return joinSorted(keys)
+	}
+
+	// Returns the label of the daemon used in the report.
+	daemonLabel := func(daemon *dbmodel.Daemon) string {
+		if daemon.App != nil && daemon.App.Name != "" {
+			return fmt.Sprintf("%s/%s", daemon.App.Name, daemon.Name)
+		}
+		return fmt.Sprintf("daemon %d", daemon.ID)
+	}
+
+	ownSubnets, err := decodeSubnets(ctx.subjectDaemon.KeaDaemon.Config)
+	if err != nil {
+		return nil, err
+	}
+	ownResolver, err := newOptionCodeResolver(ctx.subjectDaemon.Name, ctx.subjectDaemon.KeaDaemon.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSubnets, err := dbmodel.GetSubnetsByDaemonID(ctx.db, ctx.subjectDaemon.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(dbSubnets, func(i, j int) bool {
+		return dbSubnets[i].ID < dbSubnets[j].ID
+	})
+
+	// The other daemons, their decoded subnets and option code resolvers
+	// fetched so far. The daemons lacking the configuration are held as nil.
+	otherDaemons := make(map[int64]*dbmodel.Daemon)
+	otherSubnets := make(map[int64]map[string]subnet)
+	otherResolvers := make(map[int64]*optionCodeResolver)
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, dbSubnet := range dbSubnets {
+		prefix, _ := getCanonicalPrefix(dbSubnet.Prefix)
+		ownSubnet, ok := ownSubnets[prefix]
+		if !ok {
+			continue
+		}
+		localSubnets := dbSubnet.LocalSubnets
+		sort.Slice(localSubnets, func(i, j int) bool {
+			return localSubnets[i].DaemonID < localSubnets[j].DaemonID
+		})
+		var differences []string
+		for _, ls := range localSubnets {
+			if ls.DaemonID == ctx.subjectDaemon.ID {
+				continue
+			}
+			if _, ok := otherDaemons[ls.DaemonID]; !ok {
+				daemon, err := dbmodel.GetDaemonByID(ctx.db, ls.DaemonID)
+				if err != nil {
+					return nil, err
+				}
+				otherDaemons[ls.DaemonID] = nil
+				if daemon != nil && daemon.Name == ctx.subjectDaemon.Name &&
+					daemon.KeaDaemon != nil && daemon.KeaDaemon.Config != nil {
+					subnets, err := decodeSubnets(daemon.KeaDaemon.Config)
+					if err != nil {
+						return nil, err
+					}
+					resolver, err := newOptionCodeResolver(daemon.Name, daemon.KeaDaemon.Config)
+					if err != nil {
+						return nil, err
+					}
+					otherDaemons[ls.DaemonID] = daemon
+					otherSubnets[ls.DaemonID] = subnets
+					otherResolvers[ls.DaemonID] = resolver
+				}
+			}
+			daemon := otherDaemons[ls.DaemonID]
+			if daemon == nil {
+				continue
+			}
+			otherSubnet, ok := otherSubnets[ls.DaemonID][prefix]
+			if !ok {
+				continue
+			}
+			var fields []string
+			if poolsKey(ownSubnet) != poolsKey(otherSubnet) {
+				fields = append(fields, "pools")
+			}
+			if pdPoolsKey(ownSubnet) != pdPoolsKey(otherSubnet) {
+				fields = append(fields, "prefix delegation pools")
+			}
+			if optionsKey(ownSubnet, ownResolver) != optionsKey(otherSubnet, otherResolvers[ls.DaemonID]) {
+				fields = append(fields, "options")
+			}
+			if len(fields) > 0 {
+				differences = append(differences, fmt.Sprintf("%s in %s",
+					daemonLabel(daemon), strings.Join(fields, ", ")))
+			}
+		}
+		if len(differences) == 0 {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("%d. %s differs from %s",
+			len(issues)+1, formatSubnetFinding(ownSubnet.ID, prefix), strings.Join(differences, " and ")))
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"also served by other daemons which configure them differently. Stork "+
+		"presents the subnets having the same prefix as a single subnet, and the "+
+		"DHCP clients may get inconsistent leases depending on which server "+
+		"responds. Please make sure that the subnets are configured consistently "+
+		"on all servers.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying the interfaces-config of the DHCP server. If the
+// server configuration specifies no interfaces, the server does not listen
+// for the DHCP traffic at all. It is a serious misconfiguration
+// reported as an error rather than the default checker severity. If the
+// server listens on all interfaces (i.e., using the "*" wildcard), it may
+// unintentionally respond to the DHCP traffic on some interfaces in the
+// multi-interface hosts. It is merely a hint because such configuration
+// is common and often intentional.
+func interfacesConfig(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type parameters struct {
+		InterfacesConfig struct {
+			Interfaces []string
+		}
+	}
+
+	var decodedParameters parameters
+	if err := ctx.subjectDaemon.KeaDaemon.Config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+
+	interfaces := decodedParameters.InterfacesConfig.Interfaces
+	if len(interfaces) == 0 {
+		return NewReport(ctx, "The Kea {daemon} configuration specifies no interfaces "+
+			"in the interfaces-config. The server does not listen for the DHCP traffic "+
+			"on any interface, so it serves no clients. Please specify the interfaces "+
+			"on which the server should listen.").
+			referencingDaemon(ctx.subjectDaemon).
+			withSeverity(dbmodel.ConfigReportSeverityError).
+			create()
+	}
+
+	for _, iface := range interfaces {
+		if strings.TrimSpace(iface) == "*" {
+			return NewReport(ctx, "The Kea {daemon} configuration uses the \"*\" wildcard "+
+				"in the interfaces-config, so the server listens for the DHCP traffic on "+
+				"all interfaces. It may be intentional, but in the multi-interface hosts "+
+				"the server may respond to the DHCP traffic on the networks it should not "+
+				"serve. Consider specifying the interfaces explicitly.").
+				referencingDaemon(ctx.subjectDaemon).
+				create()
+		}
+	}
+
+	return nil, nil
+}
+
+// The checker verifying that the multi-threading settings of the DHCP
+// server are consistent. It reports the thread pool size of 1 and the
+// unlimited packet queue when the multi-threading is enabled, and the
+// thread pool and packet queue settings that have no effect because the
+// multi-threading is disabled. Additionally, it suggests enabling the
+// multi-threading when it is explicitly disabled on a machine with many
+// CPU cores. The checker doesn't make assumptions about the default
+// multi-threading state because it differs between the Kea versions.
+func multiThreading(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type parameters struct {
+		MultiThreading *struct {
+			EnableMultiThreading *bool
+			ThreadPoolSize       *int64
+			PacketQueueSize      *int64
+		}
+	}
+
+	var decodedParameters parameters
+	if err := ctx.subjectDaemon.KeaDaemon.Config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+
+	settings := decodedParameters.MultiThreading
+	if settings == nil || settings.EnableMultiThreading == nil {
+		return nil, nil
+	}
+
+	// Number of CPU cores above which the multi-threading is recommended.
+	const minCPUs = 4
+
+	var issues []string
+	if *settings.EnableMultiThreading {
+		if settings.ThreadPoolSize != nil && *settings.ThreadPoolSize == 1 {
+			issues = append(issues, "the thread-pool-size is 1, so the server processes "+
+				"the packets sequentially and the multi-threading only adds the overhead; "+
+				"set the thread-pool-size to 0 to use as many threads as the CPU cores or "+
+				"disable the multi-threading")
+		}
+		if settings.PacketQueueSize != nil && *settings.PacketQueueSize == 0 {
+			issues = append(issues, "the packet-queue-size is 0, so the packet queue is "+
+				"unlimited and the server may spend time on processing the stale packets "+
+				"under heavy load; consider setting a non-zero packet-queue-size")
+		}
+	} else {
+		var ignored []string
+		if settings.ThreadPoolSize != nil {
+			ignored = append(ignored, fmt.Sprintf("thread-pool-size (%d)", *settings.ThreadPoolSize))
+		}
+		if settings.PacketQueueSize != nil {
+			ignored = append(ignored, fmt.Sprintf("packet-queue-size (%d)", *settings.PacketQueueSize))
+		}
+		if len(ignored) > 0 {
+			verb := "has"
+			if len(ignored) > 1 {
+				verb = "have"
+			}
+			issues = append(issues, fmt.Sprintf("the multi-threading is disabled, so the %s "+
+				"%s no effect", strings.Join(ignored, " and the "), verb))
+		}
+		if ctx.subjectDaemon.App != nil && ctx.subjectDaemon.App.Machine != nil &&
+			ctx.subjectDaemon.App.Machine.State.Cpus >= minCPUs {
+			issues = append(issues, fmt.Sprintf("the multi-threading is disabled while "+
+				"the machine has %d CPU cores; consider enabling the multi-threading "+
+				"to improve the server performance",
+				ctx.subjectDaemon.App.Machine.State.Cpus))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	for i := range issues {
+		issues[i] = fmt.Sprintf("%d. %s", i+1, issues[i])
+	}
+
+	return NewReport(ctx, fmt.Sprintf("The Kea {daemon} configuration includes "+
+		"%s in the multi-threading settings.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "issue", "s"),
+		strings.Join(issues, "; "))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the lease sanity checks are not disabled.
+// The server with the lease-checks or the extended-info-checks set to
+// "none" silently accepts the inconsistent leases read from the lease
+// file or the database (e.g., the leases belonging to no subnet). The
+// checker reports the current settings and recommends at least the
+// warning level. The defaults are not reported because Kea enables the
+// checks by default.
+func leaseSanityChecks(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type parameters struct {
+		SanityChecks *struct {
+			LeaseChecks        *string
+			ExtendedInfoChecks *string
+		}
+	}
+
+	var decodedParameters parameters
+	if err := ctx.subjectDaemon.KeaDaemon.Config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+
+	checks := decodedParameters.SanityChecks
+	if checks == nil {
+		return nil, nil
+	}
+
+	var disabled []string
+	if checks.LeaseChecks != nil && strings.EqualFold(*checks.LeaseChecks, "none") {
+		disabled = append(disabled, fmt.Sprintf("the lease-checks is set to \"%s\", so "+
+			"the server accepts the leases that do not match the subnets configuration; "+
+			"consider setting it to \"warn\" or a stricter value", *checks.LeaseChecks))
+	}
+	if checks.ExtendedInfoChecks != nil && strings.EqualFold(*checks.ExtendedInfoChecks, "none") {
+		disabled = append(disabled, fmt.Sprintf("the extended-info-checks is set to \"%s\", "+
+			"so the server does not verify the extended information stored in the leases; "+
+			"consider setting it to \"fix\" or a stricter value", *checks.ExtendedInfoChecks))
+	}
+
+	if len(disabled) == 0 {
+		return nil, nil
+	}
+
+	for i := range disabled {
+		disabled[i] = fmt.Sprintf("%d. %s", i+1, disabled[i])
+	}
+
+	return NewReport(ctx, fmt.Sprintf("The Kea {daemon} configuration disables "+
+		"%s in the sanity-checks. The inconsistent leases loaded from the lease "+
+		"storage remain unnoticed, which may lead to the address conflicts and "+
+		"wrong lease statistics.\n%s",
+		storkutil.FormatNoun(int64(len(disabled)), "lease sanity check", "s"),
+		strings.Join(disabled, "; "))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the host reservations are within the pools
+// when the in-subnet host reservation mode is enabled and the out-of-pool
+// mode is disabled. The checker reports the subnets with the reserved
+// addresses and delegated prefixes outside of the pools in this mode. It
+// takes into account the effective reservation modes inherited from the
+// shared network and the global level. It only verifies the reservations
+// specified in the configuration file.
+func reservationsOutsideOfPools(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet struct {
+		ID           int64
+		Subnet       string
+		Pools        []keaconfig.Pool
+		PDPools      []keaconfig.PdPool
+		Reservations []struct {
+			IPAddress   string
+			IPAddresses []string
+			Prefixes    []string
+		}
+		keaconfig.ReservationModes
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+		keaconfig.ReservationModes
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets []subnet
+	err = config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet4: decodedSubnets,
+	})
+	// Get global host reservation mode settings.
+	globalModes := config.GetGlobalReservationModes()
+	if globalModes == nil {
+		return nil, errors.New("problem getting global reservation modes from Kea configuration")
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range append(network.Subnet4, network.Subnet6...) {
+			// The value set at the lowest level wins, so the subnet may
+			// disable the mode enabled globally and vice versa.
+			inSubnet := keaconfig.GetEffectiveReservationMode(func(modes keaconfig.ReservationModes) (bool, bool) {
+				return modes.IsInSubnet()
+			}, subnet.ReservationModes, network.ReservationModes, *globalModes)
+			outOfPool := keaconfig.GetEffectiveReservationMode(func(modes keaconfig.ReservationModes) (bool, bool) {
+				return modes.IsOutOfPool()
+			}, subnet.ReservationModes, network.ReservationModes, *globalModes)
+			if !inSubnet || outOfPool {
+				continue
+			}
+
+			var outside []string
+			for _, reservation := range subnet.Reservations {
+				addresses := reservation.IPAddresses
+				if reservation.IPAddress != "" {
+					addresses = append([]string{reservation.IPAddress}, addresses...)
+				}
+				for _, address := range addresses {
+					if !isAnyAddressInPools([]string{address}, subnet.Pools) {
+						outside = append(outside, address)
+					}
+				}
+				for _, prefix := range reservation.Prefixes {
+					if !isAnyPrefixInPools([]string{prefix}, subnet.PDPools) {
+						outside = append(outside, prefix)
+					}
+				}
+			}
+			if len(outside) == 0 {
+				continue
+			}
+
+			issues = append(issues, fmt.Sprintf("%d. %s: %s", len(issues)+1,
+				formatSubnetFinding(subnet.ID, subnet.Subnet), strings.Join(outside, ", ")))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the host reservations outside of the pools while the in-subnet "+
+		"reservation mode is enabled and the out-of-pool reservation mode is "+
+		"disabled. In this mode, the reserved addresses and delegated prefixes are "+
+		"expected to be within the pools, and the reservations outside of them may "+
+		"not be served. Move the reservations into the pools or enable the "+
+		"out-of-pool reservation mode.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "subnet", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker validating the combinations of the DDNS flags at the global,
+// shared network and subnet levels. It takes into account the values
+// inherited from the upper levels and reports the scopes where the flags
+// specified at that scope result in the settings that are not effective
+// or likely won't behave as intended, e.g., the DDNS overrides enabled
+// while sending the DDNS updates is disabled.
+func ddnsFlags(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type ddnsParameters struct {
+		DDNSSendUpdates          *bool
+		DDNSOverrideNoUpdate     *bool
+		DDNSOverrideClientUpdate *bool
+		DDNSReplaceClientName    *string
+		DDNSQualifyingSuffix     *string
+	}
+	type subnet struct {
+		ID     int64
+		Subnet string
+		ddnsParameters
+	}
+	type sharedNetwork struct {
+		Name    string
+		Subnet4 []subnet
+		Subnet6 []subnet
+		ddnsParameters
+	}
+	type globalParameters struct {
+		DHCPDDNS *struct {
+			EnableUpdates *bool
+		}
+		ddnsParameters
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	var decodedGlobals globalParameters
+	if err := config.DecodeTopLevelParameters(&decodedGlobals); err != nil {
+		return nil, err
+	}
+	var decodedSharedNetworks []sharedNetwork
+	if err := config.DecodeSharedNetworks(&decodedSharedNetworks); err != nil {
+		return nil, err
+	}
+	var decodedSubnets []subnet
+	if err := config.DecodeTopLevelSubnets(&decodedSubnets); err != nil {
+		return nil, err
+	}
+
+	// The DDNS updates are disabled by default in the dhcp-ddns map.
+	enableUpdates := decodedGlobals.DHCPDDNS != nil && decodedGlobals.DHCPDDNS.EnableUpdates != nil &&
+		*decodedGlobals.DHCPDDNS.EnableUpdates
+
+	// Returns the flags with the values inherited from the upper levels.
+	// The first level is the lowest.
+	getEffective := func(levels ...ddnsParameters) (effective ddnsParameters) {
+		for i := len(levels) - 1; i >= 0; i-- {
+			level := levels[i]
+			if level.DDNSSendUpdates != nil {
+				effective.DDNSSendUpdates = level.DDNSSendUpdates
+			}
+			if level.DDNSOverrideNoUpdate != nil {
+				effective.DDNSOverrideNoUpdate = level.DDNSOverrideNoUpdate
+			}
+			if level.DDNSOverrideClientUpdate != nil {
+				effective.DDNSOverrideClientUpdate = level.DDNSOverrideClientUpdate
+			}
+			if level.DDNSReplaceClientName != nil {
+				effective.DDNSReplaceClientName = level.DDNSReplaceClientName
+			}
+			if level.DDNSQualifyingSuffix != nil {
+				effective.DDNSQualifyingSuffix = level.DDNSQualifyingSuffix
+			}
+		}
+		return
+	}
+
+	// Returns the conflicts caused by the flags specified at the given
+	// level. The effective flags include the values inherited from the
+	// upper levels.
+	getConflicts := func(level, effective ddnsParameters, isGlobal bool) (conflicts []string) {
+		sendUpdates := effective.DDNSSendUpdates == nil || *effective.DDNSSendUpdates
+		disabledReason := ""
+		switch {
+		case !sendUpdates:
+			disabledReason = "ddns-send-updates is disabled"
+		case !enableUpdates:
+			disabledReason = "enable-updates is disabled in dhcp-ddns"
+		}
+		if disabledReason != "" {
+			sendUpdatesSpecified := level.DDNSSendUpdates != nil || (isGlobal && !enableUpdates)
+			if effective.DDNSOverrideNoUpdate != nil && *effective.DDNSOverrideNoUpdate &&
+				(level.DDNSOverrideNoUpdate != nil || sendUpdatesSpecified) {
+				conflicts = append(conflicts, fmt.Sprintf("ddns-override-no-update is enabled while %s", disabledReason))
+			}
+			if effective.DDNSOverrideClientUpdate != nil && *effective.DDNSOverrideClientUpdate &&
+				(level.DDNSOverrideClientUpdate != nil || sendUpdatesSpecified) {
+				conflicts = append(conflicts, fmt.Sprintf("ddns-override-client-update is enabled while %s", disabledReason))
+			}
+		}
+		if effective.DDNSReplaceClientName != nil &&
+			(*effective.DDNSReplaceClientName == "always" || *effective.DDNSReplaceClientName == "when-not-present") &&
+			(effective.DDNSQualifyingSuffix == nil || *effective.DDNSQualifyingSuffix == "") &&
+			(level.DDNSReplaceClientName != nil || level.DDNSQualifyingSuffix != nil) {
+			conflicts = append(conflicts, fmt.Sprintf("ddns-replace-client-name is %s while ddns-qualifying-suffix "+
+				"is empty, so the generated host names are not fully qualified", *effective.DDNSReplaceClientName))
+		}
+		return
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+	appendIssue := func(scope string, conflicts []string) {
+		if len(conflicts) > 0 {
+			issues = append(issues, fmt.Sprintf("%d. %s: %s", len(issues)+1, scope,
+				strings.Join(conflicts, " and ")))
+		}
+	}
+
+	global := decodedGlobals.ddnsParameters
+	checkSubnets := func(subnets []subnet, network ddnsParameters) {
+		for _, subnet := range subnets {
+			conflicts := getConflicts(subnet.ddnsParameters,
+				getEffective(subnet.ddnsParameters, network, global), false)
+			appendIssue(fmt.Sprintf("subnet %s", formatSubnetFinding(subnet.ID, subnet.Subnet)), conflicts)
+		}
+	}
+
+	appendIssue("global", getConflicts(global, getEffective(global), true))
+	for _, network := range decodedSharedNetworks {
+		appendIssue(fmt.Sprintf("shared network %s", network.Name),
+			getConflicts(network.ddnsParameters, getEffective(network.ddnsParameters, global), false))
+		checkSubnets(network.Subnet4, network.ddnsParameters)
+		checkSubnets(network.Subnet6, network.ddnsParameters)
+	}
+	checkSubnets(decodedSubnets, ddnsParameters{})
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the inconsistent DDNS settings. These settings are not effective or "+
+		"likely won't behave as intended.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "scope", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the host reservations specify the option
+// spaces and the address fields appropriate for the daemon's family. The
+// DHCPv4 reservation carrying the DHCPv6 options, or the ip-addresses or
+// prefixes lists, and the DHCPv6 reservation carrying the DHCPv4 options
+// or the ip-address field indicate the configuration built for the wrong
+// family. Kea rejects or ignores such reservations. The checker verifies
+// the global reservations and the reservations in the subnets.
+func reservationsFamilyMismatch(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type reservation struct {
+		HWAddress   string
+		ClientID    string
+		DUID        string
+		CircuitID   string
+		FlexID      string
+		Hostname    string
+		IPAddress   string
+		IPAddresses []string
+		Prefixes    []string
+		OptionData  []keaconfig.SingleOptionData
+	}
+	type subnet struct {
+		ID           int64
+		Subnet       string
+		Reservations []reservation
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets []subnet
+	err = config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	// Parse global reservations.
+	var global struct {
+		Reservations []reservation
+	}
+	err = config.DecodeTopLevelParameters(&global)
+	if err != nil {
+		return nil, err
+	}
+
+	subnets := []subnet{}
+	for _, network := range decodedSharedNetworks {
+		subnets = append(subnets, network.Subnet4...)
+		subnets = append(subnets, network.Subnet6...)
+	}
+	subnets = append(subnets, decodedSubnets...)
+
+	isDHCPv4 := ctx.subjectDaemon.Name == dbmodel.DaemonNameDHCPv4
+	expectedFamily, otherFamily := "DHCPv4", "DHCPv6"
+	otherSpace := string(keaconfig.DHCPv6OptionSpace)
+	if !isDHCPv4 {
+		expectedFamily, otherFamily = otherFamily, expectedFamily
+		otherSpace = string(keaconfig.DHCPv4OptionSpace)
+	}
+
+	// Returns the identifier and the value of the reservation.
+	getReservationLabel := func(reservation reservation) string {
+		for _, identifier := range []struct {
+			name  string
+			value string
+		}{
+			{"hw-address", reservation.HWAddress},
+			{"client-id", reservation.ClientID},
+			{"duid", reservation.DUID},
+			{"circuit-id", reservation.CircuitID},
+			{"flex-id", reservation.FlexID},
+			{"hostname", reservation.Hostname},
+		} {
+			if identifier.value != "" {
+				return fmt.Sprintf("%s=%s", identifier.name, identifier.value)
+			}
+		}
+		return "reservation without identifier"
+	}
+
+	// Returns the list of the mismatches found in the reservation.
+	getMismatches := func(reservation reservation) (mismatches []string) {
+		if isDHCPv4 {
+			if len(reservation.IPAddresses) > 0 {
+				mismatches = append(mismatches, "ip-addresses")
+			}
+			if len(reservation.Prefixes) > 0 {
+				mismatches = append(mismatches, "prefixes")
+			}
+		} else if reservation.IPAddress != "" {
+			mismatches = append(mismatches, "ip-address")
+		}
+		for _, option := range reservation.OptionData {
+			if option.Space != otherSpace {
+				continue
+			}
+			if option.Name != "" {
+				mismatches = append(mismatches, fmt.Sprintf("option %s in %s space", option.Name, option.Space))
+			} else {
+				mismatches = append(mismatches, fmt.Sprintf("option %d in %s space", option.Code, option.Space))
+			}
+		}
+		return
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	// Appends the issues for the reservations in the specified scope.
+	appendIssues := func(scope string, reservations []reservation) {
+		for _, reservation := range reservations {
+			mismatches := getMismatches(reservation)
+			if len(mismatches) == 0 {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. %s, %s: %s", len(issues)+1, scope,
+				getReservationLabel(reservation), strings.Join(mismatches, ", ")))
+		}
+	}
+
+	appendIssues("global", global.Reservations)
+	for _, subnet := range subnets {
+		appendIssues(fmt.Sprintf("subnet %s", formatSubnetFinding(subnet.ID, subnet.Subnet)), subnet.Reservations)
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"specifying the %s options or address fields while the server serves the "+
+		"%s clients. It indicates that the reservations were built for the wrong "+
+		"family and may be rejected or not served as intended.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "host reservation", "s"),
+		otherFamily, expectedFamily, joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the lengths of the prefixes reserved for the
+// DHCPv6 clients match the delegated lengths of the prefix delegation
+// pools in their subnets. Kea may reject such reservations or delegate
+// the prefixes of the unexpected lengths. The checker verifies the
+// reservations in the configuration file and, when the host_cmds hooks
+// library is used, the reservations in the host database. The subnets
+// without the prefix delegation pools are not verified.
+func prefixReservationsDelegatedLength(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type reservation struct {
+		HWAddress string
+		DUID      string
+		FlexID    string
+		Hostname  string
+		Prefixes  []string
+	}
+	type subnet6 struct {
+		ID           int64
+		Subnet       string
+		PDPools      []keaconfig.PdPool
+		Reservations []reservation
+	}
+	type sharedNetwork struct {
+		Subnet6 []subnet6
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	// Parse top-level subnets.
+	var decodedSubnets6 []subnet6
+	err = config.DecodeTopLevelSubnets(&decodedSubnets6)
+	if err != nil {
+		return nil, err
+	}
+	decodedSharedNetworks = append(decodedSharedNetworks, sharedNetwork{
+		Subnet6: decodedSubnets6,
+	})
+	// Get hosts from the database when libdhcp_host_cmds hooks library is used.
+	_, dbHosts, err := getDaemonHostsAndIndexBySubnet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Returns the identifier and the value of the configured reservation.
+	getReservationLabel := func(reservation reservation) string {
+		for _, identifier := range []struct {
+			name  string
+			value string
+		}{
+			{"hw-address", reservation.HWAddress},
+			{"duid", reservation.DUID},
+			{"flex-id", reservation.FlexID},
+			{"hostname", reservation.Hostname},
+		} {
+			if identifier.value != "" {
+				return fmt.Sprintf("%s=%s", identifier.name, identifier.value)
+			}
+		}
+		return "reservation without identifier"
+	}
+
+	// Returns the identifier and the value of the reservation from the
+	// host database.
+	getDBHostLabel := func(host dbmodel.Host) string {
+		if len(host.HostIdentifiers) > 0 {
+			return fmt.Sprintf("%s=%s", host.HostIdentifiers[0].Type, host.HostIdentifiers[0].ToHex(":"))
+		}
+		if host.Hostname != "" {
+			return fmt.Sprintf("hostname=%s", host.Hostname)
+		}
+		return "reservation without identifier"
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		for _, subnet := range network.Subnet6 {
+			if len(subnet.PDPools) == 0 {
+				continue
+			}
+			// Collect the delegated lengths of the pools.
+			var delegatedLens []string
+			delegatedLensSet := make(map[int]bool)
+			for _, pool := range subnet.PDPools {
+				if !delegatedLensSet[pool.DelegatedLen] {
+					delegatedLensSet[pool.DelegatedLen] = true
+					delegatedLens = append(delegatedLens, fmt.Sprintf("/%d", pool.DelegatedLen))
+				}
+			}
+
+			// Returns the issue description if the prefix length doesn't
+			// match any of the delegated lengths.
+			checkPrefix := func(label, prefix string) string {
+				parsedPrefix := storkutil.ParseIP(prefix)
+				if parsedPrefix == nil || !parsedPrefix.Prefix || delegatedLensSet[parsedPrefix.PrefixLength] {
+					return ""
+				}
+				return fmt.Sprintf("%d. %s, %s: %s has length /%d while the pools delegate %s",
+					len(issues)+1, formatSubnetFinding(subnet.ID, subnet.Subnet), label, prefix,
+					parsedPrefix.PrefixLength, strings.Join(delegatedLens, ", "))
+			}
+
+			for _, reservation := range subnet.Reservations {
+				for _, prefix := range reservation.Prefixes {
+					if issue := checkPrefix(getReservationLabel(reservation), prefix); issue != "" {
+						issues = append(issues, issue)
+					}
+				}
+			}
+			for _, host := range dbHosts[subnet.ID] {
+				for _, ipReservation := range host.IPReservations {
+					if !ipReservation.IsPrefix() {
+						continue
+					}
+					if issue := checkPrefix(getDBHostLabel(host), ipReservation.Address); issue != "" {
+						issues = append(issues, issue)
+					}
+				}
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"reserving the delegated prefixes whose lengths differ from the delegated "+
+		"lengths of the prefix delegation pools in their subnets. Kea may reject "+
+		"such reservations or the clients may receive the prefixes of the "+
+		"unexpected lengths.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "prefix reservation", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the host reservations use the identifier types
+// listed in the host-reservation-identifiers global parameter. Kea matches
+// the clients against the reservations using only the listed identifier
+// types, so the reservations using other identifiers never match. The
+// checker is skipped when the parameter is not specified explicitly because
+// its default value depends on the Kea version.
+func reservationIdentifiersNotListed(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type reservation struct {
+		HWAddress string
+		DUID      string
+		CircuitID string
+		ClientID  string
+		FlexID    string
+	}
+	type subnet struct {
+		ID           int64
+		Subnet       string
+		Reservations []reservation
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+	type parameters struct {
+		HostReservationIdentifiers *[]string
+		Reservations               []reservation
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	var decodedParameters parameters
+	if err := config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+	if decodedParameters.HostReservationIdentifiers == nil {
+		return nil, nil
+	}
+	listedTypes := make(map[string]bool)
+	for _, identifierType := range *decodedParameters.HostReservationIdentifiers {
+		listedTypes[identifierType] = true
+	}
+
+	// Global subnets.
+	var decodedSubnets []subnet
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	// Subnets belonging to the shared networks.
+	var decodedSharedNetworks []sharedNetwork
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, network.Subnet4...)
+		decodedSubnets = append(decodedSubnets, network.Subnet6...)
+	}
+	// Get hosts from the database when libdhcp_host_cmds hooks library is used.
+	_, dbHosts, err := getDaemonHostsAndIndexBySubnet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	// Appends the issues for the identifiers of the reservation which
+	// types are not listed. The identifiers are specified as the
+	// type/value pairs.
+	checkIdentifiers := func(scope string, identifiers ...string) {
+		for i := 0; i+1 < len(identifiers); i += 2 {
+			identifierType, value := identifiers[i], identifiers[i+1]
+			if value == "" || listedTypes[identifierType] {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. %s, %s=%s: %s is not listed",
+				len(issues)+1, scope, identifierType, value, identifierType))
+		}
+	}
+
+	// Returns the identifiers of the configured reservation as the
+	// type/value pairs.
+	getReservationIdentifiers := func(r reservation) []string {
+		return []string{
+			"hw-address", r.HWAddress,
+			"duid", r.DUID,
+			"circuit-id", r.CircuitID,
+			"client-id", r.ClientID,
+			"flex-id", r.FlexID,
+		}
+	}
+
+	for _, r := range decodedParameters.Reservations {
+		checkIdentifiers("global reservation", getReservationIdentifiers(r)...)
+	}
+
+	for _, s := range decodedSubnets {
+		scope := fmt.Sprintf("subnet %s", formatSubnetFinding(s.ID, s.Subnet))
+		for _, r := range s.Reservations {
+			checkIdentifiers(scope, getReservationIdentifiers(r)...)
+		}
+		if s.ID == 0 {
+			continue
+		}
+		for _, host := range dbHosts[s.ID] {
+			for _, identifier := range host.HostIdentifiers {
+				checkIdentifiers(scope, identifier.Type, identifier.ToHex(":"))
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"using the identifier types not listed in the host-reservation-identifiers "+
+		"parameter. Kea never matches the clients against such reservations. "+
+		"Please add the identifier types to the host-reservation-identifiers "+
+		"list or change the reservations.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "host reservation", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Validates the hostname specified in a host reservation. It returns an
+// empty string when the hostname is valid. Otherwise, it returns the
+// description of the problem. The hostname must not be blank nor padded
+// with whitespace. It must consist of the labels comprising 1 to 63
+// letters, digits and hyphens, not starting nor ending with a hyphen.
+// The trailing dot of the fully qualified name is allowed.
+func validateReservationHostname(hostname string) string {
+	if strings.TrimSpace(hostname) == "" {
+		return "is blank"
+	}
+	if strings.TrimSpace(hostname) != hostname {
+		return "has leading or trailing whitespace"
+	}
+	name := strings.TrimSuffix(hostname, ".")
+	if len(name) > 253 {
+		return "is invalid"
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 ||
+			label[0] == '-' || label[len(label)-1] == '-' {
+			return "is invalid"
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') &&
+				(c < '0' || c > '9') && c != '-' {
+				return "is invalid"
+			}
+		}
+	}
+	return ""
+}
+
+// The checker verifying that the hostnames specified in the host
+// reservations are not blank and follow the basic hostname rules. An
+// empty or whitespace-only hostname is usually a data-entry error and
+// causes the DHCP server to send odd DDNS updates. It checks the
+// reservations in the configuration and, when the host_cmds hooks library
+// is loaded, the reservations in the host database. The empty hostname
+// of the reservation in the host database is equivalent to no hostname,
+// so it is not reported.
+func reservationHostname(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type reservation struct {
+		HWAddress string
+		DUID      string
+		CircuitID string
+		ClientID  string
+		FlexID    string
+		IPAddress string
+		Hostname  *string
+	}
+	type subnet struct {
+		ID           int64
+		Subnet       string
+		Reservations []reservation
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+	type parameters struct {
+		Reservations []reservation
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	var decodedParameters parameters
+	if err := config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+	// Global subnets.
+	var decodedSubnets []subnet
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	// Subnets belonging to the shared networks.
+	var decodedSharedNetworks []sharedNetwork
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, network.Subnet4...)
+		decodedSubnets = append(decodedSubnets, network.Subnet6...)
+	}
+	// Get hosts from the database when libdhcp_host_cmds hooks library is used.
+	_, dbHosts, err := getDaemonHostsAndIndexBySubnet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []string
+
+	// Appends the finding for the reservation if its hostname is not valid.
+	// The reservation is identified by the first non-empty identifier
+	// specified as the type/value pairs.
+	checkHostname := func(scope, hostname string, identifiers ...string) {
+		problem := validateReservationHostname(hostname)
+		if problem == "" {
+			return
+		}
+		for i := 0; i+1 < len(identifiers); i += 2 {
+			if identifiers[i+1] != "" {
+				scope = fmt.Sprintf("%s, %s=%s", scope, identifiers[i], identifiers[i+1])
+				break
+			}
+		}
+		findings = append(findings, fmt.Sprintf("%d. %s: hostname %q %s",
+			len(findings)+1, scope, hostname, problem))
+	}
+
+	// Checks the configured reservations having the hostname specified.
+	checkReservations := func(scope string, reservations []reservation) {
+		for _, r := range reservations {
+			if r.Hostname == nil {
+				continue
+			}
+			checkHostname(scope, *r.Hostname,
+				"hw-address", r.HWAddress,
+				"duid", r.DUID,
+				"circuit-id", r.CircuitID,
+				"client-id", r.ClientID,
+				"flex-id", r.FlexID,
+				"ip-address", r.IPAddress)
+		}
+	}
+
+	checkReservations("global reservation", decodedParameters.Reservations)
+
+	for _, s := range decodedSubnets {
+		scope := fmt.Sprintf("subnet %s", formatSubnetFinding(s.ID, s.Subnet))
+		checkReservations(scope, s.Reservations)
+		if s.ID == 0 {
+			continue
+		}
+		for _, host := range dbHosts[s.ID] {
+			if host.Hostname == "" {
+				continue
+			}
+			var identifiers []string
+			for _, identifier := range host.HostIdentifiers {
+				identifiers = append(identifiers, identifier.Type, identifier.ToHex(":"))
+			}
+			checkHostname(scope, host.Hostname, identifiers...)
+		}
+	}
+
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	maxFindings, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the blank or invalid hostnames. It is usually a data-entry error "+
+		"causing unexpected DDNS updates. Please correct or remove the hostnames.\n%s",
+		storkutil.FormatNoun(int64(len(findings)), "host reservation", "s"),
+		joinFindings(findings, maxFindings))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the host reservations in the host database
+// reference the subnets configured in the daemon. Kea silently ignores the
+// reservations belonging to the subnets it doesn't know, so they are likely
+// leftovers after the subnet removal or renumbering. The check is performed
+// only when the host_cmds hooks library is loaded. The global reservations
+// are not checked.
+func reservationsUnknownSubnet(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	if ctx.db == nil {
+		return nil, nil
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+	if _, _, present := config.GetHooksLibrary("libdhcp_host_cmds"); !present {
+		return nil, nil
+	}
+
+	// Global subnets.
+	var decodedSubnets []minimalSubnet
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+	// Subnets belonging to the shared networks.
+	var decodedSharedNetworks []struct {
+		Subnet4 []minimalSubnet
+		Subnet6 []minimalSubnet
+	}
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, network.Subnet4...)
+		decodedSubnets = append(decodedSubnets, network.Subnet6...)
+	}
+	configuredSubnetIDs := make(map[int64]bool, len(decodedSubnets))
+	for _, s := range decodedSubnets {
+		configuredSubnetIDs[s.ID] = true
+	}
+
+	hosts, _, err := dbmodel.GetHostsByDaemonID(ctx.db, ctx.subjectDaemon.ID, dbmodel.HostDataSourceAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []string
+	for _, host := range hosts {
+		if host.SubnetID == 0 {
+			continue
+		}
+		// The host is associated with the subnet known to Stork. Find the
+		// subnet ID used by this daemon.
+		localSubnetID := int64(0)
+		prefix := ""
+		if host.Subnet != nil {
+			prefix = host.Subnet.Prefix
+			for _, ls := range host.Subnet.LocalSubnets {
+				if ls.DaemonID == ctx.subjectDaemon.ID {
+					localSubnetID = ls.LocalSubnetID
+					break
+				}
+			}
+		}
+		if localSubnetID != 0 && configuredSubnetIDs[localSubnetID] {
+			continue
+		}
+		var identifiers []string
+		for _, identifier := range host.HostIdentifiers {
+			identifiers = append(identifiers, fmt.Sprintf("%s=%s", identifier.Type, identifier.ToHex(":")))
+		}
+		findings = append(findings, fmt.Sprintf("%d. %s: subnet %s",
+			len(findings)+1, strings.Join(identifiers, ", "), formatSubnetFinding(localSubnetID, prefix)))
+	}
+
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	maxFindings, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} host database includes %s "+
+		"referencing the subnets not present in the configuration. The server "+
+		"ignores these reservations. Please move them to the configured subnets "+
+		"or remove them.\n%s",
+		storkutil.FormatNoun(int64(len(findings)), "host reservation", "s"),
+		joinFindings(findings, maxFindings))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Type of the standard DHCP option compared with the option definitions
+// specified in the configuration.
+type standardOptionType struct {
+	name       string
+	optionType keaconfig.DHCPOptionType
+	array      bool
+}
+
+// Returns the human-readable option type.
+func (t standardOptionType) String() string {
+	if t.array {
+		return fmt.Sprintf("%s array", t.optionType)
+	}
+	return t.optionType
+}
+
+// Commonly used standard DHCPv4 options by code. The list is not complete.
+var standardDHCPv4OptionTypes = map[uint16]standardOptionType{
+	1:   {"subnet-mask", keaconfig.IPv4AddressOption, false},
+	2:   {"time-offset", "int32", false},
+	3:   {"routers", keaconfig.IPv4AddressOption, true},
+	4:   {"time-servers", keaconfig.IPv4AddressOption, true},
+	5:   {"name-servers", keaconfig.IPv4AddressOption, true},
+	6:   {"domain-name-servers", keaconfig.IPv4AddressOption, true},
+	7:   {"log-servers", keaconfig.IPv4AddressOption, true},
+	12:  {"host-name", keaconfig.StringOption, false},
+	15:  {"domain-name", keaconfig.FqdnOption, false},
+	23:  {"default-ip-ttl", keaconfig.Uint8Option, false},
+	26:  {"interface-mtu", keaconfig.Uint16Option, false},
+	28:  {"broadcast-address", keaconfig.IPv4AddressOption, false},
+	42:  {"ntp-servers", keaconfig.IPv4AddressOption, true},
+	44:  {"netbios-name-servers", keaconfig.IPv4AddressOption, true},
+	51:  {"dhcp-lease-time", keaconfig.Uint32Option, false},
+	54:  {"dhcp-server-identifier", keaconfig.IPv4AddressOption, false},
+	58:  {"dhcp-renewal-time", keaconfig.Uint32Option, false},
+	59:  {"dhcp-rebinding-time", keaconfig.Uint32Option, false},
+	60:  {"vendor-class-identifier", keaconfig.StringOption, false},
+	66:  {"tftp-server-name", keaconfig.StringOption, false},
+	67:  {"boot-file-name", keaconfig.StringOption, false},
+	119: {"domain-search", keaconfig.FqdnOption, true},
+	150: {"tftp-server-address", keaconfig.IPv4AddressOption, true},
+}
+
+// Commonly used standard DHCPv6 options by code. The list is not complete.
+var standardDHCPv6OptionTypes = map[uint16]standardOptionType{
+	7:  {"preference", keaconfig.Uint8Option, false},
+	12: {"unicast", keaconfig.IPv6AddressOption, false},
+	21: {"sip-server-dns", keaconfig.FqdnOption, true},
+	22: {"sip-server-addr", keaconfig.IPv6AddressOption, true},
+	23: {"dns-servers", keaconfig.IPv6AddressOption, true},
+	24: {"domain-search", keaconfig.FqdnOption, true},
+	27: {"nis-servers", keaconfig.IPv6AddressOption, true},
+	31: {"sntp-servers", keaconfig.IPv6AddressOption, true},
+	32: {"information-refresh-time", keaconfig.Uint32Option, false},
+	59: {"bootfile-url", keaconfig.StringOption, false},
+	60: {"bootfile-param", keaconfig.TupleOption, true},
+}
+
+// Resolves the names of the DHCP options specified without the codes to
+// the option codes. It allows for matching the options specified by name
+// with the same options specified by code. The names are resolved using
+// the option definitions in the option-def list, and the standard option
+// definitions in the top-level option space.
+type optionCodeResolver struct {
+	defaultSpace string
+	codes        map[string]uint16
+}
+
+// Creates the option code resolver for the DHCP daemon configuration.
+func newOptionCodeResolver(daemonName string, config *dbmodel.KeaConfig) (*optionCodeResolver, error) {
+	resolver := &optionCodeResolver{
+		defaultSpace: string(keaconfig.DHCPv4OptionSpace),
+		codes:        make(map[string]uint16),
+	}
+	standardTypes := standardDHCPv4OptionTypes
+	if daemonName == dbmodel.DaemonNameDHCPv6 {
+		resolver.defaultSpace = string(keaconfig.DHCPv6OptionSpace)
+		standardTypes = standardDHCPv6OptionTypes
+	}
+	for code, standardType := range standardTypes {
+		resolver.codes[fmt.Sprintf("%s/%s", resolver.defaultSpace, standardType.name)] = code
+	}
+
+	type parameters struct {
+		OptionDef []struct {
+			Name  string
+			Code  uint16
+			Space string
+		}
+	}
+	var decodedParameters parameters
+	if err := config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+	// The custom definitions take precedence over the standard ones.
+	for _, def := range decodedParameters.OptionDef {
+		if def.Name == "" || def.Code == 0 {
+			continue
+		}
+		space := def.Space
+		if space == "" {
+			space = resolver.defaultSpace
+		}
+		resolver.codes[fmt.Sprintf("%s/%s", space, def.Name)] = def.Code
+	}
+	return resolver, nil
+}
+
+// Returns the key identifying the option within a configuration scope
+// and the option label used in the reports. The key comprises the option
+// space and the option code. The code is resolved from the option name if
+// it is not specified. If it cannot be resolved, the name is used instead.
+func (r *optionCodeResolver) getOptionKey(option keaconfig.SingleOptionData) (string, string) {
+	space := option.Space
+	if space == "" {
+		space = r.defaultSpace
+	}
+	code := option.Code
+	if code == 0 {
+		code = r.codes[fmt.Sprintf("%s/%s", space, option.Name)]
+	}
+	label := option.Name
+	if code != 0 {
+		label = fmt.Sprint(code)
+	}
+	return fmt.Sprintf("%s/%s", space, label), label
+}
+
+// The checker verifying that the option definitions specified in the
+// option-def list do not redefine the standard DHCP options using
+// different types. The clients expect the standard option formats, so
+// such redefinitions break the interoperability. Only the definitions in
+// the top-level option spaces (dhcp4 and dhcp6) are compared.
+func optionDefinitionsStandardConflict(ctx *ReviewContext) (*Report, error) {
+	standardTypes := standardDHCPv4OptionTypes
+	defaultSpace := keaconfig.DHCPv4OptionSpace
+	switch ctx.subjectDaemon.Name {
+	case dbmodel.DaemonNameDHCPv4:
+	case dbmodel.DaemonNameDHCPv6:
+		standardTypes = standardDHCPv6OptionTypes
+		defaultSpace = keaconfig.DHCPv6OptionSpace
+	default:
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type optionDef struct {
+		Name  string
+		Code  uint16
+		Type  string
+		Array bool
+		Space string
+	}
+	type parameters struct {
+		OptionDef []optionDef
+	}
+
+	var decodedParameters parameters
+	if err := ctx.subjectDaemon.KeaDaemon.Config.DecodeTopLevelParameters(&decodedParameters); err != nil {
+		return nil, err
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+	for _, def := range decodedParameters.OptionDef {
+		if def.Space != "" && def.Space != string(defaultSpace) {
+			continue
+		}
+		standardType, ok := standardTypes[def.Code]
+		if !ok {
+			continue
+		}
+		configuredType := standardOptionType{optionType: def.Type, array: def.Array}
+		if configuredType.optionType == standardType.optionType && configuredType.array == standardType.array {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("%d. option code %d (%s): standard type %s, configured type %s",
+			len(issues)+1, def.Code, standardType.name, standardType, configuredType))
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"redefining the standard DHCP options with different types. The DHCP "+
+		"clients expect the standard option formats, so they may be unable to "+
+		"interpret such options. Please remove these definitions or use the "+
+		"option codes not reserved for the standard options.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "option definition", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Default minimum number of addresses in a pool used by the
+// pool_size_below_minimum checker when the server database is unavailable.
+const defaultMinPoolSize = 10
+
+// Returns the number of addresses in the parsed pool. The big integer is
+// used because the IPv6 pools may be larger than the uint64 range.
+func (p parsedPool) size() *big.Int {
+	size := new(big.Int).SetBytes(p.ub)
+	size.Sub(size, new(big.Int).SetBytes(p.lb))
+	return size.Add(size, big.NewInt(1))
+}
+
+// The checker verifying that the address pools contain at least the
+// minimum number of addresses. The threshold is held in the
+// min_pool_size setting. The pools smaller than the threshold may
+// be exhausted by the expected client population. Zero threshold
+// disables the checker.
+func poolSizeBelowMinimum(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 &&
+		ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	minPoolSize := int64(defaultMinPoolSize)
+	if ctx.db != nil {
+		var err error
+		minPoolSize, err = dbmodel.GetSettingInt(ctx.db, "min_pool_size")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if minPoolSize <= 0 {
+		return nil, nil
+	}
+	threshold := big.NewInt(minPoolSize)
+
+	type subnet struct {
+		ID     int64
+		Subnet string
+		Pools  []keaconfig.Pool
+	}
+	type sharedNetwork struct {
+		Subnet4 []subnet
+		Subnet6 []subnet
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Global subnets.
+	var decodedSubnets []subnet
+	err := config.DecodeTopLevelSubnets(&decodedSubnets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subnets belonging to the shared networks.
+	var decodedSharedNetworks []sharedNetwork
+	err = config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, network.Subnet4...)
+		decodedSubnets = append(decodedSubnets, network.Subnet6...)
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, s := range decodedSubnets {
+		formattedSubnet := formatSubnetFinding(s.ID, s.Subnet)
+		for _, pool := range parsePools(s.Pools) {
+			size := pool.size()
+			if size.Cmp(threshold) >= 0 {
+				continue
+			}
+			issues = append(issues, fmt.Sprintf("%d. subnet %s: pool %s has %s",
+				len(issues)+1, formattedSubnet, pool.pool,
+				storkutil.FormatNoun(size.Int64(), "address", "es")))
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with fewer than %s. The pools this small may be quickly exhausted by the "+
+		"clients. Consider extending them or lowering the min_pool_size setting "+
+		"if they are intentionally small.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "address pool", "s"),
+		storkutil.FormatNoun(minPoolSize, "address", "es"), joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// The checker verifying that the subnets belonging to the same shared
+// network use the same effective match-client-id value. The value is
+// inherited from the shared network and the global level when it is not
+// specified at the subnet level, and it defaults to true. Mixing the
+// values causes the clients to get different leases as they move between
+// the subnets of the shared network. The checker reports the subnets with
+// the values diverging from the value effective at the shared network
+// level.
+func sharedNetworkMatchClientIDConsistency(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv4 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	type subnet4 struct {
+		ID            int64
+		Subnet        string
+		MatchClientID *bool
+	}
+	type sharedNetwork struct {
+		Name          string
+		MatchClientID *bool
+		Subnet4       []subnet4
+	}
+	type globals struct {
+		MatchClientID *bool
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	// Parse shared-networks list.
+	var decodedSharedNetworks []sharedNetwork
+	err := config.DecodeSharedNetworks(&decodedSharedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse global match-client-id.
+	var decodedGlobals globals
+	if err = config.DecodeTopLevelParameters(&decodedGlobals); err != nil {
+		return nil, err
+	}
+
+	// Returns the first non-nil value from the specified ones or the
+	// Kea default.
+	effective := func(values ...*bool) bool {
+		for _, value := range values {
+			if value != nil {
+				return *value
+			}
+		}
+		return true
+	}
+
+	maxIssues, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var issues []string
+
+	for _, network := range decodedSharedNetworks {
+		networkValue := effective(network.MatchClientID, decodedGlobals.MatchClientID)
+		var divergingSubnets []string
+		for _, subnet := range network.Subnet4 {
+			if effective(subnet.MatchClientID, network.MatchClientID, decodedGlobals.MatchClientID) == networkValue {
+				continue
+			}
+			formattedSubnet := formatSubnetFinding(subnet.ID, subnet.Subnet)
+			divergingSubnets = append(divergingSubnets, formattedSubnet)
+		}
+		if len(divergingSubnets) == 0 {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("%d. shared network %s with match-client-id %t: %s %s",
+			len(issues)+1, network.Name, networkValue,
+			storkutil.FormatNoun(int64(len(divergingSubnets)), "subnet", "s"),
+			strings.Join(divergingSubnets, ", ")))
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"with the subnets using a different match-client-id value than the shared network. "+
+		"The clients may get different leases as they move between the subnets of the shared "+
+		"network. Consider using the same match-client-id value for all subnets in the shared "+
+		"network.\n%s",
+		storkutil.FormatNoun(int64(len(issues)), "shared network", "s"),
+		joinFindings(issues, maxIssues))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
+
+// Group of the adjacent IPv6 subnets forming a complete larger prefix.
+type aggregablePrefixes struct {
+	// The network address of the larger prefix.
+	address *big.Int
+	// Length of the larger prefix.
+	length int
+	// The constituent subnet prefixes.
+	prefixes []string
+}
+
+// Returns the textual representation of the larger prefix.
+func (a *aggregablePrefixes) supernet() string {
+	ip := make(net.IP, net.IPv6len)
+	a.address.FillBytes(ip)
+	return fmt.Sprintf("%s/%d", ip, a.length)
+}
+
+// Finds the groups of the same-length IPv6 prefixes which together form
+// complete larger prefixes. Two sibling prefixes differing only in the last
+// bit of the network part form a prefix shorter by one bit. The merging is
+// repeated as long as the sibling blocks are complete, so only the largest
+// blocks are returned. The prefixes must be in the canonical form. The
+// duplicates and the prefixes of different lengths are never merged.
+func findAggregablePrefixes(prefixes []string) []*aggregablePrefixes {
+	// Group the prefixes by length.
+	byLength := make(map[int]map[string]*aggregablePrefixes)
+	for _, prefix := range prefixes {
+		ip, ipNet, err := net.ParseCIDR(prefix)
+		if err != nil || ip.To4() != nil || !ip.Equal(ipNet.IP) {
+			continue
+		}
+		length, _ := ipNet.Mask.Size()
+		if length == 0 {
+			continue
+		}
+		address := new(big.Int).SetBytes(ipNet.IP.To16())
+		if _, ok := byLength[length]; !ok {
+			byLength[length] = make(map[string]*aggregablePrefixes)
+		}
+		byLength[length][address.String()] = &aggregablePrefixes{
+			address:  address,
+			length:   length,
+			prefixes: []string{prefix},
+		}
+	}
+
+	var aggregates []*aggregablePrefixes
+	for _, blocks := range byLength {
+		for len(blocks) > 0 {
+			parents := make(map[string]*aggregablePrefixes)
+			for key, block := range blocks {
+				if block.length == 0 {
+					continue
+				}
+				// The sibling differs only in the last bit of the network part.
+				bit := net.IPv6len*8 - block.length
+				if block.address.Bit(bit) == 1 {
+					// The block is merged together with its lower sibling.
+					continue
+				}
+				siblingAddress := new(big.Int).SetBit(block.address, bit, 1)
+				sibling, ok := blocks[siblingAddress.String()]
+				if !ok {
+					continue
+				}
+				parents[key] = &aggregablePrefixes{
+					address:  block.address,
+					length:   block.length - 1,
+					prefixes: append(append([]string{}, block.prefixes...), sibling.prefixes...),
+				}
+			}
+			// The blocks which haven't been merged with their siblings are
+			// the largest complete blocks.
+			for _, block := range blocks {
+				bit := int(net.IPv6len*8 - block.length)
+				lower := new(big.Int).SetBit(block.address, bit, 0)
+				if _, merged := parents[lower.String()]; merged {
+					continue
+				}
+				if len(block.prefixes) > 1 {
+					aggregates = append(aggregates, block)
+				}
+			}
+			blocks = parents
+		}
+	}
+
+	// Sort the aggregates by the address and length for the stable output.
+	sort.Slice(aggregates, func(i, j int) bool {
+		if cmp := aggregates[i].address.Cmp(aggregates[j].address); cmp != 0 {
+			return cmp < 0
+		}
+		return aggregates[i].length < aggregates[j].length
+	})
+	return aggregates
+}
+
+// The checker suggesting the aggregation of the adjacent DHCPv6 subnets
+// into larger prefixes. It is a planning hint reported only when the
+// subnets have the same prefix length and together form a complete
+// larger prefix, e.g., 2001:db8:1::/64 and 2001:db8:1:1::/64 form
+// 2001:db8:1::/63. Such subnets could be replaced with a single subnet
+// with the larger prefix.
+func prefixAggregation(ctx *ReviewContext) (*Report, error) {
+	if ctx.subjectDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		return nil, errors.Errorf("unsupported daemon %s", ctx.subjectDaemon.Name)
+	}
+
+	config := ctx.subjectDaemon.KeaDaemon.Config
+
+	var decodedSubnets []minimalSubnet
+	if err := config.DecodeTopLevelSubnets(&decodedSubnets); err != nil {
+		return nil, err
+	}
+	type minimalSharedNetwork struct {
+		Subnet6 []minimalSubnet
+	}
+	var decodedSharedNetworks []minimalSharedNetwork
+	if err := config.DecodeSharedNetworks(&decodedSharedNetworks); err != nil {
+		return nil, err
+	}
+	for _, sharedNetwork := range decodedSharedNetworks {
+		decodedSubnets = append(decodedSubnets, sharedNetwork.Subnet6...)
+	}
+
+	var prefixes []string
+	for _, subnet := range decodedSubnets {
+		prefixes = append(prefixes, subnet.Subnet)
+	}
+
+	aggregates := findAggregablePrefixes(prefixes)
+	if len(aggregates) == 0 {
+		return nil, nil
+	}
+
+	maxFindings, err := getMaxFindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []string
+	for i, aggregate := range aggregates {
+		constituents := strings.Join(aggregate.prefixes, ", ")
+		if len(aggregate.prefixes) > 4 {
+			constituents = fmt.Sprintf("%d subnets from %s to %s", len(aggregate.prefixes),
+				aggregate.prefixes[0], aggregate.prefixes[len(aggregate.prefixes)-1])
+		}
+		issues = append(issues, fmt.Sprintf("%d. %s can be aggregated into %s",
+			i+1, constituents, aggregate.supernet()))
+	}
+
+	return NewReport(ctx, fmt.Sprintf("Kea {daemon} configuration includes %s "+
+		"of adjacent subnets with the same prefix length forming complete larger "+
+		"prefixes. Consider aggregating them into the subnets with the larger "+
+		"prefixes to simplify the configuration.\n%s",
+		storkutil.FormatNoun(int64(len(aggregates)), "group", "s"),
+		joinFindings(issues, maxFindings))).
+		referencingDaemon(ctx.subjectDaemon).
+		create()
+}
@@ -3,10 +3,13 @@ package configreview
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	keaconfig "isc.org/stork/appcfg/kea"
 	dbops "isc.org/stork/server/database"
 	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
@@ -24,8 +27,14 @@ func createReviewContext(t *testing.T, db *dbops.PgDB, configStr string) *Review
 	if strings.Contains(configStr, "Dhcp6") {
 		daemonName = dbmodel.DaemonNameDHCPv6
 	}
+	// Make sure that the checkers see a nil interface when the database
+	// is not specified.
+	var dbi dbops.DBI
+	if db != nil {
+		dbi = db
+	}
 	// Create the daemon instance and the context.
-	ctx := newReviewContext(db, &dbmodel.Daemon{
+	ctx := newReviewContext(dbi, &dbmodel.Daemon{
 		ID:   1,
 		Name: daemonName,
 		KeaDaemon: &dbmodel.KeaDaemon{
@@ -217,6 +226,81 @@ func TestHostCmdsAbsentHostsDatabases(t *testing.T) {
 	require.Contains(t, report.content, "Kea can be configured")
 }
 
+// Tests that the checker verifying the lease backend returns nil when
+// the lease_cmds hooks library is loaded and the database lease backend
+// is configured.
+func TestLeaseCmdsBackendPresent(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "lease-database": {
+                "type": "postgresql",
+                "name": "kea"
+            },
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_lease_cmds.so"
+                }
+            ]
+        }
+    }`
+	report, err := leaseCmdsBackendPresence(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker verifying the lease backend returns nil when
+// the lease_cmds hooks library is not loaded.
+func TestLeaseCmdsBackendLibraryUnused(t *testing.T) {
+	configStr := `{
+        "Dhcp4": { }
+    }`
+	report, err := leaseCmdsBackendPresence(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker verifying the lease backend returns the report
+// when the lease_cmds hooks library is loaded and the lease-database
+// parameter is not specified.
+func TestLeaseCmdsBackendAbsent(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_lease_cmds.so"
+                }
+            ]
+        }
+    }`
+	report, err := leaseCmdsBackendPresence(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "The Lease Commands hook library (libdhcp_lease_cmds) is loaded")
+	require.Contains(t, report.content, "Lease Storage")
+}
+
+// Tests that the checker verifying the lease backend returns the report
+// when the lease_cmds hooks library is loaded and the memfile lease
+// backend is configured.
+func TestLeaseCmdsBackendMemfile(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            "lease-database": {
+                "type": "memfile"
+            },
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_lease_cmds.so"
+                }
+            ]
+        }
+    }`
+	report, err := leaseCmdsBackendPresence(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "The Lease Commands hook library (libdhcp_lease_cmds) is loaded")
+}
+
 // Tests that the checker finding dispensable shared networks finds
 // an empty IPv4 shared network.
 func TestSharedNetworkDispensableNoDHCPv4Subnet(t *testing.T) {
@@ -456,6 +540,8 @@ func TestIPv4SubnetDispensableNoPoolsNoReservationsHostCmds(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
 	defer teardown()
 
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+
 	configStr := `{
         "Dhcp4": {
             "shared-networks": [
@@ -597,6 +683,8 @@ func TestIPv6SubnetDispensableNoPoolsNoReservationsHostCmds(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
 	defer teardown()
 
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+
 	configStr := `{
         "Dhcp6": {
             "shared-networks": [
@@ -1663,189 +1751,6 @@ func TestDHCPv6DatabaseReservationsOutOfPoolNoIPReservation(t *testing.T) {
 	require.Nil(t, report)
 }
 
-// Test that no overlaps are detected for empty subnet list.
-func TestFindOverlapsEmptySubnets(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Empty(t, overlaps)
-}
-
-// Test that no overlaps are detected for non-overlapping subnets.
-func TestFindOverlapsNonOverlappingSubnets(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/24"},
-		{ID: 2, Subnet: "192.168.1.0/24"},
-		{ID: 3, Subnet: "192.168.2.0/24"},
-		{ID: 4, Subnet: "192.168.3.0/24"},
-		{ID: 5, Subnet: "3001:0::/80"},
-		{ID: 6, Subnet: "3001:1::/80"},
-		{ID: 7, Subnet: "3001:2::/80"},
-		{ID: 8, Subnet: "3001:3::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Empty(t, overlaps)
-}
-
-// Test that duplicated prefixes are detected as overlaps.
-func TestFindOverlapsForDuplicates(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/24"},
-		{ID: 2, Subnet: "192.168.0.0/24"},
-		{ID: 5, Subnet: "3001:0::/80"},
-		{ID: 6, Subnet: "3001:0::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Len(t, overlaps, 2)
-	require.EqualValues(t, 2, overlaps[1].parent.ID)
-	require.EqualValues(t, 1, overlaps[1].child.ID)
-	require.EqualValues(t, 6, overlaps[0].parent.ID)
-	require.EqualValues(t, 5, overlaps[0].child.ID)
-}
-
-// Test that duplicated prefixes are detected as overlaps even if the prefix is
-// repeatedly duplicated.
-func TestFindOverlapsForMultipleDuplicates(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/24"},
-		{ID: 2, Subnet: "192.168.0.0/24"},
-		{ID: 3, Subnet: "192.168.0.0/24"},
-		{ID: 5, Subnet: "3001:0::/80"},
-		{ID: 6, Subnet: "3001:0::/80"},
-		{ID: 7, Subnet: "3001:0::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Len(t, overlaps, 6)
-	require.EqualValues(t, 2, overlaps[5].parent.ID)
-	require.EqualValues(t, 1, overlaps[5].child.ID)
-	require.EqualValues(t, 3, overlaps[4].parent.ID)
-	require.EqualValues(t, 1, overlaps[4].child.ID)
-	require.EqualValues(t, 3, overlaps[3].parent.ID)
-	require.EqualValues(t, 2, overlaps[3].child.ID)
-	require.EqualValues(t, 6, overlaps[2].parent.ID)
-	require.EqualValues(t, 5, overlaps[2].child.ID)
-	require.EqualValues(t, 7, overlaps[1].parent.ID)
-	require.EqualValues(t, 5, overlaps[1].child.ID)
-	require.EqualValues(t, 7, overlaps[0].parent.ID)
-	require.EqualValues(t, 6, overlaps[0].child.ID)
-}
-
-// Test that overlaps are detected for the same network but different prefix
-// lengths.
-func TestFindOverlapsForSameNetworkButDifferentPrefixLengths(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/16"},
-		{ID: 2, Subnet: "192.168.0.0/24"},
-		{ID: 5, Subnet: "3001:0::/64"},
-		{ID: 6, Subnet: "3001:0::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Len(t, overlaps, 2)
-	require.EqualValues(t, 1, overlaps[1].parent.ID)
-	require.EqualValues(t, 2, overlaps[1].child.ID)
-	require.EqualValues(t, 5, overlaps[0].parent.ID)
-	require.EqualValues(t, 6, overlaps[0].child.ID)
-}
-
-// Test that overlaps are detected when one prefix is contained by another.
-func TestFindOverlapsForContainingPrefixes(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/16"},
-		{ID: 2, Subnet: "192.168.5.0/24"},
-		{ID: 5, Subnet: "3001:0::/16"},
-		{ID: 6, Subnet: "3001:1::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 42)
-
-	// Assert
-	require.Len(t, overlaps, 2)
-	require.EqualValues(t, 1, overlaps[1].parent.ID)
-	require.EqualValues(t, 2, overlaps[1].child.ID)
-	require.EqualValues(t, 5, overlaps[0].parent.ID)
-	require.EqualValues(t, 6, overlaps[0].child.ID)
-}
-
-// Test that the searching for overlaps is stopped if the limit is exceeded on
-// duplicated subnets.
-func TestFindOverlapsExceedLimitOnDuplicatedSubnets(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/16"},
-		{ID: 2, Subnet: "192.168.5.0/24"},
-		{ID: 3, Subnet: "192.68.5.0/24"},
-		{ID: 4, Subnet: "192.68.5.0/24"},
-		{ID: 5, Subnet: "3001:0::/16"},
-		{ID: 6, Subnet: "3001:1::/80"},
-		{ID: 7, Subnet: "2001:0::/16"},
-		{ID: 8, Subnet: "2001:0::/16"},
-		{ID: 9, Subnet: "4001:0::/16"},
-		{ID: 10, Subnet: "4001:0::/16"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 2)
-
-	// Assert
-	require.Len(t, overlaps, 2)
-	require.EqualValues(t, 5, overlaps[0].parent.ID)
-	require.EqualValues(t, 6, overlaps[0].child.ID)
-	require.EqualValues(t, 10, overlaps[1].parent.ID)
-	require.EqualValues(t, 9, overlaps[1].child.ID)
-}
-
-// Test that the searching for overlaps is stopped if the limit of overlapping
-// subnets is exceeded.
-func TestFindOverlapsExceedLimitOnContainingSubnets(t *testing.T) {
-	// Arrange
-	subnets := []minimalSubnet{
-		{ID: 1, Subnet: "192.168.0.0/16"},
-		{ID: 2, Subnet: "192.168.5.0/24"},
-		{ID: 3, Subnet: "192.68.0.0/16"},
-		{ID: 4, Subnet: "192.68.5.0/24"},
-		{ID: 5, Subnet: "3001::/16"},
-		{ID: 6, Subnet: "3001:1::/80"},
-		{ID: 7, Subnet: "2001::/16"},
-		{ID: 8, Subnet: "2001:1::/80"},
-	}
-
-	// Act
-	overlaps := findOverlaps(subnets, 2)
-
-	// Assert
-	require.Len(t, overlaps, 2)
-	require.EqualValues(t, 5, overlaps[0].parent.ID)
-	require.EqualValues(t, 6, overlaps[0].child.ID)
-	require.EqualValues(t, 7, overlaps[1].parent.ID)
-	require.EqualValues(t, 8, overlaps[1].child.ID)
-}
-
 // Test that error is generated for non-DHCP daemon.
 func TestSubnetsOverlappingReportErrorForNonDHCPDaemon(t *testing.T) {
 	// Arrange
@@ -1971,12 +1876,80 @@ func TestSubnetsOverlappingReportForMultipleOverlap(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 	require.EqualValues(t, 42, report.daemonID)
-	require.Contains(t, report.content, "Kea {daemon} configuration includes at least 10 overlapping subnet pairs.")
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 66 overlapping subnet pairs.")
 	require.Contains(t, report.content, "1. 10.0.0.0/8 (subnet-id 1) is overlapped by 10.0.0.0/9 (subnet-id 2)")
-	require.Contains(t, report.content, "10. 10.0.0.0/8 (subnet-id 1) is overlapped by 10.0.0.0/18 (subnet-id 11)")
+	require.Contains(t, report.content, "10. 10.0.0.0/8 (subnet-id 1) is overlapped by 10.0.0.0/18 (subnet-id 11); and 56 more")
 	require.NotContains(t, report.content, "11.")
 }
 
+// Test that the report notes that the overlaps detection was truncated due
+// to exceeding the time budget.
+func TestSubnetsOverlappingReportTruncated(t *testing.T) {
+	// Arrange
+	defaultTimeBudget := overlapsDetectionTimeBudget
+	defer func() {
+		overlapsDetectionTimeBudget = defaultTimeBudget
+	}()
+	overlapsDetectionTimeBudget = time.Nanosecond
+
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                { "id": 1, "subnet": "192.168.0.0/16" },
+                { "id": 2, "subnet": "192.168.1.0/24" },
+                { "id": 3, "subnet": "192.168.2.0/24" },
+                { "id": 4, "subnet": "10.0.1.0/24" }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, func(i int64, err error) {})
+
+	// Act
+	report, err := subnetsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes at least 2 overlapping subnet pairs")
+	require.Contains(t, report.content, "The detection was truncated after 1ns due to the large number of subnets (4)")
+}
+
+// Test that the report is produced when the overlapping subnets detection
+// is truncated before any overlaps are found.
+func TestSubnetsOverlappingReportTruncatedWithoutOverlaps(t *testing.T) {
+	// Arrange
+	defaultTimeBudget := overlapsDetectionTimeBudget
+	defer func() {
+		overlapsDetectionTimeBudget = defaultTimeBudget
+	}()
+	overlapsDetectionTimeBudget = time.Nanosecond
+
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                { "id": 1, "subnet": "192.168.1.0/24" },
+                { "id": 2, "subnet": "192.168.2.0/24" },
+                { "id": 3, "subnet": "10.0.1.0/24" }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, func(i int64, err error) {})
+
+	// Act
+	report, err := subnetsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes no overlapping subnets found so far")
+	require.Contains(t, report.content, "The detection was truncated after 1ns due to the large number of subnets (3)")
+	require.EqualValues(t, 42, report.daemonID)
+}
+
 // Test that no error or overlaps are returned for a Kea config without subnet
 // node.
 func TestSubnetsOverlappingForMissingSubnetNode(t *testing.T) {
@@ -2159,6 +2132,36 @@ func TestCanonicalPrefixes(t *testing.T) {
 	require.Contains(t, report.content, "Kea {daemon} configuration contains 4 non-canonical prefixes.")
 	require.Contains(t, report.content, "1. [2] 192.168.1.2/24 is invalid prefix, expected: 192.168.1.0/24;")
 	require.Contains(t, report.content, "4. foobar is invalid prefix")
+	require.Equal(t, dbmodel.ConfigReportSeverityError, report.severity)
+}
+
+// Test that the canonical prefixes report has the default checker severity
+// when all prefixes can be parsed.
+func TestCanonicalPrefixesDefaultSeverity(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.168.1.2/24"
+                }
+            ]
+        }
+    }`)
+
+	ctx := newReviewContext(nil, daemon,
+		ManualRun, func(i int64, err error) {})
+
+	// Act
+	report, err := canonicalPrefixes(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Empty(t, report.severity)
 }
 
 // Test that the canonical prefixes report is not generated if all prefixes are valid.
@@ -2216,84 +2219,5230 @@ func TestCanonicalPrefixesForEmptyConfig(t *testing.T) {
 	require.Nil(t, report)
 }
 
-// Benchmark measuring performance of a Kea configuration checker that detects
-// subnets in which the out-of-pool host reservation mode is recommended.
-func BenchmarkReservationsOutOfPoolConfig(b *testing.B) {
-	// Create 10.000 subnets with a pool and out of pool reservation.
-	subnets := []interface{}{}
-	for i := 0; i < 10000; i++ {
-		prefix := fmt.Sprintf("192.%d.%d", i/256, i%256)
-		subnet := map[string]interface{}{
-			"subnet": fmt.Sprintf("%s.0/24", prefix),
-			"pools": []map[string]interface{}{
-				{
-					"pool": fmt.Sprintf("%s.10 - %s.100", prefix, prefix),
-				},
-			},
-			"reservations": []map[string]interface{}{
-				{
-					"ip-address": fmt.Sprintf("%s.5", prefix),
-				},
-			},
-		}
-		subnets = append(subnets, subnet)
-	}
+// Test that the preferred lifetime checker returns an error for a
+// non-DHCPv6 daemon.
+func TestPreferredLifetimeNonDHCPv6Daemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
 
-	// Create Kea DHCPv4 configuration with the subnets.
-	configMap := map[string]interface{}{
-		"Dhcp4": map[string]interface{}{
-			"subnet4": subnets,
-		},
-	}
-	configStr, err := json.Marshal(configMap)
-	if err != nil {
-		b.Fatalf("failed to marshal configuration map: %+v", err)
-	}
-	config, err := dbmodel.NewKeaConfigFromJSON(string(configStr))
-	if err != nil {
-		b.Fatalf("failed to create new Kea configuration from JSON: %+v", err)
-	}
+	// Act
+	report, err := preferredLifetime(ctx)
 
-	// The benchmark starts here.
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		ctx := newReviewContext(nil, &dbmodel.Daemon{
-			ID:   1,
-			Name: dbmodel.DaemonNameDHCPv4,
-			KeaDaemon: &dbmodel.KeaDaemon{
-				Config: config,
-			},
-		}, ManualRun, nil)
-		_, err = reservationsOutOfPool(ctx)
-		if err != nil {
-			b.Fatalf("checker failed: %+v", err)
-		}
-	}
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
 }
 
-// Benchmark measuring performance of a Kea configuration checker that detects
-// subnets in which the out-of-pool host reservation mode is recommended.
-// This benchmark stores host reservations in the database.
-func BenchmarkReservationsOutOfPoolDatabase(b *testing.B) {
-	db, _, teardown := dbtest.SetupDatabaseTestCase(b)
-	defer teardown()
-
-	// Create the machine.
-	machine := &dbmodel.Machine{
-		ID:        0,
-		Address:   "localhost",
-		AgentPort: 8080,
-	}
-	err := dbmodel.AddMachine(db, machine)
-	if err != nil {
-		b.Fatalf("failed to add a machine: %+v", err)
-	}
-
-	// Create the app.
-	app := &dbmodel.App{
-		MachineID: machine.ID,
-		Type:      dbmodel.AppTypeKea,
-		Daemons: []*dbmodel.Daemon{
+// Test that the preferred lifetime checker reports the subnets with
+// the preferred lifetime not lower than the valid lifetime and the
+// subnets lacking the preferred lifetime. The lifetimes are inherited
+// from the shared network and the global level.
+func TestPreferredLifetime(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "valid-lifetime": 4000,
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "preferred-lifetime": 3000
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "preferred-lifetime": 5000
+                },
+                {
+                    "id": 3,
+                    "subnet": "2001:db8:3::/64"
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "preferred-lifetime": 2000,
+                    "subnet6": [
+                        {
+                            "id": 4,
+                            "subnet": "2001:db8:4::/64"
+                        },
+                        {
+                            "id": 5,
+                            "subnet": "2001:db8:5::/64",
+                            "valid-lifetime": 2000
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := preferredLifetime(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 3 subnets")
+	require.Contains(t, report.content, "1. [5] 2001:db8:5::/64: preferred-lifetime 2000 is not lower than valid-lifetime 2000")
+	require.Contains(t, report.content, "2. [2] 2001:db8:2::/64: preferred-lifetime 5000 is not lower than valid-lifetime 4000")
+	require.Contains(t, report.content, "3. [3] 2001:db8:3::/64: preferred-lifetime is not set while valid-lifetime is 4000")
+	require.NotContains(t, report.content, "2001:db8:1::/64")
+	require.NotContains(t, report.content, "2001:db8:4::/64")
+}
+
+// Test that the preferred lifetime checker does not report the subnets
+// for which the valid lifetime is not specified at any level.
+func TestPreferredLifetimeNoValidLifetime(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "preferred-lifetime": 5000,
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := preferredLifetime(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the preferred lifetime checker does not generate a report
+// when the lifetimes are valid.
+func TestPreferredLifetimeValid(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "valid-lifetime": 4000,
+            "preferred-lifetime": 3000,
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "valid-lifetime": 6000,
+                    "preferred-lifetime": 5000
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := preferredLifetime(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the overlapping address pools are detected between the
+// subnets belonging to the same shared network.
+func TestSharedNetworkPoolsOverlapping(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10-192.0.2.20"
+                                },
+                                {
+                                    "pool": "192.0.2.100-192.0.2.110"
+                                }
+                            ]
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.2.0/25",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.15-192.0.2.30"
+                                }
+                            ]
+                        },
+                        {
+                            "id": 3,
+                            "subnet": "192.0.3.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.3.0/24"
+                                }
+                            ]
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "subnet4": [
+                        {
+                            "id": 4,
+                            "subnet": "10.0.0.0/8",
+                            "pools": [
+                                {
+                                    "pool": "10.0.0.0/24"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 5,
+                    "subnet": "10.0.0.0/24",
+                    "pools": [
+                        {
+                            "pool": "10.0.0.1-10.0.0.10"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 1 overlapping address pool pair")
+	require.Contains(t, report.content, "1. shared network foo: pool 192.0.2.10-192.0.2.20 in subnet [1] 192.0.2.0/24 overlaps with pool 192.0.2.15-192.0.2.30 in subnet [2] 192.0.2.0/25")
+	require.NotContains(t, report.content, "bar")
+}
+
+// Test that the overlapping address pools are detected between the
+// subnets belonging to the same shared network in the DHCPv6 server.
+func TestSharedNetworkPoolsOverlappingDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet6": [
+                        {
+                            "subnet": "2001:db8:1::/64",
+                            "pools": [
+                                {
+                                    "pool": "2001:db8:1::/80"
+                                }
+                            ]
+                        },
+                        {
+                            "subnet": "2001:db8:1::/80",
+                            "pools": [
+                                {
+                                    "pool": "2001:db8:1::10-2001:db8:1::20"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. shared network foo: pool 2001:db8:1::/80 in subnet 2001:db8:1::/64 overlaps with pool 2001:db8:1::10-2001:db8:1::20 in subnet 2001:db8:1::/80")
+}
+
+// Test that no report is generated when the pools of the sibling subnets
+// don't overlap.
+func TestSharedNetworkPoolsNotOverlapping(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10-192.0.2.20"
+                                }
+                            ]
+                        },
+                        {
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.21-192.0.2.30"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the number of reported overlapping pools is limited.
+func TestSharedNetworkPoolsOverlappingExceedLimit(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+
+	var subnetsConfig []interface{}
+	for i := 0; i < 6; i++ {
+		subnetsConfig = append(subnetsConfig, map[string]interface{}{
+			"subnet": "192.0.2.0/24",
+			"pools": []interface{}{
+				map[string]interface{}{
+					"pool": fmt.Sprintf("192.0.2.%d-192.0.2.200", i+1),
+				},
+			},
+		})
+	}
+	config, _ := json.Marshal(map[string]interface{}{
+		"Dhcp4": map[string]interface{}{
+			"shared-networks": []interface{}{
+				map[string]interface{}{
+					"name":    "foo",
+					"subnet4": subnetsConfig,
+				},
+			},
+		},
+	})
+	_ = daemon.SetConfigFromJSON(string(config))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 15 overlapping address pool pairs")
+	require.Contains(t, report.content, "10. shared network foo")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "; and 5 more")
+}
+
+// Test that the pool options overriding the subnet options with different
+// values are reported.
+func TestPoolOptionsConflict(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "option-data": [
+                                {
+                                    "code": 3,
+                                    "data": "192.0.2.1"
+                                },
+                                {
+                                    "name": "domain-name",
+                                    "data": "example.org"
+                                }
+                            ],
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10-192.0.2.20",
+                                    "option-data": [
+                                        {
+                                            "code": 3,
+                                            "data": "192.0.2.2"
+                                        },
+                                        {
+                                            "name": "domain-name",
+                                            "data": "example.org"
+                                        }
+                                    ]
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "subnet": "192.0.3.0/24",
+                    "option-data": [
+                        {
+                            "name": "domain-name",
+                            "data": "example.org"
+                        },
+                        {
+                            "code": 6,
+                            "data": "192.0.3.1, 192.0.3.2"
+                        }
+                    ],
+                    "pools": [
+                        {
+                            "pool": "192.0.3.0/25",
+                            "option-data": [
+                                {
+                                    "name": "domain-name",
+                                    "data": "example.com"
+                                },
+                                {
+                                    "code": 6,
+                                    "data": "192.0.3.1,192.0.3.2"
+                                },
+                                {
+                                    "code": 15,
+                                    "data": "example.net"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolOptionsConflict(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 3 pool options overriding the subnet-level DHCP options")
+	require.Contains(t, report.content, "1. subnet [1] 192.0.2.0/24, pool 192.0.2.10-192.0.2.20: option 3 is 192.0.2.1 in the subnet and 192.0.2.2 in the pool")
+	require.Contains(t, report.content, "2. subnet 192.0.3.0/24, pool 192.0.3.0/25: option 15 is example.org in the subnet and example.com in the pool")
+	require.Contains(t, report.content, "3. subnet 192.0.3.0/24, pool 192.0.3.0/25: option 15 is example.org in the subnet and example.net in the pool")
+	require.NotContains(t, report.content, "option 6")
+}
+
+// Test that the options specified by name are matched with the options
+// specified by code using the custom option definitions.
+func TestPoolOptionsConflictCustomOptionDefinition(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "option-def": [
+                {
+                    "name": "foo",
+                    "code": 222,
+                    "type": "string"
+                },
+                {
+                    "name": "bar",
+                    "code": 1,
+                    "space": "isc",
+                    "type": "string"
+                }
+            ],
+            "subnet4": [
+                {
+                    "subnet": "192.0.2.0/24",
+                    "option-data": [
+                        {
+                            "name": "foo",
+                            "data": "abc"
+                        },
+                        {
+                            "name": "bar",
+                            "space": "isc",
+                            "data": "abc"
+                        }
+                    ],
+                    "pools": [
+                        {
+                            "pool": "192.0.2.0/25",
+                            "option-data": [
+                                {
+                                    "code": 222,
+                                    "data": "def"
+                                },
+                                {
+                                    "code": 1,
+                                    "data": "def"
+                                },
+                                {
+                                    "code": 1,
+                                    "space": "isc",
+                                    "data": "def"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolOptionsConflict(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 pool options")
+	require.Contains(t, report.content, "1. subnet 192.0.2.0/24, pool 192.0.2.0/25: option 222 is abc in the subnet and def in the pool")
+	require.Contains(t, report.content, "2. subnet 192.0.2.0/24, pool 192.0.2.0/25: option 1 is abc in the subnet and def in the pool")
+}
+
+// Test that the option code resolver resolves the option names using the
+// standard and custom option definitions.
+func TestOptionCodeResolver(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "option-def": [
+                {
+                    "name": "foo",
+                    "code": 1234,
+                    "type": "string"
+                },
+                {
+                    "name": "bar",
+                    "code": 5,
+                    "space": "isc",
+                    "type": "string"
+                }
+            ]
+        }
+    }`)
+
+	// Act
+	resolver, err := newOptionCodeResolver(daemon.Name, daemon.KeaDaemon.Config)
+
+	// Assert
+	require.NoError(t, err)
+	key, label := resolver.getOptionKey(keaconfig.SingleOptionData{Name: "dns-servers"})
+	require.Equal(t, "dhcp6/23", key)
+	require.Equal(t, "23", label)
+	key, label = resolver.getOptionKey(keaconfig.SingleOptionData{Code: 23, Space: "dhcp6"})
+	require.Equal(t, "dhcp6/23", key)
+	require.Equal(t, "23", label)
+	key, _ = resolver.getOptionKey(keaconfig.SingleOptionData{Name: "foo"})
+	require.Equal(t, "dhcp6/1234", key)
+	key, _ = resolver.getOptionKey(keaconfig.SingleOptionData{Name: "bar", Space: "isc"})
+	require.Equal(t, "isc/5", key)
+	key, label = resolver.getOptionKey(keaconfig.SingleOptionData{Name: "bar"})
+	require.Equal(t, "dhcp6/bar", key)
+	require.Equal(t, "bar", label)
+}
+
+// Test that no report is generated when the pool options don't conflict
+// with the subnet options.
+func TestPoolOptionsNoConflict(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "subnet": "2001:db8:1::/64",
+                    "option-data": [
+                        {
+                            "code": 23,
+                            "data": "2001:db8:1::1"
+                        },
+                        {
+                            "code": 23,
+                            "space": "isc",
+                            "data": "2001:db8:1::2"
+                        }
+                    ],
+                    "pools": [
+                        {
+                            "pool": "2001:db8:1::/80",
+                            "option-data": [
+                                {
+                                    "code": 23,
+                                    "space": "dhcp6",
+                                    "data": "2001:DB8:1::1"
+                                },
+                                {
+                                    "code": 24,
+                                    "data": "example.org"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolOptionsConflict(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the pool options checker returns an error for a daemon
+// other than Kea DHCP server.
+func TestPoolOptionsConflictUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolOptionsConflict(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the subnets with the prefix length different than the most
+// common prefix length in the shared network are reported.
+func TestSharedNetworkPrefixLength(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/25"
+                        },
+                        {
+                            "id": 3,
+                            "subnet": "192.0.4.0/24"
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "subnet4": [
+                        {
+                            "subnet": "10.0.0.0/24"
+                        },
+                        {
+                            "subnet": "10.0.1.0/25"
+                        }
+                    ]
+                },
+                {
+                    "name": "baz",
+                    "subnet4": [
+                        {
+                            "subnet": "10.1.0.0/16"
+                        },
+                        {
+                            "subnet": "10.2.0.0/16"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPrefixLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 1 subnet with the prefix length different")
+	require.Contains(t, report.content, "1. shared network foo: subnet [2] 192.0.3.0/25 has the prefix length /25 while /24 is expected")
+	require.NotContains(t, report.content, "bar")
+	require.NotContains(t, report.content, "baz")
+}
+
+// Test that the subnets with the prefix length different than the most
+// common prefix length in the shared network are reported for DHCPv6.
+func TestSharedNetworkPrefixLengthDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet6": [
+                        {
+                            "subnet": "2001:db8:1::/64"
+                        },
+                        {
+                            "subnet": "2001:db8:2::/64"
+                        },
+                        {
+                            "subnet": "2001:db8:3::/48"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPrefixLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. shared network foo: subnet 2001:db8:3::/48 has the prefix length /48 while /64 is expected")
+}
+
+// Test that no report is generated when all subnets in the shared networks
+// have the same prefix length.
+func TestSharedNetworkPrefixLengthConsistent(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "subnet": "192.0.3.0/24"
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "subnet": "10.0.0.0/8"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkPrefixLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the missing authentication realm is reported for the Kea
+// Control Agent enabling the basic HTTP authentication.
+func TestBasicAuthRealm(t *testing.T) {
+	configs := []string{
+		`{
+			"Control-agent": {
+				"authentication": {
+					"type": "basic",
+					"clients": [
+						{
+							"user": "foo",
+							"password": "bar"
+						}
+					]
+				}
+			}
+		}`,
+		`{
+			"Control-agent": {
+				"authentication": {
+					"type": "basic",
+					"realm": " ",
+					"clients": []
+				}
+			}
+		}`,
+	}
+
+	for i, config := range configs {
+		config := config
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			// Arrange
+			daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+			daemon.ID = 42
+			_ = daemon.SetConfigFromJSON(config)
+			ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+			// Act
+			report, err := basicAuthRealm(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.NotNil(t, report)
+			require.EqualValues(t, 42, report.daemonID)
+			require.Contains(t, report.content, "the authentication realm is not specified")
+		})
+	}
+}
+
+// Test that no report is generated when the authentication realm is
+// specified or the basic authentication is not enabled.
+func TestBasicAuthRealmNoReport(t *testing.T) {
+	configs := []string{
+		`{
+			"Control-agent": {
+				"authentication": {
+					"type": "basic",
+					"realm": "kea-control-agent",
+					"clients": []
+				}
+			}
+		}`,
+		`{
+			"Control-agent": { }
+		}`,
+	}
+
+	for i, config := range configs {
+		config := config
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			// Arrange
+			daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+			daemon.ID = 42
+			_ = daemon.SetConfigFromJSON(config)
+			ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+			// Act
+			report, err := basicAuthRealm(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.Nil(t, report)
+		})
+	}
+}
+
+// Test that the authentication realm checker returns an error for a
+// daemon other than Kea Control Agent.
+func TestBasicAuthRealmUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := basicAuthRealm(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the subnets with pools lacking the domain-name-servers option
+// at all configuration levels are reported.
+func TestDNSServersOptionPresence(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "option-data": [
+                        {
+                            "name": "domain-name-servers",
+                            "data": "192.0.2.1"
+                        }
+                    ],
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10-192.0.2.20"
+                                }
+                            ]
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "subnet4": [
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.3.10-192.0.3.20"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.4.10-192.0.4.20",
+                            "option-data": [
+                                {
+                                    "code": 6,
+                                    "data": "192.0.4.1"
+                                }
+                            ]
+                        }
+                    ]
+                },
+                {
+                    "id": 4,
+                    "subnet": "192.0.5.0/24",
+                    "option-data": [
+                        {
+                            "code": 6,
+                            "space": "isc",
+                            "data": "192.0.5.1"
+                        },
+                        {
+                            "code": 3,
+                            "data": "192.0.5.1"
+                        }
+                    ],
+                    "pools": [
+                        {
+                            "pool": "192.0.5.10-192.0.5.20"
+                        }
+                    ]
+                },
+                {
+                    "id": 5,
+                    "subnet": "192.0.6.0/24"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dnsServersOptionPresence(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 2 subnets with address pools lacking the domain-name-servers option")
+	require.Contains(t, report.content, "1. [2] 192.0.3.0/24")
+	require.Contains(t, report.content, "2. [4] 192.0.5.0/24")
+	require.NotContains(t, report.content, "192.0.2.0/24")
+	require.NotContains(t, report.content, "192.0.4.0/24")
+	require.NotContains(t, report.content, "192.0.6.0/24")
+}
+
+// Test that no report is generated when the domain-name-servers option
+// is specified globally.
+func TestDNSServersOptionPresenceGlobal(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "option-data": [
+                {
+                    "code": 6,
+                    "data": "192.0.2.1"
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.10-192.0.2.20"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dnsServersOptionPresence(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the domain-name-servers option checker returns an error for
+// a daemon other than DHCPv4.
+func TestDNSServersOptionPresenceUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dnsServersOptionPresence(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Creates two Kea apps with DHCPv4 daemons and a shared network with
+// three subnets. The first subnet is served by both daemons, the second
+// subnet is served by the first daemon only and the third subnet is
+// served by the second daemon only when inconsistent is true. Otherwise,
+// all subnets are served by both daemons.
+func createSharedNetworkDaemons(t *testing.T, db *dbops.PgDB, inconsistent bool) []*dbmodel.Daemon {
+	var daemons []*dbmodel.Daemon
+	for i := 0; i < 2; i++ {
+		machine := &dbmodel.Machine{
+			Address:   "localhost",
+			AgentPort: int64(8080 + i),
+		}
+		err := dbmodel.AddMachine(db, machine)
+		require.NoError(t, err)
+
+		config, err := dbmodel.NewKeaConfigFromJSON(`{"Dhcp4": { }}`)
+		require.NoError(t, err)
+
+		app := &dbmodel.App{
+			MachineID: machine.ID,
+			Type:      dbmodel.AppTypeKea,
+			Name:      fmt.Sprintf("kea%d", i+1),
+			Daemons: []*dbmodel.Daemon{
+				{
+					Name:   dbmodel.DaemonNameDHCPv4,
+					Active: true,
+					KeaDaemon: &dbmodel.KeaDaemon{
+						Config: config,
+					},
+				},
+			},
+		}
+		_, err = dbmodel.AddApp(db, app)
+		require.NoError(t, err)
+		daemons = append(daemons, app.Daemons[0])
+	}
+
+	network := &dbmodel.SharedNetwork{
+		Name:   "foo",
+		Family: 4,
+		Subnets: []dbmodel.Subnet{
+			{
+				Prefix: "192.0.2.0/24",
+			},
+			{
+				Prefix: "192.0.3.0/24",
+			},
+			{
+				Prefix: "192.0.4.0/24",
+			},
+		},
+	}
+	err := dbmodel.AddSharedNetwork(db, network)
+	require.NoError(t, err)
+
+	for i := range network.Subnets {
+		for j, daemon := range daemons {
+			if inconsistent && ((i == 1 && j == 1) || (i == 2 && j == 0)) {
+				continue
+			}
+			err = dbmodel.AddDaemonToSubnet(db, &network.Subnets[i], daemon)
+			require.NoError(t, err)
+		}
+	}
+	return daemons
+}
+
+// Test that the subnets in a shared network served by different sets of
+// daemons are reported.
+func TestSharedNetworkDaemonsConsistency(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemons := createSharedNetworkDaemons(t, db, true)
+	ctx := newReviewContext(db, daemons[0], ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, daemons[0].ID, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes shared networks with 2 subnets not served by all daemons")
+	require.Contains(t, report.content, "1. shared network foo: subnet 192.0.3.0/24 is not served by kea2/dhcp4")
+	require.Contains(t, report.content, "2. shared network foo: subnet 192.0.4.0/24 is not served by kea1/dhcp4")
+	require.NotContains(t, report.content, "192.0.2.0/24")
+}
+
+// Test that no report is generated when all subnets in a shared network
+// are served by the same daemons.
+func TestSharedNetworkDaemonsConsistent(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemons := createSharedNetworkDaemons(t, db, false)
+	ctx := newReviewContext(db, daemons[1], ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the checker generates no report when the database is
+// not available.
+func TestSharedNetworkDaemonsConsistencyNoDatabase(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Creates Kea apps with the DHCPv4 daemons using the specified
+// configurations and the subnets having the specified prefixes. Each
+// subnet is associated with all daemons.
+func createSubnetDaemons(t *testing.T, db *dbops.PgDB, prefixes []string, configs ...string) []*dbmodel.Daemon {
+	var daemons []*dbmodel.Daemon
+	for i, configJSON := range configs {
+		machine := &dbmodel.Machine{
+			Address:   "localhost",
+			AgentPort: int64(8080 + i),
+		}
+		err := dbmodel.AddMachine(db, machine)
+		require.NoError(t, err)
+
+		config, err := dbmodel.NewKeaConfigFromJSON(configJSON)
+		require.NoError(t, err)
+
+		app := &dbmodel.App{
+			MachineID: machine.ID,
+			Type:      dbmodel.AppTypeKea,
+			Name:      fmt.Sprintf("kea%d", i+1),
+			Daemons: []*dbmodel.Daemon{
+				{
+					Name:   dbmodel.DaemonNameDHCPv4,
+					Active: true,
+					KeaDaemon: &dbmodel.KeaDaemon{
+						Config: config,
+					},
+				},
+			},
+		}
+		_, err = dbmodel.AddApp(db, app)
+		require.NoError(t, err)
+		daemons = append(daemons, app.Daemons[0])
+	}
+
+	for _, prefix := range prefixes {
+		subnet := &dbmodel.Subnet{
+			Prefix: prefix,
+		}
+		err := dbmodel.AddSubnet(db, subnet)
+		require.NoError(t, err)
+		for _, daemon := range daemons {
+			err = dbmodel.AddDaemonToSubnet(db, subnet, daemon)
+			require.NoError(t, err)
+		}
+	}
+	return daemons
+}
+
+// Test that the subnets configured differently on the daemons serving
+// them are reported.
+func TestSubnetDaemonsConsistency(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemons := createSubnetDaemons(t, db,
+		[]string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24"},
+		`{
+            "Dhcp4": {
+                "subnet4": [
+                    {
+                        "id": 1,
+                        "subnet": "192.0.2.0/24",
+                        "pools": [ { "pool": "192.0.2.10 - 192.0.2.100" } ]
+                    },
+                    {
+                        "id": 2,
+                        "subnet": "192.0.3.0/24",
+                        "pools": [
+                            { "pool": "192.0.3.10 - 192.0.3.20" },
+                            { "pool": "192.0.3.30 - 192.0.3.40" }
+                        ],
+                        "option-data": [
+                            { "name": "routers", "data": "192.0.3.1" }
+                        ]
+                    }
+                ],
+                "shared-networks": [
+                    {
+                        "name": "foo",
+                        "subnet4": [
+                            {
+                                "id": 3,
+                                "subnet": "192.0.4.0/24",
+                                "option-data": [
+                                    { "name": "routers", "data": "192.0.4.1" }
+                                ]
+                            }
+                        ]
+                    }
+                ]
+            }
+        }`,
+		`{
+            "Dhcp4": {
+                "subnet4": [
+                    {
+                        "id": 1,
+                        "subnet": "192.0.2.0/24",
+                        "pools": [ { "pool": "192.0.2.10 - 192.0.2.200" } ]
+                    },
+                    {
+                        "id": 2,
+                        "subnet": "192.0.3.0/24",
+                        "pools": [
+                            { "pool": "192.0.3.30-192.0.3.40" },
+                            { "pool": "192.0.3.10 - 192.0.3.20" }
+                        ],
+                        "option-data": [
+                            { "code": 3, "data": " 192.0.3.1" }
+                        ]
+                    },
+                    {
+                        "id": 3,
+                        "subnet": "192.0.4.0/24",
+                        "option-data": [
+                            { "name": "routers", "data": "192.0.4.2" }
+                        ]
+                    }
+                ]
+            }
+        }`)
+	ctx := newReviewContext(db, daemons[0], ManualRun, nil)
+
+	// Act
+	report, err := subnetDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, daemons[0].ID, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 2 subnets also served by other daemons")
+	require.Contains(t, report.content, "1. [1] 192.0.2.0/24 differs from kea2/dhcp4 in pools; 2. [3] 192.0.4.0/24 differs from kea2/dhcp4 in options")
+	require.NotContains(t, report.content, "192.0.3.0/24")
+}
+
+// Test that no report is generated when the subnets are configured
+// consistently on all daemons.
+func TestSubnetDaemonsConsistent(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	config := `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [ { "pool": "192.0.2.10 - 192.0.2.100" } ],
+                    "option-data": [
+                        { "code": 3, "data": "192.0.2.1" }
+                    ]
+                }
+            ]
+        }
+    }`
+	daemons := createSubnetDaemons(t, db, []string{"192.0.2.0/24"}, config, config)
+	ctx := newReviewContext(db, daemons[1], ManualRun, nil)
+
+	// Act
+	report, err := subnetDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the subnet daemons consistency checker generates no report
+// when the database is not available.
+func TestSubnetDaemonsConsistencyNoDatabase(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetDaemonsConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the subnet daemons consistency checker returns an error
+// for an unsupported daemon.
+func TestSubnetDaemonsConsistencyUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	daemon.ID = 42
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetDaemonsConsistency(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon ca")
+	require.Nil(t, report)
+}
+
+// Test that the interfaces-config checker reports the configuration
+// without interfaces.
+func TestInterfacesConfigNoInterfaces(t *testing.T) {
+	configs := []string{
+		`{
+            "Dhcp4": {
+                "interfaces-config": {
+                    "interfaces": [ ]
+                }
+            }
+        }`,
+		`{
+            "Dhcp6": { }
+        }`,
+	}
+	for _, config := range configs {
+		// Arrange
+		daemonName := dbmodel.DaemonNameDHCPv4
+		if strings.Contains(config, "Dhcp6") {
+			daemonName = dbmodel.DaemonNameDHCPv6
+		}
+		daemon := dbmodel.NewKeaDaemon(daemonName, true)
+		daemon.ID = 42
+		_ = daemon.SetConfigFromJSON(config)
+		ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+		// Act
+		report, err := interfacesConfig(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.EqualValues(t, 42, report.daemonID)
+		require.Contains(t, report.content, "configuration specifies no interfaces")
+		require.Equal(t, dbmodel.ConfigReportSeverityError, report.severity)
+	}
+}
+
+// Test that the interfaces-config checker reports the wildcard.
+func TestInterfacesConfigWildcard(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "interfaces-config": {
+                "interfaces": [ "eth0", "*" ]
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := interfacesConfig(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "uses the \"*\" wildcard")
+	require.Empty(t, report.severity)
+}
+
+// Test that the interfaces-config checker generates no report when
+// the interfaces are specified explicitly.
+func TestInterfacesConfigExplicitInterfaces(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "interfaces-config": {
+                "interfaces": [ "eth0", "eth1/2001:db8:1::1" ]
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := interfacesConfig(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker detects the small subnets in which the address
+// pools and the out-of-pool reservations consume all usable addresses.
+func TestAddressSpaceExhaustion(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/29",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.1 - 192.0.2.5"
+                                }
+                            ],
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:06",
+                                    "ip-address": "192.0.2.6"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/29",
+                    "pools": [
+                        {
+                            "pool": "192.0.3.1 - 192.0.3.4"
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.3.2"
+                        }
+                    ]
+                },
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/30",
+                    "pools": [
+                        {
+                            "pool": "192.0.4.0/30"
+                        }
+                    ]
+                },
+                {
+                    "id": 4,
+                    "subnet": "10.0.0.0/16",
+                    "pools": [
+                        {
+                            "pool": "10.0.0.0/16"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := addressSpaceExhaustion(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 subnets in which")
+	require.Contains(t, report.content, "1. [1] 192.0.2.0/29: 5 addresses in pools and 1 out-of-pool reservation while 6 addresses are usable")
+	require.Contains(t, report.content, "2. [3] 192.0.4.0/30: 2 addresses in pools and 0 out-of-pool reservations while 2 addresses are usable")
+	require.NotContains(t, report.content, "192.0.3.0/29")
+	require.NotContains(t, report.content, "10.0.0.0/16")
+}
+
+// Tests that the checker produces no report when the subnets have free
+// addresses.
+func TestAddressSpaceExhaustionNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.10 - 192.0.2.200"
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.2.5"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := addressSpaceExhaustion(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker counts only the pool addresses belonging to the
+// subnet and doesn't iterate over the whole huge pool exceeding the subnet.
+func TestAddressSpaceExhaustionHugePool(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/29",
+                    "pools": [
+                        {
+                            "pool": "0.0.0.0 - 255.255.255.255"
+                        }
+                    ]
+                },
+                {
+                    "id": 2,
+                    "subnet": "255.255.255.0/24",
+                    "pools": [
+                        {
+                            "pool": "255.255.255.0 - 255.255.255.255"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := addressSpaceExhaustion(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. [1] 192.0.2.0/29: 6 addresses in pools and 0 out-of-pool reservations while 6 addresses are usable")
+	require.Contains(t, report.content, "2. [2] 255.255.255.0/24: 254 addresses in pools and 0 out-of-pool reservations while 254 addresses are usable")
+}
+
+// Tests that the checker doesn't count the network and broadcast addresses
+// when the pools cover the whole subnet prefix.
+func TestAddressSpaceExhaustionPoolsCoveringWholePrefix(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/30",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.0/30"
+                        }
+                    ]
+                },
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/29",
+                    "pools": [
+                        {
+                            "pool": "192.0.3.0/29"
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.3.0"
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:07",
+                            "ip-address": "192.0.3.7"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := addressSpaceExhaustion(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. [1] 192.0.2.0/30: 2 addresses in pools and 0 out-of-pool reservations while 2 addresses are usable")
+	require.Contains(t, report.content, "2. [2] 192.0.3.0/29: 6 addresses in pools and 0 out-of-pool reservations while 6 addresses are usable")
+}
+
+// Tests that the checker returns an error for the DHCPv6 daemon.
+func TestAddressSpaceExhaustionUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := addressSpaceExhaustion(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the single-thread pool and the unlimited
+// packet queue when the multi-threading is enabled.
+func TestMultiThreadingEnabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "multi-threading": {
+                "enable-multi-threading": true,
+                "thread-pool-size": 1,
+                "packet-queue-size": 0
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := multiThreading(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes 2 issues in the multi-threading settings")
+	require.Contains(t, report.content, "1. the thread-pool-size is 1")
+	require.Contains(t, report.content, "2. the packet-queue-size is 0")
+}
+
+// Tests that the checker reports the ineffective settings and suggests
+// enabling the multi-threading on a machine with many CPU cores.
+func TestMultiThreadingDisabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	daemon.App = &dbmodel.App{
+		Machine: &dbmodel.Machine{
+			State: dbmodel.MachineState{
+				Cpus: 16,
+			},
+		},
+	}
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "multi-threading": {
+                "enable-multi-threading": false,
+                "thread-pool-size": 8,
+                "packet-queue-size": 64
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := multiThreading(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes 2 issues in the multi-threading settings")
+	require.Contains(t, report.content, "1. the multi-threading is disabled, so the thread-pool-size (8) and the packet-queue-size (64) have no effect")
+	require.Contains(t, report.content, "2. the multi-threading is disabled while the machine has 16 CPU cores")
+}
+
+// Tests that the checker produces no report for the consistent
+// multi-threading settings.
+func TestMultiThreadingNoIssues(t *testing.T) {
+	configs := []string{
+		`{ "Dhcp4": { } }`,
+		`{ "Dhcp4": { "multi-threading": { } } }`,
+		`{ "Dhcp4": { "multi-threading": { "enable-multi-threading": false } } }`,
+		`{
+            "Dhcp4": {
+                "multi-threading": {
+                    "enable-multi-threading": true,
+                    "thread-pool-size": 4,
+                    "packet-queue-size": 16
+                }
+            }
+        }`,
+	}
+	for _, config := range configs {
+		// Arrange
+		daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+		daemon.ID = 42
+		_ = daemon.SetConfigFromJSON(config)
+		ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+		// Act
+		report, err := multiThreading(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Nil(t, report)
+	}
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestMultiThreadingUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Control-agent": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := multiThreading(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the disabled lease sanity checks and
+// includes the current settings in the report.
+func TestLeaseSanityChecksDisabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "sanity-checks": {
+                "lease-checks": "none",
+                "extended-info-checks": "none"
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := leaseSanityChecks(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "disables 2 lease sanity checks in the sanity-checks")
+	require.Contains(t, report.content, "1. the lease-checks is set to \"none\"")
+	require.Contains(t, report.content, "\"warn\"")
+	require.Contains(t, report.content, "2. the extended-info-checks is set to \"none\"")
+}
+
+// Tests that the checker reports the disabled lease checks only when the
+// extended info checks are enabled.
+func TestLeaseSanityChecksLeaseChecksDisabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "sanity-checks": {
+                "lease-checks": "none",
+                "extended-info-checks": "fix"
+            }
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := leaseSanityChecks(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "disables 1 lease sanity check in the sanity-checks")
+	require.Contains(t, report.content, "1. the lease-checks is set to \"none\"")
+	require.NotContains(t, report.content, "extended-info-checks")
+}
+
+// Tests that the checker produces no report when the lease sanity checks
+// are enabled or not specified.
+func TestLeaseSanityChecksEnabled(t *testing.T) {
+	configs := []string{
+		`{ "Dhcp4": { } }`,
+		`{ "Dhcp4": { "sanity-checks": { } } }`,
+		`{ "Dhcp4": { "sanity-checks": { "lease-checks": "warn" } } }`,
+		`{
+            "Dhcp4": {
+                "sanity-checks": {
+                    "lease-checks": "fix-del",
+                    "extended-info-checks": "strict"
+                }
+            }
+        }`,
+	}
+	for _, config := range configs {
+		// Arrange
+		daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+		daemon.ID = 42
+		_ = daemon.SetConfigFromJSON(config)
+		ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+		// Act
+		report, err := leaseSanityChecks(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Nil(t, report)
+	}
+}
+
+// Tests that the lease sanity checks checker returns an error for the
+// unsupported daemon.
+func TestLeaseSanityChecksUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Control-agent": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := leaseSanityChecks(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the reservations outside of the pools
+// when the in-subnet mode is enabled and the out-of-pool mode is disabled.
+func TestReservationsOutsideOfPools(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "reservations-in-subnet": true,
+            "reservations-out-of-pool": false,
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "reservations-out-of-pool": true,
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10 - 192.0.2.100"
+                                }
+                            ],
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:06",
+                                    "ip-address": "192.0.2.200"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.3.10 - 192.0.3.100"
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.3.20"
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:07",
+                            "ip-address": "192.0.3.200"
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:08",
+                            "ip-address": "192.0.3.201"
+                        }
+                    ]
+                },
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.4.10 - 192.0.4.100"
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.4.20"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsOutsideOfPools(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 subnet with the host reservations outside of the pools")
+	require.Contains(t, report.content, "1. [2] 192.0.3.0/24: 192.0.3.200, 192.0.3.201")
+	require.NotContains(t, report.content, "192.0.2.0/24")
+	require.NotContains(t, report.content, "192.0.4.0/24")
+}
+
+// Tests that the checker reports the IPv6 addresses and prefixes outside
+// of the pools.
+func TestReservationsOutsideOfPoolsDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "pools": [
+                        {
+                            "pool": "2001:db8:1::10 - 2001:db8:1::100"
+                        }
+                    ],
+                    "pd-pools": [
+                        {
+                            "prefix": "3000::",
+                            "prefix-len": 64,
+                            "delegated-len": 96
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "duid": "01:02:03:04:05:06",
+                            "ip-addresses": [ "2001:db8:1::20", "2001:db8:1::200" ],
+                            "prefixes": [ "3000::/96", "3001::/96" ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsOutsideOfPools(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. [1] 2001:db8:1::/64: 2001:db8:1::200, 3001::/96")
+}
+
+// Tests that the checker produces no report when the out-of-pool mode
+// is enabled or the in-subnet mode is disabled.
+func TestReservationsOutsideOfPoolsModes(t *testing.T) {
+	modes := []string{
+		`"reservations-out-of-pool": true`,
+		`"reservations-in-subnet": false`,
+		`"reservation-mode": "out-of-pool"`,
+		`"reservation-mode": "global"`,
+	}
+	for _, mode := range modes {
+		// Arrange
+		daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+		daemon.ID = 42
+		_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+            "Dhcp4": {
+                %s,
+                "subnet4": [
+                    {
+                        "id": 1,
+                        "subnet": "192.0.2.0/24",
+                        "reservations": [
+                            {
+                                "hw-address": "01:02:03:04:05:06",
+                                "ip-address": "192.0.2.200"
+                            }
+                        ]
+                    }
+                ]
+            }
+        }`, mode))
+		ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+		// Act
+		report, err := reservationsOutsideOfPools(ctx)
+
+		// Assert
+		require.NoError(t, err, mode)
+		require.Nil(t, report, mode)
+	}
+}
+
+// Tests that the checker takes the reservation modes explicitly set at
+// the lower configuration levels into account, even when they disable
+// the modes enabled at the higher levels.
+func TestReservationsOutsideOfPoolsModesInheritance(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "reservations-out-of-pool": true,
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "reservations-out-of-pool": false,
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:06",
+                                    "ip-address": "192.0.2.200"
+                                }
+                            ]
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "reservations-out-of-pool": true,
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:07",
+                                    "ip-address": "192.0.3.200"
+                                }
+                            ]
+                        },
+                        {
+                            "id": 3,
+                            "subnet": "192.0.4.0/24",
+                            "reservations-in-subnet": false,
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:08",
+                                    "ip-address": "192.0.4.200"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 4,
+                    "subnet": "192.0.5.0/24",
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:09",
+                            "ip-address": "192.0.5.200"
+                        }
+                    ]
+                },
+                {
+                    "id": 5,
+                    "subnet": "192.0.6.0/24",
+                    "reservations-out-of-pool": false,
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:0a",
+                            "ip-address": "192.0.6.200"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsOutsideOfPools(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "192.0.2.0/24: 192.0.2.200")
+	require.Contains(t, report.content, "192.0.6.0/24: 192.0.6.200")
+	require.NotContains(t, report.content, "192.0.3.200")
+	require.NotContains(t, report.content, "192.0.4.200")
+	require.NotContains(t, report.content, "192.0.5.200")
+}
+
+// Tests that the checker reports the DDNS overrides enabled while
+// the DDNS updates are disabled at various levels.
+func TestDDNSFlagsOverridesWithUpdatesDisabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "dhcp-ddns": {
+                "enable-updates": true
+            },
+            "ddns-override-client-update": true,
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "ddns-send-updates": false,
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "ddns-send-updates": true
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/24",
+                    "ddns-send-updates": false,
+                    "ddns-override-no-update": true
+                },
+                {
+                    "id": 4,
+                    "subnet": "192.0.5.0/24"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := ddnsFlags(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 scopes with the inconsistent DDNS settings")
+	require.Contains(t, report.content, "1. shared network foo: ddns-override-client-update is enabled while ddns-send-updates is disabled")
+	require.Contains(t, report.content, "2. subnet [3] 192.0.4.0/24: ddns-override-no-update is enabled while ddns-send-updates is disabled "+
+		"and ddns-override-client-update is enabled while ddns-send-updates is disabled")
+	require.NotContains(t, report.content, "192.0.2.0/24")
+	require.NotContains(t, report.content, "192.0.3.0/24")
+	require.NotContains(t, report.content, "192.0.5.0/24")
+}
+
+// Tests that the checker reports the DDNS overrides enabled while the
+// DDNS updates are disabled in the dhcp-ddns map.
+func TestDDNSFlagsOverridesWithDHCPDDNSDisabled(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "ddns-override-no-update": true,
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := ddnsFlags(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. global: ddns-override-no-update is enabled while enable-updates is disabled in dhcp-ddns")
+	require.NotContains(t, report.content, "2001:db8:1::/64")
+}
+
+// Tests that the checker reports the host name replacement without
+// the qualifying suffix.
+func TestDDNSFlagsReplaceClientNameWithoutSuffix(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "dhcp-ddns": {
+                "enable-updates": true
+            },
+            "ddns-qualifying-suffix": "example.org",
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "ddns-replace-client-name": "always"
+                },
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24",
+                    "ddns-replace-client-name": "when-not-present",
+                    "ddns-qualifying-suffix": ""
+                },
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/24",
+                    "ddns-replace-client-name": "never",
+                    "ddns-qualifying-suffix": ""
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := ddnsFlags(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 scope with")
+	require.Contains(t, report.content, "1. subnet [2] 192.0.3.0/24: ddns-replace-client-name is when-not-present while ddns-qualifying-suffix is empty")
+}
+
+// Tests that the checker produces no report for the consistent DDNS
+// settings.
+func TestDDNSFlagsConsistent(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "dhcp-ddns": {
+                "enable-updates": true
+            },
+            "ddns-send-updates": true,
+            "ddns-override-no-update": true,
+            "ddns-override-client-update": true,
+            "ddns-replace-client-name": "always",
+            "ddns-qualifying-suffix": "example.org",
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "ddns-send-updates": false,
+                    "ddns-override-no-update": false,
+                    "ddns-override-client-update": false
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := ddnsFlags(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the DDNS flags checker doesn't support the Kea CA daemon.
+func TestDDNSFlagsUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Control-agent": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := ddnsFlags(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the DHCPv4 reservations with the DHCPv6
+// options and address fields.
+func TestReservationsFamilyMismatchDHCPv4(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "reservations": [
+                {
+                    "hw-address": "01:02:03:04:05:01",
+                    "option-data": [
+                        {
+                            "name": "dns-servers",
+                            "space": "dhcp6",
+                            "data": "2001:db8:1::1"
+                        }
+                    ]
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "reservations": [
+                                {
+                                    "hw-address": "01:02:03:04:05:06",
+                                    "ip-address": "192.0.2.200",
+                                    "option-data": [
+                                        {
+                                            "name": "domain-name-servers",
+                                            "space": "dhcp4",
+                                            "data": "192.0.2.1"
+                                        },
+                                        {
+                                            "code": 23,
+                                            "space": "dhcp6",
+                                            "data": "2001:db8:1::1"
+                                        }
+                                    ]
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24",
+                    "reservations": [
+                        {
+                            "client-id": "01:02:03:04",
+                            "ip-addresses": [ "192.0.3.10" ],
+                            "prefixes": [ "3000::/64" ]
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:07",
+                            "ip-address": "192.0.3.20",
+                            "option-data": [
+                                {
+                                    "name": "routers",
+                                    "data": "192.0.3.1"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsFamilyMismatch(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 3 host reservations specifying the DHCPv6 options or address fields while the server serves the DHCPv4 clients")
+	require.Contains(t, report.content, "1. global, hw-address=01:02:03:04:05:01: option dns-servers in dhcp6 space")
+	require.Contains(t, report.content, "2. subnet [1] 192.0.2.0/24, hw-address=01:02:03:04:05:06: option 23 in dhcp6 space")
+	require.Contains(t, report.content, "3. subnet [2] 192.0.3.0/24, client-id=01:02:03:04: ip-addresses, prefixes")
+	require.NotContains(t, report.content, "01:02:03:04:05:07")
+}
+
+// Tests that the checker reports the DHCPv6 reservations with the DHCPv4
+// options and the ip-address field.
+func TestReservationsFamilyMismatchDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "reservations": [
+                        {
+                            "duid": "01:02:03:04",
+                            "ip-address": "2001:db8:1::10"
+                        },
+                        {
+                            "duid": "01:02:03:05",
+                            "ip-addresses": [ "2001:db8:1::20" ],
+                            "option-data": [
+                                {
+                                    "name": "routers",
+                                    "space": "dhcp4",
+                                    "data": "192.0.2.1"
+                                }
+                            ]
+                        },
+                        {
+                            "duid": "01:02:03:06",
+                            "ip-addresses": [ "2001:db8:1::30" ],
+                            "prefixes": [ "3000::/64" ],
+                            "option-data": [
+                                {
+                                    "name": "dns-servers",
+                                    "data": "2001:db8:1::1"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsFamilyMismatch(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 host reservations specifying the DHCPv4 options or address fields while the server serves the DHCPv6 clients")
+	require.Contains(t, report.content, "1. subnet [1] 2001:db8:1::/64, duid=01:02:03:04: ip-address")
+	require.Contains(t, report.content, "2. subnet [1] 2001:db8:1::/64, duid=01:02:03:05: option routers in dhcp4 space")
+	require.NotContains(t, report.content, "01:02:03:06")
+}
+
+// Tests that the checker doesn't report the reservations matching the
+// daemon's family.
+func TestReservationsFamilyMismatchNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "reservations": [
+                {
+                    "hw-address": "01:02:03:04:05:01",
+                    "ip-address": "192.0.2.10"
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.2.200",
+                            "option-data": [
+                                {
+                                    "name": "domain-name-servers",
+                                    "space": "dhcp4",
+                                    "data": "192.0.2.1"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsFamilyMismatch(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the number of the reported reservations is limited.
+func TestReservationsFamilyMismatchMaxIssues(t *testing.T) {
+	// Arrange
+	var reservations []string
+	for i := 0; i < 12; i++ {
+		reservations = append(reservations, fmt.Sprintf(`{
+            "hw-address": "01:02:03:04:05:%02x",
+            "ip-addresses": [ "192.0.2.%d" ]
+        }`, i, i+10))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "reservations": [ %s ]
+                }
+            ]
+        }
+    }`, strings.Join(reservations, ",")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsFamilyMismatch(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 12 host reservations")
+	require.Contains(t, report.content, "10. subnet [1] 192.0.2.0/24, hw-address=01:02:03:04:05:09: ip-addresses")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "; and 2 more")
+}
+
+// Tests that the checker returns an error for an unsupported daemon.
+func TestReservationsFamilyMismatchUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Control-agent": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := reservationsFamilyMismatch(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon ca")
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the DHCPv6 subnets specifying neither
+// the interface, relay addresses nor interface-id.
+func TestDHCP6SubnetSelectors(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "interface": "eth0"
+                },
+                {
+                    "id": 3,
+                    "subnet": "2001:db8:3::/64",
+                    "relay": {
+                        "ip-addresses": [ "2001:db8:3::1" ]
+                    }
+                },
+                {
+                    "id": 4,
+                    "subnet": "2001:db8:4::/64",
+                    "interface-id": "foo"
+                },
+                {
+                    "id": 5,
+                    "subnet": "2001:db8:5::/64",
+                    "relay": {
+                        "ip-addresses": [ ]
+                    }
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dhcp6SubnetSelectors(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 subnets specifying neither")
+	require.Contains(t, report.content, "1. [1] 2001:db8:1::/64; 2. [5] 2001:db8:5::/64")
+	require.EqualValues(t, 42, report.daemonID)
+}
+
+// Tests that the subnets inherit the interface and relay addresses from
+// the shared network.
+func TestDHCP6SubnetSelectorsSharedNetwork(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "interface": "eth0",
+                    "subnet6": [
+                        {
+                            "id": 1,
+                            "subnet": "2001:db8:1::/64"
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "relay": {
+                        "ip-addresses": [ "2001:db8:2::1" ]
+                    },
+                    "subnet6": [
+                        {
+                            "id": 2,
+                            "subnet": "2001:db8:2::/64"
+                        }
+                    ]
+                },
+                {
+                    "name": "baz",
+                    "subnet6": [
+                        {
+                            "id": 3,
+                            "subnet": "2001:db8:3::/64"
+                        },
+                        {
+                            "id": 4,
+                            "subnet": "2001:db8:4::/64",
+                            "interface": "eth1"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dhcp6SubnetSelectors(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 subnet specifying neither")
+	require.Contains(t, report.content, "1. [3] 2001:db8:3::/64")
+	require.NotContains(t, report.content, "2.")
+}
+
+// Tests that the checker reports no issue when all subnets specify the
+// interface, relay addresses or interface-id.
+func TestDHCP6SubnetSelectorsNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "interface": "eth0"
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "relay": {
+                        "ip-address": "2001:db8:2::1"
+                    }
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dhcp6SubnetSelectors(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the number of the reported subnets is limited.
+func TestDHCP6SubnetSelectorsMaxIssues(t *testing.T) {
+	// Arrange
+	var subnets []string
+	for i := 1; i <= 12; i++ {
+		subnets = append(subnets, fmt.Sprintf(`{
+            "id": %d,
+            "subnet": "2001:db8:%x::/64"
+        }`, i, i))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp6": {
+            "subnet6": [ %s ]
+        }
+    }`, strings.Join(subnets, ",")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dhcp6SubnetSelectors(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 12 subnets")
+	require.Contains(t, report.content, "10. [10] 2001:db8:a::/64")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "; and 2 more")
+}
+
+// Tests that the checker returns an error for a non-DHCPv6 daemon.
+func TestDHCP6SubnetSelectorsUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := dhcp6SubnetSelectors(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon dhcp4")
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the IPv4 subnets with the pools spanning
+// the entire subnet prefix.
+func TestPoolCoversEntireSubnet(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.0 - 192.0.2.255"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.3.1 - 192.0.3.254"
+                        }
+                    ]
+                },
+                {
+                    "id": 3,
+                    "subnet": "192.0.4.0/25",
+                    "pools": [
+                        {
+                            "pool": "192.0.4.0/25"
+                        }
+                    ]
+                },
+                {
+                    "id": 4,
+                    "subnet": "192.0.5.0/31",
+                    "pools": [
+                        {
+                            "pool": "192.0.5.0/31"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolCoversEntireSubnet(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 subnets with an address pool spanning the entire subnet prefix")
+	require.Contains(t, report.content, "1. [1] 192.0.2.0/24: pool 192.0.2.0 - 192.0.2.255; 2. [3] 192.0.4.0/25: pool 192.0.4.0/25")
+	require.EqualValues(t, 42, report.daemonID)
+}
+
+// Tests that the checker reports no issue when the pools exclude the
+// network and broadcast addresses.
+func TestPoolCoversEntireSubnetNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.0 - 192.0.2.100"
+                        },
+                        {
+                            "pool": "192.0.2.101 - 192.0.2.255"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolCoversEntireSubnet(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the number of the reported subnets is limited.
+func TestPoolCoversEntireSubnetMaxIssues(t *testing.T) {
+	// Arrange
+	var subnets []string
+	for i := 1; i <= 12; i++ {
+		subnets = append(subnets, fmt.Sprintf(`{
+            "id": %d,
+            "subnet": "10.0.%d.0/24",
+            "pools": [ { "pool": "10.0.%d.0/24" } ]
+        }`, i, i, i))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [ %s ]
+        }
+    }`, strings.Join(subnets, ",")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolCoversEntireSubnet(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 12 subnets")
+	require.Contains(t, report.content, "10. [10] 10.0.10.0/24: pool 10.0.10.0/24")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "; and 2 more")
+}
+
+// Tests that the checker returns an error for a non-DHCPv4 daemon.
+func TestPoolCoversEntireSubnetUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolCoversEntireSubnet(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon dhcp6")
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the prefix reservations which lengths
+// differ from the delegated lengths of the pools in their subnets.
+func TestPrefixReservationsDelegatedLength(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet6": [
+                        {
+                            "id": 1,
+                            "subnet": "2001:db8:1::/64",
+                            "pd-pools": [
+                                {
+                                    "prefix": "3000::",
+                                    "prefix-len": 48,
+                                    "delegated-len": 64
+                                }
+                            ],
+                            "reservations": [
+                                {
+                                    "duid": "01:02:03:04",
+                                    "prefixes": [ "3000:0:0:1::/64" ]
+                                },
+                                {
+                                    "duid": "01:02:03:05",
+                                    "prefixes": [ "3000:0:0:100::/56" ]
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet6": [
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "pd-pools": [
+                        {
+                            "prefix": "3001::",
+                            "prefix-len": 48,
+                            "delegated-len": 56
+                        },
+                        {
+                            "prefix": "3002::",
+                            "prefix-len": 48,
+                            "delegated-len": 60
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "prefixes": [ "3001:0:0:100::/56", "3002::/64" ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixReservationsDelegatedLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "configuration includes 2 prefix reservations reserving the delegated prefixes whose lengths differ")
+	require.Contains(t, report.content, "1. [1] 2001:db8:1::/64, duid=01:02:03:05: 3000:0:0:100::/56 has length /56 while the pools delegate /64")
+	require.Contains(t, report.content, "2. [2] 2001:db8:2::/64, hw-address=01:02:03:04:05:06: 3002::/64 has length /64 while the pools delegate /56, /60")
+	require.NotContains(t, report.content, "duid=01:02:03:04:")
+}
+
+// Tests that the checker doesn't report the prefix reservations matching
+// the delegated length and the subnets without prefix delegation pools.
+func TestPrefixReservationsDelegatedLengthNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "pd-pools": [
+                        {
+                            "prefix": "3000::",
+                            "prefix-len": 48,
+                            "delegated-len": 64
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "duid": "01:02:03:04",
+                            "ip-addresses": [ "2001:db8:1::10" ],
+                            "prefixes": [ "3000:0:0:1::/64" ]
+                        }
+                    ]
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "reservations": [
+                        {
+                            "duid": "01:02:03:05",
+                            "prefixes": [ "3001::/56" ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixReservationsDelegatedLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker reports the prefix reservations from the host
+// database.
+func TestPrefixReservationsDelegatedLengthDatabase(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 111,
+                    "subnet": "2001:db8:1::/64",
+                    "pd-pools": [
+                        {
+                            "prefix": "3000::",
+                            "prefix-len": 48,
+                            "delegated-len": 64
+                        }
+                    ]
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "2001:db8:1::/64", "2001:db8:1::5", "3000:0:0:100::/56")
+
+	report, err := prefixReservationsDelegatedLength(createReviewContext(t, db, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 prefix reservation reserving")
+	require.Contains(t, report.content, "1. [111] 2001:db8:1::/64, hw-address=01:02:03:04:05:06: 3000:0:0:100::/56 has length /56 while the pools delegate /64")
+}
+
+// Tests that the number of the reported prefix reservations is limited.
+func TestPrefixReservationsDelegatedLengthMaxIssues(t *testing.T) {
+	// Arrange
+	var prefixes []string
+	for i := 0; i < 15; i++ {
+		prefixes = append(prefixes, fmt.Sprintf(`"3000:0:0:%x00::/56"`, i+1))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "pd-pools": [
+                        {
+                            "prefix": "3000::",
+                            "prefix-len": 48,
+                            "delegated-len": 64
+                        }
+                    ],
+                    "reservations": [
+                        {
+                            "duid": "01:02:03:04",
+                            "prefixes": [ %s ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`, strings.Join(prefixes, ", ")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixReservationsDelegatedLength(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 15 prefix reservations")
+	require.Contains(t, report.content, "10. [1]")
+	require.NotContains(t, report.content, "11. [1]")
+	require.Contains(t, report.content, "; and 5 more")
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestPrefixReservationsDelegatedLengthUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixReservationsDelegatedLength(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that error is generated for non-DHCP daemon.
+func TestSubnetsWithDuplicateIDsReportErrorForNonDHCPDaemon(t *testing.T) {
+	// Arrange
+	ctx := newReviewContext(nil, dbmodel.NewBind9Daemon(true), ManualRun, nil)
+
+	// Act
+	report, err := subnetsWithDuplicateIDs(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that report is nil for unique subnet IDs and the subnets without
+// explicit IDs are ignored.
+func TestSubnetsWithDuplicateIDsNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "10.0.1.0/24"
+                        },
+                        {
+                            "subnet": "10.0.3.0/24"
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 2,
+                    "subnet": "10.0.2.0/24"
+                },
+                {
+                    "subnet": "10.0.4.0/24"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetsWithDuplicateIDs(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the duplicated subnet IDs are reported for the top-level
+// subnets and the subnets in the shared networks.
+func TestSubnetsWithDuplicateIDsDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet6": [
+                        {
+                            "id": 1,
+                            "subnet": "2001:db8:1::/64"
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "2001:db8:2::/64"
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "subnet6": [
+                        {
+                            "id": 2,
+                            "subnet": "2001:db8:3::/64"
+                        }
+                    ]
+                }
+            ],
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:4::/64"
+                },
+                {
+                    "id": 3,
+                    "subnet": "2001:db8:5::/64"
+                },
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:6::/64"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetsWithDuplicateIDs(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 2 subnet IDs shared by multiple subnets.")
+	require.Contains(t, report.content, "1. subnet-id 1 is used by 2001:db8:4::/64, 2001:db8:6::/64, 2001:db8:1::/64")
+	require.Contains(t, report.content, "2. subnet-id 2 is used by 2001:db8:2::/64, 2001:db8:3::/64")
+	require.NotContains(t, report.content, "subnet-id 3")
+}
+
+// Test that the number of the reported subnet IDs is limited.
+func TestSubnetsWithDuplicateIDsExceedLimit(t *testing.T) {
+	// Arrange
+	var subnets []string
+	for i := 1; i <= 15; i++ {
+		subnets = append(subnets,
+			fmt.Sprintf(`{ "id": %d, "subnet": "10.%d.1.0/24" }`, i, i),
+			fmt.Sprintf(`{ "id": %d, "subnet": "10.%d.2.0/24" }`, i, i))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [ %s ]
+        }
+    }`, strings.Join(subnets, ", ")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetsWithDuplicateIDs(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 15 subnet IDs")
+	require.Contains(t, report.content, "10. subnet-id 10 is used by 10.10.1.0/24, 10.10.2.0/24")
+	require.NotContains(t, report.content, "subnet-id 11")
+	require.Contains(t, report.content, "; and 5 more")
+}
+
+// Test that the checker finding overlapping pools within a subnet returns
+// an error for the non-DHCP daemon.
+func TestSubnetPoolsOverlappingReportErrorForNonDHCPDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewBind9Daemon(true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetPoolsOverlapping(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Test that the overlapping address pools within the global subnets and
+// the subnets belonging to the shared networks are detected. The pools
+// specified as ranges and prefixes are compared and the malformed pools
+// are counted.
+func TestSubnetPoolsOverlappingDHCPv4(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10 - 192.0.2.100"
+                                },
+                                {
+                                    "pool": "192.0.2.64/26"
+                                },
+                                {
+                                    "pool": "192.0.2.200-192.0.2.210"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "subnet": "10.0.0.0/24",
+                    "pools": [
+                        {
+                            "pool": "10.0.0.1-10.0.0.10"
+                        },
+                        {
+                            "pool": "10.0.0.10-10.0.0.20"
+                        },
+                        {
+                            "pool": "foo"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 2 overlapping address pool pairs within the same subnet")
+	require.Contains(t, report.content, "Skipped 1 malformed pool.")
+	require.Contains(t, report.content, "1. subnet 10.0.0.0/24: pool 10.0.0.1-10.0.0.10 overlaps with pool 10.0.0.10-10.0.0.20")
+	require.Contains(t, report.content, "2. subnet [1] 192.0.2.0/24: pool 192.0.2.10 - 192.0.2.100 overlaps with pool 192.0.2.64/26")
+	require.NotContains(t, report.content, "192.0.2.200")
+}
+
+// Test that the overlapping address pools within a subnet are detected
+// in the DHCPv6 server.
+func TestSubnetPoolsOverlappingDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "pools": [
+                        {
+                            "pool": "2001:db8:1::/80"
+                        },
+                        {
+                            "pool": "2001:db8:1::10-2001:db8:1::20"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 overlapping address pool pair within the same subnet")
+	require.Contains(t, report.content, "1. subnet [1] 2001:db8:1::/64: pool 2001:db8:1::/80 overlaps with pool 2001:db8:1::10-2001:db8:1::20")
+	require.NotContains(t, report.content, "malformed")
+}
+
+// Test that no report is generated when the pools within the subnets
+// don't overlap, even if some pools are malformed.
+func TestSubnetPoolsNotOverlapping(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.10-192.0.2.20"
+                        },
+                        {
+                            "pool": "192.0.2.21-192.0.2.30"
+                        },
+                        {
+                            "pool": "192.0.2.30-2001:db8::1"
+                        }
+                    ]
+                },
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.10-192.0.2.20"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the number of reported overlapping pool pairs is limited.
+func TestSubnetPoolsOverlappingExceedLimit(t *testing.T) {
+	// Arrange
+	var pools []string
+	for i := 0; i < 6; i++ {
+		pools = append(pools, fmt.Sprintf(`{ "pool": "192.0.2.%d-192.0.2.100" }`, i+1))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "subnet": "192.0.2.0/24",
+                    "pools": [ %s ]
+                }
+            ]
+        }
+    }`, strings.Join(pools, ", ")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := subnetPoolsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 15 overlapping address pool pairs")
+	require.Contains(t, report.content, "10. subnet 192.0.2.0/24")
+	require.NotContains(t, report.content, "11. subnet")
+	require.Contains(t, report.content, "; and 5 more")
+}
+
+// Tests that the reservations using the identifier types not listed in
+// the host-reservation-identifiers are reported.
+func TestReservationIdentifiersNotListed(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "host-reservation-identifiers": [ "hw-address", "circuit-id" ],
+            "reservations": [
+                {
+                    "client-id": "01:aa:bb",
+                    "ip-address": "10.0.0.1"
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "reservations": [
+                                {
+                                    "flex-id": "'foo'",
+                                    "ip-address": "192.0.3.5"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "ip-address": "192.0.2.5"
+                        },
+                        {
+                            "circuit-id": "01:01",
+                            "ip-address": "192.0.2.6"
+                        },
+                        {
+                            "duid": "01:02:03",
+                            "ip-address": "192.0.2.7"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`
+	report, err := reservationIdentifiersNotListed(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 3 host reservations using the identifier types not listed")
+	require.Contains(t, report.content, "1. global reservation, client-id=01:aa:bb: client-id is not listed")
+	require.Contains(t, report.content, "2. subnet [1] 192.0.2.0/24, duid=01:02:03: duid is not listed")
+	require.Contains(t, report.content, "3. subnet [2] 192.0.3.0/24, flex-id='foo': flex-id is not listed")
+	require.NotContains(t, report.content, "hw-address=")
+	require.NotContains(t, report.content, "circuit-id=")
+}
+
+// Tests that no report is generated when the host-reservation-identifiers
+// parameter is not specified or all identifier types are listed.
+func TestReservationIdentifiersNotListedNoIssues(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            %s
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "reservations": [
+                        {
+                            "duid": "01:02:03",
+                            "ip-addresses": [ "2001:db8:1::5" ]
+                        }
+                    ]
+                }
+            ]
+        }
+    }`
+	for _, identifiers := range []string{"", `"host-reservation-identifiers": [ "hw-address", "duid" ],`} {
+		report, err := reservationIdentifiersNotListed(createReviewContext(t, nil, fmt.Sprintf(configStr, identifiers)))
+		require.NoError(t, err)
+		require.Nil(t, report)
+	}
+}
+
+// Tests that the reservations from the host database using the identifier
+// types not listed in the host-reservation-identifiers are reported.
+func TestReservationIdentifiersNotListedDatabase(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp4": {
+            "host-reservation-identifiers": [ "duid" ],
+            "subnet4": [
+                {
+                    "id": 111,
+                    "subnet": "192.0.2.0/24"
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "192.0.2.0/24", "192.0.2.5")
+
+	report, err := reservationIdentifiersNotListed(createReviewContext(t, db, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 host reservation using")
+	require.Contains(t, report.content, "1. subnet [111] 192.0.2.0/24, hw-address=01:02:03:04:05:06: hw-address is not listed")
+}
+
+// Tests that the number of the reported reservations is limited.
+func TestReservationIdentifiersNotListedMaxIssues(t *testing.T) {
+	var reservations []string
+	for i := 1; i <= 15; i++ {
+		reservations = append(reservations, fmt.Sprintf(`{ "duid": "01:02:%02x", "ip-address": "192.0.2.%d" }`, i, i))
+	}
+	configStr := fmt.Sprintf(`{
+        "Dhcp4": {
+            "host-reservation-identifiers": [ "hw-address" ],
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "reservations": [ %s ]
+                }
+            ]
+        }
+    }`, strings.Join(reservations, ", "))
+	report, err := reservationIdentifiersNotListed(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 15 host reservations")
+	require.Contains(t, report.content, "10. subnet [1] 192.0.2.0/24, duid=01:02:0a")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "; and 5 more")
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestReservationIdentifiersNotListedUnsupportedDaemon(t *testing.T) {
+	daemon := dbmodel.NewBind9Daemon(true)
+	report, err := reservationIdentifiersNotListed(newReviewContext(nil, daemon, ManualRun, nil))
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Tests that the host reservations with the blank and invalid hostnames
+// are reported.
+func TestReservationHostname(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "reservations": [
+                {
+                    "client-id": "01:aa:bb",
+                    "hostname": ""
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "reservations": [
+                                {
+                                    "flex-id": "'foo'",
+                                    "hostname": "-foo.example.org"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "reservations": [
+                        {
+                            "hw-address": "01:02:03:04:05:06",
+                            "hostname": "   "
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:07",
+                            "hostname": "host.example.org"
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:08",
+                            "hostname": " host "
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:09"
+                        },
+                        {
+                            "hw-address": "01:02:03:04:05:0a",
+                            "hostname": "host.example."
+                        },
+                        {
+                            "ip-address": "192.0.2.10",
+                            "hostname": "host..example.org"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`
+	report, err := reservationHostname(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 5 host reservations with the blank or invalid hostnames")
+	require.Contains(t, report.content, `1. global reservation, client-id=01:aa:bb: hostname "" is blank`)
+	require.Contains(t, report.content, `2. subnet [1] 192.0.2.0/24, hw-address=01:02:03:04:05:06: hostname "   " is blank`)
+	require.Contains(t, report.content, `3. subnet [1] 192.0.2.0/24, hw-address=01:02:03:04:05:08: hostname " host " has leading or trailing whitespace`)
+	require.Contains(t, report.content, `4. subnet [1] 192.0.2.0/24, ip-address=192.0.2.10: hostname "host..example.org" is invalid`)
+	require.Contains(t, report.content, `5. subnet [2] 192.0.3.0/24, flex-id='foo': hostname "-foo.example.org" is invalid`)
+	require.NotContains(t, report.content, "01:02:03:04:05:07")
+	require.NotContains(t, report.content, "01:02:03:04:05:09")
+	require.NotContains(t, report.content, "01:02:03:04:05:0a")
+}
+
+// Tests that no report is generated when the hostnames are valid.
+func TestReservationHostnameNoIssues(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            "reservations": [
+                {
+                    "duid": "01:02:03",
+                    "hostname": "global.example.org"
+                }
+            ],
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "reservations": [
+                        {
+                            "duid": "01:02:04",
+                            "hostname": "host1"
+                        },
+                        {
+                            "duid": "01:02:05"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`
+	report, err := reservationHostname(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the host reservations with the blank hostnames are reported
+// when they are stored in the host database.
+func TestReservationHostnameDatabase(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 111,
+                    "subnet": "192.0.2.0/24"
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "192.0.2.0/24", "192.0.2.5")
+	_, err := db.Exec("UPDATE host SET hostname = ?", "  ")
+	require.NoError(t, err)
+
+	report, err := reservationHostname(createReviewContext(t, db, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 host reservation with")
+	require.Contains(t, report.content, `1. subnet [111] 192.0.2.0/24, hw-address=01:02:03:04:05:06: hostname "  " is blank`)
+}
+
+// Tests that the number of the listed reservations is limited.
+func TestReservationHostnameMaxFindings(t *testing.T) {
+	var reservations []string
+	for i := 1; i <= 15; i++ {
+		reservations = append(reservations, fmt.Sprintf(`{ "duid": "01:02:%02x", "hostname": " " }`, i))
+	}
+	configStr := fmt.Sprintf(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64",
+                    "reservations": [ %s ]
+                }
+            ]
+        }
+    }`, strings.Join(reservations, ", "))
+	report, err := reservationHostname(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 15 host reservations")
+	require.Contains(t, report.content, "10. subnet [1] 2001:db8:1::/64, duid=01:02:0a")
+	require.NotContains(t, report.content, "11.")
+	require.Contains(t, report.content, "and 5 more")
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestReservationHostnameUnsupportedDaemon(t *testing.T) {
+	daemon := dbmodel.NewBind9Daemon(true)
+	report, err := reservationHostname(newReviewContext(nil, daemon, ManualRun, nil))
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Tests the validation of the reservation hostnames.
+func TestValidateReservationHostname(t *testing.T) {
+	require.Empty(t, validateReservationHostname("host"))
+	require.Empty(t, validateReservationHostname("host-1.example.org"))
+	require.Empty(t, validateReservationHostname("host.example.org."))
+	require.Empty(t, validateReservationHostname("host.example."))
+	require.Empty(t, validateReservationHostname("HOST-1.Example.org"))
+	require.Empty(t, validateReservationHostname(strings.Repeat("a", 63)+".example.org"))
+	require.Equal(t, "is blank", validateReservationHostname(""))
+	require.Equal(t, "is blank", validateReservationHostname(" \t "))
+	require.Equal(t, "is invalid", validateReservationHostname("host_1"))
+	require.Equal(t, "is invalid", validateReservationHostname("host-"))
+	require.Equal(t, "is invalid", validateReservationHostname("host..example.org"))
+	require.Equal(t, "is invalid", validateReservationHostname("host name"))
+	require.Equal(t, "is invalid", validateReservationHostname("."))
+	require.Equal(t, "is invalid", validateReservationHostname("host.example.org.."))
+	require.Equal(t, "is invalid", validateReservationHostname(strings.Repeat("a", 64)+".example.org"))
+	require.Equal(t, "is invalid", validateReservationHostname(strings.Repeat("a.", 127)+"org"))
+	require.Equal(t, "has leading or trailing whitespace", validateReservationHostname(" host "))
+	require.Equal(t, "has leading or trailing whitespace", validateReservationHostname("host.example.org\t"))
+}
+
+// Tests that the checker reports the host reservations in the host
+// database referencing the subnet not present in the configuration.
+func TestReservationsUnknownSubnet(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 111,
+                    "subnet": "192.0.2.0/24"
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "192.0.2.0/24", "192.0.2.5")
+	// Simulate the subnet renumbered in the configuration.
+	_, err := db.Exec("UPDATE local_subnet SET local_subnet_id = ?", 222)
+	require.NoError(t, err)
+
+	// Act
+	report, err := reservationsUnknownSubnet(createReviewContext(t, db, configStr))
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "host database includes 1 host reservation referencing")
+	require.Contains(t, report.content, "1. hw-address=01:02:03:04:05:06: subnet [222] 192.0.2.0/24")
+}
+
+// Tests that the checker reports the host reservations in the subnet
+// which prefix doesn't match any configured subnet.
+func TestReservationsUnknownSubnetPrefix(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "2001:db8:2::/64", "2001:db8:2::5")
+
+	// Act
+	report, err := reservationsUnknownSubnet(createReviewContext(t, db, configStr))
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. hw-address=01:02:03:04:05:06: subnet 2001:db8:2::/64")
+}
+
+// Tests that the checker doesn't report the host reservations in the
+// configured subnets.
+func TestReservationsUnknownSubnetNoIssues(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 111,
+                            "subnet": "192.0.2.0/24"
+                        }
+                    ]
+                }
+            ],
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "192.0.2.0/24", "192.0.2.5")
+	subnets, err := dbmodel.GetSubnetsByDaemonID(db, 1)
+	require.NoError(t, err)
+	require.Len(t, subnets, 1)
+	require.EqualValues(t, 111, subnets[0].LocalSubnets[0].LocalSubnetID)
+
+	// Act
+	report, err := reservationsUnknownSubnet(createReviewContext(t, db, configStr))
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker is skipped when the host_cmds hooks library is
+// not loaded.
+func TestReservationsUnknownSubnetNoHostCmds(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	configStr := `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 111,
+                    "subnet": "192.0.2.0/24"
+                }
+            ]
+        }
+    }`
+
+	createHostInDatabase(t, db, configStr, "192.0.3.0/24", "192.0.3.5")
+
+	// Act
+	report, err := reservationsUnknownSubnet(createReviewContext(t, db, configStr))
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker is skipped when the database is not available.
+func TestReservationsUnknownSubnetNoDatabase(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "hooks-libraries": [
+                {
+                    "library": "/usr/lib/kea/libdhcp_host_cmds.so"
+                }
+            ]
+        }
+    }`
+	report, err := reservationsUnknownSubnet(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestReservationsUnknownSubnetUnsupportedDaemon(t *testing.T) {
+	daemon := dbmodel.NewBind9Daemon(true)
+	report, err := reservationsUnknownSubnet(newReviewContext(nil, daemon, ManualRun, nil))
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Tests that the option definitions redefining the standard DHCPv4
+// options with different types are reported.
+func TestOptionDefinitionsStandardConflictDHCPv4(t *testing.T) {
+	configStr := `{
+        "Dhcp4": {
+            "option-def": [
+                {
+                    "name": "my-routers",
+                    "code": 3,
+                    "type": "string"
+                },
+                {
+                    "name": "my-servers",
+                    "code": 6,
+                    "type": "ipv4-address",
+                    "array": true
+                },
+                {
+                    "name": "my-mtu",
+                    "code": 26,
+                    "type": "uint16",
+                    "array": true,
+                    "space": "dhcp4"
+                },
+                {
+                    "name": "my-vendor-option",
+                    "code": 3,
+                    "type": "string",
+                    "space": "vendor-encapsulated-options-space"
+                },
+                {
+                    "name": "my-option",
+                    "code": 222,
+                    "type": "string"
+                }
+            ]
+        }
+    }`
+	report, err := optionDefinitionsStandardConflict(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 option definitions redefining the standard DHCP options")
+	require.Contains(t, report.content, "1. option code 3 (routers): standard type ipv4-address array, configured type string")
+	require.Contains(t, report.content, "2. option code 26 (interface-mtu): standard type uint16, configured type uint16 array")
+	require.NotContains(t, report.content, "code 6")
+	require.NotContains(t, report.content, "code 222")
+}
+
+// Tests that the option definitions redefining the standard DHCPv6
+// options with different types are reported.
+func TestOptionDefinitionsStandardConflictDHCPv6(t *testing.T) {
+	configStr := `{
+        "Dhcp6": {
+            "option-def": [
+                {
+                    "name": "my-dns-servers",
+                    "code": 23,
+                    "type": "ipv6-address"
+                },
+                {
+                    "name": "my-routers",
+                    "code": 3,
+                    "type": "string"
+                }
+            ]
+        }
+    }`
+	report, err := optionDefinitionsStandardConflict(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 option definition redefining")
+	require.Contains(t, report.content, "1. option code 23 (dns-servers): standard type ipv6-address array, configured type ipv6-address")
+}
+
+// Tests that no report is generated when there are no option definitions.
+func TestOptionDefinitionsStandardConflictNoDefinitions(t *testing.T) {
+	configStr := `{
+        "Dhcp4": { }
+    }`
+	report, err := optionDefinitionsStandardConflict(createReviewContext(t, nil, configStr))
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Tests that the checker returns an error for the unsupported daemon.
+func TestOptionDefinitionsStandardConflictUnsupportedDaemon(t *testing.T) {
+	daemon := dbmodel.NewBind9Daemon(true)
+	report, err := optionDefinitionsStandardConflict(newReviewContext(nil, daemon, ManualRun, nil))
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Test that the pool size is computed correctly for the IPv4 and IPv6
+// pools, including the pools exceeding the uint64 range.
+func TestParsedPoolSize(t *testing.T) {
+	pools := parsePools([]keaconfig.Pool{
+		{Pool: "192.0.2.1-192.0.2.1"},
+		{Pool: "192.0.2.0/24"},
+		{Pool: "2001:db8:1::10-2001:db8:1::1f"},
+		{Pool: "2001:db8:1::/48"},
+	})
+	require.Len(t, pools, 4)
+
+	require.EqualValues(t, 1, pools[0].size().Int64())
+	require.EqualValues(t, 256, pools[1].size().Int64())
+	require.EqualValues(t, 16, pools[2].size().Int64())
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 80), pools[3].size())
+}
+
+// Test that the pool size checker returns an error for an unsupported
+// daemon.
+func TestPoolSizeBelowMinimumReportErrorForNonDHCPDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewBind9Daemon(true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Test that the DHCPv4 pools with fewer addresses than the default
+// threshold are reported for the global subnets and the subnets belonging
+// to the shared networks.
+func TestPoolSizeBelowMinimumDHCPv4(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24",
+                            "pools": [
+                                {
+                                    "pool": "192.0.2.10 - 192.0.2.100"
+                                },
+                                {
+                                    "pool": "192.0.2.200/30"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "subnet": "10.0.0.0/24",
+                    "pools": [
+                        {
+                            "pool": "10.0.0.1-10.0.0.1"
+                        },
+                        {
+                            "pool": "10.0.0.11-10.0.0.20"
+                        },
+                        {
+                            "pool": "foo"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 address pools with fewer than 10 addresses")
+	require.Contains(t, report.content, "1. subnet 10.0.0.0/24: pool 10.0.0.1-10.0.0.1 has 1 address;")
+	require.Contains(t, report.content, "2. subnet [1] 192.0.2.0/24: pool 192.0.2.200/30 has 4 addresses")
+	require.NotContains(t, report.content, "10.0.0.11-10.0.0.20")
+	require.NotContains(t, report.content, "192.0.2.10 - 192.0.2.100")
+}
+
+// Test that the large DHCPv6 pools are not reported and the small ones
+// are.
+func TestPoolSizeBelowMinimumDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/48",
+                    "pools": [
+                        {
+                            "pool": "2001:db8:1::/64"
+                        },
+                        {
+                            "pool": "2001:db8:1:1::1-2001:db8:1:1::5"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 address pool with fewer than 10 addresses")
+	require.Contains(t, report.content, "1. subnet [1] 2001:db8:1::/48: pool 2001:db8:1:1::1-2001:db8:1:1::5 has 5 addresses")
+	require.NotContains(t, report.content, "2001:db8:1::/64")
+}
+
+// Test that no report is generated when all pools are large enough.
+func TestPoolSizeBelowMinimumNoPools(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.0/28"
+                        }
+                    ]
+                },
+                {
+                    "subnet": "192.0.3.0/24"
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the number of the reported pools is limited.
+func TestPoolSizeBelowMinimumExceedLimit(t *testing.T) {
+	// Arrange
+	var pools []string
+	for i := 0; i < 20; i++ {
+		pools = append(pools, fmt.Sprintf(`{ "pool": "192.0.2.%d-192.0.2.%d" }`, i*10, i*10+1))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "subnet": "192.0.2.0/24",
+                    "pools": [ %s ]
+                }
+            ]
+        }
+    }`, strings.Join(pools, ",")))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 20 address pools")
+	require.Contains(t, report.content, "10. subnet 192.0.2.0/24")
+	require.NotContains(t, report.content, "11. subnet")
+	require.Contains(t, report.content, "; and 10 more")
+}
+
+// Test that the threshold is read from the database settings and that
+// the zero threshold disables the checker.
+func TestPoolSizeBelowMinimumThresholdFromSettings(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+	require.NoError(t, dbmodel.SetSettingInt(db, "min_pool_size", 100))
+
+	ctx := createReviewContext(t, db, `{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "pools": [
+                        {
+                            "pool": "192.0.2.0/26"
+                        },
+                        {
+                            "pool": "192.0.2.128/25"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+
+	// Act
+	report, err := poolSizeBelowMinimum(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 1 address pool with fewer than 100 addresses")
+	require.Contains(t, report.content, "pool 192.0.2.0/26 has 64 addresses")
+
+	// Disable the checker.
+	require.NoError(t, dbmodel.SetSettingInt(db, "min_pool_size", 0))
+
+	report, err = poolSizeBelowMinimum(ctx)
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the match-client-id checker returns an error for an
+// unsupported daemon.
+func TestSharedNetworkMatchClientIDConsistencyReportErrorForNonDHCPv4Daemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkMatchClientIDConsistency(ctx)
+
+	// Assert
+	require.ErrorContains(t, err, "unsupported daemon")
+	require.Nil(t, report)
+}
+
+// Test that the subnets with the match-client-id values diverging from
+// the shared network are reported, taking into account the values
+// inherited from the shared network and the global level.
+func TestSharedNetworkMatchClientIDConsistency(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "match-client-id": false,
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 1,
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24",
+                            "match-client-id": true
+                        },
+                        {
+                            "id": 3,
+                            "subnet": "192.0.4.0/24",
+                            "match-client-id": false
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "match-client-id": true,
+                    "subnet4": [
+                        {
+                            "id": 4,
+                            "subnet": "10.0.0.0/24"
+                        },
+                        {
+                            "id": 5,
+                            "subnet": "10.0.1.0/24",
+                            "match-client-id": false
+                        },
+                        {
+                            "id": 6,
+                            "subnet": "10.0.2.0/24",
+                            "match-client-id": false
+                        }
+                    ]
+                },
+                {
+                    "name": "baz",
+                    "match-client-id": true,
+                    "subnet4": [
+                        {
+                            "id": 7,
+                            "subnet": "10.1.0.0/24"
+                        },
+                        {
+                            "id": 8,
+                            "subnet": "10.1.1.0/24",
+                            "match-client-id": true
+                        }
+                    ]
+                }
+            ],
+            "subnet4": [
+                {
+                    "id": 9,
+                    "subnet": "10.2.0.0/24",
+                    "match-client-id": true
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkMatchClientIDConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 2 shared networks with the subnets using a different match-client-id value")
+	require.Contains(t, report.content, "1. shared network foo with match-client-id false: 1 subnet [2] 192.0.3.0/24;")
+	require.Contains(t, report.content, "2. shared network bar with match-client-id true: 2 subnets [5] 10.0.1.0/24, [6] 10.0.2.0/24")
+	require.NotContains(t, report.content, "baz")
+	require.NotContains(t, report.content, "10.2.0.0/24")
+}
+
+// Test that the default match-client-id value is taken into account when
+// it is not specified at the global and shared network levels.
+func TestSharedNetworkMatchClientIDConsistencyDefault(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "subnet": "192.0.3.0/24",
+                            "match-client-id": false
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkMatchClientIDConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "1. shared network foo with match-client-id true: 1 subnet 192.0.3.0/24")
+}
+
+// Test that no report is generated when the subnets use consistent
+// match-client-id values.
+func TestSharedNetworkMatchClientIDConsistencyNoIssues(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "match-client-id": true,
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "match-client-id": false,
+                    "subnet4": [
+                        {
+                            "subnet": "192.0.2.0/24"
+                        },
+                        {
+                            "subnet": "192.0.3.0/24",
+                            "match-client-id": false
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := sharedNetworkMatchClientIDConsistency(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the loggers writing to the files are reported when the daemon
+// runs on a machine using systemd.
+func TestLoggersFileOutputUnderSystemd(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	daemon.App = &dbmodel.App{
+		Machine: &dbmodel.Machine{
+			State: dbmodel.MachineState{
+				Os:             "linux",
+				PlatformFamily: "debian",
+			},
+		},
+	}
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "loggers": [
+                {
+                    "name": "kea-dhcp4",
+                    "output_options": [
+                        { "output": "/var/log/kea-dhcp4.log" },
+                        { "output": "stdout" }
+                    ],
+                    "severity": "INFO"
+                },
+                {
+                    "name": "kea-dhcp4.packets",
+                    "output_options": [
+                        { "output": "syslog:kea" },
+                        { "output": "/var/log/kea-packets.log" }
+                    ],
+                    "severity": "DEBUG"
+                },
+                {
+                    "name": "kea-dhcp4.leases",
+                    "output_options": [
+                        { "output": "syslog" },
+                        { "output": "stderr" }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := loggersFileOutputUnderSystemd(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "configuration includes 2 logger outputs to the files")
+	require.Contains(t, report.content, "1. logger kea-dhcp4 writing to /var/log/kea-dhcp4.log")
+	require.Contains(t, report.content, "2. logger kea-dhcp4.packets writing to /var/log/kea-packets.log")
+	require.NotContains(t, report.content, "kea-dhcp4.leases")
+}
+
+// Test that the loggers writing to the files are not reported when the
+// daemon doesn't appear to run under systemd.
+func TestLoggersFileOutputNotUnderSystemd(t *testing.T) {
+	machines := []*dbmodel.Machine{
+		nil,
+		{
+			State: dbmodel.MachineState{
+				Os:             "freebsd",
+				PlatformFamily: "freebsd",
+			},
+		},
+		{
+			State: dbmodel.MachineState{
+				Os:             "linux",
+				PlatformFamily: "alpine",
+			},
+		},
+		{
+			State: dbmodel.MachineState{
+				Os:                   "linux",
+				PlatformFamily:       "debian",
+				VirtualizationSystem: "docker",
+				VirtualizationRole:   "guest",
+			},
+		},
+	}
+
+	for i, machine := range machines {
+		machine := machine
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			// Arrange
+			daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+			daemon.App = &dbmodel.App{
+				Machine: machine,
+			}
+			_ = daemon.SetConfigFromJSON(`{
+                "Control-agent": {
+                    "loggers": [
+                        {
+                            "name": "kea-ctrl-agent",
+                            "output_options": [
+                                { "output": "/var/log/kea-ctrl-agent.log" }
+                            ]
+                        }
+                    ]
+                }
+            }`)
+			ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+			// Act
+			report, err := loggersFileOutputUnderSystemd(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.Nil(t, report)
+		})
+	}
+}
+
+// Test that the checker returns an error for a daemon other than Kea.
+func TestLoggersFileOutputUnderSystemdUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewBind9Daemon(true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := loggersFileOutputUnderSystemd(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the findings beyond the maximum number are summarized.
+func TestJoinFindings(t *testing.T) {
+	findings := []string{"1. foo", "2. bar", "3. baz"}
+	require.Equal(t, "1. foo; 2. bar; 3. baz", joinFindings(findings, 3))
+	require.Equal(t, "1. foo; 2. bar; 3. baz", joinFindings(findings, 10))
+	require.Equal(t, "1. foo; and 2 more", joinFindings(findings, 1))
+	require.Empty(t, joinFindings([]string{}, 1))
+}
+
+// Test that the maximum number of findings is read from the database
+// settings and the default is used when the database is unavailable or
+// the setting is not positive.
+func TestGetMaxFindings(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+
+	// Act & Assert
+	maxFindings, err := getMaxFindings(newReviewContext(nil, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.EqualValues(t, defaultMaxFindings, maxFindings)
+
+	maxFindings, err = getMaxFindings(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.EqualValues(t, 10, maxFindings)
+
+	require.NoError(t, dbmodel.SetSettingInt(db, "max_checker_findings", 3))
+	maxFindings, err = getMaxFindings(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, maxFindings)
+
+	require.NoError(t, dbmodel.SetSettingInt(db, "max_checker_findings", 0))
+	maxFindings, err = getMaxFindings(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.EqualValues(t, defaultMaxFindings, maxFindings)
+}
+
+// Test that the overlapping subnets detection time budget is read from the
+// database settings and the default is used when the database is
+// unavailable or the setting is negative.
+func TestGetOverlapsDetectionTimeBudget(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+
+	// Act & Assert
+	timeBudget, err := getOverlapsDetectionTimeBudget(newReviewContext(nil, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.Equal(t, overlapsDetectionTimeBudget, timeBudget)
+
+	timeBudget, err = getOverlapsDetectionTimeBudget(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, timeBudget)
+
+	require.NoError(t, dbmodel.SetSettingInt(db, "overlaps_detection_time_budget", 0))
+	timeBudget, err = getOverlapsDetectionTimeBudget(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.Zero(t, timeBudget)
+
+	require.NoError(t, dbmodel.SetSettingInt(db, "overlaps_detection_time_budget", -1))
+	timeBudget, err = getOverlapsDetectionTimeBudget(newReviewContext(db, daemon, ManualRun, nil))
+	require.NoError(t, err)
+	require.Equal(t, overlapsDetectionTimeBudget, timeBudget)
+}
+
+// Test that the overlapping subnets are listed up to the maximum number
+// of findings from the settings and the remaining ones are summarized.
+func TestSubnetsOverlappingMaxFindingsFromSettings(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	require.NoError(t, dbmodel.InitializeSettings(db, 0))
+	require.NoError(t, dbmodel.SetSettingInt(db, "max_checker_findings", 2))
+
+	ctx := createReviewContext(t, db, `{
+        "Dhcp4": {
+            "subnet4": [
+                { "id": 1, "subnet": "10.0.0.0/8" },
+                { "id": 2, "subnet": "10.0.0.0/16" },
+                { "id": 3, "subnet": "10.1.0.0/16" },
+                { "id": 4, "subnet": "10.2.0.0/16" }
+            ]
+        }
+    }`)
+
+	// Act
+	report, err := subnetsOverlapping(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 3 overlapping subnet pairs.")
+	require.Contains(t, report.content, "2. 10.0.0.0/8 (subnet-id 1) is overlapped by")
+	require.Contains(t, report.content, "; and 1 more")
+	require.NotContains(t, report.content, "3. ")
+}
+
+// Test that the non-canonical prefixes beyond the maximum number of
+// findings are summarized.
+func TestCanonicalPrefixesMaxFindings(t *testing.T) {
+	// Arrange
+	var subnets []interface{}
+	for i := 0; i < 15; i++ {
+		subnets = append(subnets, map[string]interface{}{
+			"id":     i + 1,
+			"subnet": fmt.Sprintf("192.0.%d.1/24", i),
+		})
+	}
+	config, _ := json.Marshal(map[string]interface{}{
+		"Dhcp4": map[string]interface{}{
+			"subnet4": subnets,
+		},
+	})
+	ctx := createReviewContext(t, nil, string(config))
+
+	// Act
+	report, err := canonicalPrefixes(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration contains 15 non-canonical prefixes.")
+	require.Contains(t, report.content, "10. [10] 192.0.9.1/24 is invalid prefix, expected: 192.0.9.0/24; and 5 more")
+	require.NotContains(t, report.content, "11. ")
+}
+
+// Test that the dispensable subnets are listed and the ones beyond the
+// maximum number of findings are summarized.
+func TestSubnetDispensableMaxFindings(t *testing.T) {
+	// Arrange
+	var subnets []interface{}
+	for i := 0; i < 12; i++ {
+		subnets = append(subnets, map[string]interface{}{
+			"id":     i + 1,
+			"subnet": fmt.Sprintf("2001:db8:%d::/64", i+1),
+		})
+	}
+	config, _ := json.Marshal(map[string]interface{}{
+		"Dhcp6": map[string]interface{}{
+			"subnet6": subnets,
+		},
+	})
+	ctx := createReviewContext(t, nil, string(config))
+
+	// Act
+	report, err := subnetDispensable(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "configuration includes 12 subnets without pools and host reservations")
+	require.Contains(t, report.content, "1. [1] 2001:db8:1::/64; 2. [2] 2001:db8:2::/64")
+	require.Contains(t, report.content, "10. [10] 2001:db8:10::/64; and 2 more")
+	require.NotContains(t, report.content, "11. ")
+}
+
+// Test that the complete blocks of the same-length prefixes are found and
+// that only the largest blocks are returned.
+func TestFindAggregablePrefixes(t *testing.T) {
+	// Arrange
+	prefixes := []string{
+		// Complete /62 block.
+		"2001:db8:1::/64",
+		"2001:db8:1:1::/64",
+		"2001:db8:1:2::/64",
+		"2001:db8:1:3::/64",
+		// Incomplete /62 block forms only one /63 block.
+		"2001:db8:2::/64",
+		"2001:db8:2:1::/64",
+		"2001:db8:2:2::/64",
+		// Adjacent but not siblings.
+		"2001:db8:3:1::/64",
+		"2001:db8:3:2::/64",
+		// Different lengths.
+		"2001:db8:4::/64",
+		"2001:db8:4:1::/65",
+		// Non-canonical and invalid prefixes and IPv4.
+		"2001:db8:5::1/64",
+		"2001:db8:5:1::/64",
+		"foo",
+		"192.0.2.0/25",
+		"192.0.2.128/25",
+	}
+
+	// Act
+	aggregates := findAggregablePrefixes(prefixes)
+
+	// Assert
+	require.Len(t, aggregates, 2)
+	require.Equal(t, "2001:db8:1::/62", aggregates[0].supernet())
+	require.Equal(t, []string{"2001:db8:1::/64", "2001:db8:1:1::/64", "2001:db8:1:2::/64", "2001:db8:1:3::/64"}, aggregates[0].prefixes)
+	require.Equal(t, "2001:db8:2::/63", aggregates[1].supernet())
+	require.Equal(t, []string{"2001:db8:2::/64", "2001:db8:2:1::/64"}, aggregates[1].prefixes)
+}
+
+// Test that the checker suggests aggregating the subnets from the
+// top-level and the shared networks.
+func TestPrefixAggregation(t *testing.T) {
+	// Arrange
+	var subnets []interface{}
+	for i := 0; i < 8; i++ {
+		subnets = append(subnets, map[string]interface{}{
+			"id":     i + 1,
+			"subnet": fmt.Sprintf("2001:db8:2:%x::/64", i),
+		})
+	}
+	config, _ := json.Marshal(map[string]interface{}{
+		"Dhcp6": map[string]interface{}{
+			"shared-networks": []interface{}{
+				map[string]interface{}{
+					"name": "foo",
+					"subnet6": []interface{}{
+						map[string]interface{}{
+							"id":     10,
+							"subnet": "2001:db8:1::/48",
+						},
+					},
+				},
+			},
+			"subnet6": append(subnets, map[string]interface{}{
+				"id":     11,
+				"subnet": "2001:db8::/48",
+			}),
+		},
+	})
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(string(config))
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixAggregation(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "configuration includes 2 groups of adjacent subnets")
+	require.Contains(t, report.content, "1. 2001:db8::/48, 2001:db8:1::/48 can be aggregated into 2001:db8::/47")
+	require.Contains(t, report.content, "2. 8 subnets from 2001:db8:2:0::/64 to 2001:db8:2:7::/64 can be aggregated into 2001:db8:2::/61")
+}
+
+// Test that no report is generated when the subnets don't form complete
+// larger prefixes.
+func TestPrefixAggregationNoReport(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                { "id": 1, "subnet": "2001:db8:1:1::/64" },
+                { "id": 2, "subnet": "2001:db8:1:2::/64" },
+                { "id": 3, "subnet": "2001:db8:1:4::/64" }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixAggregation(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the checker returns an error for a daemon other than DHCPv6.
+func TestPrefixAggregationUnsupportedDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := prefixAggregation(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the valid lifetime checker returns an error for a daemon
+// other than the DHCP server.
+func TestValidLifetimeUnspecifiedNonDHCPDaemon(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Control-agent": { }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := validLifetimeUnspecified(ctx)
+
+	// Assert
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the valid lifetime checker reports the DHCPv4 subnets for
+// which the valid lifetime is not specified at the subnet or shared
+// network level when there is no global value.
+func TestValidLifetimeUnspecifiedDHCPv4(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 42
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24",
+                    "valid-lifetime": 3600
+                },
+                {
+                    "id": 2,
+                    "subnet": "192.0.3.0/24"
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "valid-lifetime": 7200,
+                    "subnet4": [
+                        {
+                            "id": 3,
+                            "subnet": "10.0.0.0/24"
+                        }
+                    ]
+                },
+                {
+                    "name": "bar",
+                    "subnet4": [
+                        {
+                            "id": 4,
+                            "subnet": "10.0.1.0/24"
+                        },
+                        {
+                            "id": 5,
+                            "subnet": "10.0.2.0/24",
+                            "valid-lifetime": 1800
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := validLifetimeUnspecified(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.EqualValues(t, 42, report.daemonID)
+	require.Contains(t, report.content, "Kea {daemon} configuration includes 2 subnets without the valid-lifetime")
+	require.Contains(t, report.content, "1. [4] 10.0.1.0/24")
+	require.Contains(t, report.content, "2. [2] 192.0.3.0/24")
+	require.NotContains(t, report.content, "192.0.2.0/24")
+	require.NotContains(t, report.content, "10.0.0.0/24")
+	require.NotContains(t, report.content, "10.0.2.0/24")
+}
+
+// Test that the valid lifetime checker reports the DHCPv6 subnets for
+// which the valid lifetime is not specified at any level.
+func TestValidLifetimeUnspecifiedDHCPv6(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv6, true)
+	daemon.ID = 1
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "subnet": "2001:db8:1::/64"
+                },
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64",
+                    "valid-lifetime": 4000
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := validLifetimeUnspecified(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes 1 subnet without the valid-lifetime")
+	require.Contains(t, report.content, "1. 2001:db8:1::/64")
+	require.NotContains(t, report.content, "2001:db8:2::/64")
+}
+
+// Test that the valid lifetime checker reports nothing when the valid
+// lifetime is specified at the global level.
+func TestValidLifetimeUnspecifiedGlobal(t *testing.T) {
+	// Arrange
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	_ = daemon.SetConfigFromJSON(`{
+        "Dhcp4": {
+            "valid-lifetime": 3600,
+            "subnet4": [
+                {
+                    "id": 1,
+                    "subnet": "192.0.2.0/24"
+                }
+            ],
+            "shared-networks": [
+                {
+                    "name": "foo",
+                    "subnet4": [
+                        {
+                            "id": 2,
+                            "subnet": "192.0.3.0/24"
+                        }
+                    ]
+                }
+            ]
+        }
+    }`)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := validLifetimeUnspecified(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, report)
+}
+
+// Test that the valid lifetime checker lists at most the maximum number
+// of the subnets.
+func TestValidLifetimeUnspecifiedMaxFindings(t *testing.T) {
+	// Arrange
+	var subnets []string
+	for i := 0; i < 12; i++ {
+		subnets = append(subnets, fmt.Sprintf(`{ "id": %d, "subnet": "10.0.%d.0/24" }`, i+1, i))
+	}
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	daemon.ID = 1
+	err := daemon.SetConfigFromJSON(fmt.Sprintf(`{
+        "Dhcp4": {
+            "subnet4": [ %s ]
+        }
+    }`, strings.Join(subnets, ",")))
+	require.NoError(t, err)
+	ctx := newReviewContext(nil, daemon, ManualRun, nil)
+
+	// Act
+	report, err := validLifetimeUnspecified(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Contains(t, report.content, "includes 12 subnets without the valid-lifetime")
+	require.Contains(t, report.content, "10. [10] 10.0.9.0/24; and 2 more")
+	require.NotContains(t, report.content, "10.0.10.0/24")
+}
+
+// Benchmark measuring performance of a Kea configuration checker that detects
+// subnets in which the out-of-pool host reservation mode is recommended.
+func BenchmarkReservationsOutOfPoolConfig(b *testing.B) {
+	// Create 10.000 subnets with a pool and out of pool reservation.
+	subnets := []interface{}{}
+	for i := 0; i < 10000; i++ {
+		prefix := fmt.Sprintf("192.%d.%d", i/256, i%256)
+		subnet := map[string]interface{}{
+			"subnet": fmt.Sprintf("%s.0/24", prefix),
+			"pools": []map[string]interface{}{
+				{
+					"pool": fmt.Sprintf("%s.10 - %s.100", prefix, prefix),
+				},
+			},
+			"reservations": []map[string]interface{}{
+				{
+					"ip-address": fmt.Sprintf("%s.5", prefix),
+				},
+			},
+		}
+		subnets = append(subnets, subnet)
+	}
+
+	// Create Kea DHCPv4 configuration with the subnets.
+	configMap := map[string]interface{}{
+		"Dhcp4": map[string]interface{}{
+			"subnet4": subnets,
+		},
+	}
+	configStr, err := json.Marshal(configMap)
+	if err != nil {
+		b.Fatalf("failed to marshal configuration map: %+v", err)
+	}
+	config, err := dbmodel.NewKeaConfigFromJSON(string(configStr))
+	if err != nil {
+		b.Fatalf("failed to create new Kea configuration from JSON: %+v", err)
+	}
+
+	// The benchmark starts here.
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx := newReviewContext(nil, &dbmodel.Daemon{
+			ID:   1,
+			Name: dbmodel.DaemonNameDHCPv4,
+			KeaDaemon: &dbmodel.KeaDaemon{
+				Config: config,
+			},
+		}, ManualRun, nil)
+		_, err = reservationsOutOfPool(ctx)
+		if err != nil {
+			b.Fatalf("checker failed: %+v", err)
+		}
+	}
+}
+
+// Benchmark measuring performance of a Kea configuration checker that detects
+// subnets in which the out-of-pool host reservation mode is recommended.
+// This benchmark stores host reservations in the database.
+func BenchmarkReservationsOutOfPoolDatabase(b *testing.B) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(b)
+	defer teardown()
+
+	// Create the machine.
+	machine := &dbmodel.Machine{
+		ID:        0,
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := dbmodel.AddMachine(db, machine)
+	if err != nil {
+		b.Fatalf("failed to add a machine: %+v", err)
+	}
+
+	// Create the app.
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
 			{
 				Name:   dbmodel.DaemonNameDHCPv4,
 				Active: true,
@@ -2395,53 +7544,3 @@ func BenchmarkReservationsOutOfPoolDatabase(b *testing.B) {
 		}
 	}
 }
-
-// Generates subnets of which some have overlapping prefixes.
-// The overlapping factor must be in range from 0 (no overlaps) to 1 (100% overlaps).
-// Each overlapped subnet is contained in exactly one other subnet.
-func getOverlappingSubnets(n int, overlappingFactor float32) (subnets []minimalSubnet) {
-	overlappingStep := int(float32(n) * overlappingFactor)
-
-	for i := 0; i < n; i++ {
-		id := int64(i + 1)
-		index := i
-		mask := 24
-
-		if overlappingFactor != 0. && i%overlappingStep == 1 {
-			index--
-			mask++
-		}
-
-		part4 := 0
-		part3 := index % 256
-		part2 := (index / 256) % 256
-		part1 := (index / (256 * 256)) % 256
-
-		prefix := fmt.Sprintf("%d.%d.%d.%d/%d", part1, part2, part3, part4, mask)
-
-		subnet := minimalSubnet{
-			ID:     id,
-			Subnet: prefix,
-		}
-		subnets = append(subnets, subnet)
-	}
-
-	return subnets
-}
-
-// Measures the performance of the overlapping prefixes detection based on the
-// binary prefixes without using the radix tree.
-// The possible solutions were discussed in this thread:
-// https://gitlab.isc.org/isc-projects/stork/-/merge_requests/474#note_305555
-func BenchmarkOverlapsBinaryPrefixesOnly(b *testing.B) {
-	numberOfSubnets := 8196
-	overlappingFactor := float32(0.01)
-	maximumOverlaps := 10
-
-	subnets := getOverlappingSubnets(numberOfSubnets, overlappingFactor)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = findOverlaps(subnets, maximumOverlaps)
-	}
-}
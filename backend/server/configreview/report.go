@@ -13,9 +13,11 @@ import (
 // The refDaemonIDs slice contain IDs of the daemons referenced in the
 // review. Each daemon can be referenced at most once. The presence of
 // the referenced daemons may trigger cascaded/internal reviews. See
-// the dispatcher documentation.
+// the dispatcher documentation. The severity indicates how serious the
+// issue is. It is used by the UI to highlight and filter the reports.
 type Report struct {
 	content      string
+	severity     dbmodel.ConfigReportSeverity
 	daemonID     int64
 	refDaemonIDs []int64
 }
@@ -34,8 +36,14 @@ type IntermediateReport Report
 // report, err := newReport(ctx, "some issue for {daemon} and {daemon}").
 //     referencingDaemon(daemon1).
 //     referencingDaemon(daemon2).
+//     withSeverity(dbmodel.ConfigReportSeverityError).
 //     create()
 //
+// The report has the default severity of the checker specified during
+// the checker registration unless the withSeverity() function is called.
+// The checker can use it to report an issue that is more or less serious
+// than the issues it typically reports.
+//
 // When the report is later fetched from the database it is possible to
 // use the referenced daemons to replace the {daemon} placeholders with
 // the detailed daemon information. See the similar mechanism implemented
@@ -55,6 +63,12 @@ func (r *IntermediateReport) referencingDaemon(daemon *dbmodel.Daemon) *Intermed
 	return r
 }
 
+// Sets the severity of the reported issue.
+func (r *IntermediateReport) withSeverity(severity dbmodel.ConfigReportSeverity) *IntermediateReport {
+	r.severity = severity
+	return r
+}
+
 // Validates the report contents and return an instance of the final
 // report or an error. It should never report an error if the checkers
 // generating the reports are implemented properly.
@@ -64,6 +78,11 @@ func (r *IntermediateReport) create() (*Report, error) {
 		return nil, pkgerrors.New("config review report must not be blank")
 	}
 
+	// Ensure that the severity is valid if it has been specified.
+	if r.severity != "" && !r.severity.IsValid() {
+		return nil, pkgerrors.Errorf("invalid config review report severity '%s'", r.severity)
+	}
+
 	// Ensure that the subject daemon has non-zero ID.
 	if r.daemonID == 0 {
 		return nil, pkgerrors.New("ID of the daemon for which a config report is created must not be 0")
@@ -84,6 +103,7 @@ func (r *IntermediateReport) create() (*Report, error) {
 	// Everything is fine.
 	rc := &Report{
 		content:      r.content,
+		severity:     r.severity,
 		daemonID:     r.daemonID,
 		refDaemonIDs: r.refDaemonIDs,
 	}
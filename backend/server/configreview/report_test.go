@@ -22,12 +22,39 @@ func TestCreateReport(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, report)
 	require.Equal(t, "new report for {daemon}", report.content)
+	require.Empty(t, report.severity)
 	require.EqualValues(t, 123, report.daemonID)
 	require.Len(t, report.refDaemonIDs, 2)
 	require.EqualValues(t, 567, report.refDaemonIDs[0])
 	require.EqualValues(t, 123, report.refDaemonIDs[1])
 }
 
+// Test creating a report with a severity.
+func TestCreateReportWithSeverity(t *testing.T) {
+	ctx := newReviewContext(nil, &dbmodel.Daemon{
+		ID: 123,
+	}, ConfigModified, nil)
+	report, err := NewReport(ctx, "new report").
+		withSeverity(dbmodel.ConfigReportSeverityError).
+		create()
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Equal(t, dbmodel.ConfigReportSeverityError, report.severity)
+}
+
+// Test that an attempt to create a report with an invalid severity
+// is not possible.
+func TestCreateReportInvalidSeverity(t *testing.T) {
+	ctx := newReviewContext(nil, &dbmodel.Daemon{
+		ID: 123,
+	}, ConfigModified, nil)
+	report, err := NewReport(ctx, "new report").
+		withSeverity("critical").
+		create()
+	require.Error(t, err)
+	require.Nil(t, report)
+}
+
 // Test that an attempt to create a report with a blank content is
 // not possible.
 func TestCreateBlankReport(t *testing.T) {
@@ -0,0 +1,98 @@
+package dbops
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/pkg/errors"
+)
+
+// Tables included in the database dump.
+var dumpedTables = []string{"machine", "app", "daemon", "subnet"}
+
+// Fragments of the column and JSON key names holding sensitive data.
+// Their values are redacted in the database dump.
+var redactedDumpKeys = []string{"password", "secret", "token"}
+
+// Value replacing the sensitive data in the database dump.
+const redactedDumpValue = "REDACTED"
+
+// Logical dump of the Stork database. It is meant to be attached to the
+// support tickets, so it doesn't contain the sensitive data.
+type DatabaseDump struct {
+	// Highest schema version supported by the dumping tool.
+	SchemaVersion int64 `json:"schema_version"`
+	// Schema version of the dumped database.
+	DatabaseVersion int64 `json:"database_version"`
+	// Rows of the dumped tables. Each row is a map of the column
+	// names and their values.
+	Tables map[string][]map[string]interface{} `json:"tables"`
+}
+
+// Dumps the schema version and the contents of the machine, app, daemon
+// and subnet tables to the writer as JSON. The values of the columns and
+// the nested JSON keys which names suggest they hold secrets are redacted.
+func Dump(db *PgDB, writer io.Writer) error {
+	dump := DatabaseDump{
+		SchemaVersion: AvailableVersion(),
+		Tables:        make(map[string][]map[string]interface{}),
+	}
+
+	var err error
+	dump.DatabaseVersion, err = CurrentVersion(db)
+	if err != nil {
+		return errors.WithMessage(err, "problem getting the database schema version")
+	}
+
+	for _, table := range dumpedTables {
+		var rows pg.Strings
+		_, err = db.Query(&rows, "SELECT row_to_json(t)::text FROM ? AS t ORDER BY t.id", pg.Ident(table))
+		if err != nil {
+			return errors.Wrapf(err, "problem selecting rows from the %s table", table)
+		}
+		dump.Tables[table] = []map[string]interface{}{}
+		for _, row := range rows {
+			var columns map[string]interface{}
+			if err = json.Unmarshal([]byte(row), &columns); err != nil {
+				return errors.Wrapf(err, "problem parsing a row from the %s table", table)
+			}
+			redactDumpValue(columns)
+			dump.Tables[table] = append(dump.Tables[table], columns)
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "    ")
+	return errors.Wrap(encoder.Encode(dump), "problem writing the database dump")
+}
+
+// Recursively replaces the values of the sensitive keys in the maps.
+func redactDumpValue(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if isRedactedDumpKey(key) && nested != nil {
+				value[key] = redactedDumpValue
+				continue
+			}
+			redactDumpValue(nested)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			redactDumpValue(nested)
+		}
+	}
+}
+
+// Checks if the column or JSON key holds sensitive data.
+func isRedactedDumpKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range redactedDumpKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -111,6 +111,103 @@ func CurrentVersion(db *PgDB) (int64, error) {
 	return migrations.Version(db)
 }
 
+// Describes the migrations that would be run by the up or down migration
+// without modifying the database schema.
+type MigrationPlan struct {
+	// Migration direction, i.e., up or down.
+	Direction string
+	// Current schema version.
+	CurrentVersion int64
+	// Schema version after running the migrations.
+	TargetVersion int64
+	// Ordered versions of the migrations to run. When migrating down,
+	// these are the versions of the migrations to revert.
+	Versions []int64
+}
+
+// Returns the migrations that would be run by the Migrate function called
+// with the same arguments. Only the up and down operations are supported.
+// The optional second argument specifies the target version. The database
+// schema is not modified, and the migrations table doesn't need to exist.
+func PlanMigration(db *PgDB, args ...string) (*MigrationPlan, error) {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down") {
+		return nil, errors.Errorf("unsupported migration operation %v", args)
+	}
+
+	plan := &MigrationPlan{
+		Direction: args[0],
+	}
+	if Initialized(db) {
+		version, err := CurrentVersion(db)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem checking database version")
+		}
+		plan.CurrentVersion = version
+	}
+
+	var registered []int64
+	for _, m := range migrations.RegisteredMigrations() {
+		registered = append(registered, m.Version)
+	}
+
+	if plan.Direction == "up" {
+		plan.TargetVersion = AvailableVersion()
+		if len(args) > 1 {
+			toVer, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't parse -t argument %s as database version (expected integer)", args[1])
+			}
+			plan.TargetVersion = toVer
+		}
+		for _, version := range registered {
+			if version > plan.CurrentVersion && version <= plan.TargetVersion {
+				plan.Versions = append(plan.Versions, version)
+			}
+		}
+		if plan.TargetVersion < plan.CurrentVersion {
+			plan.TargetVersion = plan.CurrentVersion
+		}
+		return plan, nil
+	}
+
+	// Without the target version, the down migration reverts the last
+	// applied migration.
+	plan.TargetVersion = -1
+	if len(args) > 1 {
+		toVer, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't parse -t argument %s as database version (expected integer)", args[1])
+		}
+		if toVer >= plan.CurrentVersion {
+			return nil, errors.Errorf("can't migrate down, current version %d, want to migrate to %d", plan.CurrentVersion, toVer)
+		}
+		plan.TargetVersion = toVer
+	}
+	for i := len(registered) - 1; i >= 0; i-- {
+		version := registered[i]
+		if version > plan.CurrentVersion {
+			continue
+		}
+		if plan.TargetVersion < 0 && len(plan.Versions) > 0 {
+			// The last applied migration has been found.
+			plan.TargetVersion = version
+			break
+		}
+		if version <= plan.TargetVersion {
+			break
+		}
+		plan.Versions = append(plan.Versions, version)
+	}
+	if plan.TargetVersion < 0 {
+		if len(plan.Versions) > 0 {
+			plan.TargetVersion = 0
+		} else {
+			plan.TargetVersion = plan.CurrentVersion
+		}
+	}
+	return plan, nil
+}
+
 // Prepares new database for the Stork server. This function must be called with
 // a pointer to the database connection using database admin credentials (typically
 // postgres user and postgres database). The dbName and userName denote the new
@@ -0,0 +1,31 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			CREATE TABLE audit_log (
+				id BIGSERIAL PRIMARY KEY,
+				created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+				user_id BIGINT,
+				user_login TEXT,
+				action TEXT NOT NULL,
+				target TEXT NOT NULL,
+				CONSTRAINT audit_log_user_id_fk FOREIGN KEY (user_id)
+					REFERENCES system_user (id)
+					ON UPDATE CASCADE
+					ON DELETE SET NULL
+			);
+
+			CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			DROP INDEX audit_log_created_at_idx;
+			DROP TABLE audit_log;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			CREATE TABLE config_report_summary (
+				id BIGSERIAL PRIMARY KEY,
+				created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+				daemon_id BIGINT NOT NULL,
+				checker_name TEXT NOT NULL,
+				report_count BIGINT NOT NULL DEFAULT 0,
+				CONSTRAINT config_report_summary_daemon_id_fk FOREIGN KEY (daemon_id)
+					REFERENCES daemon (id)
+					ON UPDATE CASCADE
+					ON DELETE CASCADE
+			);
+
+			CREATE INDEX config_report_summary_daemon_id_created_at_idx
+				ON config_report_summary (daemon_id, created_at);
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			DROP INDEX config_report_summary_daemon_id_created_at_idx;
+			DROP TABLE config_report_summary;
+		`)
+		return err
+	})
+}
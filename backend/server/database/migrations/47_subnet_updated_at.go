@@ -0,0 +1,76 @@
+package dbmigs
+
+import (
+	"github.com/go-pg/migrations/v8"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+             -- This adds a column holding the time of the last subnet change.
+             ALTER TABLE subnet
+                 ADD COLUMN updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+
+             -- It is common to select the subnets changed since a given time.
+             CREATE INDEX subnet_updated_at_idx ON subnet(updated_at);
+
+             -- Create trigger function which sets the time of the subnet update.
+             CREATE OR REPLACE FUNCTION subnet_set_updated_at()
+               RETURNS trigger
+               LANGUAGE plpgsql
+               AS $function$
+             BEGIN
+               NEW.updated_at := now() AT TIME ZONE 'utc';
+               RETURN NEW;
+             END;
+             $function$;
+
+             -- Set the update time when the subnet, including its statistics,
+             -- is updated.
+             CREATE TRIGGER subnet_updated_at
+             BEFORE UPDATE ON subnet
+               FOR EACH ROW EXECUTE PROCEDURE subnet_set_updated_at();
+
+             -- Create trigger function which sets the update time of the subnet
+             -- when its pools or its associations with the daemons change.
+             CREATE OR REPLACE FUNCTION subnet_touch_updated_at()
+               RETURNS trigger
+               LANGUAGE plpgsql
+               AS $function$
+             BEGIN
+               IF TG_OP = 'DELETE' THEN
+                   UPDATE subnet SET updated_at = now() AT TIME ZONE 'utc' WHERE id = OLD.subnet_id;
+                   RETURN OLD;
+               END IF;
+               UPDATE subnet SET updated_at = now() AT TIME ZONE 'utc' WHERE id = NEW.subnet_id;
+               RETURN NEW;
+             END;
+             $function$;
+
+             CREATE TRIGGER local_subnet_subnet_updated_at
+             AFTER INSERT OR UPDATE OR DELETE ON local_subnet
+               FOR EACH ROW EXECUTE PROCEDURE subnet_touch_updated_at();
+
+             CREATE TRIGGER address_pool_subnet_updated_at
+             AFTER INSERT OR UPDATE OR DELETE ON address_pool
+               FOR EACH ROW EXECUTE PROCEDURE subnet_touch_updated_at();
+
+             CREATE TRIGGER prefix_pool_subnet_updated_at
+             AFTER INSERT OR UPDATE OR DELETE ON prefix_pool
+               FOR EACH ROW EXECUTE PROCEDURE subnet_touch_updated_at();
+           `)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+             DROP TRIGGER IF EXISTS prefix_pool_subnet_updated_at ON prefix_pool;
+             DROP TRIGGER IF EXISTS address_pool_subnet_updated_at ON address_pool;
+             DROP TRIGGER IF EXISTS local_subnet_subnet_updated_at ON local_subnet;
+             DROP FUNCTION IF EXISTS subnet_touch_updated_at;
+             DROP TRIGGER IF EXISTS subnet_updated_at ON subnet;
+             DROP FUNCTION IF EXISTS subnet_set_updated_at;
+             DROP INDEX IF EXISTS subnet_updated_at_idx;
+             ALTER TABLE subnet DROP COLUMN IF EXISTS updated_at;
+        `)
+		return err
+	})
+}
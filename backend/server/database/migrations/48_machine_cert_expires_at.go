@@ -0,0 +1,23 @@
+package dbmigs
+
+import (
+	"github.com/go-pg/migrations/v8"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+             -- This adds a column holding the expiration time of the
+             -- certificate issued for the agent. It is NULL for the
+             -- machines registered before this column was added.
+             ALTER TABLE machine
+                 ADD COLUMN cert_expires_at TIMESTAMP WITHOUT TIME ZONE;
+           `)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+             ALTER TABLE machine DROP COLUMN IF EXISTS cert_expires_at;
+        `)
+		return err
+	})
+}
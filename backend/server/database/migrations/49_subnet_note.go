@@ -0,0 +1,21 @@
+package dbmigs
+
+import (
+	"github.com/go-pg/migrations/v8"
+)
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+             -- This adds a column holding the free-text note specified
+             -- by the operator for the subnet.
+             ALTER TABLE subnet ADD COLUMN note TEXT;
+           `)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+             ALTER TABLE subnet DROP COLUMN IF EXISTS note;
+        `)
+		return err
+	})
+}
@@ -0,0 +1,31 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			-- This creates a table holding the history of the subnet
+			-- statistics. The history is not bound to the subnet with
+			-- a foreign key, so deleting many subnets on the configuration
+			-- changes doesn't cascade to the large history. The history
+			-- of the deleted subnets is purged separately.
+			CREATE TABLE subnet_stats_history (
+				id BIGSERIAL PRIMARY KEY,
+				subnet_id BIGINT NOT NULL,
+				collected_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+				stats JSONB
+			);
+
+			CREATE INDEX subnet_stats_history_subnet_id_collected_at_idx
+				ON subnet_stats_history (subnet_id, collected_at);
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			DROP INDEX IF EXISTS subnet_stats_history_subnet_id_collected_at_idx;
+			DROP TABLE IF EXISTS subnet_stats_history;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,28 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			-- This creates a table holding the per-daemon overrides of the
+			-- periodic configuration review interval. The daemons without
+			-- an override are reviewed according to the global interval.
+			CREATE TABLE config_review_interval (
+				daemon_id BIGINT PRIMARY KEY,
+				review_interval BIGINT NOT NULL,
+				CONSTRAINT config_review_interval_daemon_id_fk FOREIGN KEY (daemon_id)
+					REFERENCES daemon (id)
+					ON UPDATE CASCADE
+					ON DELETE CASCADE,
+				CONSTRAINT config_review_interval_non_negative CHECK (review_interval >= 0)
+			);
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			DROP TABLE IF EXISTS config_review_interval;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,21 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			-- The subnet prefix is always stored in the canonical form because
+			-- the cidr column rejects the non-canonical prefixes. This column
+			-- holds the prefix as it was specified in the daemon's configuration
+			-- when it differs from the canonical form.
+			ALTER TABLE local_subnet ADD COLUMN original_prefix TEXT;
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			ALTER TABLE local_subnet DROP COLUMN IF EXISTS original_prefix;
+		`)
+		return err
+	})
+}
@@ -0,0 +1,29 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			-- Severity of the issue described in the config report.
+			DO $$ BEGIN
+				CREATE TYPE CONFIGREPORTSEVERITY AS ENUM
+					('info', 'warning', 'error');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+
+			-- The existing reports are treated as warnings until the
+			-- next config review.
+			ALTER TABLE config_report
+				ADD COLUMN severity CONFIGREPORTSEVERITY NOT NULL DEFAULT 'warning';
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			ALTER TABLE config_report DROP COLUMN IF EXISTS severity;
+			DROP TYPE IF EXISTS CONFIGREPORTSEVERITY;
+		`)
+		return err
+	})
+}
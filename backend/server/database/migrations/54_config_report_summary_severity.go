@@ -0,0 +1,29 @@
+package dbmigs
+
+import "github.com/go-pg/migrations/v8"
+
+func init() {
+	migrations.MustRegisterTx(func(db migrations.DB) error {
+		_, err := db.Exec(`
+			-- Number of the reports generated by the checker per severity.
+			-- The existing summaries count all reports as warnings, which
+			-- is consistent with the severity assigned to the existing
+			-- reports.
+			ALTER TABLE config_report_summary
+				ADD COLUMN info_count BIGINT NOT NULL DEFAULT 0,
+				ADD COLUMN warning_count BIGINT NOT NULL DEFAULT 0,
+				ADD COLUMN error_count BIGINT NOT NULL DEFAULT 0;
+
+			UPDATE config_report_summary SET warning_count = report_count;
+		`)
+		return err
+	}, func(db migrations.DB) error {
+		_, err := db.Exec(`
+			ALTER TABLE config_report_summary
+				DROP COLUMN IF EXISTS info_count,
+				DROP COLUMN IF EXISTS warning_count,
+				DROP COLUMN IF EXISTS error_count;
+		`)
+		return err
+	})
+}
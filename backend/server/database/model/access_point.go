@@ -1,5 +1,13 @@
 package dbmodel
 
+import (
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+)
+
 // A structure reflecting the access_point SQL table.
 type AccessPoint struct {
 	AppID             int64  `pg:",pk"`
@@ -28,3 +36,32 @@ func AppendAccessPoint(list []*AccessPoint, tp, address, key string, port int64,
 	})
 	return list
 }
+
+// Returns the access points of the given machine which share the same
+// address, port and type with another access point of this machine. Such
+// duplicates may be registered when the agent detects the same Kea control
+// endpoint twice. The access points are ordered by address, port, type and
+// app ID, so the duplicates are adjacent and the first one in each group
+// belongs to the oldest app. The cleanup task can keep the first one and
+// remove the others.
+func GetDuplicateAccessPoints(dbi dbops.DBI, machineID int64) ([]*AccessPoint, error) {
+	var accessPoints []*AccessPoint
+	err := dbi.Model(&accessPoints).
+		Where("access_point.machine_id = ?", machineID).
+		Where(`(access_point.address, access_point.port, access_point.type) IN (
+			SELECT ap.address, ap.port, ap.type
+			FROM access_point AS ap
+			WHERE ap.machine_id = ?
+			GROUP BY ap.address, ap.port, ap.type
+			HAVING COUNT(*) > 1
+		)`, machineID).
+		OrderExpr("access_point.address ASC").
+		OrderExpr("access_point.port ASC").
+		OrderExpr("access_point.type ASC").
+		OrderExpr("access_point.app_id ASC").
+		Select()
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		return nil, pkgerrors.Wrapf(err, "problem selecting duplicate access points for machine %d", machineID)
+	}
+	return accessPoints, nil
+}
@@ -0,0 +1,91 @@
+package dbmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the access points sharing the same address, port and type
+// within a machine are detected.
+func TestGetDuplicateAccessPoints(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// The unique constraint on the machine ID and port prevents inserting
+	// the duplicates. Drop it to simulate the duplicates registered by the
+	// agent detection.
+	_, err := db.Exec("ALTER TABLE access_point DROP CONSTRAINT access_point_unique_idx")
+	require.NoError(t, err)
+
+	machines := []*Machine{
+		{Address: "localhost", AgentPort: 8080},
+		{Address: "remote", AgentPort: 8080},
+	}
+	for _, machine := range machines {
+		require.NoError(t, AddMachine(db, machine))
+	}
+
+	addApp := func(machine *Machine, accessPoints []*AccessPoint) *App {
+		app := &App{
+			MachineID:    machine.ID,
+			Type:         AppTypeKea,
+			AccessPoints: accessPoints,
+		}
+		_, err := AddApp(db, app)
+		require.NoError(t, err)
+		return app
+	}
+
+	app1 := addApp(machines[0], AppendAccessPoint(nil, AccessPointControl, "localhost", "", 8000, false))
+	app2 := addApp(machines[0], AppendAccessPoint(nil, AccessPointControl, "localhost", "", 8000, false))
+	// Different address.
+	addApp(machines[0], AppendAccessPoint(nil, AccessPointControl, "192.0.2.1", "", 8000, false))
+	// Different port.
+	addApp(machines[0], AppendAccessPoint(nil, AccessPointControl, "localhost", "", 8001, false))
+	// Different type.
+	addApp(machines[0], AppendAccessPoint(nil, AccessPointStatistics, "localhost", "", 8000, false))
+	// Different machine.
+	addApp(machines[1], AppendAccessPoint(nil, AccessPointControl, "localhost", "", 8000, false))
+
+	// Act
+	duplicates, err := GetDuplicateAccessPoints(db, machines[0].ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, duplicates, 2)
+	require.Equal(t, app1.ID, duplicates[0].AppID)
+	require.Equal(t, app2.ID, duplicates[1].AppID)
+	for _, duplicate := range duplicates {
+		require.Equal(t, machines[0].ID, duplicate.MachineID)
+		require.Equal(t, AccessPointControl, duplicate.Type)
+		require.Equal(t, "localhost", duplicate.Address)
+		require.EqualValues(t, 8000, duplicate.Port)
+	}
+
+	// Act
+	duplicates, err = GetDuplicateAccessPoints(db, machines[1].ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, duplicates)
+}
+
+// Test that no duplicates are returned for a machine without access points.
+func TestGetDuplicateAccessPointsNoAccessPoints(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	machine := &Machine{Address: "localhost", AgentPort: 8080}
+	require.NoError(t, AddMachine(db, machine))
+
+	// Act
+	duplicates, err := GetDuplicateAccessPoints(db, machine.ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, duplicates)
+}
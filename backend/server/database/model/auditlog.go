@@ -0,0 +1,61 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+)
+
+// Represents an entry of the audit log. It records a change made by
+// a user, e.g., disabling a config checker or pausing the pullers.
+// The user login is stored next to the user ID, so the entry remains
+// meaningful after the user is deleted.
+type AuditLog struct {
+	ID        int64
+	CreatedAt time.Time
+	UserID    int64
+	UserLogin string
+	// Performed action, e.g., "config_checker_disabled".
+	Action string
+	// Subject of the action, e.g., a checker name with a daemon ID.
+	Target string
+}
+
+// Inserts the audit log entry into the database. If the creation time
+// is not specified, the database sets the current time.
+func AddAuditLog(dbi dbops.DBI, entry *AuditLog) error {
+	_, err := dbi.Model(entry).Insert()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem inserting audit log entry %+v", entry)
+	}
+	return err
+}
+
+// Fetches a collection of the audit log entries from the database. The
+// offset and limit specify the beginning of the page and the maximum
+// size of the page. Limit has to be greater than 0, otherwise an error
+// is returned. The sortField and sortDir specify the sorting order. If
+// sortField is empty then id is used for sorting.
+func GetAuditLogByPage(dbi dbops.DBI, offset, limit int64, sortField string, sortDir SortDirEnum) ([]AuditLog, int64, error) {
+	if limit == 0 {
+		return nil, 0, pkgerrors.New("limit should be greater than 0")
+	}
+	entries := []AuditLog{}
+
+	q := dbi.Model(&entries)
+	q = q.OrderExpr(prepareOrderExpr("audit_log", sortField, sortDir))
+	q = q.Offset(int(offset))
+	q = q.Limit(int(limit))
+
+	total, err := q.SelectAndCount()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return []AuditLog{}, 0, nil
+		}
+		return nil, 0, pkgerrors.Wrapf(err, "problem getting audit log entries")
+	}
+	return entries, int64(total), nil
+}
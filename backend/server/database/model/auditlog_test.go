@@ -0,0 +1,68 @@
+package dbmodel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the audit log entries are added and fetched by page.
+func TestAddAndGetAuditLog(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// The entry related to the default admin user.
+	err := AddAuditLog(db, &AuditLog{
+		UserID:    1,
+		UserLogin: "admin",
+		Action:    "config_checker_disabled",
+		Target:    "foo",
+	})
+	require.NoError(t, err)
+
+	// The entry without the user and with an explicit creation time.
+	createdAt := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	err = AddAuditLog(db, &AuditLog{
+		CreatedAt: createdAt,
+		Action:    "pullers_paused",
+		Target:    "all pullers",
+	})
+	require.NoError(t, err)
+
+	entries, total, err := GetAuditLogByPage(db, 0, 10, "", SortDirAny)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Len(t, entries, 2)
+
+	require.NotZero(t, entries[0].ID)
+	require.NotZero(t, entries[0].CreatedAt)
+	require.EqualValues(t, 1, entries[0].UserID)
+	require.Equal(t, "admin", entries[0].UserLogin)
+	require.Equal(t, "config_checker_disabled", entries[0].Action)
+	require.Equal(t, "foo", entries[0].Target)
+
+	require.Zero(t, entries[1].UserID)
+	require.Empty(t, entries[1].UserLogin)
+	require.Equal(t, createdAt, entries[1].CreatedAt)
+	require.Equal(t, "pullers_paused", entries[1].Action)
+
+	// Sort by the creation time and get the second page.
+	entries, total, err = GetAuditLogByPage(db, 1, 1, "created_at", SortDirDesc)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Len(t, entries, 1)
+	require.Equal(t, "pullers_paused", entries[0].Action)
+}
+
+// Test that an error is returned when the page limit is zero.
+func TestGetAuditLogByPageZeroLimit(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	entries, total, err := GetAuditLogByPage(db, 0, 0, "", SortDirAny)
+	require.Error(t, err)
+	require.Nil(t, entries)
+	require.Zero(t, total)
+}
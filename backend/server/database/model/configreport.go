@@ -18,6 +18,31 @@ func init() {
 	orm.RegisterTable((*DaemonToConfigReport)(nil))
 }
 
+// Severity of the issue described in the config report.
+type ConfigReportSeverity string
+
+// Valid config report severities.
+const (
+	// The report contains a suggestion or recommendation that doesn't
+	// indicate a problem with the configuration.
+	ConfigReportSeverityInfo ConfigReportSeverity = "info"
+	// The report describes a potential problem with the configuration.
+	ConfigReportSeverityWarning ConfigReportSeverity = "warning"
+	// The report describes a configuration error that is likely to
+	// cause the server misbehavior.
+	ConfigReportSeverityError ConfigReportSeverity = "error"
+)
+
+// Checks if the severity has one of the valid values.
+func (s ConfigReportSeverity) IsValid() bool {
+	switch s {
+	case ConfigReportSeverityInfo, ConfigReportSeverityWarning, ConfigReportSeverityError:
+		return true
+	default:
+		return false
+	}
+}
+
 // Structure representing a single config report generated during
 // the daemons configuration review.
 type ConfigReport struct {
@@ -25,6 +50,9 @@ type ConfigReport struct {
 	CreatedAt   time.Time
 	CheckerName string
 	Content     string
+	// Severity of the reported issue. It defaults to warning when not
+	// specified.
+	Severity ConfigReportSeverity
 
 	DaemonID int64
 
@@ -104,10 +132,24 @@ func AddConfigReport(dbi dbops.DBI, configReport *ConfigReport) error {
 // reports this function also returns the total number of reports for
 // the daemon (useful when paging the results) and an error.
 func GetConfigReportsByDaemonID(db *pg.DB, offset, limit int64, daemonID int64) ([]ConfigReport, int64, error) {
+	return GetConfigReportsByDaemonIDAndSeverity(db, offset, limit, daemonID, "")
+}
+
+// Select all or a range of the config reports with the specified severity
+// for the specified daemon. An empty severity causes the function to return
+// the reports with any severity. The offset and limit are interpreted as
+// in the GetConfigReportsByDaemonID function. The returned total number of
+// reports only includes the reports with the specified severity.
+func GetConfigReportsByDaemonIDAndSeverity(db *pg.DB, offset, limit int64, daemonID int64, severity ConfigReportSeverity) ([]ConfigReport, int64, error) {
 	var configReports []ConfigReport
 	q := db.Model(&configReports).
-		Where("config_report.daemon_id = ?", daemonID).
-		Order("config_report.id ASC").
+		Where("config_report.daemon_id = ?", daemonID)
+
+	if severity != "" {
+		q = q.Where("config_report.severity = ?", severity)
+	}
+
+	q = q.Order("config_report.id ASC").
 		Relation("RefDaemons", func(q *orm.Query) (*orm.Query, error) {
 			return q.Order("daemon_to_config_report.order_index ASC"), nil
 		}).
@@ -51,6 +51,7 @@ func TestConfigReportSharingDaemons(t *testing.T) {
 	require.EqualValues(t, 1, total)
 	require.Len(t, configReports, 1)
 	require.NotZero(t, configReports[0].DaemonID)
+	require.Equal(t, ConfigReportSeverityWarning, configReports[0].Severity)
 	require.Len(t, configReports[0].RefDaemons, 2)
 	require.Equal(t, "dhcp4", configReports[0].RefDaemons[0].Name)
 	require.NotNil(t, configReports[0].RefDaemons[0].App)
@@ -69,6 +70,67 @@ func TestConfigReportSharingDaemons(t *testing.T) {
 	require.Empty(t, configReports)
 }
 
+// Test that the configuration reports can be filtered by severity.
+func TestGetConfigReportsByDaemonIDAndSeverity(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	machine := &Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	require.NoError(t, AddMachine(db, machine))
+
+	app := &App{
+		Type:      AppTypeKea,
+		MachineID: machine.ID,
+		Daemons: []*Daemon{
+			NewKeaDaemon("dhcp4", true),
+		},
+	}
+	daemons, err := AddApp(db, app)
+	require.NoError(t, err)
+
+	severities := []ConfigReportSeverity{
+		ConfigReportSeverityError,
+		ConfigReportSeverityInfo,
+		ConfigReportSeverityError,
+		"",
+	}
+	for i, severity := range severities {
+		require.NoError(t, AddConfigReport(db, &ConfigReport{
+			CheckerName: fmt.Sprintf("checker %d", i),
+			Content:     "Report for {daemon}",
+			Severity:    severity,
+			DaemonID:    daemons[0].ID,
+			RefDaemons:  daemons,
+		}))
+	}
+
+	// Act
+	errorReports, errorTotal, errorErr := GetConfigReportsByDaemonIDAndSeverity(db, 0, 1, daemons[0].ID, ConfigReportSeverityError)
+	warningReports, warningTotal, warningErr := GetConfigReportsByDaemonIDAndSeverity(db, 0, 0, daemons[0].ID, ConfigReportSeverityWarning)
+	allReports, allTotal, allErr := GetConfigReportsByDaemonIDAndSeverity(db, 0, 0, daemons[0].ID, "")
+
+	// Assert
+	require.NoError(t, errorErr)
+	require.EqualValues(t, 2, errorTotal)
+	require.Len(t, errorReports, 1)
+	require.Equal(t, "checker 0", errorReports[0].CheckerName)
+	require.Equal(t, ConfigReportSeverityError, errorReports[0].Severity)
+
+	// The report without severity defaults to a warning.
+	require.NoError(t, warningErr)
+	require.EqualValues(t, 1, warningTotal)
+	require.Len(t, warningReports, 1)
+	require.Equal(t, "checker 3", warningReports[0].CheckerName)
+
+	require.NoError(t, allErr)
+	require.EqualValues(t, 4, allTotal)
+	require.Len(t, allReports, 4)
+}
+
 // Test inserting, selecting and deleting configuration reports associated
 // with distinct daemons.
 func TestConfigReportDistinctDaemons(t *testing.T) {
@@ -0,0 +1,80 @@
+package dbmodel
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+)
+
+// Holds the number of configuration reports generated by a checker for
+// a daemon in a single configuration review. The reports are also counted
+// per severity. A set of summaries is recorded upon each review, so they
+// can be used to present the trends of the configuration health over time.
+type ConfigReportSummary struct {
+	ID           int64
+	CreatedAt    time.Time
+	DaemonID     int64
+	CheckerName  string
+	ReportCount  int64 `pg:",use_zero"`
+	InfoCount    int64 `pg:",use_zero"`
+	WarningCount int64 `pg:",use_zero"`
+	ErrorCount   int64 `pg:",use_zero"`
+}
+
+// Increases the total number of reports and the number of reports with
+// the specified severity.
+func (s *ConfigReportSummary) AddReport(severity ConfigReportSeverity) {
+	s.ReportCount++
+	switch severity {
+	case ConfigReportSeverityInfo:
+		s.InfoCount++
+	case ConfigReportSeverityError:
+		s.ErrorCount++
+	default:
+		s.WarningCount++
+	}
+}
+
+// Inserts the configuration report summaries into the database. If the
+// creation time is not specified, the database sets the current time.
+func AddConfigReportSummaries(dbi dbops.DBI, summaries []*ConfigReportSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	_, err := dbi.Model(&summaries).Insert()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem inserting configuration report summaries for daemon %d",
+			summaries[0].DaemonID)
+	}
+	return err
+}
+
+// Fetches the configuration report summaries for the daemon created
+// within the specified time range (inclusive). The zero from or to time
+// leaves the range unbounded on the respective side. The summaries are
+// ordered by creation time.
+func GetReportSummaryHistory(dbi dbops.DBI, daemonID int64, from, to time.Time) ([]ConfigReportSummary, error) {
+	summaries := []ConfigReportSummary{}
+	q := dbi.Model(&summaries).
+		Where("config_report_summary.daemon_id = ?", daemonID)
+	if !from.IsZero() {
+		q = q.Where("config_report_summary.created_at >= ?", from.UTC())
+	}
+	if !to.IsZero() {
+		q = q.Where("config_report_summary.created_at <= ?", to.UTC())
+	}
+	err := q.OrderExpr("config_report_summary.created_at ASC").
+		OrderExpr("config_report_summary.id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return []ConfigReportSummary{}, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem selecting configuration report summaries for daemon %d", daemonID)
+		return nil, err
+	}
+	return summaries, nil
+}
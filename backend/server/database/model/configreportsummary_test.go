@@ -0,0 +1,133 @@
+package dbmodel
+
+import (
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the configuration report summaries accumulate in the database
+// and can be fetched for the specified time range.
+func TestConfigReportSummaryHistory(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// Add a machine.
+	machine := &Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := AddMachine(db, machine)
+	require.NoError(t, err)
+
+	// Add an app with two daemons.
+	app := &App{
+		Type:      AppTypeKea,
+		MachineID: machine.ID,
+		Daemons: []*Daemon{
+			NewKeaDaemon("dhcp4", true),
+			NewKeaDaemon("dhcp6", true),
+		},
+	}
+	daemons, err := AddApp(db, app)
+	require.NoError(t, err)
+	require.Len(t, daemons, 2)
+
+	// Add the summaries of the three reviews for the first daemon and
+	// one review for the second daemon.
+	for i := 0; i < 3; i++ {
+		createdAt := time.Date(2022, 1, 10+i, 10, 0, 0, 0, time.UTC)
+		err = AddConfigReportSummaries(db, []*ConfigReportSummary{
+			{
+				CreatedAt:   createdAt,
+				DaemonID:    daemons[0].ID,
+				CheckerName: "stat_cmds_presence",
+				ReportCount: 1,
+			},
+			{
+				CreatedAt:    createdAt,
+				DaemonID:     daemons[0].ID,
+				CheckerName:  "overlapping_subnet",
+				ReportCount:  int64(3 - i),
+				WarningCount: int64(2 - i),
+				ErrorCount:   1,
+			},
+		})
+		require.NoError(t, err)
+	}
+	err = AddConfigReportSummaries(db, []*ConfigReportSummary{
+		{
+			DaemonID:    daemons[1].ID,
+			CheckerName: "stat_cmds_presence",
+		},
+	})
+	require.NoError(t, err)
+
+	// Adding no summaries is no-op.
+	err = AddConfigReportSummaries(db, []*ConfigReportSummary{})
+	require.NoError(t, err)
+
+	// Get all summaries for the first daemon.
+	summaries, err := GetReportSummaryHistory(db, daemons[0].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 6)
+	for i, summary := range summaries {
+		require.EqualValues(t, daemons[0].ID, summary.DaemonID)
+		require.EqualValues(t, time.Date(2022, 1, 10+i/2, 10, 0, 0, 0, time.UTC), summary.CreatedAt)
+	}
+	require.Equal(t, "stat_cmds_presence", summaries[0].CheckerName)
+	require.EqualValues(t, 1, summaries[0].ReportCount)
+	require.Equal(t, "overlapping_subnet", summaries[1].CheckerName)
+	require.EqualValues(t, 3, summaries[1].ReportCount)
+	require.Zero(t, summaries[1].InfoCount)
+	require.EqualValues(t, 2, summaries[1].WarningCount)
+	require.EqualValues(t, 1, summaries[1].ErrorCount)
+	require.Equal(t, "overlapping_subnet", summaries[5].CheckerName)
+	require.EqualValues(t, 1, summaries[5].ReportCount)
+	require.Zero(t, summaries[5].WarningCount)
+	require.EqualValues(t, 1, summaries[5].ErrorCount)
+
+	// Get the summaries within the time range.
+	summaries, err = GetReportSummaryHistory(db, daemons[0].ID,
+		time.Date(2022, 1, 11, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 11, 23, 59, 59, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	require.EqualValues(t, 2, summaries[1].ReportCount)
+
+	// Get the summaries for the second daemon. The creation time should
+	// be set by the database.
+	summaries, err = GetReportSummaryHistory(db, daemons[1].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Zero(t, summaries[0].ReportCount)
+	require.WithinDuration(t, time.Now(), summaries[0].CreatedAt, 10*time.Second)
+
+	// Deleting the daemon should delete its summaries.
+	err = DeleteApp(db, app)
+	require.NoError(t, err)
+	summaries, err = GetReportSummaryHistory(db, daemons[0].ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+}
+
+// Test that the reports are counted per severity in the summary.
+func TestConfigReportSummaryAddReport(t *testing.T) {
+	// Arrange
+	summary := &ConfigReportSummary{}
+
+	// Act
+	summary.AddReport(ConfigReportSeverityInfo)
+	summary.AddReport(ConfigReportSeverityWarning)
+	summary.AddReport(ConfigReportSeverityWarning)
+	summary.AddReport(ConfigReportSeverityError)
+	summary.AddReport("")
+
+	// Assert
+	require.EqualValues(t, 5, summary.ReportCount)
+	require.EqualValues(t, 1, summary.InfoCount)
+	require.EqualValues(t, 3, summary.WarningCount)
+	require.EqualValues(t, 1, summary.ErrorCount)
+}
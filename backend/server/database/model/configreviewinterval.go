@@ -0,0 +1,83 @@
+package dbmodel
+
+import (
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+)
+
+// Structure representing an override of the periodic configuration review
+// interval for a single daemon. Like the config checker preferences, it
+// allows for tuning the configuration review per daemon. The daemons without
+// an override are reviewed according to the global interval.
+type ConfigReviewInterval struct {
+	DaemonID int64 `pg:",pk"`
+	// Interval between the periodic reviews in seconds. Zero disables
+	// the periodic reviews of the daemon.
+	ReviewInterval int64 `pg:",use_zero"`
+}
+
+// Adds or updates the configuration review interval override for a daemon.
+func SetConfigReviewInterval(dbi dbops.DBI, daemonID, interval int64) error {
+	if interval < 0 {
+		return pkgerrors.Errorf("config review interval for daemon %d must not be negative", daemonID)
+	}
+	override := &ConfigReviewInterval{
+		DaemonID:       daemonID,
+		ReviewInterval: interval,
+	}
+	_, err := dbi.Model(override).
+		OnConflict("(daemon_id) DO UPDATE").
+		Set("review_interval = EXCLUDED.review_interval").
+		Insert()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem upserting the config review interval for daemon %d", daemonID)
+	}
+	return err
+}
+
+// Returns the configuration review interval override for a daemon. It
+// returns nil if the daemon has no override.
+func GetConfigReviewInterval(dbi dbops.DBI, daemonID int64) (*ConfigReviewInterval, error) {
+	override := &ConfigReviewInterval{}
+	err := dbi.Model(override).
+		Where("daemon_id = ?", daemonID).
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, pkgerrors.Wrapf(err, "problem selecting the config review interval for daemon %d", daemonID)
+	}
+	return override, nil
+}
+
+// Returns the configuration review interval overrides indexed by the
+// daemon IDs.
+func GetConfigReviewIntervals(dbi dbops.DBI) (map[int64]int64, error) {
+	var overrides []ConfigReviewInterval
+	err := dbi.Model(&overrides).Select()
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		return nil, pkgerrors.Wrap(err, "problem selecting the config review intervals")
+	}
+	intervals := make(map[int64]int64, len(overrides))
+	for _, override := range overrides {
+		intervals[override.DaemonID] = override.ReviewInterval
+	}
+	return intervals, nil
+}
+
+// Deletes the configuration review interval override for a daemon, so
+// the daemon is reviewed according to the global interval. It is not an
+// error if the daemon has no override.
+func DeleteConfigReviewInterval(dbi dbops.DBI, daemonID int64) error {
+	_, err := dbi.Model((*ConfigReviewInterval)(nil)).
+		Where("daemon_id = ?", daemonID).
+		Delete()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem deleting the config review interval for daemon %d", daemonID)
+	}
+	return err
+}
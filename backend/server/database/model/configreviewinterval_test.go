@@ -0,0 +1,84 @@
+package dbmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the config review interval override is inserted, updated,
+// fetched and deleted properly.
+func TestSetConfigReviewInterval(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon1, daemon2, _ := addTestDaemons(db)
+
+	// Act
+	err1 := SetConfigReviewInterval(db, daemon1.ID, 3600)
+	err2 := SetConfigReviewInterval(db, daemon1.ID, 7200)
+	err3 := SetConfigReviewInterval(db, daemon2.ID, 0)
+
+	// Assert
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	require.NoError(t, err3)
+
+	override, err := GetConfigReviewInterval(db, daemon1.ID)
+	require.NoError(t, err)
+	require.NotNil(t, override)
+	require.EqualValues(t, 7200, override.ReviewInterval)
+
+	intervals, err := GetConfigReviewIntervals(db)
+	require.NoError(t, err)
+	require.Len(t, intervals, 2)
+	require.EqualValues(t, 7200, intervals[daemon1.ID])
+	require.Contains(t, intervals, daemon2.ID)
+	require.Zero(t, intervals[daemon2.ID])
+
+	// Act
+	err = DeleteConfigReviewInterval(db, daemon1.ID)
+
+	// Assert
+	require.NoError(t, err)
+	override, err = GetConfigReviewInterval(db, daemon1.ID)
+	require.NoError(t, err)
+	require.Nil(t, override)
+	require.NoError(t, DeleteConfigReviewInterval(db, daemon1.ID))
+}
+
+// Test that the negative config review interval is rejected.
+func TestSetNegativeConfigReviewInterval(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon, _, _ := addTestDaemons(db)
+
+	// Act
+	err := SetConfigReviewInterval(db, daemon.ID, -1)
+
+	// Assert
+	require.Error(t, err)
+	override, _ := GetConfigReviewInterval(db, daemon.ID)
+	require.Nil(t, override)
+}
+
+// Test that removing the daemon removes its config review interval override.
+func TestDeleteDaemonAndRelatedConfigReviewInterval(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	daemon1, daemon2, _ := addTestDaemons(db)
+	_ = SetConfigReviewInterval(db, daemon1.ID, 3600)
+	_ = SetConfigReviewInterval(db, daemon2.ID, 600)
+
+	// Act
+	err := DeleteApp(db, daemon1.App)
+
+	// Assert
+	require.NoError(t, err)
+	intervals, _ := GetConfigReviewIntervals(db)
+	require.Len(t, intervals, 1)
+	require.EqualValues(t, 600, intervals[daemon2.ID])
+}
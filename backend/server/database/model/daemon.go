@@ -271,11 +271,12 @@ func GetDaemonByID(dbi pg.DBI, id int64) (*Daemon, error) {
 	return &app, nil
 }
 
-// Get all Kea DHCP daemons.
+// Get all Kea DHCP daemons with their last configuration reviews.
 func GetKeaDHCPDaemons(dbi pg.DBI) (daemons []Daemon, err error) {
 	err = dbi.Model(&daemons).
 		Relation("App").
 		Relation("KeaDaemon.KeaDHCPDaemon").
+		Relation("ConfigReview").
 		Where("daemon.name ILIKE 'dhcp%'").
 		OrderExpr("daemon.id ASC").
 		Select()
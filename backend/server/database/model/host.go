@@ -540,6 +540,28 @@ func DeleteDaemonFromHosts(dbi dbops.DBI, daemonID int64, dataSource HostDataSou
 	return int64(result.RowsAffected()), nil
 }
 
+// Deletes associations of the daemon with the hosts from the configuration
+// file belonging to the specified subnet. The subnet ID of 0 selects the
+// global hosts. Returns the number of deleted associations and an error.
+func DeleteDaemonFromSubnetHosts(dbi dbops.DBI, daemonID, subnetID int64) (int64, error) {
+	subquery := dbi.Model((*Host)(nil)).Column("host.id")
+	if subnetID == 0 {
+		subquery = subquery.Where("host.subnet_id IS NULL")
+	} else {
+		subquery = subquery.Where("host.subnet_id = ?", subnetID)
+	}
+	result, err := dbi.Model((*LocalHost)(nil)).
+		Where("daemon_id = ?", daemonID).
+		Where("data_source = ?", HostDataSourceConfig).
+		Where("host_id IN (?)", subquery).
+		Delete()
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		err = pkgerrors.Wrapf(err, "problem deleting the daemon %d from hosts in subnet %d", daemonID, subnetID)
+		return 0, err
+	}
+	return int64(result.RowsAffected()), nil
+}
+
 // Deletes hosts which are not associated with any apps. Returns deleted host
 // count and an error.
 func DeleteOrphanedHosts(dbi dbops.DBI) (int64, error) {
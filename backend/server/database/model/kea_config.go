@@ -152,6 +152,11 @@ func convertSubnetFromKea(keaSubnet *KeaConfigSubnet, daemon *Daemon, source Hos
 		Prefix:      prefix,
 		ClientClass: keaSubnet.ClientClass,
 	}
+	// Remember the configured prefix if it differs from the canonical one,
+	// so it can be presented to the operator as it was configured.
+	if prefix != keaSubnet.Subnet {
+		convertedSubnet.OriginalPrefix = keaSubnet.Subnet
+	}
 
 	for _, p := range keaSubnet.Pools {
 		addressPool, err := NewAddressPoolFromRange(p.Pool)
@@ -253,6 +253,7 @@ func TestNewSubnetFromKeaWithNonCanonicalIPv4Prefix(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 	require.EqualValues(t, "10.0.0.0/8", parsedSubnet.Prefix)
+	require.EqualValues(t, "10.42.42.42/8", parsedSubnet.OriginalPrefix)
 }
 
 // Test that the IPv6 subnet prefix is converted from non-canonical to canonical form.
@@ -271,6 +272,7 @@ func TestNewSubnetFromKeaWithNonCanonicalIPv6Prefix(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 	require.EqualValues(t, "2001:db8:1::/64", parsedSubnet.Prefix)
+	require.EqualValues(t, "2001:db8:1::42/64", parsedSubnet.OriginalPrefix)
 }
 
 // Verifies that the host instance can be created by parsing Kea
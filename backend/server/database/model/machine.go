@@ -41,7 +41,10 @@ type Machine struct {
 	Apps            []*App `pg:"rel:has-many"`
 	AgentToken      string
 	CertFingerprint [32]byte
-	Authorized      bool `pg:",use_zero"`
+	// Expiration time of the certificate issued for the agent. It is
+	// zero for the machines registered before it was recorded.
+	CertExpiresAt time.Time
+	Authorized    bool `pg:",use_zero"`
 }
 
 type MachineRelation string
@@ -167,7 +170,9 @@ func RefreshMachineFromDB(db *pg.DB, machine *Machine) error {
 // sortField allows indicating sort column in database and sortDir
 // allows selection the order of sorting. If sortField is empty then
 // id is used for sorting.  in SortDirAny is used then ASC order is
-// used.
+// used. The machines having the same value in the sort column (e.g.,
+// authorized or last_visited_at) are additionally sorted by id to
+// make the pages stable.
 func GetMachinesByPage(db *pg.DB, offset int64, limit int64, filterText *string, authorized *bool, sortField string, sortDir SortDirEnum) ([]Machine, int64, error) {
 	if limit == 0 {
 		return nil, 0, pkgerrors.New("limit should be greater than 0")
@@ -208,6 +213,9 @@ func GetMachinesByPage(db *pg.DB, offset int64, limit int64, filterText *string,
 	// prepare sorting expression, offset and limit
 	ordExpr := prepareOrderExpr("machine", sortField, sortDir)
 	q = q.OrderExpr(ordExpr)
+	if sortField != "" && sortField != "id" && sortField != "machine.id" {
+		q = q.OrderExpr("machine.id ASC")
+	}
 	q = q.Offset(int(offset))
 	q = q.Limit(int(limit))
 
@@ -244,6 +252,25 @@ func GetAllMachines(db *pg.DB, authorized *bool) ([]Machine, error) {
 	return machines, nil
 }
 
+// Get the machines which agent certificates expire before the specified
+// time. The machines with the unknown certificate expiration time are not
+// returned. The machines are ordered by the expiration time.
+func GetMachinesWithCertsExpiringBefore(db *pg.DB, before time.Time) ([]Machine, error) {
+	var machines []Machine
+	err := db.Model(&machines).
+		Where("cert_expires_at IS NOT NULL").
+		Where("cert_expires_at < ?", before.UTC()).
+		OrderExpr("cert_expires_at ASC, id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, pkgerrors.Wrapf(err, "problem getting machines with certs expiring before %s", before)
+	}
+	return machines, nil
+}
+
 // Delete a machine from database.
 func DeleteMachine(db *pg.DB, machine *Machine) error {
 	result, err := db.Model(machine).WherePK().Delete()
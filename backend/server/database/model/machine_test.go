@@ -560,6 +560,64 @@ func TestGetMachinesByPageFilteredByAuthorized(t *testing.T) {
 	require.Len(t, ms, 2)
 }
 
+// Check that the machines can be sorted by the authorization status and
+// the last contact time, and that the machines with the same value in the
+// sort column are ordered by id.
+func TestGetMachinesByPageSortedByStatusAndLastVisit(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	now := time.Now().UTC()
+	machines := []Machine{
+		{Address: "host-1", AgentPort: 8080, Authorized: true, LastVisitedAt: now.Add(-3 * time.Minute)},
+		{Address: "host-2", AgentPort: 8080, Authorized: false, LastVisitedAt: now.Add(-1 * time.Minute)},
+		{Address: "host-3", AgentPort: 8080, Authorized: true, LastVisitedAt: now.Add(-2 * time.Minute)},
+		{Address: "host-4", AgentPort: 8080, Authorized: false, LastVisitedAt: now.Add(-4 * time.Minute)},
+	}
+	for i := range machines {
+		err := AddMachine(db, &machines[i])
+		require.NoError(t, err)
+	}
+
+	getAddresses := func(ms []Machine) (addresses []string) {
+		for _, m := range ms {
+			addresses = append(addresses, m.Address)
+		}
+		return
+	}
+
+	// Sort by the authorization status. The ties are ordered by id.
+	ms, total, err := GetMachinesByPage(db, 0, 10, nil, nil, "authorized", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, total)
+	require.Equal(t, []string{"host-2", "host-4", "host-1", "host-3"}, getAddresses(ms))
+
+	ms, total, err = GetMachinesByPage(db, 0, 10, nil, nil, "authorized", SortDirDesc)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, total)
+	require.Equal(t, []string{"host-1", "host-3", "host-2", "host-4"}, getAddresses(ms))
+
+	// The pages are stable when sorting by the column with ties.
+	ms, total, err = GetMachinesByPage(db, 1, 2, nil, nil, "authorized", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, total)
+	require.Equal(t, []string{"host-4", "host-1"}, getAddresses(ms))
+
+	// Sort by the last contact time.
+	ms, total, err = GetMachinesByPage(db, 0, 10, nil, nil, "last_visited_at", SortDirDesc)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, total)
+	require.Equal(t, []string{"host-2", "host-3", "host-1", "host-4"}, getAddresses(ms))
+
+	// Filter by the address and sort by the last contact time.
+	text := "host-"
+	authorized := true
+	ms, total, err = GetMachinesByPage(db, 0, 10, &text, &authorized, "last_visited_at", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Equal(t, []string{"host-1", "host-3"}, getAddresses(ms))
+}
+
 // Check if deleting only machine works.
 func TestDeleteMachineOnly(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -654,6 +712,41 @@ func TestRefreshMachineFromDB(t *testing.T) {
 	require.Equal(t, "some error", m.Error)
 }
 
+// Test getting the machines which agent certificates expire before
+// the specified time.
+func TestGetMachinesWithCertsExpiringBefore(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	now := time.Now().UTC()
+	expirations := []time.Time{
+		now.AddDate(0, 0, 40),
+		now.AddDate(0, 0, 10),
+		{},
+		now.AddDate(0, 0, -1),
+	}
+	for i, expiration := range expirations {
+		m := &Machine{
+			Address:       "localhost",
+			AgentPort:     8080 + int64(i),
+			CertExpiresAt: expiration,
+		}
+		err := AddMachine(db, m)
+		require.NoError(t, err)
+	}
+
+	machines, err := GetMachinesWithCertsExpiringBefore(db, now.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	require.Len(t, machines, 2)
+	require.EqualValues(t, 8083, machines[0].AgentPort)
+	require.EqualValues(t, 8081, machines[1].AgentPort)
+	require.WithinDuration(t, expirations[1], machines[1].CertExpiresAt, time.Second)
+
+	machines, err = GetMachinesWithCertsExpiringBefore(db, now.AddDate(0, 0, -10))
+	require.NoError(t, err)
+	require.Empty(t, machines)
+}
+
 // Check if getting all machines works.
 func TestGetAllMachines(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -2,6 +2,7 @@ package dbmodel
 
 import (
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 	"github.com/pkg/errors"
 )
 
@@ -15,6 +16,19 @@ type CalculatedNetworkMetrics struct {
 	PdUtilization int16
 }
 
+// Number of the utilization bands in the utilization histogram.
+const UtilizationHistogramBands = 10
+
+// Numbers of the subnets or shared networks in the consecutive 10%
+// utilization bands. The first item is a number of networks with the
+// utilization lower than 10%, the second item is a number of networks
+// with the utilization between 10% and 20%, and so on. The networks
+// with 100% utilization are counted in the last band.
+type UtilizationHistogram struct {
+	AddrUtilization [UtilizationHistogramBands]int64
+	PdUtilization   [UtilizationHistogramBands]int64
+}
+
 // Metric values calculated from the database.
 type CalculatedMetrics struct {
 	AuthorizedMachines   int64
@@ -22,40 +36,151 @@ type CalculatedMetrics struct {
 	UnreachableMachines  int64
 	SubnetMetrics        []CalculatedNetworkMetrics
 	SharedNetworkMetrics []CalculatedNetworkMetrics
+	// Utilization histograms are only calculated instead of the
+	// per-network metrics by GetCalculatedHistogramMetrics.
+	SubnetHistogram        *UtilizationHistogram
+	SharedNetworkHistogram *UtilizationHistogram
 }
 
-// Calculates various metrics using several SELECT queries.
-func GetCalculatedMetrics(db *pg.DB) (*CalculatedMetrics, error) {
+// Limits the query selecting from the subnet or shared_network table to
+// the networks served by the daemons belonging to the specified machine.
+// The zero machine ID doesn't limit the query.
+func whereServedByMachine(q *orm.Query, table string, machineID int64) *orm.Query {
+	if machineID == 0 {
+		return q
+	}
+	switch table {
+	case "subnet":
+		return q.Where(`EXISTS (SELECT 1 FROM local_subnet AS ls
+			INNER JOIN daemon AS d ON ls.daemon_id = d.id
+			INNER JOIN app AS a ON d.app_id = a.id
+			WHERE ls.subnet_id = subnet.id AND a.machine_id = ?)`, machineID)
+	default:
+		return q.Where(`EXISTS (SELECT 1 FROM subnet AS s
+			INNER JOIN local_subnet AS ls ON ls.subnet_id = s.id
+			INNER JOIN daemon AS d ON ls.daemon_id = d.id
+			INNER JOIN app AS a ON d.app_id = a.id
+			WHERE s.shared_network_id = shared_network.id AND a.machine_id = ?)`, machineID)
+	}
+}
+
+// Calculates the metrics related to the machines. The zero machine ID
+// means all machines.
+func getCalculatedMachineMetrics(db *pg.DB, machineID int64) (*CalculatedMetrics, error) {
 	metrics := CalculatedMetrics{}
-	err := db.Model().
+	q := db.Model().
 		Table("machine").
 		ColumnExpr("COUNT(*) FILTER (WHERE machine.authorized) AS \"authorized_machines\"").
 		ColumnExpr("COUNT(*) FILTER (WHERE NOT(machine.authorized)) AS \"unauthorized_machines\"").
-		ColumnExpr("COUNT(*) FILTER (WHERE machine.error IS NOT NULL) AS \"unreachable_machines\"").
-		Select(&metrics)
+		ColumnExpr("COUNT(*) FILTER (WHERE machine.error IS NOT NULL) AS \"unreachable_machines\"")
+	if machineID != 0 {
+		q = q.Where("machine.id = ?", machineID)
+	}
+	err := q.Select(&metrics)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot calculate global metrics")
 	}
+	return &metrics, nil
+}
+
+// Calculates various metrics using several SELECT queries.
+func GetCalculatedMetrics(db *pg.DB) (*CalculatedMetrics, error) {
+	return GetCalculatedMetricsForMachine(db, 0)
+}
+
+// Calculates various metrics using several SELECT queries. The metrics
+// are limited to the specified machine and the subnets and shared
+// networks served by its daemons. The zero machine ID means all machines.
+func GetCalculatedMetricsForMachine(db *pg.DB, machineID int64) (*CalculatedMetrics, error) {
+	metrics, err := getCalculatedMachineMetrics(db, machineID)
+	if err != nil {
+		return nil, err
+	}
 
-	err = db.Model().
+	q := db.Model().
 		Table("subnet").
 		ColumnExpr("\"prefix\" AS \"label\"").
-		Column("addr_utilization", "pd_utilization").
+		Column("addr_utilization", "pd_utilization")
+	err = whereServedByMachine(q, "subnet", machineID).
 		Select(&metrics.SubnetMetrics)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot calculate subnet metrics")
 	}
 
-	err = db.Model().
+	q = db.Model().
 		Table("shared_network").
 		ColumnExpr("\"name\" AS \"label\"").
-		Column("addr_utilization", "pd_utilization").
+		Column("addr_utilization", "pd_utilization")
+	err = whereServedByMachine(q, "shared_network", machineID).
 		Select(&metrics.SharedNetworkMetrics)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot calculate shared network metrics")
 	}
 
-	return &metrics, nil
+	return metrics, nil
+}
+
+// Calculates the histogram of the utilizations stored in the specified
+// column of the specified table. The utilization is stored in percentage
+// multiplied by 10, so dividing it by 100 yields the band index. The
+// non-zero machine ID limits the histogram to the networks served by
+// the machine.
+func getUtilizationHistogram(db *pg.DB, table, column string, machineID int64, histogram *[UtilizationHistogramBands]int64) error {
+	var bands []struct {
+		Band  int
+		Count int64
+	}
+	q := db.Model().
+		TableExpr("?", pg.Ident(table)).
+		ColumnExpr("LEAST(COALESCE(?, 0) / 100, ?) AS band", pg.Ident(column), UtilizationHistogramBands-1).
+		ColumnExpr("COUNT(*) AS count").
+		GroupExpr("band")
+	err := whereServedByMachine(q, table, machineID).
+		Select(&bands)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot calculate %s histogram for %s", column, table)
+	}
+	for _, band := range bands {
+		if band.Band >= 0 && band.Band < UtilizationHistogramBands {
+			histogram[band.Band] = band.Count
+		}
+	}
+	return nil
+}
+
+// Calculates various metrics using several SELECT queries. Unlike the
+// GetCalculatedMetrics, the subnet and shared network utilizations are
+// aggregated into the histograms instead of being returned per network.
+// It bounds the number of the returned metrics for large deployments.
+func GetCalculatedHistogramMetrics(db *pg.DB) (*CalculatedMetrics, error) {
+	return GetCalculatedHistogramMetricsForMachine(db, 0)
+}
+
+// Calculates the metrics like GetCalculatedHistogramMetrics but limits
+// them to the specified machine and the subnets and shared networks served
+// by its daemons. The zero machine ID means all machines.
+func GetCalculatedHistogramMetricsForMachine(db *pg.DB, machineID int64) (*CalculatedMetrics, error) {
+	metrics, err := getCalculatedMachineMetrics(db, machineID)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.SubnetHistogram = &UtilizationHistogram{}
+	metrics.SharedNetworkHistogram = &UtilizationHistogram{}
+
+	for table, histogram := range map[string]*UtilizationHistogram{
+		"subnet":         metrics.SubnetHistogram,
+		"shared_network": metrics.SharedNetworkHistogram,
+	} {
+		if err = getUtilizationHistogram(db, table, "addr_utilization", machineID, &histogram.AddrUtilization); err != nil {
+			return nil, err
+		}
+		if err = getUtilizationHistogram(db, table, "pd_utilization", machineID, &histogram.PdUtilization); err != nil {
+			return nil, err
+		}
+	}
+
+	return metrics, nil
 }
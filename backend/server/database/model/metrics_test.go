@@ -1,6 +1,7 @@
 package dbmodel
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -137,3 +138,123 @@ func TestFilledSharedNetworksDatabaseMetrics(t *testing.T) {
 	require.Zero(t, metrics.SharedNetworkMetrics[2].AddrUtilization)
 	require.Zero(t, metrics.SharedNetworkMetrics[2].PdUtilization)
 }
+
+// The subnet and shared network utilizations should be properly aggregated
+// into the utilization bands.
+func TestUtilizationHistogramDatabaseMetrics(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = AddMachine(db, &Machine{
+		Address: "1", AgentPort: 1, Authorized: true,
+	})
+	// Utilizations in percentage multiplied by 10.
+	subnetUtilizations := []int16{0, 5, 99, 100, 150, 500, 999, 1000}
+	for i, utilization := range subnetUtilizations {
+		_ = AddSubnet(db, &Subnet{
+			Prefix:          fmt.Sprintf("192.0.%d.0/24", i),
+			AddrUtilization: utilization,
+			PdUtilization:   1000 - utilization,
+		})
+	}
+	_ = AddSubnet(db, &Subnet{
+		Prefix: "10.0.0.0/8",
+	})
+	_ = AddSharedNetwork(db, &SharedNetwork{
+		Name:            "alice",
+		AddrUtilization: 250,
+		Family:          4,
+	})
+
+	// Act
+	metrics, err := GetCalculatedHistogramMetrics(db)
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 1, metrics.AuthorizedMachines)
+	require.Empty(t, metrics.SubnetMetrics)
+	require.Empty(t, metrics.SharedNetworkMetrics)
+	require.NotNil(t, metrics.SubnetHistogram)
+	require.NotNil(t, metrics.SharedNetworkHistogram)
+
+	require.EqualValues(t, [UtilizationHistogramBands]int64{4, 2, 0, 0, 0, 1, 0, 0, 0, 2}, metrics.SubnetHistogram.AddrUtilization)
+	require.EqualValues(t, [UtilizationHistogramBands]int64{3, 0, 0, 0, 0, 1, 0, 0, 1, 4}, metrics.SubnetHistogram.PdUtilization)
+
+	// The bucketed output sums to the total number of subnets.
+	var addrTotal, pdTotal int64
+	for i := 0; i < UtilizationHistogramBands; i++ {
+		addrTotal += metrics.SubnetHistogram.AddrUtilization[i]
+		pdTotal += metrics.SubnetHistogram.PdUtilization[i]
+	}
+	require.EqualValues(t, len(subnetUtilizations)+1, addrTotal)
+	require.EqualValues(t, len(subnetUtilizations)+1, pdTotal)
+
+	require.EqualValues(t, 1, metrics.SharedNetworkHistogram.AddrUtilization[2])
+	require.EqualValues(t, 1, metrics.SharedNetworkHistogram.PdUtilization[0])
+}
+
+// The metrics limited to a machine should only include the machine and
+// the networks served by its daemons.
+func TestMachineDatabaseMetrics(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	apps := addTestSubnetApps(t, db)
+	daemon1 := apps[0].Daemons[0]
+	daemon2 := apps[1].Daemons[0]
+
+	sharedNetworks := []*SharedNetwork{
+		{Name: "alice", Family: 4, AddrUtilization: 100},
+		{Name: "bob", Family: 4, AddrUtilization: 200},
+	}
+	for _, network := range sharedNetworks {
+		require.NoError(t, AddSharedNetwork(db, network))
+	}
+	subnets := []*Subnet{
+		{Prefix: "192.0.2.0/24", AddrUtilization: 10},
+		{Prefix: "192.0.3.0/24", AddrUtilization: 20, SharedNetworkID: sharedNetworks[0].ID},
+		{Prefix: "192.0.4.0/24", AddrUtilization: 950, SharedNetworkID: sharedNetworks[1].ID},
+		{Prefix: "192.0.5.0/24", AddrUtilization: 40},
+	}
+	for _, subnet := range subnets {
+		require.NoError(t, AddSubnet(db, subnet))
+	}
+	require.NoError(t, AddDaemonToSubnet(db, subnets[0], daemon1))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[1], daemon1))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[1], daemon2))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[2], daemon2))
+
+	// Act
+	metrics, err := GetCalculatedMetricsForMachine(db, apps[0].MachineID)
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 1, metrics.UnauthorizedMachines)
+	require.Zero(t, metrics.AuthorizedMachines)
+	require.Len(t, metrics.SubnetMetrics, 2)
+	require.ElementsMatch(t, []CalculatedNetworkMetrics{
+		{Label: "192.0.2.0/24", AddrUtilization: 10},
+		{Label: "192.0.3.0/24", AddrUtilization: 20},
+	}, metrics.SubnetMetrics)
+	require.Len(t, metrics.SharedNetworkMetrics, 1)
+	require.EqualValues(t, "alice", metrics.SharedNetworkMetrics[0].Label)
+
+	// Act
+	metrics, err = GetCalculatedHistogramMetricsForMachine(db, apps[1].MachineID)
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 1, metrics.UnauthorizedMachines)
+	require.EqualValues(t, [UtilizationHistogramBands]int64{1, 0, 0, 0, 0, 0, 0, 0, 0, 1}, metrics.SubnetHistogram.AddrUtilization)
+	require.EqualValues(t, [UtilizationHistogramBands]int64{0, 1, 1, 0, 0, 0, 0, 0, 0, 0}, metrics.SharedNetworkHistogram.AddrUtilization)
+
+	// Act
+	metrics, err = GetCalculatedMetricsForMachine(db, apps[1].MachineID+100)
+
+	// Assert
+	require.NoError(t, err)
+	require.Zero(t, metrics.AuthorizedMachines)
+	require.Zero(t, metrics.UnauthorizedMachines)
+	require.Empty(t, metrics.SubnetMetrics)
+	require.Empty(t, metrics.SharedNetworkMetrics)
+}
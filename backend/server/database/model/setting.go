@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-pg/pg/v10"
 	pkgerrors "github.com/pkg/errors"
@@ -62,6 +63,11 @@ func InitializeSettings(db *pg.DB, initialPullerInterval int64) error {
 			ValType: SettingValTypeInt,
 			Value:   longInterval,
 		},
+		{
+			Name:    "kea_stats_puller_batch_size", // 0 means all apps in each cycle
+			ValType: SettingValTypeInt,
+			Value:   "0",
+		},
 		{
 			Name:    "kea_hosts_puller_interval", // in seconds
 			ValType: SettingValTypeInt,
@@ -77,6 +83,11 @@ func InitializeSettings(db *pg.DB, initialPullerInterval int64) error {
 			ValType: SettingValTypeInt,
 			Value:   mediumInterval,
 		},
+		{
+			Name:    "config_review_puller_interval", // in seconds, 0 disables the periodic reviews
+			ValType: SettingValTypeInt,
+			Value:   "0",
+		},
 		{
 			Name:    "grafana_url",
 			ValType: SettingValTypeStr,
@@ -92,6 +103,36 @@ func InitializeSettings(db *pg.DB, initialPullerInterval int64) error {
 			ValType: SettingValTypeInt,
 			Value:   shortInterval, // in seconds
 		},
+		{
+			Name:    "metrics_utilization_histogram",
+			ValType: SettingValTypeBool,
+			Value:   "false",
+		},
+		{
+			Name:    "min_pool_size", // in addresses, 0 disables the config review checker
+			ValType: SettingValTypeInt,
+			Value:   "10",
+		},
+		{
+			Name:    "max_checker_findings", // per config review report
+			ValType: SettingValTypeInt,
+			Value:   "10",
+		},
+		{
+			Name:    "overlaps_detection_time_budget", // in seconds, 0 disables the limit
+			ValType: SettingValTypeInt,
+			Value:   "5",
+		},
+		{
+			Name:    "pullers_paused",
+			ValType: SettingValTypeBool,
+			Value:   "false",
+		},
+		{
+			Name:    "pullers_paused_until", // RFC 3339 timestamp or empty
+			ValType: SettingValTypeStr,
+			Value:   "",
+		},
 	}
 
 	// Check if there are new settings vs existing ones. Add new ones to DB.
@@ -103,9 +144,9 @@ func InitializeSettings(db *pg.DB, initialPullerInterval int64) error {
 }
 
 // Get setting record from db based on its name.
-func GetSetting(db *pg.DB, name string) (*Setting, error) {
+func GetSetting(dbi pg.DBI, name string) (*Setting, error) {
 	setting := Setting{}
-	q := db.Model(&setting).Where("setting.name = ?", name)
+	q := dbi.Model(&setting).Where("setting.name = ?", name)
 	err := q.Select()
 	if errors.Is(err, pg.ErrNoRows) {
 		return nil, pkgerrors.Wrapf(err, "setting %s is missing", name)
@@ -116,8 +157,8 @@ func GetSetting(db *pg.DB, name string) (*Setting, error) {
 }
 
 // Get setting by name and check if its type matches to expected one.
-func getAndCheckSetting(db *pg.DB, name string, expValType int64) (*Setting, error) {
-	s, err := GetSetting(db, name)
+func getAndCheckSetting(dbi pg.DBI, name string, expValType int64) (*Setting, error) {
+	s, err := GetSetting(dbi, name)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +169,8 @@ func getAndCheckSetting(db *pg.DB, name string, expValType int64) (*Setting, err
 }
 
 // Get int value of given setting by name.
-func GetSettingInt(db *pg.DB, name string) (int64, error) {
-	s, err := getAndCheckSetting(db, name, SettingValTypeInt)
+func GetSettingInt(dbi pg.DBI, name string) (int64, error) {
+	s, err := getAndCheckSetting(dbi, name, SettingValTypeInt)
 	if err != nil {
 		return 0, err
 	}
@@ -141,8 +182,8 @@ func GetSettingInt(db *pg.DB, name string) (int64, error) {
 }
 
 // Get bool value of given setting by name.
-func GetSettingBool(db *pg.DB, name string) (bool, error) {
-	s, err := getAndCheckSetting(db, name, SettingValTypeBool)
+func GetSettingBool(dbi pg.DBI, name string) (bool, error) {
+	s, err := getAndCheckSetting(dbi, name, SettingValTypeBool)
 	if err != nil {
 		return false, err
 	}
@@ -154,8 +195,8 @@ func GetSettingBool(db *pg.DB, name string) (bool, error) {
 }
 
 // Get string value of given setting by name.
-func GetSettingStr(db *pg.DB, name string) (string, error) {
-	s, err := getAndCheckSetting(db, name, SettingValTypeStr)
+func GetSettingStr(dbi pg.DBI, name string) (string, error) {
+	s, err := getAndCheckSetting(dbi, name, SettingValTypeStr)
 	if err != nil {
 		return "", err
 	}
@@ -163,8 +204,8 @@ func GetSettingStr(db *pg.DB, name string) (string, error) {
 }
 
 // Get password value of given setting by name.
-func GetSettingPasswd(db *pg.DB, name string) (string, error) {
-	s, err := getAndCheckSetting(db, name, SettingValTypePasswd)
+func GetSettingPasswd(dbi pg.DBI, name string) (string, error) {
+	s, err := getAndCheckSetting(dbi, name, SettingValTypePasswd)
 	if err != nil {
 		return "", err
 	}
@@ -269,3 +310,52 @@ func SetSettingPasswd(db *pg.DB, name string, value string) error {
 	}
 	return nil
 }
+
+// Pauses all pullers, e.g., for the time of the Kea maintenance. The pullers
+// resume automatically after the specified time. If the time is zero, the
+// pullers remain paused until they are resumed with ResumeAllPullers.
+func PauseAllPullers(db *pg.DB, until time.Time) error {
+	pausedUntil := ""
+	if !until.IsZero() {
+		pausedUntil = until.UTC().Format(time.RFC3339)
+	}
+	if err := SetSettingStr(db, "pullers_paused_until", pausedUntil); err != nil {
+		return err
+	}
+	return SetSettingBool(db, "pullers_paused", true)
+}
+
+// Resumes the pullers paused with PauseAllPullers.
+func ResumeAllPullers(db *pg.DB) error {
+	if err := SetSettingBool(db, "pullers_paused", false); err != nil {
+		return err
+	}
+	return SetSettingStr(db, "pullers_paused_until", "")
+}
+
+// Checks if all pullers are paused. The pause is over when its end time
+// has passed. It returns the end time of the pause as a second value. It
+// is zero if the pullers are not paused or if they are paused until
+// explicitly resumed.
+func GetPullersPauseState(db *pg.DB) (bool, time.Time, error) {
+	paused, err := GetSettingBool(db, "pullers_paused")
+	if err != nil || !paused {
+		return false, time.Time{}, err
+	}
+	pausedUntil, err := GetSettingStr(db, "pullers_paused_until")
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if pausedUntil == "" {
+		return true, time.Time{}, nil
+	}
+	until, err := time.Parse(time.RFC3339, pausedUntil)
+	if err != nil {
+		return false, time.Time{}, pkgerrors.Wrapf(err, "problem parsing the end time of the pullers pause %s", pausedUntil)
+	}
+	if !time.Now().Before(until) {
+		// The pause is over.
+		return false, time.Time{}, nil
+	}
+	return true, until, nil
+}
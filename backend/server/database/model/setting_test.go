@@ -2,6 +2,7 @@ package dbmodel
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	dbtest "isc.org/stork/server/database/test"
@@ -49,6 +50,26 @@ func TestInitializeSettings(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, 30, val)
 
+	val, err = GetSettingInt(db, "kea_stats_puller_batch_size")
+	require.NoError(t, err)
+	require.Zero(t, val)
+
+	val, err = GetSettingInt(db, "config_review_puller_interval")
+	require.NoError(t, err)
+	require.Zero(t, val)
+
+	val, err = GetSettingInt(db, "min_pool_size")
+	require.NoError(t, err)
+	require.EqualValues(t, 10, val)
+
+	val, err = GetSettingInt(db, "max_checker_findings")
+	require.NoError(t, err)
+	require.EqualValues(t, 10, val)
+
+	val, err = GetSettingInt(db, "overlaps_detection_time_budget")
+	require.NoError(t, err)
+	require.EqualValues(t, 5, val)
+
 	// change the setting
 	err = SetSettingInt(db, "kea_stats_puller_interval", 123)
 	require.NoError(t, err)
@@ -191,3 +212,63 @@ func TestSettingsSetAndGet(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, "H@kErZ", pwdVal)
 }
+
+// Test pausing and resuming all pullers.
+func TestPauseAllPullers(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	err := InitializeSettings(db, 0)
+	require.NoError(t, err)
+
+	// The pullers are not paused by default.
+	paused, until, err := GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.False(t, paused)
+	require.Zero(t, until)
+
+	// Pause the pullers until explicitly resumed.
+	err = PauseAllPullers(db, time.Time{})
+	require.NoError(t, err)
+
+	paused, until, err = GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.True(t, paused)
+	require.Zero(t, until)
+
+	// Resume the pullers.
+	err = ResumeAllPullers(db)
+	require.NoError(t, err)
+
+	paused, _, err = GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	// Pause the pullers for an hour.
+	end := time.Now().Add(time.Hour)
+	err = PauseAllPullers(db, end)
+	require.NoError(t, err)
+
+	paused, until, err = GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.True(t, paused)
+	require.EqualValues(t, end.Unix(), until.Unix())
+}
+
+// Test that the pause of all pullers ends automatically when its end
+// time has passed.
+func TestPauseAllPullersExpired(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	err := InitializeSettings(db, 0)
+	require.NoError(t, err)
+
+	err = PauseAllPullers(db, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	paused, until, err := GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.False(t, paused)
+	require.Zero(t, until)
+}
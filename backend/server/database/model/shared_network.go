@@ -108,6 +108,37 @@ func GetAllSharedNetworks(dbi dbops.DBI, family int) ([]SharedNetwork, error) {
 	return networks, err
 }
 
+// Counts the subnets belonging to each shared network in a single query.
+// It returns a map of the shared network ids and the numbers of their
+// subnets. The subnets which do not belong to any shared network (global
+// subnets) are counted under the zero key. If the family is set to 0 the
+// IPv4 and IPv6 subnets are counted.
+func GetSubnetCountsBySharedNetwork(dbi dbops.DBI, family int) (map[int64]int64, error) {
+	var rows []struct {
+		SharedNetworkID int64
+		Count           int64
+	}
+	q := dbi.Model((*Subnet)(nil)).
+		ColumnExpr("COALESCE(subnet.shared_network_id, 0) AS shared_network_id").
+		ColumnExpr("COUNT(*) AS count").
+		GroupExpr("COALESCE(subnet.shared_network_id, 0)")
+
+	// Let's be liberal and allow other values than 0 too. The only special
+	// ones are 4 and 6.
+	if family == 4 || family == 6 {
+		q = q.Where("family(subnet.prefix) = ?", family)
+	}
+	if err := q.Select(&rows); err != nil {
+		return nil, pkgerrors.Wrapf(err, "problem counting subnets in shared networks for family %d", family)
+	}
+
+	counts := make(map[int64]int64)
+	for _, row := range rows {
+		counts[row.SharedNetworkID] = row.Count
+	}
+	return counts, nil
+}
+
 // Fetches the information about the selected shared network.
 func GetSharedNetwork(dbi dbops.DBI, networkID int64) (*SharedNetwork, error) {
 	network := &SharedNetwork{}
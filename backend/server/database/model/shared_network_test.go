@@ -192,6 +192,85 @@ func TestGetSharedNetworksByFamily(t *testing.T) {
 	require.Equal(t, "snake", returned[1].Name)
 }
 
+// Test that the subnets are counted per shared network, including the
+// global subnets.
+func TestGetSubnetCountsBySharedNetwork(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// No subnets.
+	counts, err := GetSubnetCountsBySharedNetwork(db, 0)
+	require.NoError(t, err)
+	require.Empty(t, counts)
+
+	networks := []SharedNetwork{
+		{
+			Name:   "small",
+			Family: 4,
+			Subnets: []Subnet{
+				{Prefix: "192.0.2.0/24"},
+			},
+		},
+		{
+			Name:   "large",
+			Family: 4,
+			Subnets: []Subnet{
+				{Prefix: "192.0.3.0/24"},
+				{Prefix: "192.0.4.0/24"},
+				{Prefix: "192.0.5.0/24"},
+			},
+		},
+		{
+			Name:   "empty",
+			Family: 4,
+		},
+		{
+			Name:   "v6",
+			Family: 6,
+			Subnets: []Subnet{
+				{Prefix: "2001:db8:1::/64"},
+				{Prefix: "2001:db8:2::/64"},
+			},
+		},
+	}
+	for i := range networks {
+		err := AddSharedNetwork(db, &networks[i])
+		require.NoError(t, err)
+		require.NotZero(t, networks[i].ID)
+	}
+
+	// Global subnets.
+	for _, prefix := range []string{"10.0.0.0/8", "172.16.0.0/12", "2001:db8:3::/64"} {
+		err := AddSubnet(db, &Subnet{Prefix: prefix})
+		require.NoError(t, err)
+	}
+
+	// Both families.
+	counts, err = GetSubnetCountsBySharedNetwork(db, 0)
+	require.NoError(t, err)
+	require.Len(t, counts, 4)
+	require.EqualValues(t, 1, counts[networks[0].ID])
+	require.EqualValues(t, 3, counts[networks[1].ID])
+	require.EqualValues(t, 2, counts[networks[3].ID])
+	require.EqualValues(t, 3, counts[0])
+	require.NotContains(t, counts, networks[2].ID)
+
+	// IPv4 only.
+	counts, err = GetSubnetCountsBySharedNetwork(db, 4)
+	require.NoError(t, err)
+	require.Len(t, counts, 3)
+	require.EqualValues(t, 1, counts[networks[0].ID])
+	require.EqualValues(t, 3, counts[networks[1].ID])
+	require.EqualValues(t, 2, counts[0])
+
+	// IPv6 only.
+	counts, err = GetSubnetCountsBySharedNetwork(db, 6)
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	require.EqualValues(t, 2, counts[networks[3].ID])
+	require.EqualValues(t, 1, counts[0])
+}
+
 // Tests that the shared network information can be updated.
 func TestUpdateSharedNetwork(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
 	pkgerrors "github.com/pkg/errors"
+	keaconfig "isc.org/stork/appcfg/kea"
 	dbops "isc.org/stork/server/database"
 	storkutil "isc.org/stork/util"
 )
@@ -129,6 +131,9 @@ type LocalSubnet struct {
 	Daemon        *Daemon `pg:"rel:has-one"`
 	Subnet        *Subnet `pg:"rel:has-one"`
 	LocalSubnetID int64
+	// Subnet prefix as specified in the daemon's configuration. It is set
+	// only when the configured prefix is not in the canonical form.
+	OriginalPrefix string
 
 	Stats            SubnetStats
 	StatsCollectedAt time.Time
@@ -138,8 +143,15 @@ type LocalSubnet struct {
 type Subnet struct {
 	ID          int64
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
 	Prefix      string
 	ClientClass string
+	// Free-text note specified by the operator.
+	Note string
+	// Subnet prefix as specified in the parsed configuration when it is not
+	// in the canonical form. It is not stored in the subnet table but in the
+	// association of the subnet with the daemon.
+	OriginalPrefix string `pg:"-"`
 
 	SharedNetworkID int64
 	SharedNetwork   *SharedNetwork `pg:"rel:has-one"`
@@ -315,6 +327,40 @@ func GetSubnetsByPrefix(dbi dbops.DBI, prefix string) ([]Subnet, error) {
 	return subnets, err
 }
 
+// Fetches the subnets having the specified prefixes from the database in
+// a single query. The returned subnets are grouped by prefix. The prefixes
+// for which no subnets were found are not included in the returned map.
+func GetSubnetsByPrefixes(dbi dbops.DBI, prefixes []string) (map[string][]Subnet, error) {
+	subnetsByPrefix := make(map[string][]Subnet)
+	if len(prefixes) == 0 {
+		return subnetsByPrefix, nil
+	}
+	subnets := []Subnet{}
+	err := dbi.Model(&subnets).
+		Relation("AddressPools", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("address_pool.id ASC"), nil
+		}).
+		Relation("PrefixPools", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("prefix_pool.id ASC"), nil
+		}).
+		Relation("SharedNetwork").
+		Relation("LocalSubnets.Daemon.App.AccessPoints").
+		Where("subnet.prefix IN (?)", pg.In(prefixes)).
+		OrderExpr("subnet.id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return subnetsByPrefix, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting subnets with prefixes %v", prefixes)
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		subnetsByPrefix[subnet.Prefix] = append(subnetsByPrefix[subnet.Prefix], subnet)
+	}
+	return subnetsByPrefix, nil
+}
+
 // Fetches all subnets belonging to a given family. If the family is set to 0
 // it fetches both IPv4 and IPv6 subnet.
 func GetAllSubnets(dbi dbops.DBI, family int) ([]Subnet, error) {
@@ -347,6 +393,79 @@ func GetAllSubnets(dbi dbops.DBI, family int) ([]Subnet, error) {
 	return subnets, err
 }
 
+// Fetches the subnets which have been added or updated after the specified
+// time. The subnet is considered updated when its configuration, i.e., its
+// pools or associations with the daemons, or its statistics have changed.
+// It allows for fetching the changes without fetching all subnets.
+func GetSubnetsChangedSince(dbi dbops.DBI, since time.Time) ([]Subnet, error) {
+	subnets := []Subnet{}
+	err := dbi.Model(&subnets).
+		Relation("AddressPools", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("address_pool.id ASC"), nil
+		}).
+		Relation("PrefixPools", func(q *orm.Query) (*orm.Query, error) {
+			return q.Order("prefix_pool.id ASC"), nil
+		}).
+		Relation("SharedNetwork").
+		Relation("LocalSubnets.Daemon.App.AccessPoints").
+		Relation("LocalSubnets.Daemon.App.Machine").
+		WhereOr("subnet.updated_at > ?", since.UTC()).
+		WhereOr("subnet.stats_collected_at > ?", since.UTC()).
+		OrderExpr("id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting the subnets changed since %s", since)
+		return nil, err
+	}
+	return subnets, nil
+}
+
+// Fetches the subnets with the inconsistent statistics. The statistics are
+// inconsistent when the utilization is stored but the statistics JSON is
+// empty, or when the statistics JSON is stored but neither the utilization
+// nor the statistics collection time is set. It indicates a partial update
+// of the statistics. Note that the zero utilization is stored as NULL, so
+// the statistics JSON without the utilization is not inconsistent as long
+// as the collection time is set.
+func GetSubnetsWithInconsistentStats(dbi dbops.DBI) ([]Subnet, error) {
+	subnets := []Subnet{}
+	err := dbi.Model(&subnets).
+		WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+				q = q.WhereOr("subnet.addr_utilization IS NOT NULL").
+					WhereOr("subnet.pd_utilization IS NOT NULL")
+				return q, nil
+			}).
+				WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+					q = q.WhereOr("subnet.stats IS NULL").
+						WhereOr("subnet.stats = 'null'::jsonb")
+					return q, nil
+				})
+			return q, nil
+		}).
+		WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.Where("subnet.stats IS NOT NULL").
+				Where("subnet.stats != 'null'::jsonb").
+				Where("subnet.addr_utilization IS NULL").
+				Where("subnet.pd_utilization IS NULL").
+				Where("subnet.stats_collected_at IS NULL")
+			return q, nil
+		}).
+		OrderExpr("id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting the subnets with inconsistent statistics")
+		return nil, err
+	}
+	return subnets, nil
+}
+
 // Fetches all global subnets, i.e., subnets that do not belong to shared
 // networks. If the family is set to 0 it fetches both IPv4 and IPv6 subnet.
 func GetGlobalSubnets(dbi dbops.DBI, family int) ([]Subnet, error) {
@@ -378,6 +497,134 @@ func GetGlobalSubnets(dbi dbops.DBI, family int) ([]Subnet, error) {
 	return subnets, nil
 }
 
+// Converts the statistic value to the big integer. The statistics may be
+// held as any integer type, including the big integer, depending on their
+// magnitude. It returns nil if the value is not a number.
+func getStatisticAsBigInt(value interface{}) *big.Int {
+	switch v := value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v)
+	case uint64:
+		return new(big.Int).SetUint64(v)
+	case int64:
+		return big.NewInt(v)
+	case float64:
+		n, _ := big.NewFloat(v).Int(nil)
+		return n
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if ok {
+			return n
+		}
+	}
+	return nil
+}
+
+// Returns the number of declined addresses in the subnet. The IPv4 subnets
+// hold it in the declined-addresses statistic and the IPv6 subnets in the
+// declined-nas statistic. It returns nil if the statistic is missing.
+func (s *Subnet) getDeclinedAddresses() *big.Int {
+	return s.Stats.getDeclinedAddresses()
+}
+
+// Returns the number of declined addresses from the subnet statistics.
+// It returns nil if the statistic is missing.
+func (s SubnetStats) getDeclinedAddresses() *big.Int {
+	if s == nil {
+		return nil
+	}
+	if value, ok := s["declined-addresses"]; ok {
+		return getStatisticAsBigInt(value)
+	}
+	if value, ok := s["declined-nas"]; ok {
+		return getStatisticAsBigInt(value)
+	}
+	return nil
+}
+
+// Fetches the subnets with the highest numbers of declined addresses. The
+// number of declined addresses is derived from the stored statistics. The
+// subnets are ordered from the highest to the lowest number and the subnets
+// without declined addresses are not returned. A high number of declined
+// addresses may indicate IP conflicts or rogue devices in the subnet. If the
+// family is set to 0 it fetches both IPv4 and IPv6 subnets. The zero limit
+// means no limit.
+func GetTopDeclinedSubnets(dbi dbops.DBI, family int, limit int) ([]Subnet, error) {
+	subnets := []Subnet{}
+	q := dbi.Model(&subnets).
+		Relation("SharedNetwork").
+		Relation("LocalSubnets.Daemon.App").
+		Where("subnet.stats IS NOT NULL").
+		Where("subnet.stats != 'null'::jsonb").
+		OrderExpr("id ASC")
+
+	// Let's be liberal and allow other values than 0 too. The only special
+	// ones are 4 and 6.
+	if family == 4 || family == 6 {
+		q = q.Where("family(subnet.prefix) = ?", family)
+	}
+	err := q.Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting the top declined subnets for family %d", family)
+		return nil, err
+	}
+
+	type declinedSubnet struct {
+		subnet   Subnet
+		declined *big.Int
+	}
+	var declinedSubnets []declinedSubnet
+	for i := range subnets {
+		declined := subnets[i].getDeclinedAddresses()
+		if declined == nil || declined.Sign() <= 0 {
+			continue
+		}
+		declinedSubnets = append(declinedSubnets, declinedSubnet{subnets[i], declined})
+	}
+
+	// The subnets are already ordered by ID, so the stable sort preserves
+	// this order for the subnets with the same number of declined addresses.
+	sort.SliceStable(declinedSubnets, func(i, j int) bool {
+		return declinedSubnets[i].declined.Cmp(declinedSubnets[j].declined) > 0
+	})
+
+	if limit > 0 && len(declinedSubnets) > limit {
+		declinedSubnets = declinedSubnets[:limit]
+	}
+
+	topSubnets := []Subnet{}
+	for _, declinedSubnet := range declinedSubnets {
+		topSubnets = append(topSubnets, declinedSubnet.subnet)
+	}
+	return topSubnets, nil
+}
+
+// Fetches the subnets for which all associated daemons are inactive. Such
+// subnets are not served by any DHCP server but still show up in the
+// inventory. The subnets without any associated daemons are not returned.
+// The subnets are ordered by ID.
+func GetSubnetsServedOnlyByInactiveDaemons(dbi dbops.DBI) ([]Subnet, error) {
+	subnets := []Subnet{}
+	err := dbi.Model(&subnets).
+		Relation("SharedNetwork").
+		Relation("LocalSubnets.Daemon.App").
+		Where("EXISTS (SELECT 1 FROM local_subnet AS ls WHERE ls.subnet_id = subnet.id)").
+		Where("NOT EXISTS (SELECT 1 FROM local_subnet AS ls INNER JOIN daemon AS d ON ls.daemon_id = d.id WHERE ls.subnet_id = subnet.id AND d.active)").
+		OrderExpr("id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting the subnets served only by inactive daemons")
+		return nil, err
+	}
+	return subnets, nil
+}
+
 // Fetches a collection of subnets from the database. The offset and
 // limit specify the beginning of the page and the maximum size of the
 // page. The appID is used to filter subnets to those handled by the
@@ -385,18 +632,23 @@ func GetGlobalSubnets(dbi dbops.DBI, family int) ([]Subnet, error) {
 // IPv6 (if 6). For all other values of the family parameter both IPv4
 // and IPv6 subnets are returned. The filterText can be used to match
 // the subnet prefix or pool ranges. The nil value disables such
-// filtering. sortField allows indicating sort column in database and
+// filtering. The appVersion is used to filter subnets to those served
+// by at least one daemon having the given version. The version matches
+// when it is equal to the specified one or when it begins with it followed
+// by a dot, e.g., 2.0 matches 2.0.3 but not 2.01. The nil value disables
+// such filtering. sortField allows indicating sort column in database and
 // sortDir allows selection the order of sorting. If sortField is
 // empty then id is used for sorting.  in SortDirAny is used then ASC
 // order is used. This function returns a collection of subnets, the
 // total number of subnets and error.
-func GetSubnetsByPage(dbi dbops.DBI, offset, limit, appID, family int64, filterText *string, sortField string, sortDir SortDirEnum) ([]Subnet, int64, error) {
+func GetSubnetsByPage(dbi dbops.DBI, offset, limit, appID, family int64, filterText, appVersion *string, sortField string, sortDir SortDirEnum) ([]Subnet, int64, error) {
 	subnets := []Subnet{}
 	q := dbi.Model(&subnets).Distinct()
 
-	// When filtering by appID we also need the local_subnet table as it holds the
-	// application identifier.
-	if appID != 0 {
+	// When filtering by appID or version we also need the local_subnet table as
+	// it holds the application identifier and the daemon table holding the
+	// version.
+	if appID != 0 || appVersion != nil {
 		q = q.Join("INNER JOIN local_subnet AS ls ON subnet.id = ls.subnet_id")
 		q = q.Join("INNER JOIN daemon AS d ON ls.daemon_id = d.id")
 	}
@@ -427,6 +679,15 @@ func GetSubnetsByPage(dbi dbops.DBI, offset, limit, appID, family int64, filterT
 		q = q.Where("d.app_id = ?", appID)
 	}
 
+	// Filter by the daemon version.
+	if appVersion != nil {
+		q = q.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("d.version = ?", *appVersion).
+				WhereOr("d.version LIKE ?", *appVersion+".%")
+			return q, nil
+		})
+	}
+
 	// Quick filtering by subnet prefix, pool ranges or shared network name.
 	if filterText != nil {
 		// The combination of the concat and host functions reconstruct the textual
@@ -490,9 +751,10 @@ func addDaemonToSubnet(tx *pg.Tx, subnet *Subnet, daemon *Daemon) error {
 		localSubnetID = daemon.GetLocalSubnetID(subnet.Prefix)
 	}
 	localSubnet := LocalSubnet{
-		SubnetID:      subnet.ID,
-		DaemonID:      daemon.ID,
-		LocalSubnetID: localSubnetID,
+		SubnetID:       subnet.ID,
+		DaemonID:       daemon.ID,
+		LocalSubnetID:  localSubnetID,
+		OriginalPrefix: subnet.OriginalPrefix,
 	}
 	// Try to insert. If such association already exists we could maybe do
 	// nothing, but we do update instead to force setting the new value
@@ -501,9 +763,11 @@ func addDaemonToSubnet(tx *pg.Tx, subnet *Subnet, daemon *Daemon) error {
 		Column("subnet_id").
 		Column("daemon_id").
 		Column("local_subnet_id").
+		Column("original_prefix").
 		OnConflict("(daemon_id, subnet_id) DO UPDATE").
 		Set("daemon_id = EXCLUDED.daemon_id").
 		Set("local_subnet_id = EXCLUDED.local_subnet_id").
+		Set("original_prefix = EXCLUDED.original_prefix").
 		Insert()
 	if err != nil {
 		err = pkgerrors.Wrapf(err, "problem associating the daemon %d with the subnet %s",
@@ -650,6 +914,151 @@ func CommitNetworksIntoDB(dbi dbops.DBI, networks []SharedNetwork, subnets []Sub
 	return
 }
 
+// Commits only the subnets which have been added or changed according to
+// the diff between the previous and the current daemon configuration. The
+// daemon is first disassociated from the removed and changed subnets and
+// their hosts. Next, the added and changed subnets are committed like in
+// the commitNetworksIntoDB. The unchanged subnets and their hosts are not
+// touched, so their associations with the daemon are preserved. The subnets
+// and hosts which no longer belong to any daemon are not deleted by this
+// function; they should be removed with the functions deleting the orphans.
+func commitChangedNetworksIntoDB(tx *pg.Tx, networks []SharedNetwork, subnets []Subnet, daemon *Daemon, diff *keaconfig.SubnetsDiff) ([]*Subnet, error) {
+	detached := make(map[string]bool)
+	for _, prefix := range diff.Removed {
+		detached[prefix] = true
+	}
+	committed := make(map[string]bool)
+	for _, prefix := range diff.Changed {
+		detached[prefix] = true
+		committed[prefix] = true
+	}
+	for _, prefix := range diff.Added {
+		committed[prefix] = true
+	}
+
+	if len(detached) > 0 {
+		existingSubnets, err := GetSubnetsByDaemonID(tx, daemon.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, subnet := range existingSubnets {
+			if !detached[subnet.Prefix] {
+				continue
+			}
+			if _, err = DeleteDaemonFromSubnet(tx, subnet.ID, daemon.ID); err != nil {
+				return nil, err
+			}
+			if _, err = DeleteDaemonFromSubnetHosts(tx, daemon.ID, subnet.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Returns the subnets to be committed.
+	filterSubnets := func(subnets []Subnet) (filtered []Subnet) {
+		for _, subnet := range subnets {
+			if committed[subnet.Prefix] {
+				filtered = append(filtered, subnet)
+			}
+		}
+		return filtered
+	}
+
+	var filteredNetworks []SharedNetwork
+	for _, network := range networks {
+		network.Subnets = filterSubnets(network.Subnets)
+		if len(network.Subnets) > 0 {
+			filteredNetworks = append(filteredNetworks, network)
+		}
+	}
+	return commitNetworksIntoDB(tx, filteredNetworks, filterSubnets(subnets), daemon)
+}
+
+// Iterates over the shared networks, subnets and hosts which have been added
+// or changed according to the diff between the previous and the current
+// daemon configuration and commits them to the database. It is an
+// alternative to the CommitNetworksIntoDB which significantly reduces the
+// number of database writes when only a few subnets in a large configuration
+// have been modified. Unlike the CommitNetworksIntoDB, it expects that the
+// associations of the daemon with the subnets and hosts have not been
+// deleted prior to the call. Returns a list of added subnets.
+func CommitChangedNetworksIntoDB(dbi dbops.DBI, networks []SharedNetwork, subnets []Subnet, daemon *Daemon, diff *keaconfig.SubnetsDiff) (addedSubnets []*Subnet, err error) {
+	if db, ok := dbi.(*pg.DB); ok {
+		err = db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+			addedSubnets, err = commitChangedNetworksIntoDB(tx, networks, subnets, daemon, diff)
+			return err
+		})
+		return
+	}
+	addedSubnets, err = commitChangedNetworksIntoDB(dbi.(*pg.Tx), networks, subnets, daemon, diff)
+	return
+}
+
+// Runs the same logic as CommitNetworksIntoDB but always rolls back the
+// transaction, so the database is not modified. It returns the subnets
+// which would be added to the database. The specified networks and subnets
+// are not modified. The IDs of the returned subnets and the IDs of the new
+// shared networks they belong to are reset to 0 because they are not valid
+// after the rollback.
+func DryRunCommitNetworksIntoDB(db *dbops.PgDB, networks []SharedNetwork, subnets []Subnet, daemon *Daemon) ([]*Subnet, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "problem starting transaction to preview committing networks")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Work on the copies because committing the networks sets the IDs
+	// of the networks, subnets and hosts.
+	networksCopy := make([]SharedNetwork, len(networks))
+	for i := range networks {
+		networksCopy[i] = networks[i]
+		networksCopy[i].Subnets = copySubnetsForCommit(networks[i].Subnets)
+	}
+	subnetsCopy := copySubnetsForCommit(subnets)
+
+	addedSubnets, err := commitNetworksIntoDB(tx, networksCopy, subnetsCopy, daemon)
+	if err != nil {
+		return nil, err
+	}
+
+	newNetworkIDs := make(map[int64]bool)
+	for i := range networks {
+		if networks[i].ID == 0 {
+			newNetworkIDs[networksCopy[i].ID] = true
+		}
+	}
+	for _, subnet := range addedSubnets {
+		subnet.ID = 0
+		if newNetworkIDs[subnet.SharedNetworkID] {
+			subnet.SharedNetworkID = 0
+		}
+	}
+	return addedSubnets, nil
+}
+
+// Returns a copy of the subnets including the copies of their hosts, so
+// the subnets can be committed to the database without modifying the
+// original ones. The slices of the pools, host identifiers, reservations
+// and local hosts are copied too because their IDs are set while committing.
+func copySubnetsForCommit(subnets []Subnet) []Subnet {
+	subnetsCopy := make([]Subnet, len(subnets))
+	for i := range subnets {
+		subnetsCopy[i] = subnets[i]
+		subnetsCopy[i].AddressPools = append([]AddressPool{}, subnets[i].AddressPools...)
+		subnetsCopy[i].PrefixPools = append([]PrefixPool{}, subnets[i].PrefixPools...)
+		subnetsCopy[i].Hosts = make([]Host, len(subnets[i].Hosts))
+		for j, host := range subnets[i].Hosts {
+			host.HostIdentifiers = append([]HostIdentifier{}, host.HostIdentifiers...)
+			host.IPReservations = append([]IPReservation{}, host.IPReservations...)
+			host.LocalHosts = append([]LocalHost{}, host.LocalHosts...)
+			subnetsCopy[i].Hosts[j] = host
+		}
+	}
+	return subnetsCopy
+}
+
 // Fetch all local subnets for indicated app.
 func GetAppLocalSubnets(dbi dbops.DBI, appID int64) ([]*LocalSubnet, error) {
 	subnets := []*LocalSubnet{}
@@ -672,6 +1081,34 @@ func GetAppLocalSubnets(dbi dbops.DBI, appID int64) ([]*LocalSubnet, error) {
 	return subnets, nil
 }
 
+// Fetches the local subnets of the indicated daemon having the local
+// subnet ID of 0. It happens when Stork was unable to match the subnet
+// prefix with any subnet in the daemon's configuration. Such associations
+// break the correlation of the statistics returned by Kea with the subnets.
+func GetLocalSubnetsWithoutKeaID(dbi dbops.DBI, daemonID int64) ([]*LocalSubnet, error) {
+	subnets := []*LocalSubnet{}
+	q := dbi.Model(&subnets)
+	// only selected columns are returned while stats columns are skipped for performance reasons (they are pretty big json fields)
+	q = q.Column("daemon_id", "subnet_id", "local_subnet_id")
+	q = q.Relation("Subnet")
+	q = q.Where("local_subnet.daemon_id = ?", daemonID)
+	q = q.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+		return q.WhereOr("local_subnet.local_subnet_id = 0").
+			WhereOr("local_subnet.local_subnet_id IS NULL"), nil
+	})
+	q = q.OrderExpr("local_subnet.subnet_id ASC")
+
+	err := q.Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem getting local subnets without Kea subnet ID for daemon %d", daemonID)
+		return nil, err
+	}
+	return subnets, nil
+}
+
 // Update stats pulled for given local subnet.
 func (lsn *LocalSubnet) UpdateStats(dbi dbops.DBI, stats SubnetStats) error {
 	lsn.Stats = stats
@@ -711,6 +1148,22 @@ func (s *Subnet) UpdateStatistics(dbi dbops.DBI, statistics utilizationStats) er
 	return err
 }
 
+// Sets the free-text note of the subnet. An empty note removes the
+// existing note.
+func SetSubnetNote(dbi dbops.DBI, subnetID int64, note string) error {
+	subnet := &Subnet{
+		ID:   subnetID,
+		Note: note,
+	}
+	result, err := dbi.Model(subnet).Column("note").WherePK().Update()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem setting the note of the subnet with ID %d", subnetID)
+	} else if result.RowsAffected() <= 0 {
+		err = pkgerrors.Wrapf(ErrNotExists, "subnet with ID %d does not exist", subnetID)
+	}
+	return err
+}
+
 // Deletes subnets which are not associated with any apps. Returns deleted subnet
 // count and an error.
 func DeleteOrphanedSubnets(dbi dbops.DBI) (int64, error) {
@@ -727,3 +1180,81 @@ func DeleteOrphanedSubnets(dbi dbops.DBI) (int64, error) {
 	}
 	return int64(result.RowsAffected()), nil
 }
+
+// Converts the subnets associated with the Kea DHCP daemon, their pools and
+// the host reservations to the Kea configuration format. It returns a map
+// with a single subnet4 or subnet6 key holding the list of the subnets. It
+// can be pasted into the Dhcp4 or Dhcp6 configuration of another server.
+// The subnets are sorted by their IDs in Kea. The reservations include the
+// hosts from all data sources associated with the daemon.
+func ExportKeaSubnetsConfig(dbi dbops.DBI, daemon *Daemon) (map[string][]keaconfig.Subnet, error) {
+	var key string
+	switch daemon.Name {
+	case DaemonNameDHCPv4:
+		key = "subnet4"
+	case DaemonNameDHCPv6:
+		key = "subnet6"
+	default:
+		return nil, pkgerrors.Errorf("unsupported daemon %s", daemon.Name)
+	}
+
+	subnets, err := GetSubnetsByDaemonID(dbi, daemon.ID)
+	if err != nil {
+		return nil, err
+	}
+	hosts, _, err := GetHostsByDaemonID(dbi, daemon.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	hostsBySubnetID := make(map[int64][]Host)
+	for _, host := range hosts {
+		if host.SubnetID != 0 {
+			hostsBySubnetID[host.SubnetID] = append(hostsBySubnetID[host.SubnetID], host)
+		}
+	}
+
+	lookup := NewDHCPOptionDefinitionLookup()
+	keaSubnets := []keaconfig.Subnet{}
+	for _, subnet := range subnets {
+		keaSubnet := keaconfig.Subnet{
+			Subnet:      subnet.Prefix,
+			ClientClass: subnet.ClientClass,
+		}
+		for _, ls := range subnet.LocalSubnets {
+			if ls.DaemonID == daemon.ID {
+				keaSubnet.ID = ls.LocalSubnetID
+				break
+			}
+		}
+		for _, pool := range subnet.AddressPools {
+			keaSubnet.Pools = append(keaSubnet.Pools, keaconfig.Pool{
+				Pool: fmt.Sprintf("%s-%s", pool.LowerBound, pool.UpperBound),
+			})
+		}
+		for _, pool := range subnet.PrefixPools {
+			parsedPrefix := storkutil.ParseIP(pool.Prefix)
+			if parsedPrefix == nil {
+				return nil, pkgerrors.Errorf("invalid prefix pool %s in subnet %s", pool.Prefix, subnet.Prefix)
+			}
+			keaSubnet.PdPools = append(keaSubnet.PdPools, keaconfig.PdPool{
+				Prefix:       parsedPrefix.NetworkPrefix,
+				PrefixLen:    parsedPrefix.PrefixLength,
+				DelegatedLen: pool.DelegatedLen,
+			})
+		}
+		for _, host := range hostsBySubnetID[subnet.ID] {
+			reservation, err := keaconfig.CreateReservation(daemon.ID, lookup, host)
+			if err != nil {
+				return nil, pkgerrors.WithMessagef(err, "problem converting host reservation in subnet %s", subnet.Prefix)
+			}
+			keaSubnet.Reservations = append(keaSubnet.Reservations, *reservation)
+		}
+		keaSubnets = append(keaSubnets, keaSubnet)
+	}
+
+	sort.Slice(keaSubnets, func(i, j int) bool {
+		return keaSubnets[i].ID < keaSubnets[j].ID
+	})
+
+	return map[string][]keaconfig.Subnet{key: keaSubnets}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-pg/pg/v10"
 	"github.com/stretchr/testify/require"
 	keaconfig "isc.org/stork/appcfg/kea"
 	dbtest "isc.org/stork/server/database/test"
@@ -352,6 +353,49 @@ func TestAddDeleteAppToSubnet(t *testing.T) {
 	require.Len(t, returnedSubnet.LocalSubnets, 1)
 }
 
+// Test that the subnet parsed from the Kea configuration with a non-canonical
+// prefix is stored in the canonical form and the original prefix is preserved
+// in the association of the subnet with the daemon.
+func TestAddDaemonToSubnetNonCanonicalPrefix(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	apps := addTestSubnetApps(t, db)
+	require.Len(t, apps, 2)
+
+	rawSubnet := map[string]interface{}{
+		"id":     123,
+		"subnet": "192.0.2.1/24",
+	}
+	subnet, err := NewSubnetFromKea(&rawSubnet, apps[0].Daemons[0], HostDataSourceConfig, NewDHCPOptionDefinitionLookup())
+	require.NoError(t, err)
+	require.NoError(t, AddSubnet(db, subnet))
+
+	// Act
+	err = AddDaemonToSubnet(db, subnet, apps[0].Daemons[0])
+	require.NoError(t, err)
+	// The second daemon uses the canonical prefix.
+	subnet.OriginalPrefix = ""
+	err = AddDaemonToSubnet(db, subnet, apps[1].Daemons[0])
+	require.NoError(t, err)
+
+	// Assert
+	returnedSubnet, err := GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.NotNil(t, returnedSubnet)
+	require.Equal(t, "192.0.2.0/24", returnedSubnet.Prefix)
+	require.Empty(t, returnedSubnet.OriginalPrefix)
+	require.Len(t, returnedSubnet.LocalSubnets, 2)
+	for _, ls := range returnedSubnet.LocalSubnets {
+		if ls.DaemonID == apps[0].Daemons[0].ID {
+			require.Equal(t, "192.0.2.1/24", ls.OriginalPrefix)
+		} else {
+			require.Empty(t, ls.OriginalPrefix)
+		}
+	}
+}
+
 // Test that app's associations with multiple subnets can be removed.
 func TestDeleteAppFromSubnets(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -468,6 +512,57 @@ func TestGetSubnetsByDaemonID(t *testing.T) {
 	require.EqualValues(t, apps[1].Daemons[0].ID, returnedSubnets[0].LocalSubnets[0].DaemonID)
 }
 
+// Test that the subnets can be fetched by multiple prefixes in one query.
+func TestGetSubnetsByPrefixes(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	apps := addTestSubnetApps(t, db)
+	require.Len(t, apps, 2)
+
+	subnets := []Subnet{
+		{
+			Prefix: "192.0.2.0/24",
+		},
+		{
+			Prefix: "192.0.3.0/24",
+		},
+		{
+			Prefix: "10.0.0.0/8",
+		},
+	}
+	for i := range subnets {
+		err := AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+		require.NotZero(t, subnets[i].ID)
+
+		err = AddDaemonToSubnet(db, &subnets[i], apps[0].Daemons[0])
+		require.NoError(t, err)
+	}
+
+	// Get two existing subnets and one non-existing.
+	returnedSubnets, err := GetSubnetsByPrefixes(db, []string{"192.0.2.0/24", "10.0.0.0/8", "192.0.4.0/24"})
+	require.NoError(t, err)
+	require.Len(t, returnedSubnets, 2)
+
+	require.Contains(t, returnedSubnets, "192.0.2.0/24")
+	require.Len(t, returnedSubnets["192.0.2.0/24"], 1)
+	require.EqualValues(t, subnets[0].ID, returnedSubnets["192.0.2.0/24"][0].ID)
+	require.Len(t, returnedSubnets["192.0.2.0/24"][0].LocalSubnets, 1)
+
+	require.Contains(t, returnedSubnets, "10.0.0.0/8")
+	require.Len(t, returnedSubnets["10.0.0.0/8"], 1)
+	require.EqualValues(t, subnets[2].ID, returnedSubnets["10.0.0.0/8"][0].ID)
+
+	require.NotContains(t, returnedSubnets, "192.0.3.0/24")
+	require.NotContains(t, returnedSubnets, "192.0.4.0/24")
+
+	// An empty list of prefixes should result in an empty map.
+	returnedSubnets, err = GetSubnetsByPrefixes(db, []string{})
+	require.NoError(t, err)
+	require.Empty(t, returnedSubnets)
+}
+
 // This test verifies that subnets can be filtered by search text.
 // In particular, it verifies that matching with address pools works
 // as expected and that duplicates are eliminated from the result
@@ -517,7 +612,7 @@ func TestGetSubnetsByPage(t *testing.T) {
 
 	// This should match two subnets.
 	filterText := "192.0"
-	returned, count, err := GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, "prefix", SortDirDesc)
+	returned, count, err := GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, nil, "prefix", SortDirDesc)
 	require.NoError(t, err)
 	require.EqualValues(t, 2, count)
 	require.Len(t, returned, 2)
@@ -528,7 +623,7 @@ func TestGetSubnetsByPage(t *testing.T) {
 	// This should match multiple pools in the first subnet. However,
 	// only one record should be returned.
 	filterText = "192.0.2.1"
-	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, "prefix", SortDirDesc)
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, nil, "prefix", SortDirDesc)
 	require.NoError(t, err)
 	require.EqualValues(t, 1, count)
 	require.Len(t, returned, 1)
@@ -536,7 +631,94 @@ func TestGetSubnetsByPage(t *testing.T) {
 
 	// This should have no match.
 	filterText = "192.0.5.0"
-	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, "id", SortDirAsc)
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 4, &filterText, nil, "id", SortDirAsc)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Empty(t, returned)
+}
+
+// Test that the subnets can be filtered by the version of the daemons
+// serving them.
+func TestGetSubnetsByPageFilterByAppVersion(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	apps := addTestSubnetApps(t, db)
+	require.Len(t, apps, 2)
+
+	// The daemons run different Kea versions.
+	apps[0].Daemons[0].Version = "2.0.3"
+	err := UpdateDaemon(db, apps[0].Daemons[0])
+	require.NoError(t, err)
+	apps[1].Daemons[0].Version = "2.2.0"
+	err = UpdateDaemon(db, apps[1].Daemons[0])
+	require.NoError(t, err)
+
+	// The first subnet is served by both daemons, the second subnet
+	// by the first daemon and the third subnet by the second daemon.
+	subnets := []Subnet{
+		{
+			Prefix: "192.0.2.0/24",
+		},
+		{
+			Prefix: "192.0.3.0/24",
+		},
+		{
+			Prefix: "192.0.4.0/24",
+		},
+	}
+	for i := range subnets {
+		err = AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+		require.NotZero(t, subnets[i].ID)
+	}
+	require.NoError(t, AddDaemonToSubnet(db, &subnets[0], apps[0].Daemons[0]))
+	require.NoError(t, AddDaemonToSubnet(db, &subnets[0], apps[1].Daemons[0]))
+	require.NoError(t, AddDaemonToSubnet(db, &subnets[1], apps[0].Daemons[0]))
+	require.NoError(t, AddDaemonToSubnet(db, &subnets[2], apps[1].Daemons[0]))
+
+	// Exact match.
+	version := "2.0.3"
+	returned, count, err := GetSubnetsByPage(db, 0, 10, 0, 0, nil, &version, "prefix", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+	require.Len(t, returned, 2)
+	require.Equal(t, "192.0.2.0/24", returned[0].Prefix)
+	require.Equal(t, "192.0.3.0/24", returned[1].Prefix)
+
+	// Prefix match.
+	version = "2.2"
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 0, nil, &version, "prefix", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+	require.Len(t, returned, 2)
+	require.Equal(t, "192.0.2.0/24", returned[0].Prefix)
+	require.Equal(t, "192.0.4.0/24", returned[1].Prefix)
+	// All local subnets should be returned, not only the matching ones.
+	require.Len(t, returned[0].LocalSubnets, 2)
+
+	// The major version matches all subnets.
+	version = "2"
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 0, nil, &version, "prefix", SortDirAsc)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+	require.Len(t, returned, 3)
+
+	// The longer versions should not match.
+	version = "2.0.30"
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 0, nil, &version, "prefix", SortDirAsc)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Empty(t, returned)
+	version = "2.0.3.1"
+	returned, count, err = GetSubnetsByPage(db, 0, 10, 0, 0, nil, &version, "prefix", SortDirAsc)
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Empty(t, returned)
+
+	// Combine with the app filter.
+	version = "2.2"
+	returned, count, err = GetSubnetsByPage(db, 0, 10, apps[0].ID, 0, nil, &version, "prefix", SortDirAsc)
 	require.NoError(t, err)
 	require.Zero(t, count)
 	require.Empty(t, returned)
@@ -573,6 +755,51 @@ func TestGetAppLocalSubnets(t *testing.T) {
 	require.Equal(t, subnet.ID, subnets[0].Subnet.ID)
 }
 
+// Test that the local subnets lacking Kea subnet ID are returned for
+// a daemon.
+func TestGetLocalSubnetsWithoutKeaID(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	apps := addTestSubnetApps(t, db)
+	require.Len(t, apps, 2)
+
+	// This subnet is present in the daemon's configuration.
+	subnet := &Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	err := AddSubnet(db, subnet)
+	require.NoError(t, err)
+	err = AddDaemonToSubnet(db, subnet, apps[0].Daemons[0])
+	require.NoError(t, err)
+
+	// This subnet doesn't match any subnet in the daemon's configuration.
+	unmatchedSubnet := &Subnet{
+		Prefix: "192.0.5.0/24",
+	}
+	err = AddSubnet(db, unmatchedSubnet)
+	require.NoError(t, err)
+	err = AddDaemonToSubnet(db, unmatchedSubnet, apps[0].Daemons[0])
+	require.NoError(t, err)
+	err = AddDaemonToSubnet(db, unmatchedSubnet, apps[1].Daemons[0])
+	require.NoError(t, err)
+
+	// Only the unmatched subnet should be returned for the first daemon.
+	localSubnets, err := GetLocalSubnetsWithoutKeaID(db, apps[0].Daemons[0].ID)
+	require.NoError(t, err)
+	require.Len(t, localSubnets, 1)
+	require.Zero(t, localSubnets[0].LocalSubnetID)
+	require.EqualValues(t, apps[0].Daemons[0].ID, localSubnets[0].DaemonID)
+	require.NotNil(t, localSubnets[0].Subnet)
+	require.Equal(t, unmatchedSubnet.ID, localSubnets[0].Subnet.ID)
+	require.Equal(t, "192.0.5.0/24", localSubnets[0].Subnet.Prefix)
+
+	// Non-existing daemon.
+	localSubnets, err = GetLocalSubnetsWithoutKeaID(db, apps[1].Daemons[0].ID+1)
+	require.NoError(t, err)
+	require.Empty(t, localSubnets)
+}
+
 // Check updating stats in LocalSubnet.
 func TestUpdateStats(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -742,6 +969,235 @@ func TestCommitNetworksIntoDB(t *testing.T) {
 	require.Len(t, addedSubnets, 0)
 }
 
+// Test that only the added and changed subnets are committed and that the
+// daemon is detached from the removed and changed subnets and their hosts
+// while the associations with the unchanged subnets are preserved.
+func TestCommitChangedNetworksIntoDB(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	m := &Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := AddMachine(db, m)
+	require.NoError(t, err)
+
+	app := App{
+		MachineID: m.ID,
+		Type:      AppTypeKea,
+		Daemons: []*Daemon{
+			{
+				Name:   DaemonNameDHCPv4,
+				Active: true,
+			},
+		},
+	}
+	_, err = AddApp(db, &app)
+	require.NoError(t, err)
+	daemon := app.Daemons[0]
+
+	// Returns a subnet with a single host reservation.
+	newSubnet := func(prefix, address string) Subnet {
+		return Subnet{
+			Prefix: prefix,
+			Hosts: []Host{
+				{
+					HostIdentifiers: []HostIdentifier{
+						{
+							Type:  "hw-address",
+							Value: []byte{1, 2, 3, 4, 5, 6},
+						},
+					},
+					IPReservations: []IPReservation{
+						{
+							Address: address,
+						},
+					},
+					LocalHosts: []LocalHost{
+						{
+							DaemonID:   daemon.ID,
+							DataSource: HostDataSourceConfig,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	subnets := []Subnet{
+		newSubnet("192.0.2.0/24", "192.0.2.10/32"),
+		newSubnet("192.0.3.0/24", "192.0.3.10/32"),
+		newSubnet("192.0.4.0/24", "192.0.4.10/32"),
+	}
+	_, err = CommitNetworksIntoDB(db, []SharedNetwork{}, subnets, daemon)
+	require.NoError(t, err)
+
+	// The first subnet is unchanged, the host reservation in the second
+	// subnet has been modified, the third subnet has been removed and the
+	// fourth subnet has been added.
+	changedSubnet := newSubnet("192.0.3.0/24", "192.0.3.20/32")
+	changedSubnet.ID = subnets[1].ID
+	subnets = []Subnet{
+		subnets[0],
+		changedSubnet,
+		newSubnet("192.0.5.0/24", "192.0.5.10/32"),
+	}
+	diff := &keaconfig.SubnetsDiff{
+		Added:     []string{"192.0.5.0/24"},
+		Removed:   []string{"192.0.4.0/24"},
+		Changed:   []string{"192.0.3.0/24"},
+		Unchanged: []string{"192.0.2.0/24"},
+	}
+	addedSubnets, err := CommitChangedNetworksIntoDB(db, []SharedNetwork{}, subnets, daemon, diff)
+	require.NoError(t, err)
+	require.Len(t, addedSubnets, 1)
+	require.Equal(t, "192.0.5.0/24", addedSubnets[0].Prefix)
+
+	_, err = DeleteOrphanedHosts(db)
+	require.NoError(t, err)
+	_, err = DeleteOrphanedSubnets(db)
+	require.NoError(t, err)
+
+	daemonSubnets, err := GetSubnetsByDaemonID(db, daemon.ID)
+	require.NoError(t, err)
+	require.Len(t, daemonSubnets, 3)
+
+	expectedAddresses := map[string]string{
+		"192.0.2.0/24": "192.0.2.10/32",
+		"192.0.3.0/24": "192.0.3.20/32",
+		"192.0.5.0/24": "192.0.5.10/32",
+	}
+	for _, subnet := range daemonSubnets {
+		require.Contains(t, expectedAddresses, subnet.Prefix)
+		hosts, err := GetHostsBySubnetID(db, subnet.ID)
+		require.NoError(t, err)
+		require.Len(t, hosts, 1, subnet.Prefix)
+		require.Len(t, hosts[0].IPReservations, 1)
+		require.Equal(t, expectedAddresses[subnet.Prefix], hosts[0].IPReservations[0].Address)
+		require.Len(t, hosts[0].LocalHosts, 1)
+		require.EqualValues(t, daemon.ID, hosts[0].LocalHosts[0].DaemonID)
+	}
+
+	// The removed subnet has been deleted as an orphan.
+	removedSubnets, err := GetSubnetsByPrefix(db, "192.0.4.0/24")
+	require.NoError(t, err)
+	require.Empty(t, removedSubnets)
+}
+
+// Test that the dry run of committing the networks returns the subnets
+// which would be added but doesn't modify the database nor the specified
+// networks and subnets.
+func TestDryRunCommitNetworksIntoDB(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	m := &Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := AddMachine(db, m)
+	require.NoError(t, err)
+
+	app := App{
+		MachineID: m.ID,
+		Type:      AppTypeKea,
+		Daemons: []*Daemon{
+			{
+				Name:   DaemonNameDHCPv4,
+				Active: true,
+			},
+		},
+	}
+	_, err = AddApp(db, &app)
+	require.NoError(t, err)
+
+	networks := []SharedNetwork{
+		{
+			Name:   "foo",
+			Family: 4,
+			Subnets: []Subnet{
+				{
+					Prefix: "192.0.2.0/24",
+				},
+			},
+		},
+	}
+	subnets := []Subnet{
+		{
+			Prefix: "192.0.3.0/24",
+			AddressPools: []AddressPool{
+				{
+					LowerBound: "192.0.3.10",
+					UpperBound: "192.0.3.20",
+				},
+			},
+			Hosts: []Host{
+				{
+					HostIdentifiers: []HostIdentifier{
+						{
+							Type:  "hw-address",
+							Value: []byte{1, 2, 3, 4, 5, 6},
+						},
+					},
+					IPReservations: []IPReservation{
+						{
+							Address: "192.0.3.123/32",
+						},
+					},
+					LocalHosts: []LocalHost{
+						{
+							DaemonID:   app.Daemons[0].ID,
+							DataSource: HostDataSourceConfig,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Preview committing the networks.
+	addedSubnets, err := DryRunCommitNetworksIntoDB(db, networks, subnets, app.Daemons[0])
+	require.NoError(t, err)
+	require.Len(t, addedSubnets, 1)
+	require.Equal(t, "192.0.3.0/24", addedSubnets[0].Prefix)
+	require.Zero(t, addedSubnets[0].ID)
+
+	// Nothing should be added to the database.
+	returnedNetworks, err := GetAllSharedNetworks(db, 0)
+	require.NoError(t, err)
+	require.Empty(t, returnedNetworks)
+	returnedSubnets, err := GetAllSubnets(db, 0)
+	require.NoError(t, err)
+	require.Empty(t, returnedSubnets)
+	returnedHosts, err := GetAllHosts(db, 0)
+	require.NoError(t, err)
+	require.Empty(t, returnedHosts)
+
+	// The specified networks and subnets should be unchanged.
+	require.Zero(t, networks[0].ID)
+	require.Zero(t, networks[0].Subnets[0].ID)
+	require.Zero(t, subnets[0].ID)
+	require.Zero(t, subnets[0].AddressPools[0].SubnetID)
+	require.Zero(t, subnets[0].Hosts[0].ID)
+	require.Zero(t, subnets[0].Hosts[0].LocalHosts[0].HostID)
+
+	// The real commit should add the same subnets.
+	committedSubnets, err := CommitNetworksIntoDB(db, networks, subnets, app.Daemons[0])
+	require.NoError(t, err)
+	require.Len(t, committedSubnets, 1)
+	require.Equal(t, addedSubnets[0].Prefix, committedSubnets[0].Prefix)
+
+	// There is nothing new to add in the subsequent dry run.
+	addedSubnets, err = DryRunCommitNetworksIntoDB(db, networks, subnets, app.Daemons[0])
+	require.NoError(t, err)
+	require.Empty(t, addedSubnets)
+
+	returnedSubnets, err = GetAllSubnets(db, 0)
+	require.NoError(t, err)
+	require.Len(t, returnedSubnets, 2)
+}
+
 // Check if getting subnet family works.
 func TestGetSubnetFamily(t *testing.T) {
 	// create v4 subnet and check its family
@@ -823,6 +1279,307 @@ func TestUpdateUtilization(t *testing.T) {
 	require.InDelta(t, time.Now().UTC().Unix(), returnedSubnet2.StatsCollectedAt.Unix(), 10.0)
 }
 
+// Test that the subnets are ordered by the number of declined addresses
+// and that the family and limit are respected.
+func TestGetTopDeclinedSubnets(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	hugeDeclined, _ := new(big.Int).SetString("100000000000000000000", 10)
+	subnets := []struct {
+		prefix string
+		stats  SubnetStats
+	}{
+		{"192.0.2.0/24", SubnetStats{"declined-addresses": uint64(5)}},
+		{"192.0.3.0/24", SubnetStats{"declined-addresses": uint64(20)}},
+		{"192.0.4.0/24", SubnetStats{"declined-addresses": uint64(0)}},
+		{"192.0.5.0/24", nil},
+		{"192.0.6.0/24", SubnetStats{"declined-addresses": uint64(20)}},
+		{"2001:db8:1::/64", SubnetStats{"declined-nas": hugeDeclined}},
+		{"2001:db8:2::/64", SubnetStats{"declined-nas": uint64(1)}},
+	}
+	for _, s := range subnets {
+		subnet := &Subnet{
+			Prefix: s.prefix,
+		}
+		require.NoError(t, AddSubnet(db, subnet))
+		if s.stats != nil {
+			require.NoError(t, subnet.UpdateStatistics(db, newUtilizationStatsMock(0, 0, s.stats)))
+		}
+	}
+
+	// All families.
+	returned, err := GetTopDeclinedSubnets(db, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, returned, 5)
+	require.Equal(t, "2001:db8:1::/64", returned[0].Prefix)
+	require.Equal(t, "192.0.3.0/24", returned[1].Prefix)
+	require.Equal(t, "192.0.6.0/24", returned[2].Prefix)
+	require.Equal(t, "192.0.2.0/24", returned[3].Prefix)
+	require.Equal(t, "2001:db8:2::/64", returned[4].Prefix)
+
+	// IPv4 only with the limit.
+	returned, err = GetTopDeclinedSubnets(db, 4, 2)
+	require.NoError(t, err)
+	require.Len(t, returned, 2)
+	require.Equal(t, "192.0.3.0/24", returned[0].Prefix)
+	require.Equal(t, "192.0.6.0/24", returned[1].Prefix)
+
+	// IPv6 only.
+	returned, err = GetTopDeclinedSubnets(db, 6, 10)
+	require.NoError(t, err)
+	require.Len(t, returned, 2)
+	require.Equal(t, "2001:db8:1::/64", returned[0].Prefix)
+	require.Equal(t, "2001:db8:2::/64", returned[1].Prefix)
+}
+
+// Test that only the subnets for which all associated daemons are
+// inactive are returned.
+func TestGetSubnetsServedOnlyByInactiveDaemons(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// The daemons are inactive by default.
+	apps := addTestSubnetApps(t, db)
+	require.Len(t, apps, 2)
+	activeDaemon := apps[0].Daemons[0]
+	activeDaemon.Active = true
+	require.NoError(t, UpdateDaemon(db, activeDaemon))
+	inactiveDaemon := apps[1].Daemons[0]
+	require.False(t, inactiveDaemon.Active)
+
+	subnets := []*Subnet{
+		// Served by the active daemon only.
+		{Prefix: "192.0.2.0/24"},
+		// Served by both active and inactive daemons.
+		{Prefix: "192.0.3.0/24"},
+		// Served by the inactive daemon only.
+		{Prefix: "192.0.4.0/24"},
+		// Not associated with any daemons.
+		{Prefix: "192.0.5.0/24"},
+		// Served by the inactive daemon only.
+		{Prefix: "192.0.6.0/24"},
+	}
+	for _, subnet := range subnets {
+		require.NoError(t, AddSubnet(db, subnet))
+	}
+	require.NoError(t, AddDaemonToSubnet(db, subnets[0], activeDaemon))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[1], activeDaemon))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[1], inactiveDaemon))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[2], inactiveDaemon))
+	require.NoError(t, AddDaemonToSubnet(db, subnets[4], inactiveDaemon))
+
+	returned, err := GetSubnetsServedOnlyByInactiveDaemons(db)
+	require.NoError(t, err)
+	require.Len(t, returned, 2)
+	require.Equal(t, "192.0.4.0/24", returned[0].Prefix)
+	require.Equal(t, "192.0.6.0/24", returned[1].Prefix)
+	require.Len(t, returned[0].LocalSubnets, 1)
+	require.NotNil(t, returned[0].LocalSubnets[0].Daemon)
+	require.Equal(t, inactiveDaemon.ID, returned[0].LocalSubnets[0].Daemon.ID)
+
+	// Deactivating the daemon makes all its subnets returned.
+	activeDaemon.Active = false
+	require.NoError(t, UpdateDaemon(db, activeDaemon))
+	returned, err = GetSubnetsServedOnlyByInactiveDaemons(db)
+	require.NoError(t, err)
+	require.Len(t, returned, 4)
+	require.Equal(t, "192.0.2.0/24", returned[0].Prefix)
+	require.Equal(t, "192.0.3.0/24", returned[1].Prefix)
+	require.Equal(t, "192.0.4.0/24", returned[2].Prefix)
+	require.Equal(t, "192.0.6.0/24", returned[3].Prefix)
+}
+
+// Test that the number of declined addresses is extracted from the
+// statistics held as different integer types.
+func TestGetDeclinedAddresses(t *testing.T) {
+	hugeDeclined, _ := new(big.Int).SetString("100000000000000000000", 10)
+
+	require.Nil(t, (&Subnet{}).getDeclinedAddresses())
+	require.Nil(t, (&Subnet{Stats: SubnetStats{"total-addresses": uint64(10)}}).getDeclinedAddresses())
+	require.EqualValues(t, 10, (&Subnet{Stats: SubnetStats{"declined-addresses": uint64(10)}}).getDeclinedAddresses().Int64())
+	require.EqualValues(t, 11, (&Subnet{Stats: SubnetStats{"declined-addresses": int64(11)}}).getDeclinedAddresses().Int64())
+	require.EqualValues(t, 12, (&Subnet{Stats: SubnetStats{"declined-nas": float64(12)}}).getDeclinedAddresses().Int64())
+	require.Zero(t, hugeDeclined.Cmp((&Subnet{Stats: SubnetStats{"declined-nas": hugeDeclined}}).getDeclinedAddresses()))
+	require.Nil(t, (&Subnet{Stats: SubnetStats{"declined-nas": "foo"}}).getDeclinedAddresses())
+}
+
+// Test that only the subnets changed since the specified time are returned.
+func TestGetSubnetsChangedSince(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	subnets := []Subnet{
+		{
+			Prefix: "192.0.2.0/24",
+		},
+		{
+			Prefix: "192.0.3.0/24",
+		},
+		{
+			Prefix: "2001:db8:1::/64",
+		},
+	}
+	for i := range subnets {
+		err := AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+	}
+
+	// All subnets have been added recently.
+	returned, err := GetSubnetsChangedSince(db, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, returned, 3)
+
+	// Use the database clock to avoid the time skew.
+	var since time.Time
+	_, err = db.QueryOne(pg.Scan(&since), "SELECT now() AT TIME ZONE 'utc'")
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	// Nothing has changed yet.
+	returned, err = GetSubnetsChangedSince(db, since)
+	require.NoError(t, err)
+	require.Empty(t, returned)
+
+	// Update the statistics of one subnet.
+	err = subnets[1].UpdateStatistics(db, newUtilizationStatsMock(0.01, 0.02, SubnetStats{
+		"total-nas":    uint64(100),
+		"assigned-nas": uint64(1),
+	}))
+	require.NoError(t, err)
+
+	// Update the configuration of another subnet.
+	subnets[2].ClientClass = "foo"
+	_, err = db.Model(&subnets[2]).Column("client_class").WherePK().Update()
+	require.NoError(t, err)
+
+	returned, err = GetSubnetsChangedSince(db, since)
+	require.NoError(t, err)
+	require.Len(t, returned, 2)
+	require.Equal(t, subnets[1].ID, returned[0].ID)
+	require.Equal(t, subnets[2].ID, returned[1].ID)
+	require.True(t, returned[1].UpdatedAt.After(since))
+}
+
+// Test that the subnets with the utilization stored but the statistics
+// JSON empty (and vice versa) are detected.
+func TestGetSubnetsWithInconsistentStats(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	subnets := []Subnet{
+		{
+			Prefix: "192.0.2.0/24",
+		},
+		{
+			Prefix: "192.0.3.0/24",
+		},
+		{
+			Prefix: "192.0.4.0/24",
+		},
+		{
+			Prefix: "2001:db8:1::/64",
+		},
+		{
+			Prefix: "2001:db8:2::/64",
+		},
+	}
+	for i := range subnets {
+		err := AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+	}
+
+	// No statistics at all is consistent.
+	returned, err := GetSubnetsWithInconsistentStats(db)
+	require.NoError(t, err)
+	require.Empty(t, returned)
+
+	// Consistent statistics.
+	err = subnets[0].UpdateStatistics(db, newUtilizationStatsMock(0.01, 0.02, SubnetStats{
+		"total-nas":    uint64(100),
+		"assigned-nas": uint64(1),
+	}))
+	require.NoError(t, err)
+
+	// Zero utilization is consistent too.
+	err = subnets[1].UpdateStatistics(db, newUtilizationStatsMock(0, 0, SubnetStats{
+		"total-nas":    uint64(100),
+		"assigned-nas": uint64(0),
+	}))
+	require.NoError(t, err)
+
+	// Utilization stored but the statistics JSON is empty.
+	_, err = db.Exec("UPDATE subnet SET addr_utilization = 500, stats = NULL WHERE id = ?", subnets[2].ID)
+	require.NoError(t, err)
+
+	// Utilization stored but the statistics JSON is null.
+	_, err = db.Exec("UPDATE subnet SET pd_utilization = 300, stats = 'null'::jsonb WHERE id = ?", subnets[3].ID)
+	require.NoError(t, err)
+
+	// Statistics JSON stored without the utilization and the collection time.
+	_, err = db.Exec(`UPDATE subnet SET stats = '{"total-nas": 100}'::jsonb WHERE id = ?`, subnets[4].ID)
+	require.NoError(t, err)
+
+	returned, err = GetSubnetsWithInconsistentStats(db)
+	require.NoError(t, err)
+	require.Len(t, returned, 3)
+	require.Equal(t, subnets[2].ID, returned[0].ID)
+	require.Equal(t, subnets[3].ID, returned[1].ID)
+	require.Equal(t, subnets[4].ID, returned[2].ID)
+}
+
+// Test that the subnet note is set, returned with the subnet and removed.
+func TestSetSubnetNote(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	subnet := &Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	err := AddSubnet(db, subnet)
+	require.NoError(t, err)
+
+	returned, err := GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.Empty(t, returned.Note)
+
+	err = SetSubnetNote(db, subnet.ID, "reserved for VoIP")
+	require.NoError(t, err)
+
+	returned, err = GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.Equal(t, "reserved for VoIP", returned.Note)
+
+	// The note is returned with the subnets fetched by page.
+	subnets, _, err := GetSubnetsByPage(db, 0, 10, 0, 0, nil, nil, "", SortDirAny)
+	require.NoError(t, err)
+	require.Len(t, subnets, 1)
+	require.Equal(t, "reserved for VoIP", subnets[0].Note)
+
+	// Updating the statistics doesn't affect the note.
+	err = returned.UpdateStatistics(db, newUtilizationStatsMock(0.5, 0, SubnetStats{}))
+	require.NoError(t, err)
+	returned, err = GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.Equal(t, "reserved for VoIP", returned.Note)
+
+	// Remove the note.
+	err = SetSubnetNote(db, subnet.ID, "")
+	require.NoError(t, err)
+	returned, err = GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.Empty(t, returned.Note)
+}
+
+// Test that setting the note of a non-existing subnet returns an error.
+func TestSetSubnetNoteNonExisting(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	err := SetSubnetNote(db, 123, "migration pending")
+	require.ErrorIs(t, err, ErrNotExists)
+}
+
 // Test deleting subnets not assigned to any apps.
 func TestDeleteOrphanedSubnets(t *testing.T) {
 	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -1133,3 +1890,193 @@ func BenchmarkAddDaemonToSubnet(b *testing.B) {
 		})
 	}
 }
+
+// Test that the subnets, pools and host reservations of the daemon are
+// exported in the Kea configuration format and the result can be parsed
+// as Kea configuration.
+func TestExportKeaSubnetsConfig(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	machine := &Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := AddMachine(db, machine)
+	require.NoError(t, err)
+
+	daemon := NewKeaDaemon(DaemonNameDHCPv6, true)
+	err = daemon.SetConfigFromJSON(`{
+        "Dhcp6": {
+            "subnet6": [
+                {
+                    "id": 2,
+                    "subnet": "2001:db8:2::/64"
+                },
+                {
+                    "id": 1,
+                    "subnet": "2001:db8:1::/64"
+                }
+            ]
+        }
+    }`)
+	require.NoError(t, err)
+	app := &App{
+		MachineID: machine.ID,
+		Type:      AppTypeKea,
+		Daemons:   []*Daemon{daemon},
+	}
+	_, err = AddApp(db, app)
+	require.NoError(t, err)
+
+	subnets := []Subnet{
+		{
+			Prefix:      "2001:db8:2::/64",
+			ClientClass: "foo",
+		},
+		{
+			Prefix: "2001:db8:1::/64",
+			AddressPools: []AddressPool{
+				{
+					LowerBound: "2001:db8:1::10",
+					UpperBound: "2001:db8:1::20",
+				},
+			},
+			PrefixPools: []PrefixPool{
+				{
+					Prefix:       "3000::/48",
+					DelegatedLen: 64,
+				},
+			},
+		},
+	}
+	for i := range subnets {
+		err = AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+		err = AddDaemonToSubnet(db, &subnets[i], app.Daemons[0])
+		require.NoError(t, err)
+	}
+
+	host := &Host{
+		SubnetID: subnets[1].ID,
+		Hostname: "foo.example.org",
+		HostIdentifiers: []HostIdentifier{
+			{
+				Type:  "duid",
+				Value: []byte{1, 2, 3, 4},
+			},
+		},
+		IPReservations: []IPReservation{
+			{
+				Address: "2001:db8:1::100",
+			},
+			{
+				Address: "3000:0:0:1::/64",
+			},
+		},
+	}
+	err = AddHost(db, host)
+	require.NoError(t, err)
+	err = AddDaemonToHost(db, host, app.Daemons[0].ID, HostDataSourceAPI)
+	require.NoError(t, err)
+
+	exported, err := ExportKeaSubnetsConfig(db, app.Daemons[0])
+	require.NoError(t, err)
+	require.Contains(t, exported, "subnet6")
+
+	// The exported subnets should be accepted by the Kea configuration
+	// parser.
+	exportedJSON, err := json.Marshal(exported)
+	require.NoError(t, err)
+	config, err := NewKeaConfigFromJSON(fmt.Sprintf(`{"Dhcp6": %s}`, exportedJSON))
+	require.NoError(t, err)
+
+	var parsedSubnets []keaconfig.Subnet
+	err = config.DecodeTopLevelSubnets(&parsedSubnets)
+	require.NoError(t, err)
+	require.Len(t, parsedSubnets, 2)
+
+	require.EqualValues(t, 1, parsedSubnets[0].ID)
+	require.Equal(t, "2001:db8:1::/64", parsedSubnets[0].Subnet)
+	require.Len(t, parsedSubnets[0].Pools, 1)
+	require.Equal(t, "2001:db8:1::10-2001:db8:1::20", parsedSubnets[0].Pools[0].Pool)
+	require.Len(t, parsedSubnets[0].PdPools, 1)
+	require.Equal(t, "3000::", parsedSubnets[0].PdPools[0].Prefix)
+	require.Equal(t, 48, parsedSubnets[0].PdPools[0].PrefixLen)
+	require.Equal(t, 64, parsedSubnets[0].PdPools[0].DelegatedLen)
+	require.Len(t, parsedSubnets[0].Reservations, 1)
+	require.Equal(t, "01020304", parsedSubnets[0].Reservations[0].DUID)
+	require.Equal(t, "foo.example.org", parsedSubnets[0].Reservations[0].Hostname)
+	require.Equal(t, []string{"2001:db8:1::100"}, parsedSubnets[0].Reservations[0].IPAddresses)
+	require.Equal(t, []string{"3000:0:0:1::/64"}, parsedSubnets[0].Reservations[0].Prefixes)
+
+	require.EqualValues(t, 2, parsedSubnets[1].ID)
+	require.Equal(t, "2001:db8:2::/64", parsedSubnets[1].Subnet)
+	require.Equal(t, "foo", parsedSubnets[1].ClientClass)
+	require.Empty(t, parsedSubnets[1].Pools)
+	require.Empty(t, parsedSubnets[1].Reservations)
+}
+
+// Test that exporting the subnets of a daemon which is not a DHCP server
+// fails.
+func TestExportKeaSubnetsConfigUnsupportedDaemon(t *testing.T) {
+	_, err := ExportKeaSubnetsConfig(nil, NewKeaDaemon(DaemonNameCA, true))
+	require.ErrorContains(t, err, "unsupported daemon")
+}
+
+// Benchmark comparing the time to commit all subnets of a large configuration
+// with the time to commit only the subnets which have changed. In each
+// iteration a single subnet out of many is modified.
+func BenchmarkCommitChangedNetworksIntoDB(b *testing.B) {
+	testCases := []string{"full commit", "incremental commit"}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		b.Run(tc, func(b *testing.B) {
+			db, _, teardown := dbtest.SetupDatabaseTestCase(b)
+			defer teardown()
+
+			m := &Machine{
+				Address:   "localhost",
+				AgentPort: 8080,
+			}
+			_ = AddMachine(db, m)
+			app := App{
+				MachineID: m.ID,
+				Type:      AppTypeKea,
+				Daemons: []*Daemon{
+					NewKeaDaemon(DaemonNameDHCPv4, true),
+				},
+			}
+			_, _ = AddApp(db, &app)
+			daemon := app.Daemons[0]
+
+			subnets := []Subnet{}
+			for i := 0; i < 1000; i++ {
+				subnets = append(subnets, Subnet{
+					Prefix: fmt.Sprintf("10.%d.%d.0/24", uint8(i>>8), uint8(i)),
+				})
+			}
+			_, _ = CommitNetworksIntoDB(db, []SharedNetwork{}, subnets, daemon)
+
+			diff := &keaconfig.SubnetsDiff{
+				Changed: []string{subnets[0].Prefix},
+			}
+			for _, subnet := range subnets[1:] {
+				diff.Unchanged = append(diff.Unchanged, subnet.Prefix)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tx, _ := db.Begin()
+				if tc == "full commit" {
+					_, _ = DeleteDaemonFromSubnets(tx, daemon.ID)
+					_, _ = CommitNetworksIntoDB(tx, []SharedNetwork{}, subnets, daemon)
+				} else {
+					_, _ = CommitChangedNetworksIntoDB(tx, []SharedNetwork{}, subnets, daemon, diff)
+				}
+				_ = tx.Commit()
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package dbmodel
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	storkutil "isc.org/stork/util"
+)
+
+// Describes a pair of overlapping subnets. The parent subnet contains the
+// child subnet, or both subnets have the same prefix. The pointers refer to
+// the subnets in the slice passed to the overlaps detection function.
+type SubnetOverlap struct {
+	Parent *Subnet
+	Child  *Subnet
+}
+
+// Searches for prefix overlaps in the provided set of subnets. The
+// execution is stopped early if the specified number of found overlaps is
+// reached. The zero limit means no limit. The subnets with invalid prefixes
+// are ignored.
+func FindSubnetOverlaps(subnets []Subnet, limit int) []SubnetOverlap {
+	overlaps, _ := FindSubnetOverlapsWithinTimeBudget(subnets, limit, 0)
+	return overlaps
+}
+
+// Searches for prefix overlaps in the provided set of subnets like
+// FindSubnetOverlaps. Additionally, the detection is stopped when it takes
+// longer than the specified time budget. In this case, the overlaps found
+// so far are returned, and the second returned value is true. The zero time
+// budget means no limit.
+//
+// The subnets are compared using the binary representations of their
+// prefixes. The prefixes are sorted by length, and each prefix is compared
+// only with the longer ones. One prefix contains another if the binary
+// representation of the latter starts with the binary representation of
+// the former.
+func FindSubnetOverlapsWithinTimeBudget(subnets []Subnet, limit int, timeBudget time.Duration) (overlaps []SubnetOverlap, truncated bool) {
+	var deadline time.Time
+	if timeBudget > 0 {
+		deadline = time.Now().Add(timeBudget)
+	}
+
+	// Pair of the subnet and its binary prefix.
+	type subnetWithPrefix struct {
+		subnet       *Subnet
+		binaryPrefix string
+	}
+
+	// Calculates the binary prefixes for all subnets.
+	subnetPrefixes := make([]subnetWithPrefix, 0, len(subnets))
+
+	for i := range subnets {
+		// Calculating the binary prefixes is expensive for the huge number
+		// of subnets, so the time budget is checked here as well.
+		if !deadline.IsZero() && i%1024 == 1023 && time.Now().After(deadline) {
+			return nil, true
+		}
+		cidr := storkutil.ParseIP(subnets[i].Prefix)
+		if cidr == nil || !cidr.Prefix {
+			continue
+		}
+		subnetPrefixes = append(subnetPrefixes, subnetWithPrefix{
+			subnet:       &subnets[i],
+			binaryPrefix: cidr.GetNetworkPrefixAsBinary(),
+		})
+	}
+
+	// Sorts prefixes from the shortest (the most general masks) to the longest
+	// (the most specific masks).
+	sort.Slice(subnetPrefixes, func(i, j int) bool {
+		return len(subnetPrefixes[i].binaryPrefix) <= len(subnetPrefixes[j].binaryPrefix)
+	})
+
+	for outerIdx, outer := range subnetPrefixes {
+		// Checks if the time budget is exceeded. It is checked once per
+		// the outer prefix to avoid the overhead of fetching the time.
+		if !deadline.IsZero() && outerIdx > 0 && time.Now().After(deadline) {
+			return overlaps, true
+		}
+		for innerIdx, inner := range subnetPrefixes {
+			// The prefixes are sorted by length. The prefix length is equal to
+			// the subnet mask in bits. For a given prefix with length X, we
+			// need only check the prefixes with lengths equal to or greater
+			// than X. It means that we need to check only the following
+			// prefixes.
+			if outerIdx >= innerIdx {
+				continue
+			}
+
+			// Checks if the outer prefix contains the inner prefix. It happens
+			// when the inner prefix's binary representation starts with the
+			// outer prefix's binary representation.
+			if strings.HasPrefix(inner.binaryPrefix, outer.binaryPrefix) {
+				overlaps = append(overlaps, SubnetOverlap{
+					Parent: outer.subnet,
+					Child:  inner.subnet,
+				})
+
+				// Checks if the overlap limit is exceed.
+				if len(overlaps) == limit {
+					return overlaps, false
+				}
+			}
+		}
+	}
+	return overlaps, false
+}
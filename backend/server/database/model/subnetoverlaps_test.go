@@ -0,0 +1,310 @@
+package dbmodel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test that no overlaps are detected for empty subnet list.
+func TestFindSubnetOverlapsEmptySubnets(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Empty(t, overlaps)
+}
+
+// Test that no overlaps are detected for non-overlapping subnets.
+func TestFindSubnetOverlapsNonOverlappingSubnets(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/24"},
+		{ID: 2, Prefix: "192.168.1.0/24"},
+		{ID: 3, Prefix: "192.168.2.0/24"},
+		{ID: 4, Prefix: "192.168.3.0/24"},
+		{ID: 5, Prefix: "3001:0::/80"},
+		{ID: 6, Prefix: "3001:1::/80"},
+		{ID: 7, Prefix: "3001:2::/80"},
+		{ID: 8, Prefix: "3001:3::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Empty(t, overlaps)
+}
+
+// Test that duplicated prefixes are detected as overlaps.
+func TestFindSubnetOverlapsForDuplicates(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/24"},
+		{ID: 2, Prefix: "192.168.0.0/24"},
+		{ID: 5, Prefix: "3001:0::/80"},
+		{ID: 6, Prefix: "3001:0::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Len(t, overlaps, 2)
+	require.EqualValues(t, 2, overlaps[1].Parent.ID)
+	require.EqualValues(t, 1, overlaps[1].Child.ID)
+	require.EqualValues(t, 6, overlaps[0].Parent.ID)
+	require.EqualValues(t, 5, overlaps[0].Child.ID)
+}
+
+// Test that duplicated prefixes are detected as overlaps even if the prefix is
+// repeatedly duplicated.
+func TestFindSubnetOverlapsForMultipleDuplicates(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/24"},
+		{ID: 2, Prefix: "192.168.0.0/24"},
+		{ID: 3, Prefix: "192.168.0.0/24"},
+		{ID: 5, Prefix: "3001:0::/80"},
+		{ID: 6, Prefix: "3001:0::/80"},
+		{ID: 7, Prefix: "3001:0::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Len(t, overlaps, 6)
+	require.EqualValues(t, 2, overlaps[5].Parent.ID)
+	require.EqualValues(t, 1, overlaps[5].Child.ID)
+	require.EqualValues(t, 3, overlaps[4].Parent.ID)
+	require.EqualValues(t, 1, overlaps[4].Child.ID)
+	require.EqualValues(t, 3, overlaps[3].Parent.ID)
+	require.EqualValues(t, 2, overlaps[3].Child.ID)
+	require.EqualValues(t, 6, overlaps[2].Parent.ID)
+	require.EqualValues(t, 5, overlaps[2].Child.ID)
+	require.EqualValues(t, 7, overlaps[1].Parent.ID)
+	require.EqualValues(t, 5, overlaps[1].Child.ID)
+	require.EqualValues(t, 7, overlaps[0].Parent.ID)
+	require.EqualValues(t, 6, overlaps[0].Child.ID)
+}
+
+// Test that overlaps are detected for the same network but different prefix
+// lengths.
+func TestFindSubnetOverlapsForSameNetworkButDifferentPrefixLengths(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "192.168.0.0/24"},
+		{ID: 5, Prefix: "3001:0::/64"},
+		{ID: 6, Prefix: "3001:0::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Len(t, overlaps, 2)
+	require.EqualValues(t, 1, overlaps[1].Parent.ID)
+	require.EqualValues(t, 2, overlaps[1].Child.ID)
+	require.EqualValues(t, 5, overlaps[0].Parent.ID)
+	require.EqualValues(t, 6, overlaps[0].Child.ID)
+}
+
+// Test that overlaps are detected when one prefix is contained by another.
+func TestFindSubnetOverlapsForContainingPrefixes(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "192.168.5.0/24"},
+		{ID: 5, Prefix: "3001:0::/16"},
+		{ID: 6, Prefix: "3001:1::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 42)
+
+	// Assert
+	require.Len(t, overlaps, 2)
+	require.EqualValues(t, 1, overlaps[1].Parent.ID)
+	require.EqualValues(t, 2, overlaps[1].Child.ID)
+	require.EqualValues(t, 5, overlaps[0].Parent.ID)
+	require.EqualValues(t, 6, overlaps[0].Child.ID)
+}
+
+// Test that the searching for overlaps is stopped if the limit is exceeded on
+// duplicated subnets.
+func TestFindSubnetOverlapsExceedLimitOnDuplicatedSubnets(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "192.168.5.0/24"},
+		{ID: 3, Prefix: "192.68.5.0/24"},
+		{ID: 4, Prefix: "192.68.5.0/24"},
+		{ID: 5, Prefix: "3001:0::/16"},
+		{ID: 6, Prefix: "3001:1::/80"},
+		{ID: 7, Prefix: "2001:0::/16"},
+		{ID: 8, Prefix: "2001:0::/16"},
+		{ID: 9, Prefix: "4001:0::/16"},
+		{ID: 10, Prefix: "4001:0::/16"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 2)
+
+	// Assert
+	require.Len(t, overlaps, 2)
+	require.EqualValues(t, 5, overlaps[0].Parent.ID)
+	require.EqualValues(t, 6, overlaps[0].Child.ID)
+	require.EqualValues(t, 10, overlaps[1].Parent.ID)
+	require.EqualValues(t, 9, overlaps[1].Child.ID)
+}
+
+// Test that the searching for overlaps is stopped if the limit of overlapping
+// subnets is exceeded.
+func TestFindSubnetOverlapsExceedLimitOnContainingSubnets(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "192.168.5.0/24"},
+		{ID: 3, Prefix: "192.68.0.0/16"},
+		{ID: 4, Prefix: "192.68.5.0/24"},
+		{ID: 5, Prefix: "3001::/16"},
+		{ID: 6, Prefix: "3001:1::/80"},
+		{ID: 7, Prefix: "2001::/16"},
+		{ID: 8, Prefix: "2001:1::/80"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 2)
+
+	// Assert
+	require.Len(t, overlaps, 2)
+	require.EqualValues(t, 5, overlaps[0].Parent.ID)
+	require.EqualValues(t, 6, overlaps[0].Child.ID)
+	require.EqualValues(t, 7, overlaps[1].Parent.ID)
+	require.EqualValues(t, 8, overlaps[1].Child.ID)
+}
+
+// Test that the searching for overlaps is stopped if the time budget is
+// exceeded and the partial results are returned.
+func TestFindSubnetOverlapsExceedTimeBudget(t *testing.T) {
+	// Arrange
+	subnets := getOverlappingSubnets(8196, 0.01)
+
+	// Act
+	overlaps, truncated := FindSubnetOverlapsWithinTimeBudget(subnets, 1000, time.Nanosecond)
+
+	// Assert
+	require.True(t, truncated)
+	require.Less(t, len(overlaps), 1000)
+}
+
+// Test that the searching for overlaps is not truncated if it fits in the
+// time budget.
+func TestFindSubnetOverlapsWithinTimeBudget(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "192.168.5.0/24"},
+		{ID: 3, Prefix: "10.0.0.0/8"},
+	}
+
+	// Act
+	overlaps, truncated := FindSubnetOverlapsWithinTimeBudget(subnets, 10, time.Minute)
+
+	// Assert
+	require.False(t, truncated)
+	require.Len(t, overlaps, 1)
+}
+
+// Test that the subnets with invalid prefixes are ignored and the returned
+// overlaps point to the subnets in the input slice.
+func TestFindSubnetOverlapsInvalidPrefixes(t *testing.T) {
+	// Arrange
+	subnets := []Subnet{
+		{ID: 1, Prefix: "192.168.0.0/16"},
+		{ID: 2, Prefix: "foo"},
+		{ID: 3, Prefix: "192.168.5.1"},
+		{ID: 4, Prefix: "192.168.5.0/24"},
+	}
+
+	// Act
+	overlaps := FindSubnetOverlaps(subnets, 0)
+
+	// Assert
+	require.Len(t, overlaps, 1)
+	require.Same(t, &subnets[0], overlaps[0].Parent)
+	require.Same(t, &subnets[3], overlaps[0].Child)
+}
+
+// Generates subnets of which some have overlapping prefixes.
+// The overlapping factor must be in range from 0 (no overlaps) to 1 (100% overlaps).
+// Each overlapped subnet is contained in exactly one other subnet.
+func getOverlappingSubnets(n int, overlappingFactor float32) (subnets []Subnet) {
+	overlappingStep := int(float32(n) * overlappingFactor)
+
+	for i := 0; i < n; i++ {
+		id := int64(i + 1)
+		index := i
+		mask := 24
+
+		if overlappingFactor != 0. && i%overlappingStep == 1 {
+			index--
+			mask++
+		}
+
+		part4 := 0
+		part3 := index % 256
+		part2 := (index / 256) % 256
+		part1 := (index / (256 * 256)) % 256
+
+		prefix := fmt.Sprintf("%d.%d.%d.%d/%d", part1, part2, part3, part4, mask)
+
+		subnet := Subnet{
+			ID:     id,
+			Prefix: prefix,
+		}
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets
+}
+
+// Measures the performance of the overlapping prefixes detection based on the
+// binary prefixes without using the radix tree.
+// The possible solutions were discussed in this thread:
+// https://gitlab.isc.org/isc-projects/stork/-/merge_requests/474#note_305555
+func BenchmarkOverlapsBinaryPrefixesOnly(b *testing.B) {
+	numberOfSubnets := 8196
+	overlappingFactor := float32(0.01)
+	maximumOverlaps := 10
+
+	subnets := getOverlappingSubnets(numberOfSubnets, overlappingFactor)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = FindSubnetOverlaps(subnets, maximumOverlaps)
+	}
+}
+
+// Measures the performance of the overlapping prefixes detection for the
+// huge number of subnets limited by the time budget.
+func BenchmarkOverlapsTimeBudget(b *testing.B) {
+	numberOfSubnets := 200000
+	overlappingFactor := float32(0.01)
+	maximumOverlaps := 10
+	timeBudget := 10 * time.Millisecond
+
+	subnets := getOverlappingSubnets(numberOfSubnets, overlappingFactor)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = FindSubnetOverlapsWithinTimeBudget(subnets, maximumOverlaps, timeBudget)
+	}
+}
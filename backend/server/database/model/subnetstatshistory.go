@@ -0,0 +1,148 @@
+package dbmodel
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	pkgerrors "github.com/pkg/errors"
+	dbops "isc.org/stork/server/database"
+)
+
+// Direction in which a statistic changes over time.
+type StatsTrend string
+
+// Supported statistic trends.
+const (
+	StatsTrendIncreasing StatsTrend = "increasing"
+	StatsTrendStable     StatsTrend = "stable"
+	StatsTrendDecreasing StatsTrend = "decreasing"
+)
+
+// Holds the statistics of a subnet collected at a given time. A set of
+// the entries makes the history of the subnet statistics.
+type SubnetStatsHistory struct {
+	ID          int64
+	SubnetID    int64
+	CollectedAt time.Time
+	Stats       SubnetStats
+}
+
+// Inserts the subnet statistics history entries into the database. If the
+// collection time is not specified, the database sets the current time.
+func AddSubnetStatsHistory(dbi dbops.DBI, entries []*SubnetStatsHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := dbi.Model(&entries).Insert()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem inserting statistics history for subnet %d",
+			entries[0].SubnetID)
+	}
+	return err
+}
+
+// Fetches the statistics history of the subnet ordered by the collection
+// time.
+func GetSubnetStatsHistory(dbi dbops.DBI, subnetID int64) ([]SubnetStatsHistory, error) {
+	entries := []SubnetStatsHistory{}
+	err := dbi.Model(&entries).
+		Where("subnet_stats_history.subnet_id = ?", subnetID).
+		OrderExpr("subnet_stats_history.collected_at ASC").
+		OrderExpr("subnet_stats_history.id ASC").
+		Select()
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return []SubnetStatsHistory{}, nil
+		}
+		err = pkgerrors.Wrapf(err, "problem selecting statistics history for subnet %d", subnetID)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Deletes the statistics history of the subnets which no longer exist.
+// The history is not deleted together with the subnets, so this function
+// should be called after deleting the subnets to reclaim the space. It
+// returns the number of the deleted history entries. Calling it again
+// has no effect until more subnets are deleted.
+func PurgeStatsForDeletedSubnets(dbi dbops.DBI) (int64, error) {
+	result, err := dbi.Model(&[]SubnetStatsHistory{}).
+		Where("NOT EXISTS (SELECT 1 FROM subnet WHERE subnet.id = subnet_stats_history.subnet_id)").
+		Delete()
+	if err != nil {
+		err = pkgerrors.Wrapf(err, "problem purging statistics history of the deleted subnets")
+		return 0, err
+	}
+	return int64(result.RowsAffected()), nil
+}
+
+// Returns the trend of the declined addresses in the subnet over the
+// specified time window ending now. It allows for distinguishing a one-off
+// spike of the declined addresses from a persistent problem. The trend is
+// derived from the slope of the least squares line fitted to the history
+// samples collected in the window. The change of less than one address
+// over the window is considered stable. The trend is also stable if there
+// are fewer than two samples with the declined addresses.
+func GetDeclinedTrend(dbi dbops.DBI, subnetID int64, window time.Duration) (StatsTrend, error) {
+	entries := []SubnetStatsHistory{}
+	err := dbi.Model(&entries).
+		Where("subnet_stats_history.subnet_id = ?", subnetID).
+		Where("subnet_stats_history.collected_at >= ?", time.Now().UTC().Add(-window)).
+		OrderExpr("subnet_stats_history.collected_at ASC").
+		OrderExpr("subnet_stats_history.id ASC").
+		Select()
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		err = pkgerrors.Wrapf(err, "problem selecting statistics history for subnet %d", subnetID)
+		return "", err
+	}
+	return getDeclinedTrend(entries, window), nil
+}
+
+// Computes the trend of the declined addresses from the history samples.
+func getDeclinedTrend(entries []SubnetStatsHistory, window time.Duration) StatsTrend {
+	var times, values []float64
+	for _, entry := range entries {
+		declined := entry.Stats.getDeclinedAddresses()
+		if declined == nil {
+			continue
+		}
+		value, _ := new(big.Float).SetInt(declined).Float64()
+		times = append(times, entry.CollectedAt.Sub(entries[0].CollectedAt).Seconds())
+		values = append(values, value)
+	}
+	if len(values) < 2 {
+		return StatsTrendStable
+	}
+
+	var meanTime, meanValue float64
+	for i := range values {
+		meanTime += times[i]
+		meanValue += values[i]
+	}
+	meanTime /= float64(len(values))
+	meanValue /= float64(len(values))
+
+	var covariance, variance float64
+	for i := range values {
+		covariance += (times[i] - meanTime) * (values[i] - meanValue)
+		variance += (times[i] - meanTime) * (times[i] - meanTime)
+	}
+	if variance == 0 {
+		// All samples have been collected at the same time.
+		return StatsTrendStable
+	}
+
+	// The slope is the number of declined addresses per second.
+	change := covariance / variance * window.Seconds()
+	switch {
+	case math.Abs(change) < 1:
+		return StatsTrendStable
+	case change > 0:
+		return StatsTrendIncreasing
+	default:
+		return StatsTrendDecreasing
+	}
+}
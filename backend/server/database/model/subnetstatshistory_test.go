@@ -0,0 +1,181 @@
+package dbmodel
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the statistics history is added and fetched in the order of
+// the collection time.
+func TestSubnetStatsHistory(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	subnet := &Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	require.NoError(t, AddSubnet(db, subnet))
+
+	err := AddSubnetStatsHistory(db, []*SubnetStatsHistory{
+		{
+			SubnetID:    subnet.ID,
+			CollectedAt: time.Date(2022, 1, 11, 10, 0, 0, 0, time.UTC),
+			Stats:       SubnetStats{"assigned-addresses": 20.0},
+		},
+		{
+			SubnetID:    subnet.ID,
+			CollectedAt: time.Date(2022, 1, 10, 10, 0, 0, 0, time.UTC),
+			Stats:       SubnetStats{"assigned-addresses": 10.0},
+		},
+	})
+	require.NoError(t, err)
+
+	history, err := GetSubnetStatsHistory(db, subnet.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.EqualValues(t, 10, history[0].Stats["assigned-addresses"])
+	require.EqualValues(t, 20, history[1].Stats["assigned-addresses"])
+
+	history, err = GetSubnetStatsHistory(db, subnet.ID+1)
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
+// Test that the statistics history of the deleted subnets is purged
+// and the history of the existing subnets is preserved.
+func TestPurgeStatsForDeletedSubnets(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	apps := addTestSubnetApps(t, db)
+
+	subnets := []*Subnet{
+		{Prefix: "192.0.2.0/24"},
+		{Prefix: "192.0.3.0/24"},
+	}
+	for _, subnet := range subnets {
+		require.NoError(t, AddSubnet(db, subnet))
+		err := AddSubnetStatsHistory(db, []*SubnetStatsHistory{
+			{SubnetID: subnet.ID, Stats: SubnetStats{"total-addresses": 256.0}},
+			{SubnetID: subnet.ID, Stats: SubnetStats{"total-addresses": 256.0}},
+		})
+		require.NoError(t, err)
+	}
+	// Only the first subnet is served by a daemon, so the second one
+	// is deleted as orphaned.
+	require.NoError(t, AddDaemonToSubnet(db, subnets[0], apps[0].Daemons[0]))
+	count, err := DeleteOrphanedSubnets(db)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	// The history of the deleted subnet outlives it.
+	history, err := GetSubnetStatsHistory(db, subnets[1].ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Purge the history.
+	count, err = PurgeStatsForDeletedSubnets(db)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	history, err = GetSubnetStatsHistory(db, subnets[1].ID)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	history, err = GetSubnetStatsHistory(db, subnets[0].ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Purging again has no effect.
+	count, err = PurgeStatsForDeletedSubnets(db)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+// Creates the statistics history samples with the declined addresses
+// collected every hour starting at the specified time.
+func newDeclinedHistory(start time.Time, key string, declined ...int64) []SubnetStatsHistory {
+	var entries []SubnetStatsHistory
+	for i, value := range declined {
+		entries = append(entries, SubnetStatsHistory{
+			CollectedAt: start.Add(time.Duration(i) * time.Hour),
+			Stats:       SubnetStats{key: big.NewInt(value)},
+		})
+	}
+	return entries
+}
+
+// Test that the trend of the declined addresses is computed from the
+// slope over the samples.
+func TestGetDeclinedTrendFromSamples(t *testing.T) {
+	start := time.Date(2022, 1, 10, 10, 0, 0, 0, time.UTC)
+	window := 24 * time.Hour
+
+	require.Equal(t, StatsTrendIncreasing,
+		getDeclinedTrend(newDeclinedHistory(start, "declined-addresses", 1, 3, 4, 8, 10), window))
+	require.Equal(t, StatsTrendDecreasing,
+		getDeclinedTrend(newDeclinedHistory(start, "declined-nas", 10, 8, 8, 5, 1), window))
+	require.Equal(t, StatsTrendStable,
+		getDeclinedTrend(newDeclinedHistory(start, "declined-addresses", 5, 5, 5, 5), window))
+	// The one-off spike is not a persistent problem.
+	require.Equal(t, StatsTrendStable,
+		getDeclinedTrend(newDeclinedHistory(start, "declined-addresses", 0, 0, 20, 0, 0), window))
+	// Not enough samples.
+	require.Equal(t, StatsTrendStable,
+		getDeclinedTrend(newDeclinedHistory(start, "declined-addresses", 100), window))
+	require.Equal(t, StatsTrendStable,
+		getDeclinedTrend(newDeclinedHistory(start, "assigned-addresses", 1, 2, 3), window))
+	require.Equal(t, StatsTrendStable, getDeclinedTrend(nil, window))
+}
+
+// Test that the trend of the declined addresses is computed from the
+// statistics history samples collected in the time window.
+func TestGetDeclinedTrend(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	increasing := &Subnet{Prefix: "192.0.2.0/24"}
+	require.NoError(t, AddSubnet(db, increasing))
+	flat := &Subnet{Prefix: "192.0.3.0/24"}
+	require.NoError(t, AddSubnet(db, flat))
+
+	start := time.Now().UTC().Add(-5 * time.Hour)
+	var entries []*SubnetStatsHistory
+	for i, declined := range []int64{2, 4, 6, 8, 10} {
+		entries = append(entries, &SubnetStatsHistory{
+			SubnetID:    increasing.ID,
+			CollectedAt: start.Add(time.Duration(i) * time.Hour),
+			Stats:       SubnetStats{"declined-addresses": big.NewInt(declined)},
+		})
+		entries = append(entries, &SubnetStatsHistory{
+			SubnetID:    flat.ID,
+			CollectedAt: start.Add(time.Duration(i) * time.Hour),
+			Stats:       SubnetStats{"declined-addresses": big.NewInt(7)},
+		})
+	}
+	// The old sample outside of the window is not taken into account.
+	entries = append(entries, &SubnetStatsHistory{
+		SubnetID:    flat.ID,
+		CollectedAt: start.Add(-48 * time.Hour),
+		Stats:       SubnetStats{"declined-addresses": big.NewInt(100)},
+	})
+	require.NoError(t, AddSubnetStatsHistory(db, entries))
+
+	// Act
+	increasingTrend, increasingErr := GetDeclinedTrend(db, increasing.ID, 24*time.Hour)
+	flatTrend, flatErr := GetDeclinedTrend(db, flat.ID, 24*time.Hour)
+	noHistoryTrend, noHistoryErr := GetDeclinedTrend(db, flat.ID+1, 24*time.Hour)
+
+	// Assert
+	require.NoError(t, increasingErr)
+	require.Equal(t, StatsTrendIncreasing, increasingTrend)
+	require.NoError(t, flatErr)
+	require.Equal(t, StatsTrendStable, flatTrend)
+	require.NoError(t, noHistoryErr)
+	require.Equal(t, StatsTrendStable, noHistoryTrend)
+}
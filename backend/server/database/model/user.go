@@ -275,6 +275,20 @@ func GetUserByID(db *dbops.PgDB, id int) (*SystemUser, error) {
 	return user, err
 }
 
+// Fetches a user with a given login from the database. If the user does
+// not exist the nil value is returned. The user is returned along with the
+// list of groups it belongs to.
+func GetUserByLogin(db *dbops.PgDB, login string) (*SystemUser, error) {
+	user := &SystemUser{}
+	err := db.Model(user).Relation("Groups").Where("login = ?", login).First()
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, pkgerrors.Wrapf(err, "problem fetching user %s from the database", login)
+	}
+	return user, err
+}
+
 // Associates a user with a group. Currently only insertion by group id is supported.
 func (user *SystemUser) AddToGroupByID(db *dbops.PgDB, group *SystemGroup) (added bool, err error) {
 	if group.ID > 0 {
@@ -364,6 +364,38 @@ func TestGetUserByID(t *testing.T) {
 	require.Nil(t, user)
 }
 
+// Tests that user can be fetched by login.
+func TestGetUserByLogin(t *testing.T) {
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	user := &SystemUser{
+		Login:    "jdoe",
+		Email:    "jdoe@example.org",
+		Lastname: "Doe",
+		Name:     "John",
+		Password: "pass",
+		Groups: []*SystemGroup{
+			{
+				ID: AdminGroupID,
+			},
+		},
+	}
+	_, err := CreateUser(db, user)
+	require.NoError(t, err)
+
+	returned, err := GetUserByLogin(db, "jdoe")
+	require.NoError(t, err)
+	require.NotNil(t, returned)
+	require.Equal(t, user.ID, returned.ID)
+	require.Len(t, returned.Groups, 1)
+	require.Equal(t, AdminGroupID, returned.Groups[0].ID)
+
+	returned, err = GetUserByLogin(db, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, returned)
+}
+
 // Test that user associations with groups are created when the user
 // is created or updated.
 func TestUserGroups(t *testing.T) {
@@ -50,6 +50,7 @@ func (s *SessionMgr) LoginHandler(ctx context.Context, user *dbmodel.SystemUser)
 	}
 
 	s.scsSessionMgr.Put(ctx, "userID", user.ID)
+	s.scsSessionMgr.Remove(ctx, "2fa_verified")
 	s.scsSessionMgr.Put(ctx, "userLogin", user.Login)
 	s.scsSessionMgr.Put(ctx, "userEmail", user.Email)
 	s.scsSessionMgr.Put(ctx, "userLastname", user.Lastname)
@@ -127,6 +128,20 @@ func (s *SessionMgr) Logged(ctx context.Context) (ok bool, user *dbmodel.SystemU
 	return true, user
 }
 
+// Marks the session as verified (or not verified) with a second
+// authentication factor. The flag is cleared when the user logs in, so
+// the verification must be repeated for each new session.
+func (s *SessionMgr) SetSecondFactorVerified(ctx context.Context, verified bool) {
+	s.scsSessionMgr.Put(ctx, "2fa_verified", verified)
+}
+
+// Checks if the user holding the session has been verified with a second
+// authentication factor. It is assumed that the session data is already
+// fetched from the database and is stored in the request context.
+func (s *SessionMgr) IsSecondFactorVerified(ctx context.Context) bool {
+	return s.scsSessionMgr.GetBool(ctx, "2fa_verified")
+}
+
 // This function is only for testing purposes to prepare request context.
 func (s *SessionMgr) Load(ctx context.Context, token string) (context.Context, error) {
 	ctx2, err := s.scsSessionMgr.Load(ctx, token)
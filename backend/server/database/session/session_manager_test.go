@@ -104,3 +104,32 @@ func TestLoad(t *testing.T) {
 	_, err = mgr.Load(ctx, "")
 	require.NoError(t, err)
 }
+
+// Test that the second factor verification flag can be set in the session
+// and that it is cleared upon login.
+func TestSecondFactorVerified(t *testing.T) {
+	// Reset database schema.
+	_, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	mgr, err := NewSessionMgr(&dbSettings.BaseDatabaseSettings)
+	require.NoError(t, err)
+
+	ctx, err := mgr.Load(context.Background(), "")
+	require.NoError(t, err)
+
+	// Not verified by default.
+	require.False(t, mgr.IsSecondFactorVerified(ctx))
+
+	mgr.SetSecondFactorVerified(ctx, true)
+	require.True(t, mgr.IsSecondFactorVerified(ctx))
+
+	mgr.SetSecondFactorVerified(ctx, false)
+	require.False(t, mgr.IsSecondFactorVerified(ctx))
+
+	// Logging in should reset the flag.
+	mgr.SetSecondFactorVerified(ctx, true)
+	err = mgr.LoginHandler(ctx, &dbmodel.SystemUser{ID: 1, Login: "johnw"})
+	require.NoError(t, err)
+	require.False(t, mgr.IsSecondFactorVerified(ctx))
+}
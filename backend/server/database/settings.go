@@ -1,13 +1,16 @@
 package dbops
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
+	pkgerrors "github.com/pkg/errors"
 
 	storkutil "isc.org/stork/util"
 )
@@ -29,7 +32,9 @@ type BaseDatabaseSettings struct {
 
 type DatabaseSettings struct {
 	BaseDatabaseSettings
-	TraceSQL string `long:"db-trace-queries" description:"enable tracing SQL queries: run (only run-time, without migrations), all (migrations and run-time), all is the default and covers both migrations and run-time." env:"STORK_DATABASE_TRACE" optional:"true" optional-value:"all"`
+	TraceSQL         string        `long:"db-trace-queries" description:"enable tracing SQL queries: run (only run-time, without migrations), all (migrations and run-time), all is the default and covers both migrations and run-time." env:"STORK_DATABASE_TRACE" optional:"true" optional-value:"all"`
+	ConnectTimeout   time.Duration `long:"db-connect-timeout" description:"the timeout for establishing a new database connection; zero means the default of 5 seconds" env:"STORK_DATABASE_CONNECT_TIMEOUT"`
+	StatementTimeout time.Duration `long:"db-statement-timeout" description:"the maximum execution time of a single SQL statement; zero means no limit" env:"STORK_DATABASE_STATEMENT_TIMEOUT"`
 }
 
 // Alias to pg.DB.
@@ -116,6 +121,16 @@ func (c *DatabaseSettings) PgParams() (*PgOptions, error) {
 		return nil, err
 	}
 	pgopts.TLSConfig = tlsConfig
+	pgopts.DialTimeout = c.ConnectTimeout
+	if c.StatementTimeout > 0 {
+		statementTimeout := c.StatementTimeout.Milliseconds()
+		// The statement timeout is a session parameter, so it must be set
+		// for each new connection in the pool.
+		pgopts.OnConnect = func(ctx context.Context, conn *pg.Conn) error {
+			_, err := conn.ExecContext(ctx, "SET statement_timeout = ?", statementTimeout)
+			return pkgerrors.Wrap(err, "problem setting the statement timeout")
+		}
+	}
 	return pgopts, nil
 }
 
@@ -0,0 +1,59 @@
+package dbtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+)
+
+// Test that the database dump contains the schema version and the
+// contents of the selected tables with the secrets redacted.
+func TestDump(t *testing.T) {
+	db, _, teardown := SetupDatabaseTestCase(t)
+	defer teardown()
+
+	machine := &dbmodel.Machine{
+		Address:    "localhost",
+		AgentPort:  8080,
+		AgentToken: "secret-agent-token",
+	}
+	require.NoError(t, dbmodel.AddMachine(db, machine))
+
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	_, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	require.NoError(t, dbops.Dump(db, &buffer))
+
+	var dump dbops.DatabaseDump
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &dump))
+
+	require.Equal(t, dbops.AvailableVersion(), dump.SchemaVersion)
+	require.Equal(t, expectedSchemaVersion, dump.DatabaseVersion)
+
+	require.Len(t, dump.Tables, 4)
+	require.Len(t, dump.Tables["machine"], 1)
+	require.EqualValues(t, machine.ID, dump.Tables["machine"][0]["id"])
+	require.Equal(t, "localhost", dump.Tables["machine"][0]["address"])
+	require.Equal(t, "REDACTED", dump.Tables["machine"][0]["agent_token"])
+	require.NotContains(t, buffer.String(), "secret-agent-token")
+
+	require.Len(t, dump.Tables["app"], 1)
+	require.Len(t, dump.Tables["daemon"], 1)
+	require.Equal(t, "dhcp4", dump.Tables["daemon"][0]["name"])
+
+	// Empty tables are included too.
+	require.NotNil(t, dump.Tables["subnet"])
+	require.Empty(t, dump.Tables["subnet"])
+}
@@ -15,7 +15,7 @@ import (
 
 // Current schema version. This value must be bumped up every
 // time the schema is updated.
-const expectedSchemaVersion int64 = 44
+const expectedSchemaVersion int64 = 54
 
 // Common function which tests a selected migration action.
 func testMigrateAction(t *testing.T, db *dbops.PgDB, expectedOldVersion, expectedNewVersion int64, action ...string) {
@@ -116,6 +116,65 @@ func TestCurrentVersion(t *testing.T) {
 	testCurrentVersion(t, db, 1)
 }
 
+// Test that the pending migrations are returned without modifying the
+// database schema.
+func TestPlanMigration(t *testing.T) {
+	db, _, teardown := SetupDatabaseTestCase(t)
+	defer teardown()
+
+	_ = dbops.Toss(db)
+
+	// The migrations table doesn't exist yet.
+	plan, err := dbops.PlanMigration(db, "up", "3")
+	require.NoError(t, err)
+	require.Equal(t, "up", plan.Direction)
+	require.Zero(t, plan.CurrentVersion)
+	require.EqualValues(t, 3, plan.TargetVersion)
+	require.Equal(t, []int64{1, 2, 3}, plan.Versions)
+	require.False(t, dbops.Initialized(db))
+
+	testMigrateAction(t, db, 0, 4, "up", "4")
+
+	// Up to the latest version.
+	plan, err = dbops.PlanMigration(db, "up")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, plan.CurrentVersion)
+	require.Equal(t, expectedSchemaVersion, plan.TargetVersion)
+	require.Len(t, plan.Versions, int(expectedSchemaVersion)-4)
+	require.EqualValues(t, 5, plan.Versions[0])
+	require.Equal(t, expectedSchemaVersion, plan.Versions[len(plan.Versions)-1])
+
+	// Down by one version.
+	plan, err = dbops.PlanMigration(db, "down")
+	require.NoError(t, err)
+	require.Equal(t, "down", plan.Direction)
+	require.EqualValues(t, 3, plan.TargetVersion)
+	require.Equal(t, []int64{4}, plan.Versions)
+
+	// Down to the specific version.
+	plan, err = dbops.PlanMigration(db, "down", "1")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, plan.TargetVersion)
+	require.Equal(t, []int64{4, 3, 2}, plan.Versions)
+
+	// Nothing to do.
+	plan, err = dbops.PlanMigration(db, "up", "4")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, plan.TargetVersion)
+	require.Empty(t, plan.Versions)
+
+	// The schema hasn't been modified.
+	testCurrentVersion(t, db, 4)
+
+	// Invalid arguments.
+	_, err = dbops.PlanMigration(db, "down", "4")
+	require.Error(t, err)
+	_, err = dbops.PlanMigration(db, "up", "foo")
+	require.Error(t, err)
+	_, err = dbops.PlanMigration(db, "reset")
+	require.Error(t, err)
+}
+
 // Test creating the server database and the user with access to
 // this database using generated password.
 func TestCreateDatabase(t *testing.T) {
@@ -2,7 +2,9 @@ package dbtest
 
 import (
 	"testing"
+	"time"
 
+	"github.com/go-pg/pg/v10"
 	"github.com/stretchr/testify/require"
 	dbops "isc.org/stork/server/database"
 	testutil "isc.org/stork/testutil"
@@ -141,3 +143,66 @@ func TestPgParamsWithWrongSSLModeSettings(t *testing.T) {
 	require.Nil(t, params)
 	require.Error(t, err)
 }
+
+// Test that the connect and statement timeouts are converted to the
+// go-pg options independently.
+func TestPgParamsTimeouts(t *testing.T) {
+	settings := dbops.DatabaseSettings{
+		BaseDatabaseSettings: dbops.BaseDatabaseSettings{
+			DBName: "stork",
+			User:   "admin",
+		},
+	}
+
+	// No timeouts by default.
+	params, err := settings.PgParams()
+	require.NoError(t, err)
+	require.Zero(t, params.DialTimeout)
+	require.Nil(t, params.OnConnect)
+
+	// Connect timeout only.
+	settings.ConnectTimeout = 3 * time.Second
+	params, err = settings.PgParams()
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, params.DialTimeout)
+	require.Nil(t, params.OnConnect)
+
+	// Statement timeout only.
+	settings.ConnectTimeout = 0
+	settings.StatementTimeout = time.Minute
+	params, err = settings.PgParams()
+	require.NoError(t, err)
+	require.Zero(t, params.DialTimeout)
+	require.NotNil(t, params.OnConnect)
+
+	// Both timeouts.
+	settings.ConnectTimeout = 3 * time.Second
+	params, err = settings.PgParams()
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, params.DialTimeout)
+	require.NotNil(t, params.OnConnect)
+}
+
+// Test that the statement timeout is set for the database connections.
+func TestStatementTimeout(t *testing.T) {
+	_, settings, teardown := SetupDatabaseTestCase(t)
+	defer teardown()
+
+	settings.ConnectTimeout = 3 * time.Second
+	settings.StatementTimeout = 1500 * time.Millisecond
+	params, err := settings.PgParams()
+	require.NoError(t, err)
+
+	db, err := dbops.NewPgDBConn(params, false)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var timeout string
+	_, err = db.QueryOne(pg.Scan(&timeout), "SHOW statement_timeout")
+	require.NoError(t, err)
+	require.Equal(t, "1500ms", timeout)
+
+	// The statement exceeding the timeout is canceled.
+	_, err = db.Exec("SELECT pg_sleep(3)")
+	require.ErrorContains(t, err, "statement timeout")
+}
@@ -0,0 +1,247 @@
+package hostimport
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/pkg/errors"
+	dbmodel "isc.org/stork/server/database/model"
+	storkutil "isc.org/stork/util"
+)
+
+// Number of columns in each row of the imported CSV file. The columns
+// are: subnet-prefix, identifier-type, identifier, ip-address, hostname.
+const columnsCount = 5
+
+// Supported host identifier types. They are the same as in Kea.
+var identifierTypes = map[string]bool{
+	"hw-address": true,
+	"duid":       true,
+	"circuit-id": true,
+	"client-id":  true,
+	"flex-id":    true,
+}
+
+// Error occurred while importing a single row of the CSV file.
+type RowError struct {
+	// Line number in the CSV file counted from 1.
+	Line int
+	Err  error
+}
+
+// Returns the error message including the line number.
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Unwraps the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Result of the host reservations import.
+type Result struct {
+	// Number of the successfully imported hosts.
+	Imported int
+	// Errors of the rows that were not imported.
+	RowErrors []*RowError
+}
+
+// Single row of the imported CSV file.
+type row struct {
+	subnetPrefix   string
+	identifierType string
+	identifier     string
+	ipAddress      string
+	hostname       string
+}
+
+// Imports the host reservations from the CSV file into the database.
+// Each row of the file must have the following columns: subnet-prefix,
+// identifier-type, identifier, ip-address and hostname. The ip-address
+// and hostname may be empty. The first row is skipped if it is a header
+// (begins with the subnet-prefix label). The identifier must be specified
+// as a string of hexadecimal digits, optionally separated with colons.
+// The subnet is resolved by prefix and the host is associated with all
+// daemons serving it. The hosts are marked as the ones added via the API.
+//
+// All hosts are imported in a single transaction. If the strict flag is
+// false, the invalid rows are skipped and returned in the result, and the
+// valid rows are committed. If the strict flag is true, the first invalid
+// row aborts the import, and no hosts are committed.
+func ImportHosts(db *pg.DB, reader io.Reader, strict bool) (*Result, error) {
+	result := &Result{}
+	err := db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		csvReader := csv.NewReader(reader)
+		csvReader.Comment = '#'
+		csvReader.TrimLeadingSpace = true
+		csvReader.FieldsPerRecord = -1
+
+		// Cache the subnets to avoid fetching them for each row.
+		subnets := make(map[string]*dbmodel.Subnet)
+
+		for first := true; ; first = false {
+			record, err := csvReader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			var line int
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+				err = parseErr.Err
+			} else if err == nil {
+				line, _ = csvReader.FieldPos(0)
+				if first && strings.TrimSpace(record[0]) == "subnet-prefix" {
+					continue
+				}
+				err = importRow(tx, record, subnets)
+			}
+			if err != nil {
+				rowErr := &RowError{Line: line, Err: err}
+				if strict {
+					return rowErr
+				}
+				result.RowErrors = append(result.RowErrors, rowErr)
+				continue
+			}
+			result.Imported++
+		}
+		return nil
+	})
+	if err != nil {
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			return nil, err
+		}
+		return nil, errors.WithMessage(err, "problem importing the hosts")
+	}
+	return result, nil
+}
+
+// Parses a CSV record and checks the column count.
+func parseRecord(record []string) (*row, error) {
+	if len(record) != columnsCount {
+		return nil, errors.Errorf("expected %d columns but got %d", columnsCount, len(record))
+	}
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	return &row{
+		subnetPrefix:   record[0],
+		identifierType: record[1],
+		identifier:     record[2],
+		ipAddress:      record[3],
+		hostname:       record[4],
+	}, nil
+}
+
+// Validates a single row and converts it to a host. The subnet must be
+// already fetched from the database.
+func newHost(r *row, subnet *dbmodel.Subnet) (*dbmodel.Host, error) {
+	if !identifierTypes[r.identifierType] {
+		return nil, errors.Errorf("unsupported identifier type %q", r.identifierType)
+	}
+	if !storkutil.IsHexIdentifier(r.identifier) {
+		return nil, errors.Errorf("invalid identifier %q", r.identifier)
+	}
+	identifier := storkutil.HexToBytes(strings.Join(strings.Fields(r.identifier), ""))
+
+	host := &dbmodel.Host{
+		SubnetID: subnet.ID,
+		Hostname: r.hostname,
+		HostIdentifiers: []dbmodel.HostIdentifier{
+			{
+				Type:  r.identifierType,
+				Value: identifier,
+			},
+		},
+	}
+
+	if r.ipAddress != "" {
+		ip := net.ParseIP(r.ipAddress)
+		if ip == nil {
+			return nil, errors.Errorf("invalid IP address %q", r.ipAddress)
+		}
+		_, prefix, err := net.ParseCIDR(subnet.Prefix)
+		if err != nil || !prefix.Contains(ip) {
+			return nil, errors.Errorf("IP address %s does not belong to the subnet %s", r.ipAddress, subnet.Prefix)
+		}
+		host.IPReservations = []dbmodel.IPReservation{
+			{
+				Address: r.ipAddress,
+			},
+		}
+	}
+	return host, nil
+}
+
+// Imports a single row into the database. The row is inserted within
+// a savepoint, so the failure does not abort the whole transaction.
+func importRow(tx *pg.Tx, record []string, subnets map[string]*dbmodel.Subnet) error {
+	r, err := parseRecord(record)
+	if err != nil {
+		return err
+	}
+	if r.subnetPrefix == "" {
+		return errors.New("missing subnet prefix")
+	}
+
+	subnet, ok := subnets[r.subnetPrefix]
+	if !ok {
+		found, err := dbmodel.GetSubnetsByPrefix(tx, r.subnetPrefix)
+		if err != nil {
+			return err
+		}
+		switch len(found) {
+		case 0:
+			return errors.Errorf("subnet %s not found", r.subnetPrefix)
+		case 1:
+			subnet = &found[0]
+		default:
+			return errors.Errorf("subnet %s is ambiguous because it is defined %d times", r.subnetPrefix, len(found))
+		}
+		subnets[r.subnetPrefix] = subnet
+	}
+	if len(subnet.LocalSubnets) == 0 {
+		return errors.Errorf("subnet %s is not served by any daemon", r.subnetPrefix)
+	}
+
+	host, err := newHost(r, subnet)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec("SAVEPOINT host_import_row"); err != nil {
+		return errors.Wrap(err, "problem creating the savepoint")
+	}
+	err = addHost(tx, host, subnet)
+	if err != nil {
+		if _, rollbackErr := tx.Exec("ROLLBACK TO SAVEPOINT host_import_row"); rollbackErr != nil {
+			return errors.Wrap(rollbackErr, "problem rolling back to the savepoint")
+		}
+		return err
+	}
+	if _, err = tx.Exec("RELEASE SAVEPOINT host_import_row"); err != nil {
+		return errors.Wrap(err, "problem releasing the savepoint")
+	}
+	return nil
+}
+
+// Adds the host and associates it with the daemons serving the subnet.
+func addHost(tx *pg.Tx, host *dbmodel.Host, subnet *dbmodel.Subnet) error {
+	if err := dbmodel.AddHost(tx, host); err != nil {
+		return err
+	}
+	for _, localSubnet := range subnet.LocalSubnets {
+		if err := dbmodel.AddDaemonToHost(tx, host, localSubnet.DaemonID, dbmodel.HostDataSourceAPI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package hostimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbops "isc.org/stork/server/database"
+	dbmodel "isc.org/stork/server/database/model"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Adds a Kea app with the DHCPv4 daemon and the subnets. The first subnet
+// is served by the daemon, and the second one is not served by any daemon.
+func addTestSubnets(t *testing.T, db *dbops.PgDB) (*dbmodel.Daemon, []dbmodel.Subnet) {
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err := dbmodel.AddMachine(db, machine)
+	require.NoError(t, err)
+
+	var accessPoints []*dbmodel.AccessPoint
+	accessPoints = dbmodel.AppendAccessPoint(accessPoints, dbmodel.AccessPointControl, "localhost", "", 8000, false)
+
+	app := &dbmodel.App{
+		MachineID:    machine.ID,
+		Type:         dbmodel.AppTypeKea,
+		Active:       true,
+		AccessPoints: accessPoints,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+	}
+	_, err = dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	subnets := []dbmodel.Subnet{
+		{
+			Prefix: "192.0.2.0/24",
+		},
+		{
+			Prefix: "192.0.3.0/24",
+		},
+	}
+	for i := range subnets {
+		err = dbmodel.AddSubnet(db, &subnets[i])
+		require.NoError(t, err)
+	}
+	err = dbmodel.AddDaemonToSubnet(db, &subnets[0], app.Daemons[0])
+	require.NoError(t, err)
+
+	return app.Daemons[0], subnets
+}
+
+// Test that the valid rows are imported and the malformed rows are
+// reported in the non-strict mode.
+func TestImportHosts(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	daemon, subnets := addTestSubnets(t, db)
+
+	csv := strings.Join([]string{
+		"subnet-prefix,identifier-type,identifier,ip-address,hostname",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:06,192.0.2.10,foo.example.org",
+		"# Comment",
+		"192.0.2.0/24, client-id, 01 0a 0b 0c,,",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:07",
+		"192.0.2.0/24,foo-id,01:02:03:04:05:08,192.0.2.11,",
+		"192.0.2.0/24,hw-address,xyz,192.0.2.12,",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:09,192.0.3.12,",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:0a,192.0.2.300,",
+		"198.51.100.0/24,hw-address,01:02:03:04:05:0b,198.51.100.10,",
+		"192.0.3.0/24,hw-address,01:02:03:04:05:0c,192.0.3.10,",
+		",hw-address,01:02:03:04:05:0d,192.0.2.13,",
+		"192.0.2.0/24,duid,01:02:03,192.0.2.14,bar.example.org",
+	}, "\n")
+
+	// Act
+	result, err := ImportHosts(db, strings.NewReader(csv), false)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.EqualValues(t, 3, result.Imported)
+	require.Len(t, result.RowErrors, 8)
+
+	// The line numbers include the header and the comment.
+	expectedLines := []int{5, 6, 7, 8, 9, 10, 11, 12}
+	for i, rowErr := range result.RowErrors {
+		require.Equal(t, expectedLines[i], rowErr.Line)
+	}
+	require.Contains(t, result.RowErrors[0].Error(), "line 5: expected 5 columns but got 3")
+	require.Contains(t, result.RowErrors[1].Error(), "unsupported identifier type")
+	require.Contains(t, result.RowErrors[2].Error(), "invalid identifier")
+	require.Contains(t, result.RowErrors[3].Error(), "does not belong to the subnet")
+	require.Contains(t, result.RowErrors[4].Error(), "invalid IP address")
+	require.Contains(t, result.RowErrors[5].Error(), "subnet 198.51.100.0/24 not found")
+	require.Contains(t, result.RowErrors[6].Error(), "not served by any daemon")
+	require.Contains(t, result.RowErrors[7].Error(), "missing subnet prefix")
+
+	hosts, err := dbmodel.GetHostsBySubnetID(db, subnets[0].ID)
+	require.NoError(t, err)
+	require.Len(t, hosts, 3)
+
+	require.Equal(t, "foo.example.org", hosts[0].Hostname)
+	require.Len(t, hosts[0].HostIdentifiers, 1)
+	require.Equal(t, "hw-address", hosts[0].HostIdentifiers[0].Type)
+	require.Equal(t, []byte{1, 2, 3, 4, 5, 6}, hosts[0].HostIdentifiers[0].Value)
+	require.Len(t, hosts[0].IPReservations, 1)
+	require.Equal(t, "192.0.2.10/32", hosts[0].IPReservations[0].Address)
+
+	require.Empty(t, hosts[1].Hostname)
+	require.Equal(t, "client-id", hosts[1].HostIdentifiers[0].Type)
+	require.Equal(t, []byte{1, 10, 11, 12}, hosts[1].HostIdentifiers[0].Value)
+	require.Empty(t, hosts[1].IPReservations)
+
+	require.Equal(t, "bar.example.org", hosts[2].Hostname)
+	require.Equal(t, "duid", hosts[2].HostIdentifiers[0].Type)
+
+	// The hosts should be associated with the daemon as added via API.
+	daemonHosts, total, err := dbmodel.GetHostsByDaemonID(db, daemon.ID, dbmodel.HostDataSourceAPI)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.Len(t, daemonHosts, 3)
+}
+
+// Test that no hosts are imported in the strict mode when any row
+// is malformed.
+func TestImportHostsStrict(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	_, subnets := addTestSubnets(t, db)
+
+	csv := strings.Join([]string{
+		"192.0.2.0/24,hw-address,01:02:03:04:05:06,192.0.2.10,foo.example.org",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:07,192.0.3.10,bar.example.org",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:08,192.0.2.12,baz.example.org",
+	}, "\n")
+
+	// Act
+	result, err := ImportHosts(db, strings.NewReader(csv), true)
+
+	// Assert
+	require.ErrorContains(t, err, "line 2: IP address 192.0.3.10 does not belong to the subnet")
+	require.Nil(t, result)
+
+	var rowErr *RowError
+	require.ErrorAs(t, err, &rowErr)
+	require.Equal(t, 2, rowErr.Line)
+
+	hosts, err := dbmodel.GetHostsBySubnetID(db, subnets[0].ID)
+	require.NoError(t, err)
+	require.Empty(t, hosts)
+}
+
+// Test that all rows are imported in the strict mode when they are valid.
+func TestImportHostsStrictValid(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	_, subnets := addTestSubnets(t, db)
+
+	csv := strings.Join([]string{
+		"192.0.2.0/24,hw-address,01:02:03:04:05:06,192.0.2.10,foo.example.org",
+		"192.0.2.0/24,hw-address,01:02:03:04:05:07,192.0.2.11,bar.example.org",
+	}, "\n")
+
+	// Act
+	result, err := ImportHosts(db, strings.NewReader(csv), true)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.EqualValues(t, 2, result.Imported)
+	require.Empty(t, result.RowErrors)
+
+	hosts, err := dbmodel.GetHostsBySubnetID(db, subnets[0].ID)
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+}
@@ -1,11 +1,13 @@
 package metrics
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
 	dbmodel "isc.org/stork/server/database/model"
 	storkutil "isc.org/stork/util"
 )
@@ -19,6 +21,9 @@ import (
 type Collector interface {
 	// It returns the metrics on HTTP request.
 	GetHTTPHandler(next http.Handler) http.Handler
+	// It returns the metrics limited to the specified machine on HTTP
+	// request.
+	GetMachineHTTPHandler(machineID int64) http.Handler
 	// Shutdown metrics collecting.
 	Shutdown()
 }
@@ -26,6 +31,7 @@ type Collector interface {
 // Metrics collector created on top of
 // Prometheus library.
 type prometheusCollector struct {
+	db      *pg.DB
 	metrics *metrics
 	puller  *storkutil.PeriodicExecutor
 }
@@ -57,6 +63,7 @@ func NewCollector(db *pg.DB) (Collector, error) {
 	}
 
 	return &prometheusCollector{
+		db:      db,
 		metrics: metrics,
 		puller:  metricPuller,
 	}, nil
@@ -67,6 +74,35 @@ func (c *prometheusCollector) GetHTTPHandler(next http.Handler) http.Handler {
 	return promhttp.HandlerFor(c.metrics.Registry, promhttp.HandlerOpts{})
 }
 
+// Creates Prometheus HTTP handler exporting the metrics of the specified
+// machine and the subnets and shared networks served by its daemons. It
+// allows for scraping the data of a single machine by an exporter running
+// on this machine. The metrics are calculated on each request in the
+// separate registry, so they don't interfere with the global metrics. The
+// handler responds with HTTP 404 if the machine doesn't exist.
+func (c *prometheusCollector) GetMachineHTTPHandler(machineID int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		machine, err := dbmodel.GetMachineByIDWithRelations(c.db, machineID)
+		if err != nil {
+			log.WithError(err).Errorf("Problem getting machine %d for metrics", machineID)
+			http.Error(w, "Cannot get the machine from the database", http.StatusInternalServerError)
+			return
+		}
+		if machine == nil {
+			http.Error(w, fmt.Sprintf("Cannot find machine with ID %d", machineID), http.StatusNotFound)
+			return
+		}
+
+		machineMetrics := newMetrics(c.db)
+		if err = machineMetrics.update(machineID); err != nil {
+			log.WithError(err).Errorf("Problem calculating metrics for machine %d", machineID)
+			http.Error(w, "Cannot calculate the machine metrics", http.StatusInternalServerError)
+			return
+		}
+		promhttp.HandlerFor(machineMetrics.Registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
 // Stops periodically collecting the metrics and unregisters
 // all metrics.
 func (c *prometheusCollector) Shutdown() {
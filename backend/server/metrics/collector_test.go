@@ -148,3 +148,92 @@ func TestPeriodicMetricsUpdate(t *testing.T) {
 		return authorizedCount == 1
 	}, 5*time.Second, 100*time.Millisecond)
 }
+
+// Test that the machine handler exports only the metrics of the subnets
+// served by the machine's daemons.
+func TestMachineHandlerResponse(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	var (
+		machineIDs []int64
+		daemons    []*dbmodel.Daemon
+	)
+	for i := 0; i < 2; i++ {
+		machine := &dbmodel.Machine{
+			Address:    "localhost",
+			AgentPort:  int64(8080 + i),
+			Authorized: true,
+		}
+		require.NoError(t, dbmodel.AddMachine(db, machine))
+		machineIDs = append(machineIDs, machine.ID)
+		app := &dbmodel.App{
+			MachineID: machine.ID,
+			Type:      dbmodel.AppTypeKea,
+			Daemons: []*dbmodel.Daemon{
+				dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+			},
+		}
+		addedDaemons, err := dbmodel.AddApp(db, app)
+		require.NoError(t, err)
+		daemons = append(daemons, addedDaemons...)
+	}
+	for i, prefix := range []string{"192.0.2.0/24", "192.0.3.0/24"} {
+		subnet := &dbmodel.Subnet{
+			Prefix:          prefix,
+			AddrUtilization: 100,
+		}
+		require.NoError(t, dbmodel.AddSubnet(db, subnet))
+		require.NoError(t, dbmodel.AddDaemonToSubnet(db, subnet, daemons[i]))
+	}
+
+	collector, _ := NewCollector(db)
+	defer collector.Shutdown()
+	handler := collector.GetMachineHTTPHandler(machineIDs[0])
+	req := httptest.NewRequest("GET", "http://localhost/metrics/machine", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 200, resp.StatusCode)
+	require.Contains(t, string(content), `storkserver_subnet_address_utilization{subnet="192.0.2.0/24"} 0.1`)
+	require.NotContains(t, string(content), "192.0.3.0/24")
+	require.Contains(t, string(content), "storkserver_auth_authorized_machine_total 1")
+
+	// The global metrics are not affected.
+	w = httptest.NewRecorder()
+	collector.GetHTTPHandler(nil).ServeHTTP(w, req)
+	globalResp := w.Result()
+	defer globalResp.Body.Close()
+	authorizedCount, err := parseAuthorizedMachinesFromPrometheus(globalResp.Body)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, authorizedCount)
+}
+
+// Test that the machine handler responds with HTTP 404 for an unknown
+// machine.
+func TestMachineHandlerResponseUnknownMachine(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+	collector, _ := NewCollector(db)
+	defer collector.Shutdown()
+	handler := collector.GetMachineHTTPHandler(42)
+	req := httptest.NewRequest("GET", "http://localhost/metrics/machine/42", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.EqualValues(t, http.StatusNotFound, w.Code)
+}
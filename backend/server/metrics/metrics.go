@@ -9,6 +9,7 @@ package metrics
 // 4. Change the updateMetrics function to collect new metric values.
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/go-pg/pg/v10"
@@ -29,6 +30,11 @@ type metrics struct {
 	SubnetPdUtilization             *prometheus.GaugeVec
 	SharedNetworkAddressUtilization *prometheus.GaugeVec
 	SharedNetworkPdUtilization      *prometheus.GaugeVec
+
+	SubnetAddressUtilizationHistogram        *prometheus.GaugeVec
+	SubnetPdUtilizationHistogram             *prometheus.GaugeVec
+	SharedNetworkAddressUtilizationHistogram *prometheus.GaugeVec
+	SharedNetworkPdUtilizationHistogram      *prometheus.GaugeVec
 }
 
 // Constructor of the metrics. They are automatically
@@ -85,14 +91,60 @@ func newMetrics(db *pg.DB) *metrics {
 			Subsystem: "shared_network",
 			Help:      "Shared-network delegated-prefix utilization",
 		}, []string{"name"}),
+		SubnetAddressUtilizationHistogram: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "address_utilization_band_total",
+			Subsystem: "subnet",
+			Help:      "Subnets in the address utilization band",
+		}, []string{"band"}),
+		SubnetPdUtilizationHistogram: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pd_utilization_band_total",
+			Subsystem: "subnet",
+			Help:      "Subnets in the delegated-prefix utilization band",
+		}, []string{"band"}),
+		SharedNetworkAddressUtilizationHistogram: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "address_utilization_band_total",
+			Subsystem: "shared_network",
+			Help:      "Shared networks in the address utilization band",
+		}, []string{"band"}),
+		SharedNetworkPdUtilizationHistogram: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pd_utilization_band_total",
+			Subsystem: "shared_network",
+			Help:      "Shared networks in the delegated-prefix utilization band",
+		}, []string{"band"}),
 	}
 
 	return &metrics
 }
 
-// Calculate current metric values from the database.
+// Calculate current metric values from the database. Depending on the
+// metrics_utilization_histogram setting, the subnet and shared network
+// utilizations are exported per network or aggregated into the 10%
+// utilization bands. The latter bounds the metrics size in the large
+// deployments.
 func (m *metrics) Update() error {
-	calculatedMetrics, err := dbmodel.GetCalculatedMetrics(m.db)
+	return m.update(0)
+}
+
+// Calculate current metric values from the database for the specified
+// machine. The subnet and shared network metrics are limited to the
+// networks served by the machine's daemons. The zero machine ID means
+// all machines.
+func (m *metrics) update(machineID int64) error {
+	histogram, err := dbmodel.GetSettingBool(m.db, "metrics_utilization_histogram")
+	if err != nil {
+		return err
+	}
+
+	var calculatedMetrics *dbmodel.CalculatedMetrics
+	if histogram {
+		calculatedMetrics, err = dbmodel.GetCalculatedHistogramMetricsForMachine(m.db, machineID)
+	} else {
+		calculatedMetrics, err = dbmodel.GetCalculatedMetricsForMachine(m.db, machineID)
+	}
 	if err != nil {
 		return err
 	}
@@ -101,6 +153,26 @@ func (m *metrics) Update() error {
 	m.UnauthorizedMachineTotal.Set(float64(calculatedMetrics.UnauthorizedMachines))
 	m.UnreachableMachineTotal.Set(float64(calculatedMetrics.UnreachableMachines))
 
+	if histogram {
+		// Remove the per-network metrics exported before the setting
+		// was changed.
+		m.SubnetAddressUtilization.Reset()
+		m.SubnetPdUtilization.Reset()
+		m.SharedNetworkAddressUtilization.Reset()
+		m.SharedNetworkPdUtilization.Reset()
+
+		setUtilizationHistogram(m.SubnetAddressUtilizationHistogram, calculatedMetrics.SubnetHistogram.AddrUtilization)
+		setUtilizationHistogram(m.SubnetPdUtilizationHistogram, calculatedMetrics.SubnetHistogram.PdUtilization)
+		setUtilizationHistogram(m.SharedNetworkAddressUtilizationHistogram, calculatedMetrics.SharedNetworkHistogram.AddrUtilization)
+		setUtilizationHistogram(m.SharedNetworkPdUtilizationHistogram, calculatedMetrics.SharedNetworkHistogram.PdUtilization)
+		return nil
+	}
+
+	m.SubnetAddressUtilizationHistogram.Reset()
+	m.SubnetPdUtilizationHistogram.Reset()
+	m.SharedNetworkAddressUtilizationHistogram.Reset()
+	m.SharedNetworkPdUtilizationHistogram.Reset()
+
 	for _, networkMetrics := range calculatedMetrics.SubnetMetrics {
 		m.SubnetAddressUtilization.
 			With(prometheus.Labels{"subnet": networkMetrics.Label}).
@@ -122,6 +194,16 @@ func (m *metrics) Update() error {
 	return nil
 }
 
+// Sets the numbers of networks in the utilization bands. The bands are
+// labeled with their percentage ranges, e.g., "0-10", "10-20".
+func setUtilizationHistogram(gauge *prometheus.GaugeVec, histogram [dbmodel.UtilizationHistogramBands]int64) {
+	bandWidth := 100 / dbmodel.UtilizationHistogramBands
+	for i, count := range histogram {
+		band := fmt.Sprintf("%d-%d", i*bandWidth, (i+1)*bandWidth)
+		gauge.With(prometheus.Labels{"band": band}).Set(float64(count))
+	}
+}
+
 // Unregister all metrics from the Prometheus registry.
 func (m *metrics) UnregisterAll() {
 	v := reflect.ValueOf(*m)
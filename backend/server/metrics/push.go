@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"github.com/go-pg/pg/v10"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Calculates the current metric values from the database and pushes them
+// to the Prometheus PushGateway using the Prometheus text format. It is
+// useful for taking snapshots of the metrics (e.g., utilization) without
+// scraping the running server. The pushed metrics replace the metrics
+// previously pushed for the same job.
+func PushMetrics(db *pg.DB, url, job string) error {
+	metrics := newMetrics(db)
+	defer metrics.UnregisterAll()
+
+	if err := metrics.Update(); err != nil {
+		return errors.WithMessage(err, "problem calculating the metrics")
+	}
+
+	err := push.New(url, job).
+		Gatherer(metrics.Registry).
+		Format(expfmt.FmtText).
+		Push()
+	if err != nil {
+		return errors.Wrapf(err, "problem pushing the metrics to %s", url)
+	}
+	return nil
+}
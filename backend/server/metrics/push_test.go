@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbmodel "isc.org/stork/server/database/model"
+	dbtest "isc.org/stork/server/database/test"
+)
+
+// Test that the metrics are pushed to the PushGateway in the text format.
+func TestPushMetrics(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	_ = dbmodel.AddMachine(db, &dbmodel.Machine{
+		Address:    "machine",
+		AgentPort:  8080,
+		Authorized: true,
+	})
+	_ = dbmodel.AddSubnet(db, &dbmodel.Subnet{
+		Prefix: "192.0.2.0/24",
+	})
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Act
+	err := PushMetrics(db, server.URL, "stork")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "/metrics/job/stork", path)
+	require.Contains(t, body, "storkserver_auth_authorized_machine_total 1")
+	require.Contains(t, body, "storkserver_auth_unauthorized_machine_total 0")
+	require.Contains(t, body, "storkserver_auth_unreachable_machine_total")
+	require.Contains(t, body, "storkserver_subnet_address_utilization{subnet=\"192.0.2.0/24\"}")
+}
+
+// Test that an error is returned when the PushGateway rejects the metrics.
+func TestPushMetricsError(t *testing.T) {
+	// Arrange
+	db, _, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	// Act
+	err := PushMetrics(db, server.URL, "stork")
+
+	// Assert
+	require.Error(t, err)
+}
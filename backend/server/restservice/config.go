@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"isc.org/stork/server/configreview"
@@ -49,7 +51,7 @@ func (r *RestAPI) GetDaemonConfig(ctx context.Context, params services.GetDaemon
 		return rsp
 	}
 
-	_, dbUser := r.SessionManager.Logged(ctx)
+	_, dbUser := r.getLoggedUser(ctx)
 	if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
 		dbDaemon.KeaDaemon.Config.HideSensitiveData()
 	}
@@ -58,6 +60,48 @@ func (r *RestAPI) GetDaemonConfig(ctx context.Context, params services.GetDaemon
 	return rsp
 }
 
+// Get the subnets of the specified Kea DHCP daemon with their pools and
+// host reservations in the Kea configuration format. The returned snippet
+// comprises the subnet4 or subnet6 list.
+func (r *RestAPI) GetDaemonSubnetsConfig(ctx context.Context, params services.GetDaemonSubnetsConfigParams) middleware.Responder {
+	dbDaemon, err := dbmodel.GetDaemonByID(r.DB, params.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot get daemon with ID %d from db", params.ID)
+		rsp := services.NewGetDaemonSubnetsConfigDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	if dbDaemon == nil {
+		msg := fmt.Sprintf("Cannot find daemon with ID %d", params.ID)
+		rsp := services.NewGetDaemonSubnetsConfigDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	if dbDaemon.Name != dbmodel.DaemonNameDHCPv4 && dbDaemon.Name != dbmodel.DaemonNameDHCPv6 {
+		msg := fmt.Sprintf("Daemon with ID %d is not a Kea DHCP daemon", params.ID)
+		rsp := services.NewGetDaemonSubnetsConfigDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	subnetsConfig, err := dbmodel.ExportKeaSubnetsConfig(r.DB, dbDaemon)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot export subnets of daemon with ID %d", params.ID)
+		rsp := services.NewGetDaemonSubnetsConfigDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	rsp := services.NewGetDaemonSubnetsConfigOK().WithPayload(subnetsConfig)
+	return rsp
+}
+
 // Get configuration review reports for a specified daemon. Only Kea
 // daemons are currently supported. The daemon id value is mandatory.
 // The start and limit values are optional. They are used to retrieve
@@ -104,7 +148,12 @@ func (r *RestAPI) GetDaemonConfigReports(ctx context.Context, params services.Ge
 		limit = *params.Limit
 	}
 
-	dbReports, total, err := dbmodel.GetConfigReportsByDaemonID(r.DB, start, limit, params.ID)
+	severity := dbmodel.ConfigReportSeverity("")
+	if params.Severity != nil {
+		severity = dbmodel.ConfigReportSeverity(*params.Severity)
+	}
+
+	dbReports, total, err := dbmodel.GetConfigReportsByDaemonIDAndSeverity(r.DB, start, limit, params.ID, severity)
 	if err != nil {
 		log.Error(err)
 		msg := fmt.Sprintf("Cannot get configuration review reports for daemon with ID %d from db", params.ID)
@@ -129,6 +178,7 @@ func (r *RestAPI) GetDaemonConfigReports(ctx context.Context, params services.Ge
 			CreatedAt: strfmt.DateTime(dbReport.CreatedAt),
 			Checker:   dbReport.CheckerName,
 			Content:   dbReport.Content,
+			Severity:  string(dbReport.Severity),
 		}
 		configReports.Items = append(configReports.Items, report)
 	}
@@ -177,6 +227,7 @@ func (r *RestAPI) PutDaemonConfigReview(ctx context.Context, params services.Put
 
 	// Begin the review but do not wait for the result.
 	_ = r.ReviewDispatcher.BeginReview(daemon, configreview.ManualRun, nil)
+	r.recordAuditLog(ctx, "config_review_started", fmt.Sprintf("daemon %d", daemon.ID))
 
 	// Inform the caller that the review request has been "accepted".
 	rsp := services.NewPutDaemonConfigReviewAccepted()
@@ -247,6 +298,36 @@ func (r *RestAPI) GetGlobalConfigCheckers(ctx context.Context, params services.G
 	return rsp
 }
 
+// Returns the information about all registered config checkers, i.e.,
+// their descriptions, reviewed daemons and default severities.
+func (r *RestAPI) GetConfigCheckersInfo(ctx context.Context, params services.GetConfigCheckersInfoParams) middleware.Responder {
+	metadata, err := r.ReviewDispatcher.GetCheckersMetadata(nil)
+	if err != nil {
+		log.Error(err)
+		msg := "Cannot get the config checkers information"
+		rsp := services.NewGetConfigCheckersInfoDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	checkers := make([]*models.ConfigCheckerInfo, len(metadata))
+	for i, m := range metadata {
+		checkers[i] = &models.ConfigCheckerInfo{
+			Name:        m.Name,
+			Description: m.Description,
+			Daemons:     m.Selectors.GetDaemonNames(),
+			Severity:    string(m.Severity),
+		}
+	}
+
+	rsp := services.NewGetConfigCheckersInfoOK().WithPayload(&models.ConfigCheckersInfo{
+		Items: checkers,
+		Total: int64(len(checkers)),
+	})
+	return rsp
+}
+
 // Returns the config checkers metadata for a given daemon.
 func (r *RestAPI) GetDaemonConfigCheckers(ctx context.Context, params services.GetDaemonConfigCheckersParams) middleware.Responder {
 	daemon, err := dbmodel.GetDaemonByID(r.DB, params.ID)
@@ -355,6 +436,11 @@ func (r *RestAPI) PutDaemonConfigCheckerPreferences(ctx context.Context, params
 		return rsp
 	}
 
+	for _, change := range params.Changes.Items {
+		r.recordAuditLog(ctx, fmt.Sprintf("config_checker_%s", change.State),
+			fmt.Sprintf("%s checker for daemon %d", change.Name, daemon.ID))
+	}
+
 	metadata, err := r.ReviewDispatcher.GetCheckersMetadata(daemon)
 	if err != nil {
 		log.Error(err)
@@ -414,6 +500,11 @@ func (r *RestAPI) PutGlobalConfigCheckerPreferences(ctx context.Context, params
 		return rsp
 	}
 
+	for _, change := range params.Changes.Items {
+		r.recordAuditLog(ctx, fmt.Sprintf("config_checker_%s", change.State),
+			fmt.Sprintf("%s checker globally", change.Name))
+	}
+
 	metadata, err := r.ReviewDispatcher.GetCheckersMetadata(nil)
 	if err != nil {
 		log.Error(err)
@@ -429,3 +520,223 @@ func (r *RestAPI) PutGlobalConfigCheckerPreferences(ctx context.Context, params
 	rsp := services.NewGetDaemonConfigCheckersOK().WithPayload(payload)
 	return rsp
 }
+
+// Converts the config checker profile from the REST API to the internal
+// type. It returns an error if the daemon ID is not a number.
+func convertConfigCheckerProfileFromRestAPI(restProfile *models.ConfigCheckerProfile) (*configreview.CheckerProfile, error) {
+	profile := &configreview.CheckerProfile{}
+	if restProfile == nil {
+		return profile, nil
+	}
+	if len(restProfile.Global) > 0 {
+		profile.Global = make(map[string]configreview.CheckerState, len(restProfile.Global))
+		for name, state := range restProfile.Global {
+			profile.Global[name] = configreview.CheckerState(state)
+		}
+	}
+	if len(restProfile.Daemons) > 0 {
+		profile.Daemons = make(map[int64]map[string]configreview.CheckerState, len(restProfile.Daemons))
+		for id, restStates := range restProfile.Daemons {
+			daemonID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid daemon ID %s in the checker profile", id)
+			}
+			states := make(map[string]configreview.CheckerState, len(restStates))
+			for name, state := range restStates {
+				states[name] = configreview.CheckerState(state)
+			}
+			profile.Daemons[daemonID] = states
+		}
+	}
+	return profile, nil
+}
+
+// Applies the config checker profile, i.e., the desired global and
+// per-daemon checker states, in one call. The changes are persistent.
+// It returns the list of the changed checker states.
+func (r *RestAPI) PutConfigCheckerProfile(ctx context.Context, params services.PutConfigCheckerProfileParams) middleware.Responder {
+	profile, err := convertConfigCheckerProfileFromRestAPI(params.Profile)
+	if err != nil {
+		log.Error(err)
+		msg := err.Error()
+		rsp := services.NewPutConfigCheckerProfileDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	changes, err := configreview.ApplyCheckerProfile(r.DB, r.ReviewDispatcher, profile)
+	if err != nil {
+		log.WithError(err).Error("Cannot apply the config checker profile")
+		msg := fmt.Sprintf("Cannot apply the config checker profile: %s", err)
+		rsp := services.NewPutConfigCheckerProfileDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	items := make([]*models.ConfigCheckerStateChange, len(changes))
+	for i, change := range changes {
+		items[i] = &models.ConfigCheckerStateChange{
+			Checker:  change.CheckerName,
+			DaemonID: change.DaemonID,
+			OldState: change.OldState,
+			NewState: change.NewState,
+		}
+		target := fmt.Sprintf("%s checker globally", change.CheckerName)
+		if change.DaemonID != 0 {
+			target = fmt.Sprintf("%s checker for daemon %d", change.CheckerName, change.DaemonID)
+		}
+		r.recordAuditLog(ctx, fmt.Sprintf("config_checker_%s", change.NewState), target)
+	}
+
+	rsp := services.NewPutConfigCheckerProfileOK().WithPayload(&models.ConfigCheckerStateChanges{
+		Items: items,
+		Total: int64(len(items)),
+	})
+	return rsp
+}
+
+// Fetches the daemon for which the periodic configuration review interval
+// is requested. It returns the HTTP status and the error message if the
+// daemon doesn't exist or it is not a Kea daemon.
+func (r *RestAPI) getConfigReviewIntervalDaemon(daemonID int64) (*dbmodel.Daemon, int, string) {
+	daemon, err := dbmodel.GetDaemonByID(r.DB, daemonID)
+	if err != nil {
+		log.Error(err)
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Cannot get daemon with ID %d from db", daemonID)
+	}
+	if daemon == nil {
+		return nil, http.StatusBadRequest, fmt.Sprintf("Cannot find daemon with ID %d", daemonID)
+	}
+	// Config review is currently only supported for Kea.
+	if daemon.KeaDaemon == nil {
+		return nil, http.StatusBadRequest, fmt.Sprintf("Daemon with ID %d is not a Kea daemon", daemonID)
+	}
+	return daemon, http.StatusOK, ""
+}
+
+// Returns the effective periodic configuration review interval of the
+// daemon in the REST API format.
+func (r *RestAPI) getConfigReviewInterval(daemonID int64) (*models.ConfigReviewInterval, error) {
+	globalInterval, err := dbmodel.GetSettingInt(r.DB, "config_review_puller_interval")
+	if err != nil {
+		return nil, err
+	}
+	override, err := dbmodel.GetConfigReviewInterval(r.DB, daemonID)
+	if err != nil {
+		return nil, err
+	}
+	interval := globalInterval
+	if override != nil {
+		interval = override.ReviewInterval
+	}
+	return &models.ConfigReviewInterval{
+		Interval:       &interval,
+		GlobalInterval: globalInterval,
+		Inherited:      override == nil,
+	}, nil
+}
+
+// Returns the periodic configuration review interval of a daemon.
+func (r *RestAPI) GetDaemonConfigReviewInterval(ctx context.Context, params services.GetDaemonConfigReviewIntervalParams) middleware.Responder {
+	daemon, status, msg := r.getConfigReviewIntervalDaemon(params.ID)
+	if daemon == nil {
+		rsp := services.NewGetDaemonConfigReviewIntervalDefault(status).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	interval, err := r.getConfigReviewInterval(daemon.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot get the config review interval for daemon with ID %d", daemon.ID)
+		rsp := services.NewGetDaemonConfigReviewIntervalDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	rsp := services.NewGetDaemonConfigReviewIntervalOK().WithPayload(interval)
+	return rsp
+}
+
+// Overrides the periodic configuration review interval of a daemon.
+func (r *RestAPI) PutDaemonConfigReviewInterval(ctx context.Context, params services.PutDaemonConfigReviewIntervalParams) middleware.Responder {
+	if params.Interval == nil || params.Interval.Interval == nil || *params.Interval.Interval < 0 {
+		msg := "Config review interval must be specified and must not be negative"
+		rsp := services.NewPutDaemonConfigReviewIntervalDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	daemon, status, msg := r.getConfigReviewIntervalDaemon(params.ID)
+	if daemon == nil {
+		rsp := services.NewPutDaemonConfigReviewIntervalDefault(status).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	err := dbmodel.SetConfigReviewInterval(r.DB, daemon.ID, *params.Interval.Interval)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot set the config review interval for daemon with ID %d", daemon.ID)
+		rsp := services.NewPutDaemonConfigReviewIntervalDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	r.recordAuditLog(ctx, "config_review_interval_set",
+		fmt.Sprintf("%d seconds for daemon %d", *params.Interval.Interval, daemon.ID))
+
+	interval, err := r.getConfigReviewInterval(daemon.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot get the config review interval for daemon with ID %d", daemon.ID)
+		rsp := services.NewPutDaemonConfigReviewIntervalDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	rsp := services.NewPutDaemonConfigReviewIntervalOK().WithPayload(interval)
+	return rsp
+}
+
+// Removes the periodic configuration review interval override of a daemon.
+func (r *RestAPI) DeleteDaemonConfigReviewInterval(ctx context.Context, params services.DeleteDaemonConfigReviewIntervalParams) middleware.Responder {
+	daemon, status, msg := r.getConfigReviewIntervalDaemon(params.ID)
+	if daemon == nil {
+		rsp := services.NewDeleteDaemonConfigReviewIntervalDefault(status).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	err := dbmodel.DeleteConfigReviewInterval(r.DB, daemon.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot delete the config review interval for daemon with ID %d", daemon.ID)
+		rsp := services.NewDeleteDaemonConfigReviewIntervalDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	r.recordAuditLog(ctx, "config_review_interval_deleted", fmt.Sprintf("daemon %d", daemon.ID))
+
+	interval, err := r.getConfigReviewInterval(daemon.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot get the config review interval for daemon with ID %d", daemon.ID)
+		rsp := services.NewDeleteDaemonConfigReviewIntervalDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	rsp := services.NewDeleteDaemonConfigReviewIntervalOK().WithPayload(interval)
+	return rsp
+}
@@ -2,11 +2,13 @@ package restservice
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	keaconfig "isc.org/stork/appcfg/kea"
 	agentcommtest "isc.org/stork/server/agentcomm/test"
 	"isc.org/stork/server/configreview"
 	dbmodel "isc.org/stork/server/database/model"
@@ -515,6 +517,128 @@ func TestGetDaemonConfigForDatabaseError(t *testing.T) {
 	require.Equal(t, msg, *defaultRsp.Payload.Message)
 }
 
+// Test that the subnets of the Kea DHCP daemon are exported in the Kea
+// configuration format.
+func TestGetDaemonSubnetsConfig(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	fa := agentcommtest.NewFakeAgents(nil, nil)
+	fd := &storktest.FakeDispatcher{}
+	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err = dbmodel.AddMachine(db, m)
+	require.NoError(t, err)
+
+	daemon := dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true)
+	err = daemon.SetConfigFromJSON(`{
+		"Dhcp4": {
+			"subnet4": [
+				{
+					"id": 7,
+					"subnet": "192.0.2.0/24"
+				}
+			]
+		}
+	}`)
+	require.NoError(t, err)
+	app := &dbmodel.App{
+		MachineID: m.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons:   []*dbmodel.Daemon{daemon},
+	}
+	_, err = dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	subnet := &dbmodel.Subnet{
+		Prefix: "192.0.2.0/24",
+		AddressPools: []dbmodel.AddressPool{
+			{
+				LowerBound: "192.0.2.10",
+				UpperBound: "192.0.2.20",
+			},
+		},
+	}
+	err = dbmodel.AddSubnet(db, subnet)
+	require.NoError(t, err)
+	err = dbmodel.AddDaemonToSubnet(db, subnet, app.Daemons[0])
+	require.NoError(t, err)
+
+	params := services.GetDaemonSubnetsConfigParams{
+		ID: app.Daemons[0].ID,
+	}
+	rsp := rapi.GetDaemonSubnetsConfig(ctx, params)
+	require.IsType(t, &services.GetDaemonSubnetsConfigOK{}, rsp)
+	okRsp := rsp.(*services.GetDaemonSubnetsConfigOK)
+
+	// The payload should be parsed as Kea configuration.
+	payload, err := json.Marshal(okRsp.Payload)
+	require.NoError(t, err)
+	config, err := dbmodel.NewKeaConfigFromJSON(fmt.Sprintf(`{"Dhcp4": %s}`, payload))
+	require.NoError(t, err)
+	var subnets []keaconfig.Subnet
+	err = config.DecodeTopLevelSubnets(&subnets)
+	require.NoError(t, err)
+	require.Len(t, subnets, 1)
+	require.EqualValues(t, 7, subnets[0].ID)
+	require.Equal(t, "192.0.2.0/24", subnets[0].Subnet)
+	require.Len(t, subnets[0].Pools, 1)
+	require.Equal(t, "192.0.2.10-192.0.2.20", subnets[0].Pools[0].Pool)
+}
+
+// Test that exporting the subnets fails for the daemons which are not
+// Kea DHCP servers and for non-existing daemons.
+func TestGetDaemonSubnetsConfigForNonDHCPDaemon(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	fa := agentcommtest.NewFakeAgents(nil, nil)
+	fd := &storktest.FakeDispatcher{}
+	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err = dbmodel.AddMachine(db, m)
+	require.NoError(t, err)
+
+	app := &dbmodel.App{
+		MachineID: m.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameCA, true),
+		},
+	}
+	_, err = dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+
+	params := services.GetDaemonSubnetsConfigParams{
+		ID: app.Daemons[0].ID,
+	}
+	rsp := rapi.GetDaemonSubnetsConfig(ctx, params)
+	require.IsType(t, &services.GetDaemonSubnetsConfigDefault{}, rsp)
+	defaultRsp := rsp.(*services.GetDaemonSubnetsConfigDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+	require.Equal(t, fmt.Sprintf("Daemon with ID %d is not a Kea DHCP daemon", params.ID), *defaultRsp.Payload.Message)
+
+	params = services.GetDaemonSubnetsConfigParams{
+		ID: app.Daemons[0].ID + 1,
+	}
+	rsp = rapi.GetDaemonSubnetsConfig(ctx, params)
+	require.IsType(t, &services.GetDaemonSubnetsConfigDefault{}, rsp)
+	defaultRsp = rsp.(*services.GetDaemonSubnetsConfigDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+}
+
 // Test that config review reports are successfully retrieved for a daemon.
 func TestGetDaemonConfigReports(t *testing.T) {
 	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -569,6 +693,7 @@ func TestGetDaemonConfigReports(t *testing.T) {
 		{
 			CheckerName: "name 2",
 			Content:     "another funny review contents for {daemon}",
+			Severity:    dbmodel.ConfigReportSeverityError,
 			DaemonID:    app.Daemons[0].ID,
 			RefDaemons: []*dbmodel.Daemon{
 				{
@@ -628,8 +753,23 @@ func TestGetDaemonConfigReports(t *testing.T) {
 	require.Equal(t, "funny review contents for <daemon id=\"1\" name=\"dhcp4\" appId=\"1\" appType=\"kea\"> and <daemon id=\"2\" name=\"dhcp6\" appId=\"1\" appType=\"kea\">",
 		okRsp.Payload.Items[0].Content)
 
+	require.Equal(t, "warning", okRsp.Payload.Items[0].Severity)
+
 	require.EqualValues(t, "name 2", okRsp.Payload.Items[1].Checker)
 	require.Equal(t, "another funny review contents for <daemon id=\"2\" name=\"dhcp6\" appId=\"1\" appType=\"kea\">", okRsp.Payload.Items[1].Content)
+	require.Equal(t, "error", okRsp.Payload.Items[1].Severity)
+
+	// Test filtering the reports by severity.
+	severity := "error"
+	params.Severity = &severity
+	rsp = rapi.GetDaemonConfigReports(ctx, params)
+	require.IsType(t, &services.GetDaemonConfigReportsOK{}, rsp)
+	okRsp = rsp.(*services.GetDaemonConfigReportsOK)
+
+	require.EqualValues(t, 1, okRsp.Payload.Total)
+	require.Len(t, okRsp.Payload.Items, 1)
+	require.EqualValues(t, "name 2", okRsp.Payload.Items[0].Checker)
+	params.Severity = nil
 
 	// Test getting the paged result.
 	params.Start = new(int64)
@@ -752,7 +892,7 @@ func TestPutDaemonConfigReview(t *testing.T) {
 	fd := &storktest.FakeDispatcher{}
 	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
 	require.NoError(t, err)
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	// Use a valid daemon ID to create new config review.
 	params := services.PutDaemonConfigReviewParams{
@@ -777,6 +917,116 @@ func TestPutDaemonConfigReview(t *testing.T) {
 	require.Contains(t, *defaultRsp.Payload.Message, "Cannot find daemon with ID")
 }
 
+// Test that the periodic config review interval of a daemon can be
+// fetched, overridden and restored to the global interval.
+func TestDaemonConfigReviewInterval(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+	_ = dbmodel.SetSettingInt(db, "config_review_puller_interval", 600)
+
+	machine := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	_ = dbmodel.AddMachine(db, machine)
+	app := &dbmodel.App{
+		MachineID: machine.ID,
+		Type:      dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon("dhcp4", true),
+		},
+	}
+	daemons, err := dbmodel.AddApp(db, app)
+	require.NoError(t, err)
+	daemonID := daemons[0].ID
+
+	rapi, _ := NewRestAPI(dbSettings, db, agentcommtest.NewFakeAgents(nil, nil), &storktest.FakeDispatcher{})
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+
+	// Act
+	rsp := rapi.GetDaemonConfigReviewInterval(ctx, services.GetDaemonConfigReviewIntervalParams{ID: daemonID})
+
+	// Assert
+	require.IsType(t, &services.GetDaemonConfigReviewIntervalOK{}, rsp)
+	interval := rsp.(*services.GetDaemonConfigReviewIntervalOK).Payload
+	require.EqualValues(t, 600, *interval.Interval)
+	require.EqualValues(t, 600, interval.GlobalInterval)
+	require.True(t, interval.Inherited)
+
+	// Act
+	value := int64(86400)
+	rsp = rapi.PutDaemonConfigReviewInterval(ctx, services.PutDaemonConfigReviewIntervalParams{
+		ID:       daemonID,
+		Interval: &models.ConfigReviewInterval{Interval: &value},
+	})
+
+	// Assert
+	require.IsType(t, &services.PutDaemonConfigReviewIntervalOK{}, rsp)
+	interval = rsp.(*services.PutDaemonConfigReviewIntervalOK).Payload
+	require.EqualValues(t, 86400, *interval.Interval)
+	require.EqualValues(t, 600, interval.GlobalInterval)
+	require.False(t, interval.Inherited)
+
+	override, _ := dbmodel.GetConfigReviewInterval(db, daemonID)
+	require.NotNil(t, override)
+	require.EqualValues(t, 86400, override.ReviewInterval)
+
+	// Act
+	rsp = rapi.DeleteDaemonConfigReviewInterval(ctx, services.DeleteDaemonConfigReviewIntervalParams{ID: daemonID})
+
+	// Assert
+	require.IsType(t, &services.DeleteDaemonConfigReviewIntervalOK{}, rsp)
+	interval = rsp.(*services.DeleteDaemonConfigReviewIntervalOK).Payload
+	require.EqualValues(t, 600, *interval.Interval)
+	require.True(t, interval.Inherited)
+
+	entries, _, _ := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 2)
+}
+
+// Test that the invalid config review interval overrides are rejected.
+func TestPutDaemonConfigReviewIntervalInvalid(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	rapi, _ := NewRestAPI(dbSettings, db, agentcommtest.NewFakeAgents(nil, nil), &storktest.FakeDispatcher{})
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+
+	negative := int64(-1)
+	positive := int64(60)
+	testCases := []struct {
+		name   string
+		params services.PutDaemonConfigReviewIntervalParams
+	}{
+		{"missing interval", services.PutDaemonConfigReviewIntervalParams{ID: 1}},
+		{"negative interval", services.PutDaemonConfigReviewIntervalParams{
+			ID:       1,
+			Interval: &models.ConfigReviewInterval{Interval: &negative},
+		}},
+		{"non-existing daemon", services.PutDaemonConfigReviewIntervalParams{
+			ID:       1,
+			Interval: &models.ConfigReviewInterval{Interval: &positive},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			// Act
+			rsp := rapi.PutDaemonConfigReviewInterval(ctx, testCase.params)
+
+			// Assert
+			require.IsType(t, &services.PutDaemonConfigReviewIntervalDefault{}, rsp)
+			defaultRsp := rsp.(*services.PutDaemonConfigReviewIntervalDefault)
+			require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+		})
+	}
+}
+
 // Test that HTTP internal server error is returned when the database
 // connection fails while creating new config review.
 func TestPutDaemonConfigReviewDatabaseError(t *testing.T) {
@@ -789,7 +1039,7 @@ func TestPutDaemonConfigReviewDatabaseError(t *testing.T) {
 	fd := &storktest.FakeDispatcher{}
 	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
 	require.NoError(t, err)
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	params := services.PutDaemonConfigReviewParams{
 		ID: 1,
@@ -836,7 +1086,7 @@ func TestPutDaemonConfigReviewNotKeaDaemon(t *testing.T) {
 	fd := &storktest.FakeDispatcher{}
 	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
 	require.NoError(t, err)
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	params := services.PutDaemonConfigReviewParams{
 		ID: daemons[0].ID,
@@ -884,7 +1134,7 @@ func TestPutDaemonConfigReviewNoConfig(t *testing.T) {
 	fd := &storktest.FakeDispatcher{}
 	rapi, err := NewRestAPI(dbSettings, db, fa, fd)
 	require.NoError(t, err)
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	params := services.PutDaemonConfigReviewParams{
 		ID: daemons[0].ID,
@@ -967,6 +1217,49 @@ func TestGetGlobalConfigCheckers(t *testing.T) {
 	require.NotEmpty(t, okRsp.Payload.Items)
 }
 
+// Test that the information about the registered config checkers is
+// returned properly.
+func TestGetConfigCheckersInfo(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	dispatcher := configreview.NewDispatcher(db)
+	configreview.RegisterDefaultCheckers(dispatcher)
+
+	rapi, _ := NewRestAPI(dbSettings, db, dispatcher)
+
+	// Act
+	ctx := context.Background()
+	params := services.GetConfigCheckersInfoParams{}
+	rsp := rapi.GetConfigCheckersInfo(ctx, params)
+
+	// Assert
+	require.IsType(t, &services.GetConfigCheckersInfoOK{}, rsp)
+	okRsp := rsp.(*services.GetConfigCheckersInfoOK)
+	require.NotNil(t, okRsp.Payload)
+	require.EqualValues(t, len(okRsp.Payload.Items), okRsp.Payload.Total)
+
+	checkers := make(map[string]*models.ConfigCheckerInfo)
+	for _, checker := range okRsp.Payload.Items {
+		require.NotEmpty(t, checker.Description)
+		require.NotEmpty(t, checker.Daemons)
+		require.NotEmpty(t, checker.Severity)
+		checkers[checker.Name] = checker
+	}
+
+	require.Contains(t, checkers, "overlapping_subnet")
+	require.Equal(t, []string{"dhcp4", "dhcp6"}, checkers["overlapping_subnet"].Daemons)
+	require.EqualValues(t, "error", checkers["overlapping_subnet"].Severity)
+	require.Equal(t, "The checker verifying if subnet prefixes do not overlap.", checkers["overlapping_subnet"].Description)
+
+	require.Contains(t, checkers, "dns_servers_option")
+	require.Equal(t, []string{"dhcp4"}, checkers["dns_servers_option"].Daemons)
+
+	require.Contains(t, checkers, "ca_basic_auth_realm")
+	require.Equal(t, []string{"ca"}, checkers["ca_basic_auth_realm"].Daemons)
+	require.EqualValues(t, "warning", checkers["ca_basic_auth_realm"].Severity)
+}
+
 // Test that the configuration checkers for a given daemon are returned properly.
 func TestGetDaemonConfigCheckers(t *testing.T) {
 	// Arrange
@@ -1041,7 +1334,7 @@ func TestPutNewGlobalConfigCheckerPreferences(t *testing.T) {
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
 
 	// Act
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 	params := services.PutGlobalConfigCheckerPreferencesParams{
 		Changes: &models.ConfigCheckerPreferences{
 			Total: 3,
@@ -1083,9 +1376,10 @@ func TestPutUpdateGlobalConfigCheckerPreferences(t *testing.T) {
 
 	fd := &storktest.FakeDispatcher{}
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	// Act
-	rsp1 := rapi.PutGlobalConfigCheckerPreferences(context.Background(), services.PutGlobalConfigCheckerPreferencesParams{
+	rsp1 := rapi.PutGlobalConfigCheckerPreferences(ctx, services.PutGlobalConfigCheckerPreferencesParams{
 		Changes: &models.ConfigCheckerPreferences{
 			Total: 2,
 			Items: []*models.ConfigCheckerPreference{
@@ -1101,7 +1395,7 @@ func TestPutUpdateGlobalConfigCheckerPreferences(t *testing.T) {
 		},
 	})
 
-	rsp2 := rapi.PutGlobalConfigCheckerPreferences(context.Background(), services.PutGlobalConfigCheckerPreferencesParams{
+	rsp2 := rapi.PutGlobalConfigCheckerPreferences(ctx, services.PutGlobalConfigCheckerPreferencesParams{
 		Changes: &models.ConfigCheckerPreferences{
 			Total: 2,
 			Items: []*models.ConfigCheckerPreference{
@@ -1153,7 +1447,7 @@ func TestPutDaemonConfigCheckerPreferencesAPIResponse(t *testing.T) {
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
 
 	// Act
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 	params := services.PutDaemonConfigCheckerPreferencesParams{
 		ID: daemon.ID,
 		Changes: &models.ConfigCheckerPreferences{
@@ -1201,7 +1495,7 @@ func TestPutNewDaemonConfigCheckers(t *testing.T) {
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
 
 	// Act
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 	params := services.PutDaemonConfigCheckerPreferencesParams{
 		ID: daemon.ID,
 		Changes: &models.ConfigCheckerPreferences{
@@ -1224,6 +1518,62 @@ func TestPutNewDaemonConfigCheckers(t *testing.T) {
 	require.True(t, preferences[0].Enabled)
 }
 
+// Test that disabling a config checker is recorded in the audit log along
+// with the user who made the change.
+func TestPutDaemonConfigCheckerPreferencesAuditLog(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	_ = dbmodel.AddMachine(db, m)
+	app := &dbmodel.App{
+		Type: dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+		MachineID: m.ID,
+	}
+	daemons, _ := dbmodel.AddApp(db, app)
+	daemon := daemons[0]
+
+	fd := &storktest.FakeDispatcher{}
+	rapi, _ := NewRestAPI(dbSettings, db, fd)
+
+	user, _ := dbmodel.GetUserByID(rapi.DB, 1)
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	_ = rapi.SessionManager.LoginHandler(ctx, user)
+
+	params := services.PutDaemonConfigCheckerPreferencesParams{
+		ID: daemon.ID,
+		Changes: &models.ConfigCheckerPreferences{
+			Total: 1,
+			Items: []*models.ConfigCheckerPreference{
+				{
+					Name: "foo", State: "disabled",
+				},
+			},
+		},
+	}
+
+	// Act
+	rsp := rapi.PutDaemonConfigCheckerPreferences(ctx, params)
+
+	// Assert
+	require.IsType(t, &services.PutDaemonConfigCheckerPreferencesOK{}, rsp)
+	entries, total, err := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Len(t, entries, 1)
+	require.EqualValues(t, user.ID, entries[0].UserID)
+	require.Equal(t, user.Login, entries[0].UserLogin)
+	require.Equal(t, "config_checker_disabled", entries[0].Action)
+	require.Equal(t, fmt.Sprintf("foo checker for daemon %d", daemon.ID), entries[0].Target)
+}
+
 // Test that the config checker preferences are updated properly.
 func TestPutDaemonConfigCheckerPreferencesUpdate(t *testing.T) {
 	// Arrange
@@ -1247,11 +1597,12 @@ func TestPutDaemonConfigCheckerPreferencesUpdate(t *testing.T) {
 
 	fd := &storktest.FakeDispatcher{}
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 
 	// Act
 	// Initialize the config checker preferences.
 	rsp1 := rapi.PutDaemonConfigCheckerPreferences(
-		context.Background(),
+		ctx,
 		services.PutDaemonConfigCheckerPreferencesParams{
 			ID: daemon.ID,
 			Changes: &models.ConfigCheckerPreferences{
@@ -1271,7 +1622,7 @@ func TestPutDaemonConfigCheckerPreferencesUpdate(t *testing.T) {
 	)
 	// Modify the config checker preferences.
 	rsp2 := rapi.PutDaemonConfigCheckerPreferences(
-		context.Background(),
+		ctx,
 		services.PutDaemonConfigCheckerPreferencesParams{
 			ID: daemon.ID,
 			Changes: &models.ConfigCheckerPreferences{
@@ -1325,7 +1676,7 @@ func TestPutDaemonConfigCheckerPreferencesForMissingDaemon(t *testing.T) {
 	rapi, _ := NewRestAPI(dbSettings, db, fd)
 
 	// Act
-	ctx := context.Background()
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
 	params := services.PutDaemonConfigCheckerPreferencesParams{
 		ID: 1,
 		Changes: &models.ConfigCheckerPreferences{
@@ -1344,3 +1695,138 @@ func TestPutDaemonConfigCheckerPreferencesForMissingDaemon(t *testing.T) {
 	preferences, _ := dbmodel.GetCheckerPreferences(db, daemonID)
 	require.Empty(t, preferences)
 }
+
+// Test that the config checker profile is applied and the changed states
+// are returned.
+func TestPutConfigCheckerProfile(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	_ = dbmodel.AddMachine(db, m)
+	app := &dbmodel.App{
+		Type: dbmodel.AppTypeKea,
+		Daemons: []*dbmodel.Daemon{
+			dbmodel.NewKeaDaemon(dbmodel.DaemonNameDHCPv4, true),
+		},
+		MachineID: m.ID,
+	}
+	daemons, _ := dbmodel.AddApp(db, app)
+	daemon := daemons[0]
+
+	dispatcher := configreview.NewDispatcher(db)
+	configreview.RegisterDefaultCheckers(dispatcher)
+	rapi, _ := NewRestAPI(dbSettings, db, dispatcher)
+
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	params := services.PutConfigCheckerProfileParams{
+		Profile: &models.ConfigCheckerProfile{
+			Global: map[string]models.ConfigCheckerState{
+				"overlapping_subnet": models.ConfigCheckerStateEnabled,
+				"stat_cmds_presence": models.ConfigCheckerStateDisabled,
+			},
+			Daemons: map[string]map[string]models.ConfigCheckerState{
+				fmt.Sprint(daemon.ID): {
+					"stat_cmds_presence":         models.ConfigCheckerStateEnabled,
+					"dispensable_shared_network": models.ConfigCheckerStateDisabled,
+				},
+			},
+		},
+	}
+
+	// Act
+	rsp := rapi.PutConfigCheckerProfile(ctx, params)
+
+	// Assert
+	require.IsType(t, &services.PutConfigCheckerProfileOK{}, rsp)
+	okRsp := rsp.(*services.PutConfigCheckerProfileOK)
+	require.EqualValues(t, 3, okRsp.Payload.Total)
+	require.Len(t, okRsp.Payload.Items, 3)
+
+	require.Equal(t, "stat_cmds_presence", okRsp.Payload.Items[0].Checker)
+	require.Zero(t, okRsp.Payload.Items[0].DaemonID)
+	require.EqualValues(t, "enabled", okRsp.Payload.Items[0].OldState)
+	require.EqualValues(t, "disabled", okRsp.Payload.Items[0].NewState)
+
+	require.Equal(t, "dispensable_shared_network", okRsp.Payload.Items[1].Checker)
+	require.Equal(t, daemon.ID, okRsp.Payload.Items[1].DaemonID)
+	require.EqualValues(t, "inherit", okRsp.Payload.Items[1].OldState)
+	require.EqualValues(t, "disabled", okRsp.Payload.Items[1].NewState)
+
+	require.Equal(t, "stat_cmds_presence", okRsp.Payload.Items[2].Checker)
+	require.Equal(t, daemon.ID, okRsp.Payload.Items[2].DaemonID)
+
+	preferences, _ := dbmodel.GetAllCheckerPreferences(db)
+	require.Len(t, preferences, 3)
+
+	entries, total, err := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, total)
+	require.Len(t, entries, 3)
+}
+
+// Test that the config checker profile with unknown checkers is rejected.
+func TestPutConfigCheckerProfileUnknownCheckers(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	dispatcher := configreview.NewDispatcher(db)
+	configreview.RegisterDefaultCheckers(dispatcher)
+	rapi, _ := NewRestAPI(dbSettings, db, dispatcher)
+
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	params := services.PutConfigCheckerProfileParams{
+		Profile: &models.ConfigCheckerProfile{
+			Global: map[string]models.ConfigCheckerState{
+				"overlapping_subnet": models.ConfigCheckerStateDisabled,
+				"foo":                models.ConfigCheckerStateDisabled,
+			},
+		},
+	}
+
+	// Act
+	rsp := rapi.PutConfigCheckerProfile(ctx, params)
+
+	// Assert
+	require.IsType(t, &services.PutConfigCheckerProfileDefault{}, rsp)
+	defaultRsp := rsp.(*services.PutConfigCheckerProfileDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+	require.Contains(t, *defaultRsp.Payload.Message, "unknown config checkers in the profile: foo")
+	preferences, _ := dbmodel.GetAllCheckerPreferences(db)
+	require.Empty(t, preferences)
+}
+
+// Test that the config checker profile with the invalid daemon ID is rejected.
+func TestPutConfigCheckerProfileInvalidDaemonID(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	dispatcher := configreview.NewDispatcher(db)
+	configreview.RegisterDefaultCheckers(dispatcher)
+	rapi, _ := NewRestAPI(dbSettings, db, dispatcher)
+
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	params := services.PutConfigCheckerProfileParams{
+		Profile: &models.ConfigCheckerProfile{
+			Daemons: map[string]map[string]models.ConfigCheckerState{
+				"dhcp4": {
+					"overlapping_subnet": models.ConfigCheckerStateDisabled,
+				},
+			},
+		},
+	}
+
+	// Act
+	rsp := rapi.PutConfigCheckerProfile(ctx, params)
+
+	// Assert
+	require.IsType(t, &services.PutConfigCheckerProfileDefault{}, rsp)
+	defaultRsp := rsp.(*services.PutConfigCheckerProfileDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+}
@@ -0,0 +1,27 @@
+package restservice
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// The UI files copied to the www directory by the build system before
+// building the server binary (see the build:server_embedded_ui task). The
+// directory holds only a placeholder file when the UI hasn't been copied.
+//
+//go:embed all:www
+var embeddedUI embed.FS
+
+// Returns the filesystem with the UI files embedded in the binary. It
+// returns nil if the UI hasn't been embedded, i.e., the index file is
+// missing.
+func getEmbeddedStaticFiles() fs.FS {
+	files, err := fs.Sub(embeddedUI, "www")
+	if err != nil {
+		return nil
+	}
+	if _, err = fs.Stat(files, "index.html"); err != nil {
+		return nil
+	}
+	return files
+}
@@ -71,3 +71,60 @@ func (r *RestAPI) GetEvents(ctx context.Context, params events.GetEventsParams)
 	rsp := events.NewGetEventsOK().WithPayload(eventRecs)
 	return rsp
 }
+
+// Records the change made by the user in the audit log. The user is taken
+// from the session. A failure to record the entry is logged but it doesn't
+// interrupt processing the request.
+func (r *RestAPI) recordAuditLog(ctx context.Context, action, target string) {
+	entry := &dbmodel.AuditLog{
+		Action: action,
+		Target: target,
+	}
+	if _, user := r.getLoggedUser(ctx); user != nil {
+		entry.UserID = int64(user.ID)
+		entry.UserLogin = user.Login
+	}
+	if err := dbmodel.AddAuditLog(r.DB, entry); err != nil {
+		log.Errorf("Problem recording the change in the audit log: %+v", err)
+	}
+}
+
+// Get list of the audit log entries with specifying an offset and a limit.
+func (r *RestAPI) GetAuditLog(ctx context.Context, params events.GetAuditLogParams) middleware.Responder {
+	var start int64
+	if params.Start != nil {
+		start = *params.Start
+	}
+
+	var limit int64 = 10
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	dbEntries, total, err := dbmodel.GetAuditLogByPage(r.DB, start, limit, "created_at", dbmodel.SortDirDesc)
+	if err != nil {
+		msg := "Problem fetching audit log entries from the database"
+		log.Error(err)
+		rsp := events.NewGetAuditLogDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	entries := &models.AuditLogEntries{
+		Total: total,
+	}
+	for _, dbEntry := range dbEntries {
+		entries.Items = append(entries.Items, &models.AuditLogEntry{
+			ID:        dbEntry.ID,
+			CreatedAt: strfmt.DateTime(dbEntry.CreatedAt),
+			UserID:    dbEntry.UserID,
+			UserLogin: dbEntry.UserLogin,
+			Action:    dbEntry.Action,
+			Target:    dbEntry.Target,
+		})
+	}
+
+	rsp := events.NewGetAuditLogOK().WithPayload(entries)
+	return rsp
+}
@@ -43,3 +43,35 @@ func TestEvents(t *testing.T) {
 	require.EqualValues(t, "some event", ev2.Text)
 	require.EqualValues(t, dbmodel.EvInfo, ev2.Level)
 }
+
+// Check getting the audit log entries via rest api functions.
+func TestGetAuditLog(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// add audit log entry
+	err := dbmodel.AddAuditLog(db, &dbmodel.AuditLog{
+		UserID:    1,
+		UserLogin: "admin",
+		Action:    "config_checker_disabled",
+		Target:    "foo checker globally",
+	})
+	require.NoError(t, err)
+
+	// prepare RestAPI
+	rapi, err := NewRestAPI(dbSettings, db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	params := events.GetAuditLogParams{}
+	rsp := rapi.GetAuditLog(ctx, params)
+	require.IsType(t, &events.GetAuditLogOK{}, rsp)
+	okRsp := rsp.(*events.GetAuditLogOK)
+	require.Len(t, okRsp.Payload.Items, 1)
+	require.EqualValues(t, 1, okRsp.Payload.Total)
+	entry := okRsp.Payload.Items[0]
+	require.EqualValues(t, 1, entry.UserID)
+	require.Equal(t, "admin", entry.UserLogin)
+	require.Equal(t, "config_checker_disabled", entry.Action)
+	require.Equal(t, "foo checker globally", entry.Target)
+}
@@ -237,7 +237,7 @@ func (r *RestAPI) commonCreateOrUpdateHostBegin(ctx context.Context) ([]*models.
 		respSubnets = append(respSubnets, subnetToRestAPI(&subnets[i]))
 	}
 	// Get the logged user's ID.
-	ok, user := r.SessionManager.Logged(ctx)
+	ok, user := r.getLoggedUser(ctx)
 	if !ok {
 		msg := "unable to begin transaction because user is not logged in"
 		log.Error("problem with creating transaction context because user has no session")
@@ -322,7 +322,7 @@ func (r *RestAPI) commonCreateOrUpdateHostSubmit(ctx context.Context, transactio
 		return http.StatusBadRequest, msg
 	}
 	// Get the user ID and recover the transaction context.
-	ok, user := r.SessionManager.Logged(ctx)
+	ok, user := r.getLoggedUser(ctx)
 	if !ok {
 		msg := "unable to submit because user is not logged in"
 		log.Error("problem with recovering transaction context because user has no session")
@@ -394,7 +394,7 @@ func (r *RestAPI) CreateHostSubmit(ctx context.Context, params dhcp.CreateHostSu
 // or an empty string if there is no error.
 func (r *RestAPI) commonCreateOrUpdateHostDelete(ctx context.Context, transactionID int64) (int, string) {
 	// Get the user ID and recover the transaction context.
-	ok, user := r.SessionManager.Logged(ctx)
+	ok, user := r.getLoggedUser(ctx)
 	if !ok {
 		msg := "unable to cancel transaction because user is not logged in"
 		log.Error("problem with recovering transaction context because user has no session")
@@ -554,7 +554,7 @@ func (r *RestAPI) DeleteHost(ctx context.Context, params dhcp.DeleteHostParams)
 		return rsp
 	}
 	// Get the logged user's ID.
-	ok, user := r.SessionManager.Logged(ctx)
+	ok, user := r.getLoggedUser(ctx)
 	if !ok {
 		msg := "unable to begin transaction because user is not logged in"
 		log.Error("problem with creating transaction context because user has no session")
@@ -380,6 +380,15 @@ func (r *RestAPI) CreateMachine(ctx context.Context, params services.CreateMachi
 		return rsp
 	}
 
+	// Remember when the agent certificate expires to warn the operator
+	// before it happens.
+	var agentCertExpiresAt time.Time
+	if agentCert, err := pki.ParseCert(agentCertPEM); err == nil {
+		agentCertExpiresAt = agentCert.NotAfter
+	} else {
+		log.Warnf("Cannot parse the agent certificate to get its expiration time: %+v", err)
+	}
+
 	// We can't create new machine and pull machines' states at the same time. This may
 	// put heavy workload on the server and it may also result in conflicts. Temporarily
 	// disable the puller while the new machine is being added.
@@ -394,6 +403,7 @@ func (r *RestAPI) CreateMachine(ctx context.Context, params services.CreateMachi
 			AgentPort:       params.Machine.AgentPort,
 			AgentToken:      *params.Machine.AgentToken,
 			CertFingerprint: agentCertFingerprint,
+			CertExpiresAt:   agentCertExpiresAt,
 			Authorized:      machineAuthorized,
 		}
 		err = dbmodel.AddMachine(r.DB, dbMachine)
@@ -409,6 +419,7 @@ func (r *RestAPI) CreateMachine(ctx context.Context, params services.CreateMachi
 	} else {
 		dbMachine.AgentToken = *params.Machine.AgentToken
 		dbMachine.CertFingerprint = agentCertFingerprint
+		dbMachine.CertExpiresAt = agentCertExpiresAt
 		dbMachine.Authorized = machineAuthorized
 		err = dbmodel.UpdateMachine(r.DB, dbMachine)
 		if err != nil {
@@ -558,7 +569,7 @@ func (r *RestAPI) UpdateMachine(ctx context.Context, params services.UpdateMachi
 
 	// if machine authorization is changed then this action requires super-admin group
 	if dbMachine.Authorized != params.Machine.Authorized {
-		_, dbUser := r.SessionManager.Logged(ctx)
+		_, dbUser := r.getLoggedUser(ctx)
 		if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
 			msg := "User is forbidden to change machine authorization"
 			rsp := services.NewUpdateMachineDefault(http.StatusForbidden).WithPayload(&models.APIError{
@@ -604,7 +615,7 @@ func (r *RestAPI) UpdateMachine(ctx context.Context, params services.UpdateMachi
 // Get machine's server token. It is used by user during manual agent registration.
 func (r *RestAPI) GetMachinesServerToken(ctx context.Context, params services.GetMachinesServerTokenParams) middleware.Responder {
 	// only super-admin can get server token
-	_, dbUser := r.SessionManager.Logged(ctx)
+	_, dbUser := r.getLoggedUser(ctx)
 	if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
 		msg := "User is forbidden to get server token"
 		rsp := services.NewGetMachinesServerTokenDefault(http.StatusForbidden).WithPayload(&models.APIError{
@@ -641,7 +652,7 @@ func (r *RestAPI) GetMachinesServerToken(ctx context.Context, params services.Ge
 // Regenerate machines server token.
 func (r *RestAPI) RegenerateMachinesServerToken(ctx context.Context, params services.RegenerateMachinesServerTokenParams) middleware.Responder {
 	// only super-admin can get server token
-	_, dbUser := r.SessionManager.Logged(ctx)
+	_, dbUser := r.getLoggedUser(ctx)
 	if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
 		msg := "User is forbidden to generate new server token"
 		rsp := services.NewGetMachinesServerTokenDefault(http.StatusForbidden).WithPayload(&models.APIError{
@@ -667,6 +678,49 @@ func (r *RestAPI) RegenerateMachinesServerToken(ctx context.Context, params serv
 	return rsp
 }
 
+// Get the server and agent certificates which expire within the specified
+// number of days or have already expired.
+func (r *RestAPI) GetExpiringCerts(ctx context.Context, params services.GetExpiringCertsParams) middleware.Responder {
+	window := certs.DefaultCertExpiryWindow
+	if params.Days != nil {
+		if *params.Days < 0 {
+			msg := "Number of days must not be negative"
+			rsp := services.NewGetExpiringCertsDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+				Message: &msg,
+			})
+			return rsp
+		}
+		window = time.Duration(*params.Days) * 24 * time.Hour
+	}
+
+	now := time.Now().UTC()
+	expiring, err := certs.FindExpiringCerts(r.DB, now, window)
+	if err != nil {
+		log.Error(err)
+		msg := "Cannot get the expiring certificates from the database"
+		rsp := services.NewGetExpiringCertsDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	items := []*models.ExpiringCert{}
+	for _, cert := range expiring {
+		items = append(items, &models.ExpiringCert{
+			Name:      cert.Name,
+			MachineID: cert.MachineID,
+			NotAfter:  strfmt.DateTime(cert.NotAfter),
+			Expired:   cert.IsExpired(now),
+		})
+	}
+
+	rsp := services.NewGetExpiringCertsOK().WithPayload(&models.ExpiringCerts{
+		Items: items,
+		Total: int64(len(items)),
+	})
+	return rsp
+}
+
 // Add a machine where Stork Agent is running.
 func (r *RestAPI) DeleteMachine(ctx context.Context, params services.DeleteMachineParams) middleware.Responder {
 	dbMachine, err := dbmodel.GetMachineByIDWithRelations(r.DB, params.ID)
@@ -1308,7 +1362,7 @@ func (r *RestAPI) GetAppsStats(ctx context.Context, params services.GetAppsStats
 // Get DHCP overview.
 func (r *RestAPI) GetDhcpOverview(ctx context.Context, params dhcp.GetDhcpOverviewParams) middleware.Responder {
 	// get list of mostly utilized subnets
-	subnets4, err := r.getSubnets(0, 5, 0, 4, nil, "addr_utilization", dbmodel.SortDirDesc)
+	subnets4, err := r.getSubnets(0, 5, 0, 4, nil, nil, "addr_utilization", dbmodel.SortDirDesc)
 	if err != nil {
 		log.Error(err)
 		msg := "Cannot get IPv4 subnets from db"
@@ -1318,7 +1372,7 @@ func (r *RestAPI) GetDhcpOverview(ctx context.Context, params dhcp.GetDhcpOvervi
 		return rsp
 	}
 
-	subnets6, err := r.getSubnets(0, 5, 0, 6, nil, "addr_utilization", dbmodel.SortDirDesc)
+	subnets6, err := r.getSubnets(0, 5, 0, 6, nil, nil, "addr_utilization", dbmodel.SortDirDesc)
 	if err != nil {
 		log.Error(err)
 		msg := "Cannot get IPv6 subnets from db"
@@ -1498,7 +1552,7 @@ func (r *RestAPI) UpdateDaemon(ctx context.Context, params services.UpdateDaemon
 		return rsp
 	}
 
-	_, dbUser := r.SessionManager.Logged(ctx)
+	_, dbUser := r.getLoggedUser(ctx)
 
 	if oldMonitored != params.Daemon.Monitored {
 		if params.Daemon.Monitored {
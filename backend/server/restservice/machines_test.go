@@ -431,6 +431,9 @@ func TestCreateMachine(t *testing.T) {
 	m1 := machines[0]
 	require.True(t, m1.Authorized)
 	certFingerprint1 := m1.CertFingerprint
+	agentCert, err := pki.ParseCert([]byte(okRsp.Payload.AgentCert))
+	require.NoError(t, err)
+	require.WithinDuration(t, agentCert.NotAfter, m1.CertExpiresAt, time.Second)
 
 	// ok, now lets ping the machine if it is alive
 	pingParams := services.PingMachineParams{
@@ -1859,6 +1862,65 @@ func TestServerToken(t *testing.T) {
 	require.NotEmpty(t, okRegenRsp.Payload.Token)
 }
 
+// Test that the certificates expiring within the specified number of days
+// are returned.
+func TestGetExpiringCerts(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rapi, err := NewRestAPI(dbSettings, db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	machines := []*dbmodel.Machine{
+		{
+			Address:       "agent1",
+			AgentPort:     8080,
+			CertExpiresAt: now.AddDate(0, 0, -1),
+		},
+		{
+			Address:       "agent2",
+			AgentPort:     8080,
+			CertExpiresAt: now.AddDate(0, 0, 20),
+		},
+		{
+			Address:       "agent3",
+			AgentPort:     8080,
+			CertExpiresAt: now.AddDate(0, 0, 60),
+		},
+	}
+	for _, machine := range machines {
+		err = dbmodel.AddMachine(db, machine)
+		require.NoError(t, err)
+	}
+
+	// Use the default window.
+	rsp := rapi.GetExpiringCerts(ctx, services.GetExpiringCertsParams{})
+	require.IsType(t, &services.GetExpiringCertsOK{}, rsp)
+	okRsp := rsp.(*services.GetExpiringCertsOK)
+	require.EqualValues(t, 2, okRsp.Payload.Total)
+	require.Len(t, okRsp.Payload.Items, 2)
+	require.Equal(t, "agent cert of machine agent1:8080", okRsp.Payload.Items[0].Name)
+	require.Equal(t, machines[0].ID, okRsp.Payload.Items[0].MachineID)
+	require.True(t, okRsp.Payload.Items[0].Expired)
+	require.Equal(t, "agent cert of machine agent2:8080", okRsp.Payload.Items[1].Name)
+	require.False(t, okRsp.Payload.Items[1].Expired)
+
+	// Extend the window.
+	days := int64(90)
+	rsp = rapi.GetExpiringCerts(ctx, services.GetExpiringCertsParams{Days: &days})
+	require.IsType(t, &services.GetExpiringCertsOK{}, rsp)
+	okRsp = rsp.(*services.GetExpiringCertsOK)
+	require.Len(t, okRsp.Payload.Items, 3)
+
+	// Negative number of days is rejected.
+	days = -1
+	rsp = rapi.GetExpiringCerts(ctx, services.GetExpiringCertsParams{Days: &days})
+	require.IsType(t, &services.GetExpiringCertsDefault{}, rsp)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*rsp.(*services.GetExpiringCertsDefault)))
+}
+
 // Test that a PUT request to rename an app will cause the app to be successfully renamed.
 // Also, test that invalid app name will cause an error.
 func TestRenameApp(t *testing.T) {
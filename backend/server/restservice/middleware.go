@@ -1,11 +1,22 @@
 package restservice
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -13,8 +24,11 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"isc.org/stork/server/auth"
+	dbmodel "isc.org/stork/server/database/model"
+	dbsession "isc.org/stork/server/database/session"
 	"isc.org/stork/server/eventcenter"
 	"isc.org/stork/server/metrics"
+	storkutil "isc.org/stork/util"
 )
 
 // Struct for holding response details.
@@ -54,18 +68,92 @@ func (r *loggingResponseWriter) Header() http.Header {
 	return r.rw.Header()
 }
 
-// Install a middleware that traces ReST calls using logrus.
-func loggingMiddleware(next http.Handler) http.Handler {
+// Name of the HTTP header carrying the request ID.
+const requestIDHeader = "X-Request-ID"
+
+// Maximum length of the request ID received in the request header.
+const maxRequestIDLength = 128
+
+// Pattern matching the valid request IDs received in the request header.
+// It excludes the characters that could break the log entries.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:+/=-]+$`)
+
+// Type of the request context key holding the request ID.
+type requestIDContextKeyType int
+
+// Request context key holding the request ID.
+const requestIDContextKey requestIDContextKeyType = iota
+
+// Returns the ID of the request the context belongs to. It allows for
+// correlating the log entries produced while serving the request. It
+// returns an empty string if the context holds no request ID.
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// Generates a random request ID.
+func generateRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// It is very unlikely but let's fall back to the ID that is
+		// unique enough to correlate the log entries.
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Install a middleware assigning an ID to each request. The ID received
+// in the X-Request-ID header is used if it is valid. Otherwise, a random
+// ID is generated. The ID is stored in the request context, so it can be
+// retrieved with the GetRequestID function, and returned in the
+// X-Request-ID response header. The middleware should be installed before
+// the logging middleware, so the ID is included in the logged entries.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if len(requestID) > maxRequestIDLength || !requestIDPattern.MatchString(requestID) {
+			requestID = generateRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), requestIDContextKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Returns the address of the client sending the request. The address
+// received in the X-Real-IP header takes precedence over the address
+// of the connection peer, so the client address is known when the
+// server is behind a reverse proxy.
+func getRemoteAddress(r *http.Request) string {
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+	return r.RemoteAddr
+}
+
+// Install a middleware that traces ReST calls using logrus. The requests to
+// the endpoints having the specified path prefixes are traced at the debug
+// level, so the frequent health checks and metrics scraping don't drown
+// the real traffic in the logs.
+func loggingMiddleware(next http.Handler, suppressedPaths []string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		remoteAddr := r.RemoteAddr
-		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-			remoteAddr = realIP
+		level := log.InfoLevel
+		for _, suppressedPath := range suppressedPaths {
+			if strings.HasPrefix(r.URL.Path, suppressedPath) {
+				level = log.DebugLevel
+				break
+			}
 		}
+
 		entry := log.WithFields(log.Fields{
 			"path":   r.RequestURI,
 			"method": r.Method,
-			"remote": remoteAddr,
+			"remote": getRemoteAddress(r),
 		})
+		if requestID := GetRequestID(r.Context()); requestID != "" {
+			entry = entry.WithField("request_id", requestID)
+		}
 
 		responseData := &responseData{
 			status: 0,
@@ -76,7 +164,7 @@ func loggingMiddleware(next http.Handler) http.Handler {
 			responseData: responseData,
 		}
 
-		entry.Info("HTTP request incoming")
+		entry.Log(level, "HTTP request incoming")
 
 		start := time.Now()
 
@@ -90,30 +178,412 @@ func loggingMiddleware(next http.Handler) http.Handler {
 			"took":        duration,
 			"size":        responseData.size,
 		})
-		entry.Info("HTTP request served")
+		entry.Log(level, "HTTP request served")
+	})
+}
+
+// Token bucket holding the number of the requests a single client may
+// send before it is rate limited.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Rate limiter using a token bucket per client IP address. The buckets
+// are refilled at a constant rate up to the burst size. Each request
+// takes one token from the bucket. The request is rejected if the
+// bucket is empty.
+type rateLimiter struct {
+	mutex sync.Mutex
+	// Number of the tokens added to the bucket per second.
+	rate float64
+	// Maximum number of the tokens in the bucket.
+	burst float64
+	// Buckets by client IP address.
+	buckets map[string]*tokenBucket
+	// Time when the full buckets were last removed.
+	pruned time.Time
+	// Returns the current time. It is replaced in the unit tests.
+	now func() time.Time
+}
+
+// Creates a rate limiter allowing the specified number of requests per
+// interval for each client. The burst is the maximum number of requests
+// a client may send at once. The non-positive burst is replaced with the
+// limit. It returns nil if the limit or the interval is non-positive,
+// i.e., the rate limiting is disabled.
+func newRateLimiter(limit int, interval time.Duration, burst int) *rateLimiter {
+	if limit <= 0 || interval <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = limit
+	}
+	return &rateLimiter{
+		rate:    float64(limit) / interval.Seconds(),
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Takes a token from the bucket of the specified client. It returns true
+// if the request is allowed. Otherwise, it returns false and the duration
+// after which the next token is available.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens:  l.burst,
+			updated: now,
+		}
+		l.buckets[client] = bucket
+	}
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = math.Min(l.burst, bucket.tokens+elapsed.Seconds()*l.rate)
+		bucket.updated = now
+	}
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// Removes the buckets which have been refilled since their last use.
+// They are equivalent to the new buckets, so they needn't be held. It
+// prevents the unbounded growth of the buckets map. The buckets are
+// examined not more often than it takes to refill an empty bucket.
+func (l *rateLimiter) prune(now time.Time) {
+	refillDuration := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.pruned) < refillDuration {
+		return
+	}
+	for client, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= refillDuration {
+			delete(l.buckets, client)
+		}
+	}
+	l.pruned = now
+}
+
+// Parses the IP addresses or prefixes of the trusted reverse proxies. The
+// IP address is converted to a prefix matching only this address.
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var prefixes []*net.IPNet
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy address %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			prefixes = append(prefixes, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, prefix, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy prefix %s", proxy)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// Returns the IP address of the client sending the request. The address
+// received in the X-Real-IP header is used only if the connection peer is
+// one of the trusted proxies. Otherwise, the header could be forged by the
+// client to avoid the rate limiting. The port number is removed.
+func getClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	realIP := strings.TrimSpace(r.Header.Get("X-Real-IP"))
+	if realIP == "" {
+		return peer
+	}
+	if peerIP := net.ParseIP(peer); peerIP != nil {
+		for _, proxy := range trustedProxies {
+			if proxy.Contains(peerIP) {
+				return realIP
+			}
+		}
+	}
+	return peer
+}
+
+// Install a middleware limiting the rate of the API requests sent by each
+// client. The clients are distinguished by their IP addresses. The request
+// exceeding the limit is rejected with HTTP 429 status code and the
+// Retry-After header holding the number of seconds after which the client
+// may retry. It protects the login and the authenticated endpoints against
+// the brute-force attacks. The requests for the static files, metrics and
+// server-sent events are not limited. The nil limiter disables the rate
+// limiting. The client address passed in the X-Real-IP header is only
+// trusted if the request comes from one of the trusted proxies. The
+// middleware should be installed before the authentication.
+func rateLimitingMiddleware(next http.Handler, limiter *rateLimiter, trustedProxies []*net.IPNet) http.Handler {
+	if limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		client := getClientIP(r, trustedProxies)
+		if ok, retryAfter := limiter.allow(client); !ok {
+			log.WithField("remote", client).Warn("Rejected HTTP request exceeding the rate limit")
+			seconds := int64(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Content types of the responses which are already compressed or are
+// binary. They are not compressed by the compression middleware.
+var incompressibleContentTypes = []string{
+	"application/gzip",
+	"application/octet-stream",
+	"application/vnd.debian.binary-package",
+	"application/x-gzip",
+	"application/x-rpm",
+	"application/zip",
+	"audio/",
+	"font/woff",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"video/",
+}
+
+// http.ResponseWriter implementation compressing the response body with
+// gzip. The decision whether to compress the response is made when the
+// header is written because the handler may indicate in the header that
+// the content is already compressed.
+type gzipResponseWriter struct {
+	rw          http.ResponseWriter // compose original http.ResponseWriter
+	method      string
+	gzipWriter  *gzip.Writer
+	wroteHeader bool
+}
+
+// http.ResponseWriter Header implementation wrapper
+// that returns the header.
+func (w *gzipResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+// http.ResponseWriter WriteHeader implementation wrapper that enables
+// the compression if the response is eligible for it.
+func (w *gzipResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.isCompressible(statusCode) {
+			w.rw.Header().Set("Content-Encoding", "gzip")
+			// The length of the compressed content is unknown.
+			w.rw.Header().Del("Content-Length")
+			w.gzipWriter = gzip.NewWriter(w.rw)
+		}
+	}
+	w.rw.WriteHeader(statusCode)
+}
+
+// http.ResponseWriter Write implementation wrapper that compresses the
+// data if the compression has been enabled.
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		// The content type would be otherwise detected from the
+		// compressed data.
+		if w.rw.Header().Get("Content-Type") == "" {
+			w.rw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gzipWriter != nil {
+		return w.gzipWriter.Write(b)
+	}
+	return w.rw.Write(b)
+}
+
+// Writes the remaining compressed data and the gzip footer. It does
+// nothing if the response has not been compressed.
+func (w *gzipResponseWriter) Close() error {
+	if w.gzipWriter == nil {
+		return nil
+	}
+	return w.gzipWriter.Close()
+}
+
+// Checks if the response having the specified status code should be
+// compressed. The responses without the body, the partial content and
+// the content already compressed are not compressed.
+func (w *gzipResponseWriter) isCompressible(statusCode int) bool {
+	switch {
+	case w.method == http.MethodHead,
+		statusCode < http.StatusOK,
+		statusCode == http.StatusNoContent,
+		statusCode == http.StatusPartialContent,
+		statusCode == http.StatusNotModified:
+		return false
+	}
+	header := w.rw.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return false
+	}
+	for _, incompressible := range incompressibleContentTypes {
+		if strings.HasPrefix(contentType, incompressible) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks if the client accepts the gzip encoding of the response. The
+// encoding is not accepted if its quality value is zero.
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(encoding, ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		params = strings.ReplaceAll(params, " ", "")
+		if strings.HasPrefix(params, "q=") {
+			if quality, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err == nil && quality == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Install a middleware compressing the responses with gzip when the client
+// accepts it. The content which is already compressed is passed unchanged.
+// The server-sent events are excluded because the stream must not be
+// buffered. The middleware should be installed after the logging
+// middleware, so the logged response size is the size of the compressed
+// content.
+func compressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/sse") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// The response depends on the Accept-Encoding header, so the
+		// caches must not serve the compressed content to the clients
+		// which do not accept it.
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gzw := &gzipResponseWriter{
+			rw:     w,
+			method: r.Method,
+		}
+		defer func() {
+			if err := gzw.Close(); err != nil {
+				log.WithError(err).Warn("Problem compressing HTTP response")
+			}
+		}()
+		next.ServeHTTP(gzw, r)
 	})
 }
 
 // Install a middleware that is serving static files for UI
-// and assets/pkgs content ie. stork rpm and deb packages.
-func fileServerMiddleware(next http.Handler, staticFilesDir string) http.Handler {
+// and assets/pkgs content ie. stork rpm and deb packages. The files are
+// read from the specified filesystem. It may be a directory on disk
+// (see os.DirFS) or a filesystem embedded in the binary (see embed.FS).
+// The index.html is served with the no-cache directive, so the browsers
+// pick up the new deployments. The fingerprinted files (having the content
+// hash in their names) are immutable and may be cached by the browsers for
+// the specified maxAge period. The zero maxAge disables their caching.
+func fileServerMiddleware(next http.Handler, staticFiles fs.FS, maxAge time.Duration) http.Handler {
+	fileServer := http.FileServer(http.FS(staticFiles))
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/api") || r.URL.Path == "/swagger.json" {
 			// serve API request
 			next.ServeHTTP(w, r)
 		} else {
-			pth := path.Join(staticFilesDir, r.URL.Path)
-			if _, err := os.Stat(pth); os.IsNotExist(err) {
+			// The fs.FS paths must not begin with a slash.
+			pth := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+			if pth == "" {
+				pth = "."
+			}
+			if _, err := fs.Stat(staticFiles, pth); errors.Is(err, fs.ErrNotExist) {
 				// if file does not exist then return content of index.html
-				http.ServeFile(w, r, path.Join(staticFilesDir, "index.html"))
+				w.Header().Set("Cache-Control", "no-cache")
+				serveIndexFile(w, r, staticFiles)
 			} else {
 				// if file exists then serve it
-				http.FileServer(http.Dir(staticFilesDir)).ServeHTTP(w, r)
+				if cacheControl := getStaticFileCacheControl(pth, maxAge); cacheControl != "" {
+					w.Header().Set("Cache-Control", cacheControl)
+				}
+				fileServer.ServeHTTP(w, r)
 			}
 		}
 	})
 }
 
+// Pattern matching the static file names with the content hash,
+// e.g., main.0123456789abcdef.js.
+var fingerprintedFilePattern = regexp.MustCompile(`[.-][0-9a-f]{16,}\.[0-9a-z]+$`)
+
+// Returns the value of the Cache-Control header for the static file
+// having the specified path. It returns an empty string if the default
+// headers should be used.
+func getStaticFileCacheControl(pth string, maxAge time.Duration) string {
+	switch {
+	case pth == "." || path.Base(pth) == "index.html":
+		return "no-cache"
+	case fingerprintedFilePattern.MatchString(path.Base(pth)):
+		if maxAge <= 0 {
+			return "no-cache"
+		}
+		return fmt.Sprintf("public, max-age=%d, immutable", int64(maxAge.Seconds()))
+	default:
+		return ""
+	}
+}
+
+// Serves the content of the index.html file from the specified filesystem.
+// It returns HTTP 404 if the file doesn't exist.
+func serveIndexFile(w http.ResponseWriter, r *http.Request, staticFiles fs.FS) {
+	content, err := fs.ReadFile(staticFiles, "index.html")
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, "index.html", time.Time{}, bytes.NewReader(content))
+}
+
 // Install a middleware that is serving `server-sent events` (SSE).
 func sseMiddleware(next http.Handler, eventCenter eventcenter.EventCenter) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -126,7 +596,42 @@ func sseMiddleware(next http.Handler, eventCenter eventcenter.EventCenter) http.
 	})
 }
 
-// Install a middleware that is serving Agent installer.
+// Compares the package file names taking into account the numbers they
+// contain, so the isc-stork-agent_1.10.0_amd64.deb is newer than the
+// isc-stork-agent_1.9.0_amd64.deb. It returns a negative number if the
+// first name is older, a positive number if it is newer and zero if the
+// names are equal.
+func comparePackageFilenames(name1, name2 string) int {
+	for name1 != "" && name2 != "" {
+		digits1 := len(name1) - len(strings.TrimLeft(name1, "0123456789"))
+		digits2 := len(name2) - len(strings.TrimLeft(name2, "0123456789"))
+		if digits1 > 0 && digits2 > 0 {
+			// Compare the numbers without the leading zeros by their
+			// lengths first and then lexicographically.
+			number1 := strings.TrimLeft(name1[:digits1], "0")
+			number2 := strings.TrimLeft(name2[:digits2], "0")
+			if len(number1) != len(number2) {
+				return len(number1) - len(number2)
+			}
+			if result := strings.Compare(number1, number2); result != 0 {
+				return result
+			}
+			name1, name2 = name1[digits1:], name2[digits2:]
+			continue
+		}
+		if name1[0] != name2[0] {
+			return int(name1[0]) - int(name2[0])
+		}
+		name1, name2 = name1[1:], name2[1:]
+	}
+	return len(name1) - len(name2)
+}
+
+// Install a middleware that is serving Agent installer. The installer
+// script uses the agent packages available in the assets/pkgs directory.
+// If there are many versions of the package of the same type, the newest
+// one is used. The script installing the package of the type which is
+// not available prints an error and exits.
 func agentInstallerMiddleware(next http.Handler, staticFilesDir string) http.Handler {
 	// Agent installer as Bash script.
 	const agentInstallerScript = `#!/bin/bash
@@ -135,14 +640,32 @@ set -e -x
 rm -f /tmp/isc-stork-agent.{deb,rpm,apk}
 
 if [ -e /etc/debian_version ]; then
+{{- if .DebPath}}
     curl -o /tmp/isc-stork-agent.deb "{{.ServerAddress}}{{.DebPath}}"
     DEBIAN_FRONTEND=noninteractive dpkg -i --force-confold /tmp/isc-stork-agent.deb
+{{- else}}
+    # The Stork server provides no deb package of the Stork agent.
+    echo "The Stork server provides no deb package of the Stork agent" >&2
+    exit 1
+{{- end}}
 elif [ -e /etc/alpine-release ]; then
+{{- if .ApkPath}}
 	wget -O /tmp/isc-stork-agent.apk "{{.ServerAddress}}{{.ApkPath}}"
 	apk add --no-cache --no-network /tmp/isc-stork-agent.apk
+{{- else}}
+    # The Stork server provides no apk package of the Stork agent.
+    echo "The Stork server provides no apk package of the Stork agent" >&2
+    exit 1
+{{- end}}
 else
+{{- if .RpmPath}}
     curl -o /tmp/isc-stork-agent.rpm "{{.ServerAddress}}{{.RpmPath}}"
     yum install -y /tmp/isc-stork-agent.rpm
+{{- else}}
+    # The Stork server provides no RPM package of the Stork agent.
+    echo "The Stork server provides no RPM package of the Stork agent" >&2
+    exit 1
+{{- end}}
 fi
 
 systemctl daemon-reload
@@ -169,39 +692,42 @@ su stork-agent -s /bin/sh -c 'stork-agent register -u http://{{.ServerAddress}}'
 			packageExtensions := []string{".deb", ".rpm", ".apk"}
 			packageFiles := map[string]string{}
 			for _, f := range files {
-				if !strings.HasPrefix(f.Name(), "isc-stork-agent") {
+				if f.IsDir() || !strings.HasPrefix(f.Name(), "isc-stork-agent") {
 					continue
 				}
 
 				for _, extension := range packageExtensions {
-					if strings.HasSuffix(f.Name(), extension) {
+					if !strings.HasSuffix(f.Name(), extension) {
+						continue
+					}
+					// Use the newest package if there are many versions.
+					if current, ok := packageFiles[extension]; !ok || comparePackageFilenames(f.Name(), current) > 0 {
 						packageFiles[extension] = f.Name()
 					}
 				}
 			}
 
-			if len(packageFiles) != len(packageExtensions) {
-				for _, extension := range packageExtensions {
-					if _, ok := packageFiles[extension]; ok {
-						continue
-					}
-
-					msg := fmt.Sprintf("Cannot find agent %s file in '%s' directory\n", extension, pkgsDir)
-					log.Errorf(msg)
-					w.WriteHeader(http.StatusNotFound)
-					fmt.Fprint(w, msg)
-					return
-				}
+			if len(packageFiles) == 0 {
+				msg := fmt.Sprintf("Cannot find any agent package in '%s' directory\n", pkgsDir)
+				log.Errorf(msg)
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, msg)
+				return
 			}
 
 			data := map[string]string{
 				"ServerAddress": r.Host,
 			}
 
-			for extension, path := range packageFiles {
+			for _, extension := range packageExtensions {
 				key := strings.TrimLeft(extension, ".")
 				key = strings.ToUpper(key[0:1]) + key[1:] + "Path"
-				data[key] = path
+				if packageFile, ok := packageFiles[extension]; ok {
+					data[key] = path.Join("/assets/pkgs", packageFile)
+				} else {
+					log.Warnf("Cannot find agent %s file in '%s' directory", extension, pkgsDir)
+					data[key] = ""
+				}
 			}
 
 			t := template.Must(template.New("script").Parse(agentInstallerScript))
@@ -220,7 +746,12 @@ su stork-agent -s /bin/sh -c 'stork-agent register -u http://{{.ServerAddress}}'
 	})
 }
 
-// Metric collector middleware that handles the metric endpoint.
+// Path prefix of the endpoint exporting the metrics of a single machine.
+// It is followed by the machine ID.
+const machineMetricsPathPrefix = "/metrics/machine/"
+
+// Metric collector middleware that handles the metric endpoint. The
+// metrics of a single machine are served at /metrics/machine/{id}.
 func metricsMiddleware(next http.Handler, collector metrics.Collector) http.Handler {
 	var handler http.Handler
 	if collector != nil {
@@ -236,7 +767,14 @@ func metricsMiddleware(next http.Handler, collector metrics.Collector) http.Hand
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasPrefix(r.URL.Path, "/metrics") {
+		if strings.HasPrefix(r.URL.Path, machineMetricsPathPrefix) && collector != nil {
+			machineID, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, machineMetricsPathPrefix), 10, 64)
+			if err != nil || machineID <= 0 {
+				http.NotFound(w, r)
+				return
+			}
+			collector.GetMachineHTTPHandler(machineID).ServeHTTP(w, r)
+		} else if strings.HasPrefix(r.URL.Path, "/metrics") {
 			handler.ServeHTTP(w, r)
 		} else {
 			// pass request to another handler
@@ -247,27 +785,211 @@ func metricsMiddleware(next http.Handler, collector metrics.Collector) http.Hand
 
 // Global middleware function provides a common place to setup middlewares for
 // the server. It is invoked before everything.
-func (r *RestAPI) GlobalMiddleware(handler http.Handler, staticFilesDir string, eventCenter eventcenter.EventCenter) http.Handler {
+// The static files for the UI are served from the staticFiles filesystem,
+// while the agent installer packages are read from the staticFilesDir.
+// The fingerprinted static files may be cached by the browsers for the
+// staticFilesMaxAge period. The requests to the endpoints having the
+// logSuppressedPaths prefixes are logged at the debug level. Each client
+// may send rateLimit API requests per rateLimitInterval and at most
+// rateLimitBurst requests at once. The zero rateLimit disables the rate
+// limiting. The clients behind the rateLimitTrustedProxies are identified
+// by the X-Real-IP header.
+func (r *RestAPI) GlobalMiddleware(handler http.Handler, staticFilesDir string, staticFiles fs.FS, staticFilesMaxAge time.Duration, eventCenter eventcenter.EventCenter, logSuppressedPaths []string, rateLimit int, rateLimitInterval time.Duration, rateLimitBurst int, rateLimitTrustedProxies []*net.IPNet) http.Handler {
 	// last handler is executed first for incoming request
-	handler = fileServerMiddleware(handler, staticFilesDir)
+	handler = fileServerMiddleware(handler, staticFiles, staticFilesMaxAge)
 	handler = agentInstallerMiddleware(handler, staticFilesDir)
 	handler = sseMiddleware(handler, eventCenter)
 	handler = metricsMiddleware(handler, r.MetricsCollector)
-	handler = loggingMiddleware(handler)
+	handler = rateLimitingMiddleware(handler, newRateLimiter(rateLimit, rateLimitInterval, rateLimitBurst), rateLimitTrustedProxies)
+	handler = compressionMiddleware(handler)
+	handler = loggingMiddleware(handler, logSuppressedPaths)
+	handler = requestIDMiddleware(handler)
 	return handler
 }
 
+// Checks if the URL path equals the path prefix or is located under it.
+// The prefix matches whole path segments only, e.g., the /api/users prefix
+// matches the /api/users/1 path but not the /api/users-groups path.
+func hasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// Middleware requiring the second authentication factor for the sensitive
+// endpoints. The requests to the endpoints having the specified path prefixes
+// are rejected with 403 status code unless the session has been verified
+// with the second factor. The session must be already loaded into the
+// request context. The requests authenticated with the OIDC bearer token
+// have no session and are passed unchanged because the identity provider
+// is responsible for the multi-factor authentication of such users.
+func secondFactorMiddleware(next http.Handler, sessionManager *dbsession.SessionMgr, sensitivePaths []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(oidcUserContextKey).(*dbmodel.SystemUser); !ok {
+			for _, sensitivePath := range sensitivePaths {
+				if hasPathPrefix(r.URL.Path, sensitivePath) {
+					if !sessionManager.IsSecondFactorVerified(r.Context()) {
+						http.Error(w, "Second authentication factor required", http.StatusForbidden)
+						return
+					}
+					break
+				}
+			}
+		}
+		// pass request to another handler
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Type of the request context key holding the user authenticated with
+// the OIDC bearer token.
+type oidcUserContextKeyType int
+
+// Request context key holding the user authenticated with the OIDC
+// bearer token.
+const oidcUserContextKey oidcUserContextKeyType = iota
+
+// Prefix of the logins of the users provisioned from the OIDC tokens.
+// It prevents the conflicts with the local user logins. The local user
+// accounts with this prefix can't be created or logged in.
+const oidcLoginPrefix = "oidc:"
+
+// Middleware authenticating the requests carrying the OIDC bearer token in
+// the Authorization header. The user described by the valid token claims
+// is stored in the request context. No session is created because the
+// token is sent with every request. The user account is created or updated
+// in the database accordingly. The requests carrying an invalid token are
+// rejected with 401 status code. The requests without the bearer token are
+// passed unchanged, so they may be authenticated with a session.
+func (r *RestAPI) oidcMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization := req.Header.Get("Authorization")
+		if r.OIDCVerifier == nil || !strings.HasPrefix(authorization, "Bearer ") {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		claims, err := r.OIDCVerifier.Verify(strings.TrimPrefix(authorization, "Bearer "), time.Now())
+		if err != nil {
+			log.WithError(err).Warn("Rejected OIDC bearer token")
+			http.Error(w, "Invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		groups := r.OIDCVerifier.Groups(claims)
+		if len(groups) == 0 {
+			log.WithField("subject", claims.Subject).Warn("Rejected OIDC bearer token without any Stork role")
+			http.Error(w, "Bearer token does not grant any Stork role", http.StatusForbidden)
+			return
+		}
+
+		user, err := r.provisionOIDCUser(claims, groups)
+		if err != nil {
+			log.WithError(err).Error("Problem provisioning user authenticated with OIDC bearer token")
+			http.Error(w, "Unable to authenticate user", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(req.Context(), oidcUserContextKey, user)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// Returns the user authenticated with the OIDC bearer token or, if there
+// is no such user, the user logged in with a session.
+func (r *RestAPI) getLoggedUser(ctx context.Context) (bool, *dbmodel.SystemUser) {
+	if user, ok := ctx.Value(oidcUserContextKey).(*dbmodel.SystemUser); ok {
+		return true, user
+	}
+	return r.SessionManager.Logged(ctx)
+}
+
+// Checks if both users belong to the same groups. The groups are compared
+// by their identifiers.
+func hasSameGroups(user1, user2 *dbmodel.SystemUser) bool {
+	if len(user1.Groups) != len(user2.Groups) {
+		return false
+	}
+	for _, group := range user1.Groups {
+		if !user2.InGroup(&dbmodel.SystemGroup{ID: group.ID}) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks if the user account matches the OIDC token claims and the groups
+// mapped from the token roles.
+func isOIDCUserUpToDate(user *dbmodel.SystemUser, claims *auth.OIDCClaims, groups []*dbmodel.SystemGroup) bool {
+	return user.Email == claims.Email && user.Name == claims.Name &&
+		user.Lastname == claims.FamilyName &&
+		hasSameGroups(user, &dbmodel.SystemUser{Groups: groups})
+}
+
+// Creates or updates the user account described by the OIDC token claims.
+// The user groups are replaced with the groups mapped from the token roles.
+// The existing account is updated only if the claims have changed. The
+// created account has a random password, and the logins with the OIDC
+// prefix are rejected, so it is not possible to log in to it with the
+// credentials.
+func (r *RestAPI) provisionOIDCUser(claims *auth.OIDCClaims, groups []*dbmodel.SystemGroup) (*dbmodel.SystemUser, error) {
+	login := oidcLoginPrefix + claims.Subject
+	user, err := dbmodel.GetUserByLogin(r.DB, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		password, err := storkutil.Base64Random(32)
+		if err != nil {
+			return nil, errors.WithMessage(err, "cannot generate password for the OIDC user")
+		}
+		user = &dbmodel.SystemUser{
+			Login:    login,
+			Email:    claims.Email,
+			Name:     claims.Name,
+			Lastname: claims.FamilyName,
+			Password: password,
+			Groups:   groups,
+		}
+		if _, err = dbmodel.CreateUser(r.DB, user); err != nil {
+			return nil, err
+		}
+		user.Password = ""
+		return user, nil
+	}
+
+	if isOIDCUserUpToDate(user, claims, groups) {
+		return user, nil
+	}
+
+	// Leave the password unchanged.
+	user.Password = ""
+	user.Email = claims.Email
+	user.Name = claims.Name
+	user.Lastname = claims.FamilyName
+	user.Groups = groups
+	if _, err = dbmodel.UpdateUser(r.DB, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // Inner middleware function provides a common place to setup middlewares for
 // the server. It is invoked after routing but before authentication, binding and validation.
 func (r *RestAPI) InnerMiddleware(handler http.Handler) http.Handler {
-	// last handler is executed first for incoming request
+	// last handler is executed first for incoming request; the second
+	// factor middleware follows the OIDC middleware to recognize the
+	// requests authenticated with the bearer token
+	handler = secondFactorMiddleware(handler, r.SessionManager, r.SecondFactorRequiredPaths)
+	handler = r.oidcMiddleware(handler)
 	handler = r.SessionManager.SessionMiddleware(handler)
 	return handler
 }
 
-// Checks if the user us authorized to access the system (has session).
+// Checks if the user us authorized to access the system (has session or
+// has been authenticated with the OIDC bearer token).
 func (r *RestAPI) Authorizer(req *http.Request) error {
-	ok, u := r.SessionManager.Logged(req.Context())
+	ok, u := r.getLoggedUser(req.Context())
 	if !ok {
 		return errors.Errorf("user unauthorized")
 	}
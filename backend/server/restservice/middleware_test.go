@@ -1,14 +1,32 @@
 package restservice
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
+	flags "github.com/jessevdk/go-flags"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+	"isc.org/stork/server/auth"
+	dbmodel "isc.org/stork/server/database/model"
 	dbsession "isc.org/stork/server/database/session"
 	dbtest "isc.org/stork/server/database/test"
 	storktest "isc.org/stork/server/test"
@@ -22,7 +40,7 @@ func TestFileServerMiddleware(t *testing.T) {
 		apiRequestReceived = true
 	})
 
-	handler := fileServerMiddleware(apiHandler, "./non-existing-static/")
+	handler := fileServerMiddleware(apiHandler, os.DirFS("./non-existing-static/"), 0)
 
 	// let request some static file, as it does not exist 404 code should be returned
 	req := httptest.NewRequest("GET", "http://localhost/abc", nil)
@@ -47,6 +65,118 @@ func TestFileServerMiddleware(t *testing.T) {
 	require.True(t, apiRequestReceived)
 }
 
+//go:embed testdata/www
+var testStaticFiles embed.FS
+
+// Checks that the files are served from the specified filesystem, the
+// index.html is returned for the non-existing files and the API requests
+// are passed to the next handler.
+func testFileServerMiddlewareWithFS(t *testing.T, staticFiles fs.FS) {
+	apiRequestReceived := false
+	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiRequestReceived = true
+	})
+
+	handler := fileServerMiddleware(apiHandler, staticFiles, 0)
+
+	// The existing file should be returned.
+	req := httptest.NewRequest("GET", "http://localhost/assets/main.js", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.EqualValues(t, 200, resp.StatusCode)
+	require.Contains(t, string(body), "console.log")
+
+	// The root path should return the index.html.
+	req = httptest.NewRequest("GET", "http://localhost/", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp = w.Result()
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.EqualValues(t, 200, resp.StatusCode)
+	require.Contains(t, string(body), "<html>")
+
+	// The non-existing file should be substituted with the index.html
+	// to support the routing in the UI.
+	req = httptest.NewRequest("GET", "http://localhost/dhcp/subnets", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp = w.Result()
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.EqualValues(t, 200, resp.StatusCode)
+	require.Contains(t, string(body), "<html>")
+	require.False(t, apiRequestReceived)
+
+	// The API request should be forwarded to the apiHandler.
+	req = httptest.NewRequest("GET", "http://localhost/api/users", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.True(t, apiRequestReceived)
+
+	// The request for swagger.json also should be forwarded to the apiHandler.
+	req = httptest.NewRequest("GET", "http://localhost/swagger.json", nil)
+	w = httptest.NewRecorder()
+	apiRequestReceived = false
+	handler.ServeHTTP(w, req)
+	require.True(t, apiRequestReceived)
+}
+
+// Check if fileServerMiddleware serves the files from the directory on disk.
+func TestFileServerMiddlewareDirectory(t *testing.T) {
+	testFileServerMiddlewareWithFS(t, os.DirFS("testdata/www"))
+}
+
+// Check if fileServerMiddleware serves the files from the embedded filesystem.
+func TestFileServerMiddlewareEmbedded(t *testing.T) {
+	staticFiles, err := fs.Sub(testStaticFiles, "testdata/www")
+	require.NoError(t, err)
+	testFileServerMiddlewareWithFS(t, staticFiles)
+}
+
+// Check that the fingerprinted static files are cached by the browsers
+// while the index.html is not.
+func TestFileServerMiddlewareCacheControl(t *testing.T) {
+	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := fileServerMiddleware(apiHandler, os.DirFS("testdata/www"), 24*time.Hour)
+
+	getCacheControl := func(url string) string {
+		req := httptest.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		resp := w.Result()
+		resp.Body.Close()
+		require.EqualValues(t, 200, resp.StatusCode)
+		return resp.Header.Get("Cache-Control")
+	}
+
+	// The fingerprinted file is immutable.
+	require.Equal(t, "public, max-age=86400, immutable", getCacheControl("http://localhost/assets/main.0123456789abcdef.js"))
+	// The index.html must be always revalidated.
+	require.Equal(t, "no-cache", getCacheControl("http://localhost/"))
+	require.Equal(t, "no-cache", getCacheControl("http://localhost/dhcp/subnets"))
+	// The file without the hash in the name uses the default headers.
+	require.Empty(t, getCacheControl("http://localhost/assets/main.js"))
+}
+
+// Check that the caching of the fingerprinted files is disabled for the
+// zero max-age.
+func TestFileServerMiddlewareCacheControlDisabled(t *testing.T) {
+	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := fileServerMiddleware(apiHandler, os.DirFS("testdata/www"), 0)
+
+	req := httptest.NewRequest("GET", "http://localhost/assets/main.0123456789abcdef.js", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	resp.Body.Close()
+	require.EqualValues(t, 200, resp.StatusCode)
+	require.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+}
+
 // Check if InnerMiddleware works.
 func TestInnerMiddleware(t *testing.T) {
 	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -61,6 +191,297 @@ func TestInnerMiddleware(t *testing.T) {
 	require.NotNil(t, handler)
 }
 
+// Check that the sensitive endpoints are blocked by secondFactorMiddleware
+// unless the session has been verified with the second factor.
+func TestSecondFactorMiddleware(t *testing.T) {
+	_, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	sm, err := dbsession.NewSessionMgr(&dbSettings.BaseDatabaseSettings)
+	require.NoError(t, err)
+
+	requestReceived := false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestReceived = true
+	})
+	handler := secondFactorMiddleware(nextHandler, sm, []string{"/api/users"})
+
+	ctx, err := sm.Load(context.Background(), "")
+	require.NoError(t, err)
+
+	// The sensitive endpoint without the second factor.
+	req := httptest.NewRequest("GET", "http://localhost/api/users/1/password", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	require.False(t, requestReceived)
+
+	// The sensitive endpoint matching the path prefix exactly.
+	req = httptest.NewRequest("GET", "http://localhost/api/users", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	require.False(t, requestReceived)
+
+	// The other endpoints are not affected.
+	req = httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.True(t, requestReceived)
+
+	// The endpoint sharing the prefix but not the path segment is not affected.
+	requestReceived = false
+	req = httptest.NewRequest("GET", "http://localhost/api/users-groups", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.True(t, requestReceived)
+
+	// The sensitive endpoint with the second factor.
+	requestReceived = false
+	sm.SetSecondFactorVerified(ctx, true)
+	req = httptest.NewRequest("GET", "http://localhost/api/users/1/password", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.True(t, requestReceived)
+}
+
+// Test that the requests authenticated with the OIDC bearer token are not
+// required to have the second factor verified because they have no session.
+func TestSecondFactorMiddlewareOIDCUser(t *testing.T) {
+	// Arrange
+	requestReceived := false
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestReceived = true
+	})
+	// The session manager is not used for the OIDC users.
+	handler := secondFactorMiddleware(nextHandler, nil, []string{"/api/users"})
+	ctx := context.WithValue(context.Background(), oidcUserContextKey, &dbmodel.SystemUser{ID: 1})
+	req := httptest.NewRequest("GET", "http://localhost/api/users/1/password", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.True(t, requestReceived)
+}
+
+// Test that the path prefixes match whole path segments only.
+func TestHasPathPrefix(t *testing.T) {
+	require.True(t, hasPathPrefix("/api/users", "/api/users"))
+	require.True(t, hasPathPrefix("/api/users/1/password", "/api/users"))
+	require.True(t, hasPathPrefix("/api/users/1", "/api/users/"))
+	require.True(t, hasPathPrefix("/api/users", "/api/users/"))
+	require.False(t, hasPathPrefix("/api/users-groups", "/api/users"))
+	require.False(t, hasPathPrefix("/api/usersfoo/1", "/api/users"))
+	require.False(t, hasPathPrefix("/api", "/api/users"))
+}
+
+// Signs the claims with the ES256 algorithm for the OIDC middleware tests.
+func signTestOIDCToken(t *testing.T, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": "test-key", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Check that oidcMiddleware passes the user authenticated with a valid
+// OIDC bearer token to the handlers without establishing the session and
+// rejects the invalid tokens.
+func TestOIDCMiddleware(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// Stub of the identity provider's JWKS endpoint.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "EC",
+					"kid": "test-key",
+					"crv": "P-256",
+					"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+					"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+				},
+			},
+		})
+	}))
+	defer jwksServer.Close()
+
+	settings := &RestAPISettings{
+		OIDCIssuer:         "https://idp.example.org",
+		OIDCAudience:       "stork",
+		OIDCJWKSURL:        jwksServer.URL,
+		OIDCRolesClaim:     "groups",
+		OIDCSuperAdminRole: "stork-super-admin",
+		OIDCAdminRole:      "stork-admin",
+	}
+	rapi, err := NewRestAPI(dbSettings, db, settings)
+	require.NoError(t, err)
+	require.NotNil(t, rapi.OIDCVerifier)
+
+	var (
+		authorizeErr error
+		loggedUser   *dbmodel.SystemUser
+	)
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizeErr = rapi.Authorizer(r)
+		_, loggedUser = rapi.getLoggedUser(r.Context())
+	})
+	handler := rapi.oidcMiddleware(nextHandler)
+
+	claims := map[string]interface{}{
+		"iss":         "https://idp.example.org",
+		"aud":         "stork",
+		"sub":         "1234",
+		"email":       "jdoe@example.org",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"groups":      []string{"stork-admin"},
+		"exp":         time.Now().Add(time.Hour).Unix(),
+	}
+
+	// Valid token.
+	ctx, err := rapi.SessionManager.Load(context.Background(), "")
+	require.NoError(t, err)
+	req := httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+signTestOIDCToken(t, key, claims))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.NoError(t, authorizeErr)
+
+	// The user is available to the handlers.
+	require.NotNil(t, loggedUser)
+	require.Equal(t, "oidc:1234", loggedUser.Login)
+	require.True(t, loggedUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.AdminGroupID}))
+
+	// The session has not been established.
+	ok, _ := rapi.SessionManager.Logged(ctx)
+	require.False(t, ok)
+
+	// The user has been provisioned.
+	dbUser, err := dbmodel.GetUserByLogin(db, "oidc:1234")
+	require.NoError(t, err)
+	require.NotNil(t, dbUser)
+	require.Equal(t, "jdoe@example.org", dbUser.Email)
+	require.Len(t, dbUser.Groups, 1)
+	require.Equal(t, dbmodel.AdminGroupID, dbUser.Groups[0].ID)
+
+	// The roles changed in the identity provider are applied to the
+	// existing user.
+	claims["groups"] = []string{"stork-super-admin"}
+	ctx, err = rapi.SessionManager.Load(context.Background(), "")
+	require.NoError(t, err)
+	req = httptest.NewRequest("GET", "http://localhost/api/users", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+signTestOIDCToken(t, key, claims))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.NoError(t, authorizeErr)
+
+	dbUser2, err := dbmodel.GetUserByLogin(db, "oidc:1234")
+	require.NoError(t, err)
+	require.Equal(t, dbUser.ID, dbUser2.ID)
+	require.Len(t, dbUser2.Groups, 1)
+	require.Equal(t, dbmodel.SuperAdminGroupID, dbUser2.Groups[0].ID)
+
+	// Expired token.
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	ctx, err = rapi.SessionManager.Load(context.Background(), "")
+	require.NoError(t, err)
+	req = httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+signTestOIDCToken(t, key, claims))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	ok, _ = rapi.SessionManager.Logged(ctx)
+	require.False(t, ok)
+
+	// Invalid token.
+	req = httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer invalid")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+
+	// Token without any Stork role.
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	claims["groups"] = []string{"other"}
+	req = httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+signTestOIDCToken(t, key, claims))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+
+	// No token and no session.
+	authorizeErr = nil
+	req = httptest.NewRequest("GET", "http://localhost/api/machines", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Error(t, authorizeErr)
+}
+
+// Check that the user account is considered up to date only when it
+// matches the OIDC token claims and the groups mapped from the roles.
+func TestIsOIDCUserUpToDate(t *testing.T) {
+	// Arrange
+	user := &dbmodel.SystemUser{
+		Email:    "jdoe@example.org",
+		Name:     "John",
+		Lastname: "Doe",
+		Groups: []*dbmodel.SystemGroup{
+			{ID: dbmodel.AdminGroupID},
+		},
+	}
+	claims := &auth.OIDCClaims{
+		Email:      "jdoe@example.org",
+		Name:       "John",
+		FamilyName: "Doe",
+	}
+	groups := []*dbmodel.SystemGroup{
+		{ID: dbmodel.AdminGroupID},
+	}
+
+	// Act & Assert
+	require.True(t, isOIDCUserUpToDate(user, claims, groups))
+
+	require.False(t, isOIDCUserUpToDate(user, claims, []*dbmodel.SystemGroup{
+		{ID: dbmodel.SuperAdminGroupID},
+	}))
+	require.False(t, isOIDCUserUpToDate(user, claims, append(groups, &dbmodel.SystemGroup{
+		ID: dbmodel.SuperAdminGroupID,
+	})))
+
+	claims.Email = "john.doe@example.org"
+	require.False(t, isOIDCUserUpToDate(user, claims, groups))
+}
+
+// Check that the OIDC authentication requires the complete settings.
+func TestNewRestAPIInvalidOIDCSettings(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	settings := &RestAPISettings{
+		OIDCIssuer: "https://idp.example.org",
+	}
+	_, err := NewRestAPI(dbSettings, db, settings)
+	require.Error(t, err)
+}
+
 // Check if fileServerMiddleware works and handles requests correctly.
 func TestSSEMiddleware(t *testing.T) {
 	requestReceived := false
@@ -146,6 +567,79 @@ func TestAgentInstallerMiddleware(t *testing.T) {
 	require.True(t, requestReceived)
 }
 
+// Creates empty files with the specified names in the packages directory
+// and returns the agent installer script generated by the middleware.
+func getAgentInstallerScript(t *testing.T, packageFiles ...string) (int, string) {
+	tmpDir := t.TempDir()
+	packagesDir := path.Join(tmpDir, "assets/pkgs")
+	require.NoError(t, os.MkdirAll(packagesDir, 0o755))
+	for _, packageFile := range packageFiles {
+		require.NoError(t, os.WriteFile(path.Join(packagesDir, packageFile), []byte{}, 0o600))
+	}
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := agentInstallerMiddleware(nextHandler, tmpDir)
+	req := httptest.NewRequest("GET", "http://stork.example.org:8080/stork-install-agent.sh", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+// Test that the agent installer script is served when only one type of
+// the agent packages is available.
+func TestAgentInstallerMiddlewareSinglePackage(t *testing.T) {
+	// Act
+	status, script := getAgentInstallerScript(t, "isc-stork-agent_1.6.0_amd64.deb")
+
+	// Assert
+	require.EqualValues(t, http.StatusOK, status)
+	require.Contains(t, script, `curl -o /tmp/isc-stork-agent.deb "stork.example.org:8080/assets/pkgs/isc-stork-agent_1.6.0_amd64.deb"`)
+	require.Contains(t, script, "# The Stork server provides no RPM package of the Stork agent.")
+	require.Contains(t, script, "# The Stork server provides no apk package of the Stork agent.")
+	require.NotContains(t, script, "yum install")
+	require.NotContains(t, script, "apk add")
+	require.Contains(t, script, "stork-agent register -u http://stork.example.org:8080")
+}
+
+// Test that the newest versions of the available agent packages are used
+// in the installer script.
+func TestAgentInstallerMiddlewareMixedPackages(t *testing.T) {
+	// Act
+	status, script := getAgentInstallerScript(t,
+		"isc-stork-agent_1.9.0_amd64.deb",
+		"isc-stork-agent_1.10.0_amd64.deb",
+		"isc-stork-agent_1.2.0_amd64.deb",
+		"isc-stork-agent-1.9.0-1.x86_64.rpm",
+		"isc-stork-server_1.11.0_amd64.deb",
+		"README.txt",
+	)
+
+	// Assert
+	require.EqualValues(t, http.StatusOK, status)
+	require.Contains(t, script, `"stork.example.org:8080/assets/pkgs/isc-stork-agent_1.10.0_amd64.deb"`)
+	require.Contains(t, script, `"stork.example.org:8080/assets/pkgs/isc-stork-agent-1.9.0-1.x86_64.rpm"`)
+	require.Contains(t, script, "yum install -y /tmp/isc-stork-agent.rpm")
+	require.Contains(t, script, "# The Stork server provides no apk package of the Stork agent.")
+	require.NotContains(t, script, "isc-stork-agent_1.9.0_amd64.deb")
+	require.NotContains(t, script, "isc-stork-server")
+	require.NotContains(t, script, "provides no deb package")
+	require.NotContains(t, script, "provides no RPM package")
+}
+
+// Test that the package file names are compared taking into account
+// the numbers they contain.
+func TestComparePackageFilenames(t *testing.T) {
+	require.Zero(t, comparePackageFilenames("isc-stork-agent_1.9.0_amd64.deb", "isc-stork-agent_1.9.0_amd64.deb"))
+	require.Positive(t, comparePackageFilenames("isc-stork-agent_1.10.0_amd64.deb", "isc-stork-agent_1.9.0_amd64.deb"))
+	require.Negative(t, comparePackageFilenames("isc-stork-agent_1.9.0_amd64.deb", "isc-stork-agent_1.10.0_amd64.deb"))
+	require.Positive(t, comparePackageFilenames("isc-stork-agent_2.0.0_amd64.deb", "isc-stork-agent_1.99.99_amd64.deb"))
+	require.Positive(t, comparePackageFilenames("isc-stork-agent_1.9.1_amd64.deb", "isc-stork-agent_1.9.0_amd64.deb"))
+	require.Positive(t, comparePackageFilenames("isc-stork-agent-1.9.0.230102-r0.apk", "isc-stork-agent-1.9.0.221231-r0.apk"))
+	require.Zero(t, comparePackageFilenames("isc-stork-agent_1.09.0.deb", "isc-stork-agent_1.9.0.deb"))
+	require.Positive(t, comparePackageFilenames("isc-stork-agent_1.9.0-2.deb", "isc-stork-agent_1.9.0-1.deb"))
+	require.Negative(t, comparePackageFilenames("isc-stork-agent", "isc-stork-agent_1.9.0.deb"))
+}
+
 // Check if metricsMiddleware works and handles requests correctly.
 func TestMetricsMiddleware(t *testing.T) {
 	// Arrange
@@ -162,6 +656,40 @@ func TestMetricsMiddleware(t *testing.T) {
 	require.EqualValues(t, 1, metrics.RequestCount)
 }
 
+// Check if metricsMiddleware routes the requests for the machine metrics
+// to the machine handler and rejects invalid machine IDs.
+func TestMetricsMiddlewareMachine(t *testing.T) {
+	// Arrange
+	metrics := storktest.NewFakeMetricsCollector()
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := metricsMiddleware(nextHandler, metrics)
+
+	// Act
+	req := httptest.NewRequest("GET", "http://localhost/metrics/machine/42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.EqualValues(t, 1, metrics.RequestCount)
+	require.Equal(t, []int64{42}, metrics.MachineIDs)
+
+	for _, url := range []string{
+		"http://localhost/metrics/machine/",
+		"http://localhost/metrics/machine/foo",
+		"http://localhost/metrics/machine/0",
+		"http://localhost/metrics/machine/1/2",
+	} {
+		// Act
+		req = httptest.NewRequest("GET", url, nil)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		// Assert
+		require.EqualValues(t, http.StatusNotFound, w.Code, url)
+	}
+	require.EqualValues(t, 1, metrics.RequestCount)
+}
+
 // Check if metricsMiddelware returns placeholder when the endpoint is disabled.
 func TestMetricsMiddlewarePlaceholder(t *testing.T) {
 	// Arrange
@@ -216,3 +744,655 @@ func TestLoggingMiddlewareHelpers(t *testing.T) {
 	hdr := lrw.Header()
 	require.Empty(t, hdr)
 }
+
+// Test that the requests to the endpoints having the suppressed path
+// prefixes are not logged at the info level while other requests are.
+func TestLoggingMiddlewareSuppressedPaths(t *testing.T) {
+	// Arrange
+	output := logrus.StandardLogger().Out
+	level := logrus.GetLevel()
+	defer func() {
+		logrus.SetOutput(output)
+		logrus.SetLevel(level)
+	}()
+	var buffer bytes.Buffer
+	logrus.SetOutput(&buffer)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := loggingMiddleware(nextHandler, []string{"/healthz", "/metrics"})
+
+	// Act
+	for _, url := range []string{"http://localhost/healthz", "http://localhost/metrics?foo=bar"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+
+	// Assert
+	require.Empty(t, buffer.String())
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost/api/machines", nil))
+
+	// Assert
+	require.Contains(t, buffer.String(), "HTTP request incoming")
+	require.Contains(t, buffer.String(), "HTTP request served")
+	require.Contains(t, buffer.String(), "/api/machines")
+	require.NotContains(t, buffer.String(), "/healthz")
+	require.NotContains(t, buffer.String(), "/metrics")
+}
+
+// Test that the requests to the endpoints having the suppressed path
+// prefixes are still logged at the debug level.
+func TestLoggingMiddlewareSuppressedPathsDebug(t *testing.T) {
+	// Arrange
+	output := logrus.StandardLogger().Out
+	level := logrus.GetLevel()
+	defer func() {
+		logrus.SetOutput(output)
+		logrus.SetLevel(level)
+	}()
+	var buffer bytes.Buffer
+	logrus.SetOutput(&buffer)
+	logrus.SetLevel(logrus.DebugLevel)
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := loggingMiddleware(nextHandler, []string{"/healthz"})
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost/healthz", nil))
+
+	// Assert
+	require.Contains(t, buffer.String(), "level=debug")
+	require.Contains(t, buffer.String(), "/healthz")
+	require.NotContains(t, buffer.String(), "level=info")
+}
+
+// Test that the response is compressed when the client accepts the gzip
+// encoding.
+func TestCompressionMiddlewareCompressed(t *testing.T) {
+	// Arrange
+	content := strings.Repeat(`{"subnet": "192.0.2.0/24"}`, 100)
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		_, _ = w.Write([]byte(content))
+	})
+	handler := compressionMiddleware(nextHandler)
+	req := httptest.NewRequest("GET", "http://localhost/api/subnets", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	resp := w.Result()
+	defer resp.Body.Close()
+	require.EqualValues(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	require.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+	require.Empty(t, resp.Header.Get("Content-Length"))
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	reader, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(decompressed))
+	require.Less(t, w.Body.Len(), len(content))
+}
+
+// Test that the response is not compressed when the client doesn't
+// accept the gzip encoding.
+func TestCompressionMiddlewareUncompressed(t *testing.T) {
+	for _, acceptEncoding := range []string{"", "deflate, br", "gzip;q=0", "gzip; q=0.0"} {
+		// Arrange
+		nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"foo": "bar"}`))
+		})
+		handler := compressionMiddleware(nextHandler)
+		req := httptest.NewRequest("GET", "http://localhost/api/subnets", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(w, req)
+
+		// Assert
+		require.Empty(t, w.Header().Get("Content-Encoding"), acceptEncoding)
+		require.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), acceptEncoding)
+		require.Equal(t, `{"foo": "bar"}`, w.Body.String(), acceptEncoding)
+	}
+}
+
+// Test that the content type is detected from the uncompressed data
+// when the handler doesn't specify it.
+func TestCompressionMiddlewareDetectContentType(t *testing.T) {
+	// Arrange
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>foo</body></html>"))
+	})
+	handler := compressionMiddleware(nextHandler)
+	req := httptest.NewRequest("GET", "http://localhost/index.html", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+// Test that the already compressed content, the responses without body
+// and the server-sent events are not compressed.
+func TestCompressionMiddlewareSkipped(t *testing.T) {
+	testCases := map[string]struct {
+		method  string
+		path    string
+		status  int
+		headers map[string]string
+	}{
+		"encoded": {
+			method:  "GET",
+			path:    "/metrics",
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Type": "text/plain", "Content-Encoding": "gzip"},
+		},
+		"image": {
+			method:  "GET",
+			path:    "/assets/logo.png",
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Type": "image/png"},
+		},
+		"package": {
+			method:  "GET",
+			path:    "/assets/pkgs/isc-stork-agent.deb",
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Type": "application/vnd.debian.binary-package"},
+		},
+		"partial content": {
+			method:  "GET",
+			path:    "/assets/main.js",
+			status:  http.StatusPartialContent,
+			headers: map[string]string{"Content-Type": "text/javascript", "Content-Range": "bytes 0-2/10"},
+		},
+		"not modified": {
+			method: "GET",
+			path:   "/assets/main.js",
+			status: http.StatusNotModified,
+		},
+		"head": {
+			method:  "HEAD",
+			path:    "/api/subnets",
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Type": "application/json"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range testCase.headers {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(testCase.status)
+				if testCase.status != http.StatusNotModified && r.Method != "HEAD" {
+					_, _ = w.Write([]byte("foo"))
+				}
+			})
+			handler := compressionMiddleware(nextHandler)
+			req := httptest.NewRequest(testCase.method, "http://localhost"+testCase.path, nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			require.Equal(t, testCase.headers["Content-Encoding"], w.Header().Get("Content-Encoding"))
+			require.EqualValues(t, testCase.status, w.Code)
+			if testCase.status != http.StatusNotModified && testCase.method != "HEAD" {
+				require.Equal(t, "foo", w.Body.String())
+			} else {
+				require.Empty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+// Test that the server-sent events stream is neither compressed nor
+// buffered.
+func TestCompressionMiddlewareSSE(t *testing.T) {
+	// Arrange
+	var writer http.ResponseWriter
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer = w
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: foo\n\n"))
+	})
+	handler := compressionMiddleware(nextHandler)
+	req := httptest.NewRequest("GET", "http://localhost/sse?stream=messages", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.Same(t, w, writer)
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	require.Empty(t, w.Header().Get("Vary"))
+	require.Equal(t, "data: foo\n\n", w.Body.String())
+}
+
+// Test that the logging middleware reports the size of the compressed
+// response.
+func TestCompressionMiddlewareLoggedSize(t *testing.T) {
+	// Arrange
+	output := logrus.StandardLogger().Out
+	level := logrus.GetLevel()
+	defer func() {
+		logrus.SetOutput(output)
+		logrus.SetLevel(level)
+	}()
+	var buffer bytes.Buffer
+	logrus.SetOutput(&buffer)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	content := strings.Repeat("foo", 1000)
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(content))
+	})
+	handler := loggingMiddleware(compressionMiddleware(nextHandler), nil)
+	req := httptest.NewRequest("GET", "http://localhost/api/foo", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	require.Less(t, w.Body.Len(), len(content))
+	require.Contains(t, buffer.String(), fmt.Sprintf("size=%d ", w.Body.Len()))
+}
+
+// Test that the gzip encoding acceptance is recognized in the
+// Accept-Encoding header.
+func TestAcceptsGzip(t *testing.T) {
+	testCases := map[string]bool{
+		"":                   false,
+		"gzip":               true,
+		"GZIP":               false,
+		"deflate, gzip":      true,
+		"br;q=1.0, gzip;q=0": false,
+		"gzip;q=0.5":         true,
+		"gzip ; q=0.000":     false,
+		"x-gzip":             false,
+		"*":                  false,
+	}
+	for acceptEncoding, expected := range testCases {
+		req := httptest.NewRequest("GET", "http://localhost/api/foo", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		require.Equal(t, expected, acceptsGzip(req), acceptEncoding)
+	}
+}
+
+// Test that the request ID is generated when the request lacks it and
+// that it is stored in the request context and the response header.
+func TestRequestIDMiddlewareGenerated(t *testing.T) {
+	// Arrange
+	var requestIDs []string
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, GetRequestID(r.Context()))
+	})
+	handler := requestIDMiddleware(nextHandler)
+
+	var responseIDs []string
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "http://localhost/api/machines", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(w, req)
+		responseIDs = append(responseIDs, w.Header().Get("X-Request-ID"))
+	}
+
+	// Assert
+	require.Len(t, requestIDs, 2)
+	require.Len(t, requestIDs[0], 32)
+	require.Equal(t, requestIDs, responseIDs)
+	require.NotEqual(t, requestIDs[0], requestIDs[1])
+}
+
+// Test that the valid request ID received in the request header is used.
+func TestRequestIDMiddlewareInbound(t *testing.T) {
+	// Arrange
+	var requestID string
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = GetRequestID(r.Context())
+	})
+	handler := requestIDMiddleware(nextHandler)
+	req := httptest.NewRequest("GET", "http://localhost/api/machines", nil)
+	req.Header.Set("X-Request-ID", "f9b3c1d2-1234-4e5f-a678-90abcdef1234")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, "f9b3c1d2-1234-4e5f-a678-90abcdef1234", requestID)
+	require.Equal(t, "f9b3c1d2-1234-4e5f-a678-90abcdef1234", w.Header().Get("X-Request-ID"))
+}
+
+// Test that the invalid request ID received in the request header is
+// replaced with the generated one.
+func TestRequestIDMiddlewareInvalidInbound(t *testing.T) {
+	for _, inbound := range []string{"foo\nbar", "foo bar", `foo"bar`, strings.Repeat("a", 129)} {
+		// Arrange
+		var requestID string
+		nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID = GetRequestID(r.Context())
+		})
+		handler := requestIDMiddleware(nextHandler)
+		req := httptest.NewRequest("GET", "http://localhost/api/machines", nil)
+		req.Header.Set("X-Request-ID", inbound)
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.ServeHTTP(w, req)
+
+		// Assert
+		require.NotEqual(t, inbound, requestID)
+		require.Len(t, requestID, 32)
+		require.Equal(t, requestID, w.Header().Get("X-Request-ID"))
+	}
+}
+
+// Test that an empty request ID is returned for the context without it.
+func TestGetRequestIDMissing(t *testing.T) {
+	require.Empty(t, GetRequestID(context.Background()))
+}
+
+// Test that the request ID is included in the log entries produced by
+// the logging middleware and the downstream handler.
+func TestRequestIDMiddlewareLogging(t *testing.T) {
+	// Arrange
+	output := logrus.StandardLogger().Out
+	level := logrus.GetLevel()
+	defer func() {
+		logrus.SetOutput(output)
+		logrus.SetLevel(level)
+	}()
+	var buffer bytes.Buffer
+	logrus.SetOutput(&buffer)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logrus.WithField("request_id", GetRequestID(r.Context())).Info("Handling request")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := requestIDMiddleware(loggingMiddleware(nextHandler, nil))
+	req := httptest.NewRequest("GET", "http://localhost/api/machines", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, req)
+
+	// Assert
+	require.EqualValues(t, http.StatusAccepted, w.Code)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "HTTP request incoming")
+	require.Contains(t, lines[1], "Handling request")
+	require.Contains(t, lines[2], "HTTP request served")
+	require.Contains(t, lines[2], "status=202")
+	for _, line := range lines {
+		require.Contains(t, line, "request_id=abc123")
+	}
+}
+
+// Test that the rate limiter is not created when the rate limiting is
+// disabled.
+func TestNewRateLimiterDisabled(t *testing.T) {
+	require.Nil(t, newRateLimiter(0, time.Second, 10))
+	require.Nil(t, newRateLimiter(-1, time.Second, 10))
+	require.Nil(t, newRateLimiter(10, 0, 10))
+}
+
+// Test that the rate limiter refills the buckets over time and tracks
+// the clients independently.
+func TestRateLimiterAllow(t *testing.T) {
+	// Arrange
+	limiter := newRateLimiter(2, 10*time.Second, 3)
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	// Act & Assert
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.allow("192.0.2.1")
+		require.True(t, ok, "request %d", i)
+	}
+	ok, retryAfter := limiter.allow("192.0.2.1")
+	require.False(t, ok)
+	require.Equal(t, 5*time.Second, retryAfter)
+
+	// Other client is not limited.
+	ok, _ = limiter.allow("192.0.2.2")
+	require.True(t, ok)
+
+	// One token is added after 5 seconds.
+	now = now.Add(5 * time.Second)
+	ok, _ = limiter.allow("192.0.2.1")
+	require.True(t, ok)
+	ok, _ = limiter.allow("192.0.2.1")
+	require.False(t, ok)
+}
+
+// Test that the buckets which have been refilled are removed.
+func TestRateLimiterPrune(t *testing.T) {
+	// Arrange
+	limiter := newRateLimiter(1, time.Second, 2)
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("192.0.2.1")
+	limiter.allow("192.0.2.2")
+	require.Len(t, limiter.buckets, 2)
+
+	// Act
+	now = now.Add(time.Second)
+	limiter.allow("192.0.2.2")
+	now = now.Add(time.Second)
+	limiter.allow("192.0.2.3")
+
+	// Assert
+	require.Len(t, limiter.buckets, 2)
+	require.NotContains(t, limiter.buckets, "192.0.2.1")
+	require.Contains(t, limiter.buckets, "192.0.2.2")
+	require.Contains(t, limiter.buckets, "192.0.2.3")
+}
+
+// Test that the trusted proxies are parsed from the IP addresses and
+// prefixes.
+func TestParseTrustedProxies(t *testing.T) {
+	// Act
+	proxies, err := parseTrustedProxies([]string{"192.0.2.100", " 10.0.0.0/8", "2001:db8::1", ""})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+	require.Equal(t, "192.0.2.100/32", proxies[0].String())
+	require.Equal(t, "10.0.0.0/8", proxies[1].String())
+	require.Equal(t, "2001:db8::1/128", proxies[2].String())
+}
+
+// Test that an error is returned for an invalid trusted proxy.
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	_, err := parseTrustedProxies([]string{"192.0.2.300"})
+	require.Error(t, err)
+
+	_, err = parseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+}
+
+// Test that the client IP address is taken from the X-Real-IP header
+// only if the request comes from a trusted proxy. Otherwise, the
+// connection peer address without the port is used.
+func TestGetClientIP(t *testing.T) {
+	// Arrange
+	trustedProxies, err := parseTrustedProxies([]string{"192.0.2.100", "2001:db8:1::/64"})
+	require.NoError(t, err)
+	req := httptest.NewRequest("GET", "http://localhost/api/users", nil)
+
+	// Act & Assert
+	req.RemoteAddr = "192.0.2.1:1234"
+	require.Equal(t, "192.0.2.1", getClientIP(req, trustedProxies))
+
+	req.RemoteAddr = "[2001:db8::1]:1234"
+	require.Equal(t, "2001:db8::1", getClientIP(req, trustedProxies))
+
+	// The header sent by the untrusted peer is ignored.
+	req.Header.Set("X-Real-IP", "192.0.2.2")
+	require.Equal(t, "2001:db8::1", getClientIP(req, trustedProxies))
+	require.Equal(t, "2001:db8::1", getClientIP(req, nil))
+
+	// The header sent by the trusted proxy is honored.
+	req.RemoteAddr = "[2001:db8:1::5]:1234"
+	require.Equal(t, "192.0.2.2", getClientIP(req, trustedProxies))
+
+	req.RemoteAddr = "192.0.2.100:1234"
+	require.Equal(t, "192.0.2.2", getClientIP(req, trustedProxies))
+}
+
+// Test that the requests from the same IP address exceeding the limit are
+// rejected with HTTP 429 and the Retry-After header, while the requests
+// from other addresses are passed.
+func TestRateLimitingMiddleware(t *testing.T) {
+	// Arrange
+	nextCalls := 0
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalls++
+	})
+	limiter := newRateLimiter(3, time.Minute, 0)
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	trustedProxies, err := parseTrustedProxies([]string{"192.0.2.100"})
+	require.NoError(t, err)
+	handler := rateLimitingMiddleware(nextHandler, limiter, trustedProxies)
+
+	newRequest := func(remoteAddr, realIP string) *http.Request {
+		req := httptest.NewRequest("POST", "http://localhost/api/sessions", nil)
+		req.RemoteAddr = remoteAddr
+		if realIP != "" {
+			req.Header.Set("X-Real-IP", realIP)
+		}
+		return req
+	}
+
+	// Act & Assert
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(fmt.Sprintf("192.0.2.1:%d", 1000+i), ""))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	require.Equal(t, 3, nextCalls)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.0.2.1:2000", ""))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "20", w.Header().Get("Retry-After"))
+	require.Equal(t, 3, nextCalls)
+
+	// The request from the same address behind the proxy is limited too.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.0.2.100:3000", "192.0.2.1"))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, 3, nextCalls)
+
+	// The request from other address is passed.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.0.2.100:3000", "192.0.2.2"))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 4, nextCalls)
+
+	// The limited client cannot bypass the limit by sending the header
+	// directly.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.0.2.1:3000", "192.0.2.3"))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, 4, nextCalls)
+
+	// The requests for the static files are not limited.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost/index.html", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 5, nextCalls)
+
+	// The request is passed after the token is added.
+	now = now.Add(20 * time.Second)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.0.2.1:4000", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 6, nextCalls)
+}
+
+// Test that the requests are not limited when the rate limiting is
+// disabled.
+func TestRateLimitingMiddlewareDisabled(t *testing.T) {
+	// Arrange
+	nextCalls := 0
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalls++
+	})
+	handler := rateLimitingMiddleware(nextHandler, newRateLimiter(0, time.Minute, 0), nil)
+
+	// Act
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost/api/users", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Assert
+	require.Equal(t, 100, nextCalls)
+}
+
+// Test that the rate limiting is disabled by default, so the requests
+// passed by a reverse proxy are not limited together unless the trusted
+// proxies are configured.
+func TestRateLimitingDisabledByDefault(t *testing.T) {
+	// Arrange
+	settings := RestAPISettings{}
+	parser := flags.NewParser(&settings, flags.Default)
+	_, err := parser.ParseArgs([]string{})
+	require.NoError(t, err)
+
+	nextCalls := 0
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalls++
+	})
+	limiter := newRateLimiter(settings.RateLimit, settings.RateLimitInterval, settings.RateLimitBurst)
+	handler := rateLimitingMiddleware(nextHandler, limiter, nil)
+
+	// Act
+	for i := 0; i < 200; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "http://localhost/api/sessions", nil)
+		req.RemoteAddr = "192.0.2.1:4000"
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Assert
+	require.Zero(t, settings.RateLimit)
+	require.Nil(t, limiter)
+	require.Equal(t, 200, nextCalls)
+}
@@ -2,6 +2,7 @@ package restservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -9,9 +10,12 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
 	"isc.org/stork/server/agentcomm"
+	dbmodel "isc.org/stork/server/database/model"
 	"isc.org/stork/server/gen/models"
 	"isc.org/stork/server/gen/restapi/operations/settings"
+	storkutil "isc.org/stork/util"
 )
 
 // Allows accessing the metadata of the periodic puller.
@@ -25,70 +29,99 @@ type pullerMetadata interface {
 
 var _ pullerMetadata = (*agentcomm.PeriodicPuller)(nil)
 
-// Returns a list of puller statuses.
-func (r *RestAPI) GetPullers(ctx context.Context, params settings.GetPullersParams) middleware.Responder {
-	v := reflect.ValueOf(*r.Pullers)
+// Allows executing the periodic puller out of its schedule.
+type pullerExecutor interface {
+	pullerMetadata
+	ExecuteNow() error
+}
 
-	pullers := []*models.Puller{}
+var _ pullerExecutor = (*agentcomm.PeriodicPuller)(nil)
+
+// Returns the puller having the specified ID, i.e., the interval setting
+// name. It returns nil if there is no such puller.
+func (r *RestAPI) getPullerByID(id string) pullerMetadata {
+	v := reflect.ValueOf(*r.Pullers)
 
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		if !field.CanInterface() || field.IsNil() {
 			continue
 		}
+
 		puller, ok := field.Interface().(pullerMetadata)
 		if !ok {
 			continue
 		}
 
-		metadata := &models.Puller{
-			Name:           puller.GetName(),
-			ID:             puller.GetIntervalSettingName(),
-			Interval:       puller.GetInterval(),
-			LastInvokedAt:  strfmt.DateTime(puller.GetLastInvokedAt()),
-			LastFinishedAt: strfmt.DateTime(puller.GetLastFinishedAt()),
+		if puller.GetIntervalSettingName() == id {
+			return puller
 		}
-
-		pullers = append(pullers, metadata)
 	}
+	return nil
+}
 
-	rsp := settings.NewGetPullersOK().WithPayload(&models.Pullers{
-		Items: pullers,
-		Total: int64(len(pullers)),
-	})
-	return rsp
+// Converts the puller metadata to the REST API format.
+func newRestPuller(puller pullerMetadata, paused bool) *models.Puller {
+	return &models.Puller{
+		Name:           puller.GetName(),
+		ID:             puller.GetIntervalSettingName(),
+		Interval:       puller.GetInterval(),
+		LastInvokedAt:  strfmt.DateTime(puller.GetLastInvokedAt()),
+		LastFinishedAt: strfmt.DateTime(puller.GetLastFinishedAt()),
+		Paused:         paused,
+	}
 }
 
-// Returns a specific puller status.
-func (r *RestAPI) GetPuller(ctx context.Context, params settings.GetPullerParams) middleware.Responder {
+// Returns a list of puller statuses.
+func (r *RestAPI) GetPullers(ctx context.Context, params settings.GetPullersParams) middleware.Responder {
+	paused, _, err := dbmodel.GetPullersPauseState(r.DB)
+	if err != nil {
+		log.Error(err)
+		msg := "Cannot get the pullers pause state"
+		rsp := settings.NewGetPullersDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
 	v := reflect.ValueOf(*r.Pullers)
 
+	pullers := []*models.Puller{}
+
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		if !field.CanInterface() || field.IsNil() {
 			continue
 		}
-
 		puller, ok := field.Interface().(pullerMetadata)
 		if !ok {
 			continue
 		}
 
-		pullerID := puller.GetIntervalSettingName()
+		pullers = append(pullers, newRestPuller(puller, paused))
+	}
 
-		if params.ID != pullerID {
-			continue
-		}
+	rsp := settings.NewGetPullersOK().WithPayload(&models.Pullers{
+		Items: pullers,
+		Total: int64(len(pullers)),
+	})
+	return rsp
+}
 
-		metadata := &models.Puller{
-			Name:           puller.GetName(),
-			ID:             puller.GetIntervalSettingName(),
-			Interval:       puller.GetInterval(),
-			LastInvokedAt:  strfmt.DateTime(puller.GetLastInvokedAt()),
-			LastFinishedAt: strfmt.DateTime(puller.GetLastFinishedAt()),
-		}
+// Returns a specific puller status.
+func (r *RestAPI) GetPuller(ctx context.Context, params settings.GetPullerParams) middleware.Responder {
+	paused, _, err := dbmodel.GetPullersPauseState(r.DB)
+	if err != nil {
+		log.Error(err)
+		msg := "Cannot get the pullers pause state"
+		rsp := settings.NewGetPullerDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
 
-		rsp := settings.NewGetPullerOK().WithPayload(metadata)
+	if puller := r.getPullerByID(params.ID); puller != nil {
+		rsp := settings.NewGetPullerOK().WithPayload(newRestPuller(puller, paused))
 		return rsp
 	}
 
@@ -98,3 +131,97 @@ func (r *RestAPI) GetPuller(ctx context.Context, params settings.GetPullerParams
 	})
 	return rsp
 }
+
+// Executes a specific puller immediately, without waiting for its interval
+// to elapse. It returns the puller status after the execution. It returns
+// HTTP 409 Conflict if the puller is already running or all pullers are
+// paused.
+func (r *RestAPI) ExecutePuller(ctx context.Context, params settings.ExecutePullerParams) middleware.Responder {
+	puller, ok := r.getPullerByID(params.ID).(pullerExecutor)
+	if !ok {
+		msg := fmt.Sprintf("Cannot find puller with ID %s", params.ID)
+		rsp := settings.NewExecutePullerDefault(http.StatusNotFound).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	paused, _, err := dbmodel.GetPullersPauseState(r.DB)
+	if err != nil {
+		log.Error(err)
+		msg := "Cannot get the pullers pause state"
+		rsp := settings.NewExecutePullerDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	if paused {
+		msg := fmt.Sprintf("Cannot execute puller with ID %s because all pullers are paused", params.ID)
+		rsp := settings.NewExecutePullerDefault(http.StatusConflict).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	err = puller.ExecuteNow()
+	switch {
+	case errors.Is(err, storkutil.ErrPeriodicExecutorRunning):
+		msg := fmt.Sprintf("Puller with ID %s is already running", params.ID)
+		rsp := settings.NewExecutePullerDefault(http.StatusConflict).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	case err != nil:
+		log.WithError(err).Errorf("Problem executing puller %s", params.ID)
+		msg := fmt.Sprintf("Problem executing puller with ID %s", params.ID)
+		rsp := settings.NewExecutePullerDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	log.Infof("Puller %s has been executed on demand", params.ID)
+	r.recordAuditLog(ctx, "puller_executed", puller.GetName())
+
+	rsp := settings.NewExecutePullerOK().WithPayload(newRestPuller(puller, false))
+	return rsp
+}
+
+// Pauses or resumes all pullers. The pullers are paused until the specified
+// end time or, if it is not specified, until they are explicitly resumed.
+func (r *RestAPI) UpdatePullersPause(ctx context.Context, params settings.UpdatePullersPauseParams) middleware.Responder {
+	if params.Pause == nil {
+		msg := "Missing pullers pause state"
+		log.Error(msg)
+		rsp := settings.NewUpdatePullersPauseDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	var err error
+	if params.Pause.Paused {
+		err = dbmodel.PauseAllPullers(r.DB, time.Time(params.Pause.PausedUntil))
+	} else {
+		err = dbmodel.ResumeAllPullers(r.DB)
+	}
+	if err != nil {
+		log.Error(err)
+		msg := "Problem updating the pullers pause state"
+		rsp := settings.NewUpdatePullersPauseDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	if params.Pause.Paused {
+		log.Info("All pullers have been paused")
+		r.recordAuditLog(ctx, "pullers_paused", "all pullers")
+	} else {
+		log.Info("All pullers have been resumed")
+		r.recordAuditLog(ctx, "pullers_resumed", "all pullers")
+	}
+
+	rsp := settings.NewUpdatePullersPauseOK()
+	return rsp
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/stretchr/testify/require"
+	"isc.org/stork/server/agentcomm"
 	apps "isc.org/stork/server/apps"
 	"isc.org/stork/server/apps/bind9"
 	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
+	"isc.org/stork/server/gen/models"
 	"isc.org/stork/server/gen/restapi/operations/settings"
 )
 
@@ -100,3 +104,206 @@ func TestGetNonExistPuller(t *testing.T) {
 	rspDefault := rsp.(*settings.GetPullerDefault)
 	require.Equal(t, http.StatusNotFound, getStatusCode(*rspDefault))
 }
+
+// Test that the puller is executed on demand and its updated status is
+// returned.
+func TestExecutePuller(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	rapiSettings := RestAPISettings{}
+
+	bind9Puller, _ := bind9.NewStatsPuller(db, nil, nil)
+	defer bind9Puller.Shutdown()
+	pullers := &apps.Pullers{
+		Bind9StatsPuller: bind9Puller,
+	}
+	rapi, _ := NewRestAPI(&rapiSettings, dbSettings, db, pullers)
+
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	params := settings.ExecutePullerParams{
+		ID: "bind9_stats_puller_interval",
+	}
+	require.Zero(t, bind9Puller.GetLastInvokedAt())
+
+	// Act
+	rsp := rapi.ExecutePuller(ctx, params)
+
+	// Assert
+	require.IsType(t, &settings.ExecutePullerOK{}, rsp)
+	rspOk := rsp.(*settings.ExecutePullerOK)
+	require.EqualValues(t, "bind9_stats_puller_interval", rspOk.Payload.ID)
+	require.NotZero(t, bind9Puller.GetLastInvokedAt())
+	require.EqualValues(t, bind9Puller.GetLastInvokedAt().Unix(), time.Time(rspOk.Payload.LastInvokedAt).Unix())
+	require.EqualValues(t, bind9Puller.GetLastFinishedAt().Unix(), time.Time(rspOk.Payload.LastFinishedAt).Unix())
+
+	entries, _, _ := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 1)
+	require.Equal(t, "puller_executed", entries[0].Action)
+}
+
+// Test that the HTTP 404 Not Found status is returned when executing
+// a puller which doesn't exist.
+func TestExecuteNonExistPuller(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	rapiSettings := RestAPISettings{}
+	rapi, _ := NewRestAPI(&rapiSettings, dbSettings, db, &apps.Pullers{})
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+
+	// Act
+	rsp := rapi.ExecutePuller(ctx, settings.ExecutePullerParams{ID: "not_exists"})
+
+	// Assert
+	require.IsType(t, &settings.ExecutePullerDefault{}, rsp)
+	rspDefault := rsp.(*settings.ExecutePullerDefault)
+	require.Equal(t, http.StatusNotFound, getStatusCode(*rspDefault))
+}
+
+// Test that the HTTP 409 Conflict status is returned when executing
+// a puller which is already running or when the pullers are paused.
+func TestExecutePullerConflict(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	started := make(chan bool)
+	finish := make(chan bool)
+	puller, _ := agentcomm.NewPeriodicPuller(db, nil, "test puller", "bind9_stats_puller_interval", func() error {
+		started <- true
+		<-finish
+		return nil
+	})
+	defer puller.Shutdown()
+	pullers := &apps.Pullers{
+		Bind9StatsPuller: &bind9.StatsPuller{PeriodicPuller: puller},
+	}
+
+	rapiSettings := RestAPISettings{}
+	rapi, _ := NewRestAPI(&rapiSettings, dbSettings, db, pullers)
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	params := settings.ExecutePullerParams{
+		ID: "bind9_stats_puller_interval",
+	}
+
+	done := make(chan bool)
+	go func() {
+		_ = puller.ExecuteNow()
+		done <- true
+	}()
+	<-started
+
+	// Act
+	rsp := rapi.ExecutePuller(ctx, params)
+
+	// Assert
+	require.IsType(t, &settings.ExecutePullerDefault{}, rsp)
+	rspDefault := rsp.(*settings.ExecutePullerDefault)
+	require.Equal(t, http.StatusConflict, getStatusCode(*rspDefault))
+
+	finish <- true
+	<-done
+
+	// Arrange
+	require.NoError(t, dbmodel.PauseAllPullers(db, time.Time{}))
+
+	// Act
+	rsp = rapi.ExecutePuller(ctx, params)
+
+	// Assert
+	require.IsType(t, &settings.ExecutePullerDefault{}, rsp)
+	rspDefault = rsp.(*settings.ExecutePullerDefault)
+	require.Equal(t, http.StatusConflict, getStatusCode(*rspDefault))
+}
+
+// Test that all pullers can be paused and resumed and that the puller
+// metadata reflect the paused state.
+func TestUpdatePullersPause(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	_ = dbmodel.InitializeSettings(db, 0)
+
+	rapiSettings := RestAPISettings{}
+
+	bind9Puller, _ := bind9.NewStatsPuller(db, nil, nil)
+	defer bind9Puller.Shutdown()
+	pullers := &apps.Pullers{
+		Bind9StatsPuller: bind9Puller,
+	}
+	rapi, _ := NewRestAPI(&rapiSettings, dbSettings, db, pullers)
+
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+	until := time.Now().Add(time.Hour)
+	params := settings.UpdatePullersPauseParams{
+		Pause: &models.PullersPause{
+			Paused:      true,
+			PausedUntil: strfmt.DateTime(until),
+		},
+	}
+
+	// Act
+	rsp := rapi.UpdatePullersPause(ctx, params)
+
+	// Assert
+	require.IsType(t, &settings.UpdatePullersPauseOK{}, rsp)
+
+	paused, pausedUntil, err := dbmodel.GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.True(t, paused)
+	require.EqualValues(t, until.Unix(), pausedUntil.Unix())
+
+	entries, _, _ := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 1)
+	require.Equal(t, "pullers_paused", entries[0].Action)
+
+	rsp = rapi.GetPullers(ctx, settings.GetPullersParams{})
+	require.IsType(t, &settings.GetPullersOK{}, rsp)
+	rspOk := rsp.(*settings.GetPullersOK)
+	require.Len(t, rspOk.Payload.Items, 1)
+	require.True(t, rspOk.Payload.Items[0].Paused)
+
+	// Act
+	params.Pause = &models.PullersPause{
+		Paused: false,
+	}
+	rsp = rapi.UpdatePullersPause(ctx, params)
+
+	// Assert
+	require.IsType(t, &settings.UpdatePullersPauseOK{}, rsp)
+
+	paused, _, err = dbmodel.GetPullersPauseState(db)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	rsp = rapi.GetPullers(ctx, settings.GetPullersParams{})
+	require.IsType(t, &settings.GetPullersOK{}, rsp)
+	rspOk = rsp.(*settings.GetPullersOK)
+	require.False(t, rspOk.Payload.Items[0].Paused)
+}
+
+// Test that the HTTP 400 Bad Request status is returned when the pullers
+// pause state is not specified.
+func TestUpdatePullersPauseMissingState(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rapiSettings := RestAPISettings{}
+	rapi, _ := NewRestAPI(&rapiSettings, dbSettings, db, &apps.Pullers{})
+	ctx, _ := rapi.SessionManager.Load(context.Background(), "")
+
+	// Act
+	rsp := rapi.UpdatePullersPause(ctx, settings.UpdatePullersPauseParams{})
+
+	// Assert
+	require.IsType(t, &settings.UpdatePullersPauseDefault{}, rsp)
+	rspDefault := rsp.(*settings.UpdatePullersPauseDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*rspDefault))
+}
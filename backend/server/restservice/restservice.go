@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"io/fs"
 	"net"
 	"net/http"
 	"os"
@@ -23,6 +24,7 @@ import (
 	keaconfig "isc.org/stork/appcfg/kea"
 	"isc.org/stork/server/agentcomm"
 	"isc.org/stork/server/apps"
+	"isc.org/stork/server/auth"
 	"isc.org/stork/server/config"
 	"isc.org/stork/server/configreview"
 	dbops "isc.org/stork/server/database"
@@ -38,6 +40,7 @@ type RestAPISettings struct {
 	GracefulTimeout time.Duration    `long:"rest-graceful-timeout" description:"the waiting period before shutting down the server" default:"15s"`
 	MaxHeaderSize   flagext.ByteSize `long:"rest-max-header-size" description:"controls the maximum number of bytes the server reads when parsing the request header's keys and values, including the request line. It does not limit the size of the request body." default:"1MiB"`
 
+	Network      string        `long:"rest-network" description:"the network to listen on: tcp4 for IPv4 only, tcp6 for IPv6 only or tcp for both" choice:"tcp" choice:"tcp4" choice:"tcp6" default:"tcp" env:"STORK_REST_NETWORK"`
 	Host         string        `long:"rest-host" description:"the IP to listen on" default:"" env:"STORK_REST_HOST"`
 	Port         int           `long:"rest-port" description:"the port to listen on for connections" default:"8080" env:"STORK_REST_PORT"`
 	ListenLimit  int           `long:"rest-listen-limit" description:"limits the number of outstanding requests"`
@@ -49,7 +52,22 @@ type RestAPISettings struct {
 	TLSCertificateKey flags.Filename `long:"rest-tls-key" description:"the private key to use for secure connections" env:"STORK_REST_TLS_PRIVATE_KEY"`
 	TLSCACertificate  flags.Filename `long:"rest-tls-ca" description:"the certificate authority file to be used with mutual tls auth" env:"STORK_REST_TLS_CA_CERTIFICATE"`
 
-	StaticFilesDir string `long:"rest-static-files-dir" description:"the directory with static files for the UI" default:"" env:"STORK_REST_STATIC_FILES_DIR"`
+	StaticFilesDir    string        `long:"rest-static-files-dir" description:"the directory with static files for the UI" default:"" env:"STORK_REST_STATIC_FILES_DIR"`
+	StaticFilesMaxAge time.Duration `long:"rest-static-files-max-age" description:"the period for which the browsers may cache the static files with the content hash in their names; zero disables the caching" default:"8760h" env:"STORK_REST_STATIC_FILES_MAX_AGE"`
+
+	RateLimit               int           `long:"rest-rate-limit" description:"the maximum number of API requests a single client IP address may send in the rate limit interval; zero disables the rate limiting" default:"0" env:"STORK_REST_RATE_LIMIT"`
+	RateLimitInterval       time.Duration `long:"rest-rate-limit-interval" description:"the interval in which the rate limit applies" default:"10s" env:"STORK_REST_RATE_LIMIT_INTERVAL"`
+	RateLimitBurst          int           `long:"rest-rate-limit-burst" description:"the maximum number of API requests a single client IP address may send at once; zero means the same as the rate limit" default:"0" env:"STORK_REST_RATE_LIMIT_BURST"`
+	RateLimitTrustedProxies []string      `long:"rest-rate-limit-trusted-proxies" description:"the IP addresses or prefixes of the reverse proxies trusted to pass the client IP address in the X-Real-IP header for the rate limiting; the header sent by other peers is ignored" env:"STORK_REST_RATE_LIMIT_TRUSTED_PROXIES" env-delim:","`
+
+	LogSuppressedPaths []string `long:"rest-log-suppressed-paths" description:"the path prefixes of the endpoints whose requests are logged at the debug level instead of the info level, e.g., the health checks and metrics" default:"/healthz" default:"/metrics" env:"STORK_REST_LOG_SUPPRESSED_PATHS" env-delim:","`
+
+	OIDCIssuer         string `long:"rest-oidc-issuer" description:"the OIDC issuer of the bearer tokens accepted as an alternative to the session-based login; empty disables the OIDC authentication" default:"" env:"STORK_REST_OIDC_ISSUER"`
+	OIDCAudience       string `long:"rest-oidc-audience" description:"the audience the OIDC bearer tokens must be issued for" default:"" env:"STORK_REST_OIDC_AUDIENCE"`
+	OIDCJWKSURL        string `long:"rest-oidc-jwks-url" description:"the URL of the JSON Web Key Set of the OIDC issuer" default:"" env:"STORK_REST_OIDC_JWKS_URL"`
+	OIDCRolesClaim     string `long:"rest-oidc-roles-claim" description:"the OIDC token claim holding the user roles" default:"groups" env:"STORK_REST_OIDC_ROLES_CLAIM"`
+	OIDCSuperAdminRole string `long:"rest-oidc-super-admin-role" description:"the OIDC role mapped to the Stork super-admin group" default:"stork-super-admin" env:"STORK_REST_OIDC_SUPER_ADMIN_ROLE"`
+	OIDCAdminRole      string `long:"rest-oidc-admin-role" description:"the OIDC role mapped to the Stork admin group" default:"stork-admin" env:"STORK_REST_OIDC_ADMIN_ROLE"`
 }
 
 // Runtime information and settings for RestAPI service.
@@ -64,6 +82,16 @@ type RestAPI struct {
 	MetricsCollector           metrics.Collector
 	ConfigManager              config.Manager
 	DHCPOptionDefinitionLookup keaconfig.DHCPOptionDefinitionLookup
+	// Filesystem with the UI files embedded in the binary. It is used
+	// when no static files directory has been explicitly configured. It
+	// is nil if the binary has been built without the UI.
+	EmbeddedStaticFiles fs.FS
+	// Path prefixes of the sensitive endpoints which require the user
+	// to be verified with a second authentication factor.
+	SecondFactorRequiredPaths []string
+	// Verifier of the OIDC bearer tokens. It is nil when the OIDC
+	// authentication is disabled.
+	OIDCVerifier *auth.OIDCVerifier
 
 	Agents agentcomm.ConnectedAgents
 
@@ -194,6 +222,24 @@ func NewRestAPI(args ...interface{}) (*RestAPI, error) {
 	}
 	api.SessionManager = sm
 
+	api.EmbeddedStaticFiles = getEmbeddedStaticFiles()
+
+	// Instantiate the OIDC token verifier if the issuer is configured.
+	if api.Settings != nil && api.Settings.OIDCIssuer != "" {
+		verifier, err := auth.NewOIDCVerifier(auth.OIDCSettings{
+			Issuer:         api.Settings.OIDCIssuer,
+			Audience:       api.Settings.OIDCAudience,
+			JWKSURL:        api.Settings.OIDCJWKSURL,
+			RolesClaim:     api.Settings.OIDCRolesClaim,
+			SuperAdminRole: api.Settings.OIDCSuperAdminRole,
+			AdminRole:      api.Settings.OIDCAdminRole,
+		})
+		if err != nil {
+			return nil, pkgerrors.WithMessage(err, "invalid OIDC authentication settings")
+		}
+		api.OIDCVerifier = verifier
+	}
+
 	// All ok.
 	return api, nil
 }
@@ -291,6 +337,10 @@ func (r *RestAPI) Serve() (err error) {
 			// return the token.
 			return token, nil
 		},
+		AuthBearer: func(token string) (interface{}, error) {
+			// The bearer token is verified in the OIDC middleware.
+			return token, nil
+		},
 	})
 	if err != nil {
 		return pkgerrors.Wrap(err, "cannot setup RESTful API handler")
@@ -327,6 +377,12 @@ func (r *RestAPI) Serve() (err error) {
 		httpServer.IdleTimeout = s.CleanupTimeout
 	}
 
+	var staticFiles fs.FS
+	if s.StaticFilesDir == "" && r.EmbeddedStaticFiles != nil {
+		// Serve the UI from the filesystem embedded in the binary.
+		log.Info("Serving the UI embedded in the binary")
+		staticFiles = r.EmbeddedStaticFiles
+	}
 	if s.StaticFilesDir == "" {
 		devPath := "./webui/dist/stork"
 		// Check if the Stork is running in the development environment.
@@ -337,7 +393,19 @@ func (r *RestAPI) Serve() (err error) {
 			s.StaticFilesDir = devPath
 		}
 	}
-	httpServer.Handler = r.GlobalMiddleware(r.handler, s.StaticFilesDir, r.EventCenter)
+	if staticFiles == nil {
+		if _, err := os.Stat(s.StaticFilesDir); err != nil {
+			log.WithError(err).Errorf("The UI is not embedded in the binary and the static files directory %s is not accessible; the UI will not be available", s.StaticFilesDir)
+		} else {
+			log.Infof("Serving the UI from the %s directory", s.StaticFilesDir)
+		}
+		staticFiles = os.DirFS(s.StaticFilesDir)
+	}
+	trustedProxies, err := parseTrustedProxies(s.RateLimitTrustedProxies)
+	if err != nil {
+		return err
+	}
+	httpServer.Handler = r.GlobalMiddleware(r.handler, s.StaticFilesDir, staticFiles, s.StaticFilesMaxAge, r.EventCenter, s.LogSuppressedPaths, s.RateLimit, s.RateLimitInterval, s.RateLimitBurst, trustedProxies)
 
 	if r.TLS {
 		err = prepareTLS(httpServer, s)
@@ -375,6 +443,12 @@ func (r *RestAPI) Listen() error {
 
 	s := r.Settings
 
+	// Listen on both IPv4 and IPv6 unless the network is specified.
+	network := s.Network
+	if network == "" {
+		network = "tcp"
+	}
+
 	if s.TLSCertificate == "" {
 		r.TLS = false
 	} else {
@@ -383,7 +457,7 @@ func (r *RestAPI) Listen() error {
 
 	if !r.TLS {
 		// TLS disabled
-		listener, err := net.Listen("tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+		listener, err := net.Listen(network, net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
 		if err != nil {
 			return pkgerrors.Wrap(err, "problem occurred while starting to listen using RESTful API")
 		}
@@ -398,7 +472,7 @@ func (r *RestAPI) Listen() error {
 	} else {
 		// TLS enabled
 
-		tlsListener, err := net.Listen("tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+		tlsListener, err := net.Listen(network, net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
 		if err != nil {
 			return pkgerrors.Wrap(err, "problem occurred while starting to listen using RESTful API")
 		}
@@ -1,6 +1,7 @@
 package restservice
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -109,3 +110,47 @@ func TestNewRestAPI(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, api)
 }
+
+// Tests that the RestAPI listens on the requested network.
+func TestListenNetwork(t *testing.T) {
+	db, dbs, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	// Listen on all IPv4 addresses.
+	settings := &RestAPISettings{
+		Network: "tcp4",
+	}
+	api, err := NewRestAPI(settings, dbs, db)
+	require.NoError(t, err)
+
+	err = api.Listen()
+	require.NoError(t, err)
+	defer api.srvListener.Close()
+
+	addr, ok := api.srvListener.Addr().(*net.TCPAddr)
+	require.True(t, ok)
+	require.NotNil(t, addr.IP.To4())
+	require.Equal(t, "0.0.0.0", api.Host)
+	require.NotZero(t, api.Port)
+
+	// The IPv6 address cannot be used with the IPv4 network.
+	settings = &RestAPISettings{
+		Network: "tcp4",
+		Host:    "::1",
+	}
+	api, err = NewRestAPI(settings, dbs, db)
+	require.NoError(t, err)
+
+	err = api.Listen()
+	require.Error(t, err)
+}
+
+// Test that no embedded UI is returned when the www directory holds only
+// the placeholder file.
+func TestGetEmbeddedStaticFilesPlaceholderOnly(t *testing.T) {
+	// Act
+	files := getEmbeddedStaticFiles()
+
+	// Assert
+	require.Nil(t, files)
+}
@@ -42,7 +42,7 @@ func (r *RestAPI) SearchRecords(ctx context.Context, params search.SearchRecords
 	text := strings.TrimSpace(*params.Text)
 
 	// get list of subnets
-	subnets, err := r.getSubnets(0, 5, 0, 0, &text, "", dbmodel.SortDirAny)
+	subnets, err := r.getSubnets(0, 5, 0, 0, &text, nil, "", dbmodel.SortDirAny)
 	if err != nil {
 		return handleSearchError(err, "Cannot get subnets from the db")
 	}
@@ -2,6 +2,7 @@ package restservice
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/runtime/middleware"
@@ -25,14 +26,20 @@ func (r *RestAPI) GetSettings(ctx context.Context, params settings.GetSettingsPa
 	}
 
 	s := &models.Settings{
-		Bind9StatsPullerInterval: dbSettingsMap["bind9_stats_puller_interval"].(int64),
-		GrafanaURL:               dbSettingsMap["grafana_url"].(string),
-		KeaHostsPullerInterval:   dbSettingsMap["kea_hosts_puller_interval"].(int64),
-		KeaStatsPullerInterval:   dbSettingsMap["kea_stats_puller_interval"].(int64),
-		KeaStatusPullerInterval:  dbSettingsMap["kea_status_puller_interval"].(int64),
-		AppsStatePullerInterval:  dbSettingsMap["apps_state_puller_interval"].(int64),
-		PrometheusURL:            dbSettingsMap["prometheus_url"].(string),
-		MetricsCollectorInterval: dbSettingsMap["metrics_collector_interval"].(int64),
+		Bind9StatsPullerInterval:    dbSettingsMap["bind9_stats_puller_interval"].(int64),
+		GrafanaURL:                  dbSettingsMap["grafana_url"].(string),
+		KeaHostsPullerInterval:      dbSettingsMap["kea_hosts_puller_interval"].(int64),
+		KeaStatsPullerInterval:      dbSettingsMap["kea_stats_puller_interval"].(int64),
+		KeaStatsPullerBatchSize:     dbSettingsMap["kea_stats_puller_batch_size"].(int64),
+		KeaStatusPullerInterval:     dbSettingsMap["kea_status_puller_interval"].(int64),
+		AppsStatePullerInterval:     dbSettingsMap["apps_state_puller_interval"].(int64),
+		PrometheusURL:               dbSettingsMap["prometheus_url"].(string),
+		MetricsCollectorInterval:    dbSettingsMap["metrics_collector_interval"].(int64),
+		MetricsUtilizationHistogram: dbSettingsMap["metrics_utilization_histogram"].(bool),
+		MinPoolSize:                 dbSettingsMap["min_pool_size"].(int64),
+		MaxCheckerFindings:          dbSettingsMap["max_checker_findings"].(int64),
+		OverlapsDetectionTimeBudget: dbSettingsMap["overlaps_detection_time_budget"].(int64),
+		ConfigReviewPullerInterval:  dbSettingsMap["config_review_puller_interval"].(int64),
 	}
 	rsp := settings.NewGetSettingsOK().WithPayload(s)
 
@@ -76,6 +83,11 @@ func (r *RestAPI) UpdateSettings(ctx context.Context, params settings.UpdateSett
 		log.Error(err)
 		return errRsp
 	}
+	err = dbmodel.SetSettingInt(r.DB, "kea_stats_puller_batch_size", s.KeaStatsPullerBatchSize)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
 	err = dbmodel.SetSettingInt(r.DB, "kea_status_puller_interval", s.KeaStatusPullerInterval)
 	if err != nil {
 		log.Error(err)
@@ -96,7 +108,113 @@ func (r *RestAPI) UpdateSettings(ctx context.Context, params settings.UpdateSett
 		log.Error(err)
 		return errRsp
 	}
+	err = dbmodel.SetSettingBool(r.DB, "metrics_utilization_histogram", s.MetricsUtilizationHistogram)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
+	err = dbmodel.SetSettingInt(r.DB, "min_pool_size", s.MinPoolSize)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
+	err = dbmodel.SetSettingInt(r.DB, "max_checker_findings", s.MaxCheckerFindings)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
+	err = dbmodel.SetSettingInt(r.DB, "overlaps_detection_time_budget", s.OverlapsDetectionTimeBudget)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
+	err = dbmodel.SetSettingInt(r.DB, "config_review_puller_interval", s.ConfigReviewPullerInterval)
+	if err != nil {
+		log.Error(err)
+		return errRsp
+	}
 
 	rsp := settings.NewUpdateSettingsOK()
 	return rsp
 }
+
+// Logging levels which can be set at runtime.
+var supportedLogLevels = map[string]log.Level{
+	"debug": log.DebugLevel,
+	"info":  log.InfoLevel,
+	"warn":  log.WarnLevel,
+	"error": log.ErrorLevel,
+}
+
+// Returns the name of the logging level. It returns warn instead of
+// the warning returned by the logrus.
+func getLogLevelName(level log.Level) string {
+	for name, supportedLevel := range supportedLogLevels {
+		if level == supportedLevel {
+			return name
+		}
+	}
+	return level.String()
+}
+
+// Get the current logging level of the server. Only the super-admin
+// is permitted to get it.
+func (r *RestAPI) GetLogLevel(ctx context.Context, params settings.GetLogLevelParams) middleware.Responder {
+	_, dbUser := r.getLoggedUser(ctx)
+	if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
+		msg := "User is forbidden to get the logging level"
+		rsp := settings.NewGetLogLevelDefault(http.StatusForbidden).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	level := getLogLevelName(log.GetLevel())
+	rsp := settings.NewGetLogLevelOK().WithPayload(&models.LogLevel{
+		Level: &level,
+	})
+	return rsp
+}
+
+// Set the logging level of the server at runtime. Only the super-admin
+// is permitted to set it. The change is recorded in the audit log.
+func (r *RestAPI) SetLogLevel(ctx context.Context, params settings.SetLogLevelParams) middleware.Responder {
+	_, dbUser := r.getLoggedUser(ctx)
+	if !dbUser.InGroup(&dbmodel.SystemGroup{ID: dbmodel.SuperAdminGroupID}) {
+		msg := "User is forbidden to set the logging level"
+		rsp := settings.NewSetLogLevelDefault(http.StatusForbidden).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	if params.Level == nil || params.Level.Level == nil {
+		msg := "Missing logging level"
+		log.Error(msg)
+		rsp := settings.NewSetLogLevelDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	level, ok := supportedLogLevels[*params.Level.Level]
+	if !ok {
+		msg := fmt.Sprintf("Unsupported logging level %s, supported levels are debug, info, warn and error", *params.Level.Level)
+		log.Error(msg)
+		rsp := settings.NewSetLogLevelDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	oldLevel := getLogLevelName(log.GetLevel())
+	log.SetLevel(level)
+	newLevel := getLogLevelName(level)
+	log.Infof("Logging level changed from %s to %s", oldLevel, newLevel)
+	r.recordAuditLog(ctx, "log_level_changed", fmt.Sprintf("log level %s", newLevel))
+
+	rsp := settings.NewSetLogLevelOK().WithPayload(&models.LogLevel{
+		Level: &newLevel,
+	})
+	return rsp
+}
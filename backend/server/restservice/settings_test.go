@@ -2,8 +2,10 @@ package restservice
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	agentcommtest "isc.org/stork/server/agentcomm/test"
 	dbmodel "isc.org/stork/server/database/model"
@@ -57,3 +59,129 @@ func TestSettings(t *testing.T) {
 	require.EqualValues(t, 10, okRsp.Payload.Bind9StatsPullerInterval)
 	require.EqualValues(t, "http://localhost:3000", okRsp.Payload.GrafanaURL)
 }
+
+// Test that the super-admin can change the logging level at runtime
+// and that the invalid levels are rejected.
+func TestSetLogLevel(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.InfoLevel)
+
+	rapi, err := NewRestAPI(dbSettings, db)
+	require.NoError(t, err)
+
+	user, err := dbmodel.GetUserByID(rapi.DB, 1)
+	require.NoError(t, err)
+	ctx, err := rapi.SessionManager.Load(context.Background(), "")
+	require.NoError(t, err)
+	err = rapi.SessionManager.LoginHandler(ctx, user)
+	require.NoError(t, err)
+
+	level := "debug"
+
+	// Act
+	rsp := rapi.SetLogLevel(ctx, settings.SetLogLevelParams{
+		Level: &models.LogLevel{Level: &level},
+	})
+
+	// Assert
+	require.IsType(t, &settings.SetLogLevelOK{}, rsp)
+	require.Equal(t, "debug", *rsp.(*settings.SetLogLevelOK).Payload.Level)
+	require.Equal(t, log.DebugLevel, log.GetLevel())
+
+	entries, _, _ := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 1)
+	require.Equal(t, "log_level_changed", entries[0].Action)
+	require.Equal(t, "log level debug", entries[0].Target)
+
+	rsp = rapi.GetLogLevel(ctx, settings.GetLogLevelParams{})
+	require.IsType(t, &settings.GetLogLevelOK{}, rsp)
+	require.Equal(t, "debug", *rsp.(*settings.GetLogLevelOK).Payload.Level)
+
+	// Act
+	level = "warn"
+	rsp = rapi.SetLogLevel(ctx, settings.SetLogLevelParams{
+		Level: &models.LogLevel{Level: &level},
+	})
+
+	// Assert
+	require.IsType(t, &settings.SetLogLevelOK{}, rsp)
+	require.Equal(t, "warn", *rsp.(*settings.SetLogLevelOK).Payload.Level)
+	require.Equal(t, log.WarnLevel, log.GetLevel())
+
+	// Act
+	for _, invalid := range []string{"verbose", "trace", "fatal", ""} {
+		invalid := invalid
+		rsp = rapi.SetLogLevel(ctx, settings.SetLogLevelParams{
+			Level: &models.LogLevel{Level: &invalid},
+		})
+
+		// Assert
+		require.IsType(t, &settings.SetLogLevelDefault{}, rsp, invalid)
+		require.Equal(t, http.StatusBadRequest, getStatusCode(*rsp.(*settings.SetLogLevelDefault)))
+		require.Equal(t, log.WarnLevel, log.GetLevel())
+	}
+
+	// Act
+	rsp = rapi.SetLogLevel(ctx, settings.SetLogLevelParams{})
+
+	// Assert
+	require.IsType(t, &settings.SetLogLevelDefault{}, rsp)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*rsp.(*settings.SetLogLevelDefault)))
+
+	entries, _, _ = dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 2)
+}
+
+// Test that the user who isn't a super-admin is forbidden to get and
+// set the logging level.
+func TestSetLogLevelForbidden(t *testing.T) {
+	// Arrange
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.InfoLevel)
+
+	rapi, err := NewRestAPI(dbSettings, db)
+	require.NoError(t, err)
+
+	user := &dbmodel.SystemUser{
+		Email:    "jan@example.org",
+		Lastname: "Kowalski",
+		Login:    "jan",
+		Name:     "Jan",
+		Password: "pass",
+		Groups: []*dbmodel.SystemGroup{
+			{ID: dbmodel.AdminGroupID},
+		},
+	}
+	conflict, err := dbmodel.CreateUser(rapi.DB, user)
+	require.False(t, conflict)
+	require.NoError(t, err)
+
+	ctx, err := rapi.SessionManager.Load(context.Background(), "")
+	require.NoError(t, err)
+	err = rapi.SessionManager.LoginHandler(ctx, user)
+	require.NoError(t, err)
+
+	level := "debug"
+
+	// Act
+	rsp := rapi.SetLogLevel(ctx, settings.SetLogLevelParams{
+		Level: &models.LogLevel{Level: &level},
+	})
+
+	// Assert
+	require.IsType(t, &settings.SetLogLevelDefault{}, rsp)
+	require.Equal(t, http.StatusForbidden, getStatusCode(*rsp.(*settings.SetLogLevelDefault)))
+	require.Equal(t, log.InfoLevel, log.GetLevel())
+
+	// Act
+	rsp = rapi.GetLogLevel(ctx, settings.GetLogLevelParams{})
+
+	// Assert
+	require.IsType(t, &settings.GetLogLevelDefault{}, rsp)
+	require.Equal(t, http.StatusForbidden, getStatusCode(*rsp.(*settings.GetLogLevelDefault)))
+}
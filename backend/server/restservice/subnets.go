@@ -2,11 +2,14 @@ package restservice
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	log "github.com/sirupsen/logrus"
+	"isc.org/stork/server/apps/kea"
 	dbmodel "isc.org/stork/server/database/model"
 
 	"isc.org/stork/server/gen/models"
@@ -18,6 +21,7 @@ func subnetToRestAPI(sn *dbmodel.Subnet) *models.Subnet {
 		ID:               sn.ID,
 		Subnet:           sn.Prefix,
 		ClientClass:      sn.ClientClass,
+		Note:             sn.Note,
 		AddrUtilization:  float64(sn.AddrUtilization) / 10,
 		Stats:            sn.Stats,
 		StatsCollectedAt: strfmt.DateTime(sn.StatsCollectedAt),
@@ -40,6 +44,7 @@ func subnetToRestAPI(sn *dbmodel.Subnet) *models.Subnet {
 			ID:               lsn.LocalSubnetID,
 			MachineAddress:   lsn.Daemon.App.Machine.Address,
 			MachineHostname:  lsn.Daemon.App.Machine.State.Hostname,
+			OriginalPrefix:   lsn.OriginalPrefix,
 			Stats:            lsn.Stats,
 			StatsCollectedAt: strfmt.DateTime(lsn.StatsCollectedAt),
 		}
@@ -48,9 +53,9 @@ func subnetToRestAPI(sn *dbmodel.Subnet) *models.Subnet {
 	return subnet
 }
 
-func (r *RestAPI) getSubnets(offset, limit, appID, family int64, filterText *string, sortField string, sortDir dbmodel.SortDirEnum) (*models.Subnets, error) {
+func (r *RestAPI) getSubnets(offset, limit, appID, family int64, filterText, appVersion *string, sortField string, sortDir dbmodel.SortDirEnum) (*models.Subnets, error) {
 	// get subnets from db
-	dbSubnets, total, err := dbmodel.GetSubnetsByPage(r.DB, offset, limit, appID, family, filterText, sortField, sortDir)
+	dbSubnets, total, err := dbmodel.GetSubnetsByPage(r.DB, offset, limit, appID, family, filterText, appVersion, sortField, sortDir)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +75,8 @@ func (r *RestAPI) getSubnets(offset, limit, appID, family int64, filterText *str
 	return subnets, nil
 }
 
-// Get list of DHCP subnets. The list can be filtered by app ID, DHCP version and text.
+// Get list of DHCP subnets. The list can be filtered by app ID, DHCP version,
+// app version and text.
 func (r *RestAPI) GetSubnets(ctx context.Context, params dhcp.GetSubnetsParams) middleware.Responder {
 	var start int64
 	if params.Start != nil {
@@ -93,7 +99,7 @@ func (r *RestAPI) GetSubnets(ctx context.Context, params dhcp.GetSubnetsParams)
 	}
 
 	// get subnets from db
-	subnets, err := r.getSubnets(start, limit, appID, dhcpVer, params.Text, "", dbmodel.SortDirAny)
+	subnets, err := r.getSubnets(start, limit, appID, dhcpVer, params.Text, params.AppVersion, "", dbmodel.SortDirAny)
 	if err != nil {
 		msg := "Cannot get subnets from db"
 		log.Error(err)
@@ -106,6 +112,100 @@ func (r *RestAPI) GetSubnets(ctx context.Context, params dhcp.GetSubnetsParams)
 	return rsp
 }
 
+// Get the utilization of the subnet computed on demand from the latest
+// statistics of its local subnets. The response also includes the utilization
+// stored by the statistics puller to make the discrepancies visible. The
+// utilization values are expressed in percents.
+func (r *RestAPI) GetSubnetLiveStats(ctx context.Context, params dhcp.GetSubnetLiveStatsParams) middleware.Responder {
+	dbSubnet, err := dbmodel.GetSubnet(r.DB, params.ID)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot get subnet with ID %d from db", params.ID)
+		rsp := dhcp.NewGetSubnetLiveStatsDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	if dbSubnet == nil {
+		msg := fmt.Sprintf("Cannot find subnet with ID %d", params.ID)
+		rsp := dhcp.NewGetSubnetLiveStatsDefault(http.StatusNotFound).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	utilization, err := kea.CalculateSubnetUtilization(r.DB, dbSubnet)
+	if err != nil {
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot compute utilization of subnet with ID %d", params.ID)
+		rsp := dhcp.NewGetSubnetLiveStatsDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	liveStats := &models.SubnetLiveStats{
+		SubnetID: dbSubnet.ID,
+		Cached: &models.SubnetUtilization{
+			AddrUtilization: float64(dbSubnet.AddrUtilization) / 10,
+			PdUtilization:   float64(dbSubnet.PdUtilization) / 10,
+			Stats:           dbSubnet.Stats,
+		},
+		CachedAt: strfmt.DateTime(dbSubnet.StatsCollectedAt),
+		Computed: &models.SubnetUtilization{
+			AddrUtilization: utilization.AddressUtilization * 100,
+			PdUtilization:   utilization.DelegatedPrefixUtilization * 100,
+			Stats:           utilization.Statistics,
+		},
+	}
+	rsp := dhcp.NewGetSubnetLiveStatsOK().WithPayload(liveStats)
+	return rsp
+}
+
+// Maximum length of the subnet note.
+const maxSubnetNoteLength = 1024
+
+// Set the free-text note of the subnet. An empty note removes the existing
+// note. The change is recorded in the audit log.
+func (r *RestAPI) UpdateSubnetNote(ctx context.Context, params dhcp.UpdateSubnetNoteParams) middleware.Responder {
+	if params.Note == nil {
+		msg := "Missing subnet note"
+		rsp := dhcp.NewUpdateSubnetNoteDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+	if len(params.Note.Note) > maxSubnetNoteLength {
+		msg := fmt.Sprintf("Subnet note must not be longer than %d characters", maxSubnetNoteLength)
+		rsp := dhcp.NewUpdateSubnetNoteDefault(http.StatusBadRequest).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	err := dbmodel.SetSubnetNote(r.DB, params.ID, params.Note.Note)
+	if err != nil {
+		if errors.Is(err, dbmodel.ErrNotExists) {
+			msg := fmt.Sprintf("Cannot find subnet with ID %d", params.ID)
+			rsp := dhcp.NewUpdateSubnetNoteDefault(http.StatusNotFound).WithPayload(&models.APIError{
+				Message: &msg,
+			})
+			return rsp
+		}
+		log.Error(err)
+		msg := fmt.Sprintf("Cannot set the note of the subnet with ID %d", params.ID)
+		rsp := dhcp.NewUpdateSubnetNoteDefault(http.StatusInternalServerError).WithPayload(&models.APIError{
+			Message: &msg,
+		})
+		return rsp
+	}
+
+	r.recordAuditLog(ctx, "subnet_note_updated", fmt.Sprintf("subnet %d", params.ID))
+
+	rsp := dhcp.NewUpdateSubnetNoteOK()
+	return rsp
+}
+
 func (r *RestAPI) getSharedNetworks(offset, limit, appID, family int64, filterText *string, sortField string, sortDir dbmodel.SortDirEnum) (*models.SharedNetworks, error) {
 	// get shared networks from db
 	dbSharedNetworks, total, err := dbmodel.GetSharedNetworksByPage(r.DB, offset, limit, appID, family, filterText, sortField, sortDir)
@@ -2,13 +2,17 @@ package restservice
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	agentcommtest "isc.org/stork/server/agentcomm/test"
+	"isc.org/stork/server/apps/kea"
 	dbmodel "isc.org/stork/server/database/model"
 	dbtest "isc.org/stork/server/database/test"
+	"isc.org/stork/server/gen/models"
 	dhcp "isc.org/stork/server/gen/restapi/operations/d_h_c_p"
 	storktest "isc.org/stork/server/test/dbmodel"
 )
@@ -353,6 +357,190 @@ func TestGetSubnets(t *testing.T) {
 	require.EqualValues(t, 1, okRsp.Payload.Items[0].LocalSubnets[0].ID)
 }
 
+// Check getting the subnet utilization computed on demand via rest api
+// functions.
+func TestGetSubnetLiveStats(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	settings := RestAPISettings{}
+	fa := agentcommtest.NewFakeAgents(nil, nil)
+	fec := &storktest.FakeEventCenter{}
+	fd := &storktest.FakeDispatcher{}
+	rapi, err := NewRestAPI(&settings, dbSettings, db, fa, fec, nil, fd, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// add machine
+	m := &dbmodel.Machine{
+		Address:   "localhost",
+		AgentPort: 8080,
+	}
+	err = dbmodel.AddMachine(db, m)
+	require.NoError(t, err)
+
+	// add app kea with dhcp4 to machine
+	var accessPoints []*dbmodel.AccessPoint
+	accessPoints = dbmodel.AppendAccessPoint(accessPoints, dbmodel.AccessPointControl, "", "", 1114, false)
+
+	a4 := &dbmodel.App{
+		ID:           0,
+		MachineID:    m.ID,
+		Type:         dbmodel.AppTypeKea,
+		Name:         "test-app4",
+		Active:       true,
+		AccessPoints: accessPoints,
+		Daemons: []*dbmodel.Daemon{
+			{
+				KeaDaemon: &dbmodel.KeaDaemon{
+					Config: dbmodel.NewKeaConfig(&map[string]interface{}{
+						"Dhcp4": &map[string]interface{}{
+							"subnet4": []map[string]interface{}{{
+								"id":     1,
+								"subnet": "192.168.0.0/24",
+								"pools": []map[string]interface{}{{
+									"pool": "192.168.0.1-192.168.0.100",
+								}},
+							}},
+						},
+					}),
+				},
+			},
+		},
+	}
+	_, err = dbmodel.AddApp(db, a4)
+	require.NoError(t, err)
+
+	appSubnets := []dbmodel.Subnet{
+		{
+			Prefix: "192.168.0.0/24",
+			AddressPools: []dbmodel.AddressPool{
+				{
+					LowerBound: "192.168.0.1",
+					UpperBound: "192.168.0.100",
+				},
+			},
+		},
+	}
+	addedSubnets, err := dbmodel.CommitNetworksIntoDB(db, []dbmodel.SharedNetwork{}, appSubnets, a4.Daemons[0])
+	require.NoError(t, err)
+	require.Len(t, addedSubnets, 1)
+
+	subnet, err := dbmodel.GetSubnet(db, addedSubnets[0].ID)
+	require.NoError(t, err)
+	require.Len(t, subnet.LocalSubnets, 1)
+
+	// The statistics pulled after the last utilization update.
+	err = subnet.LocalSubnets[0].UpdateStats(db, dbmodel.SubnetStats{
+		"total-addresses":    uint64(100),
+		"assigned-addresses": uint64(25),
+		"declined-addresses": uint64(0),
+	})
+	require.NoError(t, err)
+
+	// get live stats of the subnet
+	params := dhcp.GetSubnetLiveStatsParams{
+		ID: subnet.ID,
+	}
+	rsp := rapi.GetSubnetLiveStats(ctx, params)
+	require.IsType(t, &dhcp.GetSubnetLiveStatsOK{}, rsp)
+	okRsp := rsp.(*dhcp.GetSubnetLiveStatsOK)
+	require.EqualValues(t, subnet.ID, okRsp.Payload.SubnetID)
+
+	// The stored utilization hasn't been updated yet.
+	require.NotNil(t, okRsp.Payload.Cached)
+	require.Zero(t, okRsp.Payload.Cached.AddrUtilization)
+
+	// The computed utilization should match the calculator output.
+	subnet, err = dbmodel.GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	utilization, err := kea.CalculateSubnetUtilization(db, subnet)
+	require.NoError(t, err)
+	require.NotNil(t, okRsp.Payload.Computed)
+	require.InDelta(t, 25.0, okRsp.Payload.Computed.AddrUtilization, 0.001)
+	require.InDelta(t, utilization.AddressUtilization*100, okRsp.Payload.Computed.AddrUtilization, 0.001)
+	require.Zero(t, okRsp.Payload.Computed.PdUtilization)
+	require.EqualValues(t, utilization.Statistics, okRsp.Payload.Computed.Stats)
+
+	// get live stats of non-existing subnet
+	params = dhcp.GetSubnetLiveStatsParams{
+		ID: subnet.ID + 1,
+	}
+	rsp = rapi.GetSubnetLiveStats(ctx, params)
+	require.IsType(t, &dhcp.GetSubnetLiveStatsDefault{}, rsp)
+	defaultRsp := rsp.(*dhcp.GetSubnetLiveStatsDefault)
+	require.Equal(t, http.StatusNotFound, getStatusCode(*defaultRsp))
+}
+
+// Test that the subnet note is set via the REST API and returned with
+// the subnet.
+func TestUpdateSubnetNote(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	rapi, err := NewRestAPI(dbSettings, db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	subnet := &dbmodel.Subnet{
+		Prefix: "192.0.2.0/24",
+	}
+	err = dbmodel.AddSubnet(db, subnet)
+	require.NoError(t, err)
+
+	// Set the note.
+	rsp := rapi.UpdateSubnetNote(ctx, dhcp.UpdateSubnetNoteParams{
+		ID: subnet.ID,
+		Note: &models.SubnetNote{
+			Note: "migration pending",
+		},
+	})
+	require.IsType(t, &dhcp.UpdateSubnetNoteOK{}, rsp)
+
+	// The note should be returned with the subnet.
+	rsp = rapi.GetSubnets(ctx, dhcp.GetSubnetsParams{})
+	require.IsType(t, &dhcp.GetSubnetsOK{}, rsp)
+	okRsp := rsp.(*dhcp.GetSubnetsOK)
+	require.Len(t, okRsp.Payload.Items, 1)
+	require.Equal(t, "migration pending", okRsp.Payload.Items[0].Note)
+
+	entries, _, _ := dbmodel.GetAuditLogByPage(db, 0, 10, "", dbmodel.SortDirAny)
+	require.Len(t, entries, 1)
+	require.Equal(t, "subnet_note_updated", entries[0].Action)
+
+	// Non-existing subnet.
+	rsp = rapi.UpdateSubnetNote(ctx, dhcp.UpdateSubnetNoteParams{
+		ID: subnet.ID + 1,
+		Note: &models.SubnetNote{
+			Note: "migration pending",
+		},
+	})
+	require.IsType(t, &dhcp.UpdateSubnetNoteDefault{}, rsp)
+	require.Equal(t, http.StatusNotFound, getStatusCode(*rsp.(*dhcp.UpdateSubnetNoteDefault)))
+
+	// Too long note.
+	rsp = rapi.UpdateSubnetNote(ctx, dhcp.UpdateSubnetNoteParams{
+		ID: subnet.ID,
+		Note: &models.SubnetNote{
+			Note: strings.Repeat("a", maxSubnetNoteLength+1),
+		},
+	})
+	require.IsType(t, &dhcp.UpdateSubnetNoteDefault{}, rsp)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*rsp.(*dhcp.UpdateSubnetNoteDefault)))
+
+	// Missing note.
+	rsp = rapi.UpdateSubnetNote(ctx, dhcp.UpdateSubnetNoteParams{
+		ID: subnet.ID,
+	})
+	require.IsType(t, &dhcp.UpdateSubnetNoteDefault{}, rsp)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*rsp.(*dhcp.UpdateSubnetNoteDefault)))
+
+	// The note is preserved.
+	returned, err := dbmodel.GetSubnet(db, subnet.ID)
+	require.NoError(t, err)
+	require.Equal(t, "migration pending", returned.Note)
+}
+
 // Check getting shared networks via rest api functions.
 func TestGetSharedNetworks(t *testing.T) {
 	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -68,6 +68,12 @@ func (r *RestAPI) CreateSession(ctx context.Context, params users.CreateSessionP
 	}
 
 	ok, err := dbmodel.Authenticate(r.DB, user)
+	if ok && strings.HasPrefix(user.Login, oidcLoginPrefix) {
+		// The accounts provisioned from the OIDC tokens can be only used
+		// with the bearer tokens.
+		log.Warnf("Rejected local login to the OIDC user account %s", user.Login)
+		ok = false
+	}
 	if ok {
 		err = r.SessionManager.LoginHandler(ctx, user)
 	}
@@ -195,6 +201,16 @@ func (r *RestAPI) CreateUser(ctx context.Context, params users.CreateUserParams)
 		return users.NewCreateUserDefault(http.StatusBadRequest).WithPayload(&rspErr)
 	}
 
+	if strings.HasPrefix(*u.Login, oidcLoginPrefix) {
+		log.Warnf("Failed to create new user account: login %s is reserved for OIDC users", *u.Login)
+
+		msg := fmt.Sprintf("Failed to create new user account: logins starting with %s are reserved for OIDC users", oidcLoginPrefix)
+		rspErr := models.APIError{
+			Message: &msg,
+		}
+		return users.NewCreateUserDefault(http.StatusBadRequest).WithPayload(&rspErr)
+	}
+
 	su := &dbmodel.SystemUser{
 		Login:    *u.Login,
 		Email:    *u.Email,
@@ -258,6 +274,16 @@ func (r *RestAPI) UpdateUser(ctx context.Context, params users.UpdateUserParams)
 		return users.NewUpdateUserDefault(http.StatusBadRequest).WithPayload(&rspErr)
 	}
 
+	if strings.HasPrefix(*u.Login, oidcLoginPrefix) {
+		log.Warnf("Failed to update user account: login %s is reserved for OIDC users", *u.Login)
+
+		msg := fmt.Sprintf("Failed to update user account: logins starting with %s are reserved for OIDC users", oidcLoginPrefix)
+		rspErr := models.APIError{
+			Message: &msg,
+		}
+		return users.NewUpdateUserDefault(http.StatusBadRequest).WithPayload(&rspErr)
+	}
+
 	su := &dbmodel.SystemUser{
 		ID:       int(*u.ID),
 		Login:    *u.Login,
@@ -113,6 +113,55 @@ func TestCreateUser(t *testing.T) {
 	require.Equal(t, 409, getStatusCode(*defaultRsp))
 }
 
+// Tests that the user accounts with the logins reserved for the OIDC users
+// can't be created or updated via REST API.
+func TestCreateUpdateUserOIDCLogin(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	ctx := context.Background()
+	rapi, _ := NewRestAPI(dbSettings, db)
+
+	su := dbmodel.SystemUser{
+		Email:    "jb@example.org",
+		Lastname: "Born",
+		Login:    "oidc:1234",
+		Name:     "John",
+	}
+	createParams := users.CreateUserParams{
+		Account: &models.UserAccount{
+			User:     newRestUser(su),
+			Password: models.Password("pass"),
+		},
+	}
+	rsp := rapi.CreateUser(ctx, createParams)
+	require.IsType(t, &users.CreateUserDefault{}, rsp)
+	defaultRsp := rsp.(*users.CreateUserDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*defaultRsp))
+
+	// Create the local user and try to rename it.
+	su.Login = "jb"
+	su.Password = "pass"
+	_, err := dbmodel.CreateUser(db, &su)
+	require.NoError(t, err)
+
+	su.Login = "oidc:1234"
+	updateParams := users.UpdateUserParams{
+		Account: &models.UserAccount{
+			User:     newRestUser(su),
+			Password: models.Password(""),
+		},
+	}
+	rsp = rapi.UpdateUser(ctx, updateParams)
+	require.IsType(t, &users.UpdateUserDefault{}, rsp)
+	updateRsp := rsp.(*users.UpdateUserDefault)
+	require.Equal(t, http.StatusBadRequest, getStatusCode(*updateRsp))
+
+	returned, err := dbmodel.GetUserByID(db, su.ID)
+	require.NoError(t, err)
+	require.Equal(t, "jb", returned.Login)
+}
+
 // Tests that user account can be updated via REST API.
 func TestUpdateUser(t *testing.T) {
 	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
@@ -423,3 +472,37 @@ func TestCreateSession(t *testing.T) {
 	rsp = rapi.DeleteSession(ctx2, delParams)
 	require.IsType(t, &users.DeleteSessionOK{}, rsp)
 }
+
+// Tests that the user account provisioned from the OIDC token can't be
+// used to log in with the credentials.
+func TestCreateSessionOIDCUser(t *testing.T) {
+	db, dbSettings, teardown := dbtest.SetupDatabaseTestCase(t)
+	defer teardown()
+
+	ctx := context.Background()
+	rapi, _ := NewRestAPI(dbSettings, db)
+
+	user := &dbmodel.SystemUser{
+		Login:    "oidc:1234",
+		Email:    "jan@example.org",
+		Lastname: "Kowalski",
+		Name:     "Jan",
+		Password: "pass",
+	}
+	_, err := dbmodel.CreateUser(db, user)
+	require.NoError(t, err)
+
+	ctx, err = rapi.SessionManager.Load(ctx, "")
+	require.NoError(t, err)
+
+	for _, login := range []string{user.Login, user.Email} {
+		params := users.CreateSessionParams{}
+		params.Credentials.Useremail = &login
+		params.Credentials.Userpassword = &user.Password
+
+		rsp := rapi.CreateSession(ctx, params)
+		require.IsType(t, &users.CreateSessionBadRequest{}, rsp, login)
+		ok, _ := rapi.SessionManager.Logged(ctx)
+		require.False(t, ok, login)
+	}
+}
@@ -47,6 +47,8 @@ type StorkServer struct {
 	Pullers *apps.Pullers
 
 	InitialPullerInterval int64
+	ConfigReviewWorkers   int
+	ConfigCheckerWorkers  int
 	EnableMetricsEndpoint bool
 	MetricsCollector      metrics.Collector
 
@@ -64,6 +66,8 @@ type Settings struct {
 	Version               bool  `short:"v" long:"version" description:"Show software version"`
 	EnableMetricsEndpoint bool  `short:"m" long:"metrics" description:"Enable Prometheus /metrics endpoint (no auth)" env:"STORK_SERVER_ENABLE_METRICS"`
 	InitialPullerInterval int64 `long:"initial-puller-interval" description:"Initial interval used by pullers fetching data from Kea. If not provided the recommended values for each puller are used." env:"STORK_SERVER_INITIAL_PULLER_INTERVAL"`
+	ConfigReviewWorkers   int   `long:"config-review-workers" description:"Maximum number of daemons whose configurations are reviewed concurrently." default:"4" env:"STORK_SERVER_CONFIG_REVIEW_WORKERS"`
+	ConfigCheckerWorkers  int   `long:"config-checker-workers" description:"Maximum number of configuration checkers run concurrently during a review of a single daemon." default:"4" env:"STORK_SERVER_CONFIG_CHECKER_WORKERS"`
 }
 
 // Parse the command line arguments into GO structures.
@@ -106,6 +110,8 @@ func (ss *StorkServer) ParseArgs() (command Command, err error) {
 
 	ss.EnableMetricsEndpoint = serverSettings.EnableMetricsEndpoint
 	ss.InitialPullerInterval = serverSettings.InitialPullerInterval
+	ss.ConfigReviewWorkers = serverSettings.ConfigReviewWorkers
+	ss.ConfigCheckerWorkers = serverSettings.ConfigCheckerWorkers
 
 	if serverSettings.Version {
 		// If user specified --version or -v, print the version and quit.
@@ -163,7 +169,7 @@ func (ss *StorkServer) Bootstrap(reload bool) (err error) {
 	// }()
 
 	// Setup configuration review dispatcher.
-	ss.ReviewDispatcher = configreview.NewDispatcher(ss.DB)
+	ss.ReviewDispatcher = configreview.NewDispatcherWithWorkers(ss.DB, ss.ConfigReviewWorkers, ss.ConfigCheckerWorkers)
 	configreview.RegisterDefaultCheckers(ss.ReviewDispatcher)
 	err = configreview.LoadAndValidateCheckerPreferences(ss.DB, ss.ReviewDispatcher)
 	if err != nil {
@@ -213,6 +219,12 @@ func (ss *StorkServer) Bootstrap(reload bool) (err error) {
 		return err
 	}
 
+	// Setup Kea config review puller.
+	ss.Pullers.KeaConfigReviewPuller, err = kea.NewConfigReviewPuller(ss.DB, ss.ReviewDispatcher)
+	if err != nil {
+		return err
+	}
+
 	if ss.EnableMetricsEndpoint {
 		ss.MetricsCollector, err = metrics.NewCollector(ss.DB)
 		if err != nil {
@@ -237,6 +249,7 @@ func (ss *StorkServer) Bootstrap(reload bool) (err error) {
 		ss.Pullers, ss.ReviewDispatcher, ss.MetricsCollector, ss.ConfigManager,
 		ss.DHCPOptionDefinitionLookup)
 	if err != nil {
+		ss.Pullers.KeaConfigReviewPuller.Shutdown()
 		ss.Pullers.HAStatusPuller.Shutdown()
 		ss.Pullers.KeaHostsPuller.Shutdown()
 		ss.Pullers.KeaStatsPuller.Shutdown()
@@ -282,6 +295,7 @@ func (ss *StorkServer) Shutdown(reload bool) {
 			log.Println("Shutting down Stork Server")
 		}
 		ss.RestAPI.Shutdown()
+		ss.Pullers.KeaConfigReviewPuller.Shutdown()
 		ss.Pullers.HAStatusPuller.Shutdown()
 		ss.Pullers.KeaHostsPuller.Shutdown()
 		ss.Pullers.KeaStatsPuller.Shutdown()
@@ -32,9 +32,10 @@ func getExpectedSwitches() []string {
 	return []string{
 		"-v", "-m", "--metrics", "--version", "-d", "--db-name", "-u", "--db-user", "--db-host",
 		"-p", "--db-port", "--db-trace-queries", "--rest-cleanup-timeout", "--rest-graceful-timeout",
-		"--rest-max-header-size", "--rest-host", "--rest-port", "--rest-listen-limit",
+		"--rest-max-header-size", "--rest-network", "--rest-host", "--rest-port", "--rest-listen-limit",
 		"--rest-keep-alive", "--rest-read-timeout", "--rest-write-timeout", "--rest-tls-certificate",
-		"--rest-tls-key", "--rest-tls-ca", "--rest-static-files-dir", "--initial-puller-interval",
+		"--rest-tls-key", "--rest-tls-ca", "--rest-static-files-dir", "--rest-static-files-max-age",
+		"--initial-puller-interval", "--config-review-workers",
 	}
 }
 
@@ -113,6 +114,7 @@ func TestNewStorkServer(t *testing.T) {
 		"--rest-cleanup-timeout", "12s",
 		"--rest-graceful-timeout", "34m",
 		"--rest-max-header-size", "56",
+		"--rest-network", "tcp6",
 		"--rest-host", "resthost",
 		"--rest-port", "1234",
 		"--rest-listen-limit", "78",
@@ -124,6 +126,8 @@ func TestNewStorkServer(t *testing.T) {
 		"--rest-tls-ca", "tlsca",
 		"--rest-static-files-dir", "staticdir",
 		"--initial-puller-interval", "54",
+		"--config-review-workers", "8",
+		"--config-checker-workers", "6",
 	)
 
 	// Act
@@ -146,6 +150,7 @@ func TestNewStorkServer(t *testing.T) {
 	require.EqualValues(t, 12*time.Second, ss.RestAPISettings.CleanupTimeout)
 	require.EqualValues(t, 34*time.Minute, ss.RestAPISettings.GracefulTimeout)
 	require.EqualValues(t, 56, ss.RestAPISettings.MaxHeaderSize)
+	require.EqualValues(t, "tcp6", ss.RestAPISettings.Network)
 	require.EqualValues(t, "resthost", ss.RestAPISettings.Host)
 	require.EqualValues(t, 1234, ss.RestAPISettings.Port)
 	require.EqualValues(t, 78, ss.RestAPISettings.ListenLimit)
@@ -157,6 +162,8 @@ func TestNewStorkServer(t *testing.T) {
 	require.EqualValues(t, "tlsca", ss.RestAPISettings.TLSCACertificate)
 	require.EqualValues(t, "staticdir", ss.RestAPISettings.StaticFilesDir)
 	require.EqualValues(t, 54, ss.InitialPullerInterval)
+	require.EqualValues(t, 8, ss.ConfigReviewWorkers)
+	require.EqualValues(t, 6, ss.ConfigCheckerWorkers)
 }
 
 // Test that the Stork Server is not constructed if the arguments are wrong.
@@ -32,6 +32,10 @@ func (d *FakeDispatcher) RegisterChecker(selector configreview.DispatchGroupSele
 	d.CallLog = append(d.CallLog, FakeDispatcherCall{CallName: "RegisterChecker"})
 }
 
+func (d *FakeDispatcher) RegisterCheckerWithInfo(selector configreview.DispatchGroupSelector, checkerName string, triggers configreview.Triggers, checkFn func(*configreview.ReviewContext) (*configreview.Report, error), info configreview.CheckerInfo) {
+	d.CallLog = append(d.CallLog, FakeDispatcherCall{CallName: "RegisterCheckerWithInfo"})
+}
+
 func (d *FakeDispatcher) UnregisterChecker(selector configreview.DispatchGroupSelector, checkerName string) bool {
 	d.CallLog = append(d.CallLog, FakeDispatcherCall{CallName: "UnregisterChecker"})
 	return true
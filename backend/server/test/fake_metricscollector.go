@@ -7,6 +7,8 @@ import "net/http"
 type FakeMetricsCollector struct {
 	IsRunning    bool
 	RequestCount int
+	// IDs of the machines whose metrics were requested.
+	MachineIDs []int64
 }
 
 func NewFakeMetricsCollector() *FakeMetricsCollector {
@@ -22,6 +24,13 @@ func (c *FakeMetricsCollector) GetHTTPHandler(next http.Handler) http.Handler {
 	})
 }
 
+func (c *FakeMetricsCollector) GetMachineHTTPHandler(machineID int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.RequestCount++
+		c.MachineIDs = append(c.MachineIDs, machineID)
+	})
+}
+
 func (c *FakeMetricsCollector) Shutdown() {
 	c.IsRunning = false
 }
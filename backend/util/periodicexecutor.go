@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -16,6 +17,7 @@ type PeriodicExecutor struct {
 	interval        int64
 	ticker          *time.Ticker
 	active          bool
+	running         bool
 	pauseCount      uint16
 	done            chan bool
 	wg              *sync.WaitGroup
@@ -25,6 +27,10 @@ type PeriodicExecutor struct {
 
 const InactiveInterval int64 = 60
 
+// Error returned when the executor is requested to execute the function
+// while it is already executing it.
+var ErrPeriodicExecutorRunning = errors.New("periodic executor is already running")
+
 // Creates an instance of a new periodic executor. The periodic executor offers a mechanism
 // to periodically trigger an action. This action is supplied as a function instance.
 // This function is executed within a goroutine periodically according to the timer
@@ -151,6 +157,48 @@ func (executor *PeriodicExecutor) Reset(interval int64) {
 	executor.unpause(false, interval)
 }
 
+// Marks the executor as executing the function. It returns false if the
+// executor is already executing it.
+func (executor *PeriodicExecutor) beginExecution() bool {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	if executor.running {
+		return false
+	}
+	executor.running = true
+	return true
+}
+
+// Marks the executor as not executing the function.
+func (executor *PeriodicExecutor) endExecution() {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	executor.running = false
+}
+
+// Checks if the executor is currently executing the function.
+func (executor *PeriodicExecutor) Running() bool {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	return executor.running
+}
+
+// Executes the function immediately, regardless of the timer. The timer
+// is paused while the function is executed and it is rescheduled to the
+// full interval afterwards. It returns ErrPeriodicExecutorRunning if the
+// function is already being executed, either on the timer or on an
+// earlier call to this function. Otherwise, it returns the error returned
+// by the function.
+func (executor *PeriodicExecutor) ExecuteNow() error {
+	if !executor.beginExecution() {
+		return ErrPeriodicExecutorRunning
+	}
+	defer executor.endExecution()
+	executor.Pause()
+	defer executor.Unpause()
+	return executor.executorFunc()
+}
+
 // This function controls the timing of the function execution and captures the
 // termination signal.
 func (executor *PeriodicExecutor) executorLoop() {
@@ -159,12 +207,15 @@ func (executor *PeriodicExecutor) executorLoop() {
 		select {
 		// every N seconds execute user defined function
 		case <-executor.ticker.C:
-			if executor.active {
+			// Skip the execution if the function is already being executed
+			// on demand.
+			if executor.active && executor.beginExecution() {
 				// Temporarily stop the executor while running the external action.
 				// It will be resumed when the action ends.
 				executor.Pause()
 				err := executor.executorFunc()
 				executor.Unpause()
+				executor.endExecution()
 				if err != nil {
 					log.Errorf("Errors were encountered while pulling data from apps: %+v", err)
 				}
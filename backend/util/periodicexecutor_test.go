@@ -1,6 +1,7 @@
 package storkutil
 
 import (
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -171,3 +172,46 @@ func TestGetName(t *testing.T) {
 	// Assert
 	require.EqualValues(t, "foobar", name)
 }
+
+// Test that the function is executed on demand and that it cannot be
+// executed concurrently.
+func TestExecuteNow(t *testing.T) {
+	// Arrange
+	started := make(chan bool)
+	finish := make(chan bool)
+	var calls int64
+	executorFunc := func() error {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			started <- true
+			<-finish
+			return nil
+		}
+		return errors.New("test error")
+	}
+	// Long interval ensures that the timer doesn't trigger the function.
+	executor, _ := NewPeriodicExecutor("foobar", executorFunc, func() (int64, error) { return 3600, nil })
+	defer executor.Shutdown()
+
+	var err error
+	done := make(chan bool)
+	go func() {
+		err = executor.ExecuteNow()
+		done <- true
+	}()
+	<-started
+
+	// Act & Assert
+	require.True(t, executor.Running())
+	require.True(t, executor.Paused())
+	require.ErrorIs(t, executor.ExecuteNow(), ErrPeriodicExecutorRunning)
+
+	finish <- true
+	<-done
+	require.NoError(t, err)
+	require.False(t, executor.Running())
+	require.False(t, executor.Paused())
+
+	// The error returned by the function is passed to the caller.
+	require.ErrorContains(t, executor.ExecuteNow(), "test error")
+	require.EqualValues(t, 2, atomic.LoadInt64(&calls))
+}
@@ -164,6 +164,11 @@ export class ConfigCheckerPreferencePickerComponent {
                 return (
                     'The checker verifying if the host_cmds hooks library is ' + 'loaded when host backend is in use.'
                 )
+            case 'lease_cmds_backend_presence':
+                return (
+                    'The checker verifying if the lease database backend is configured ' +
+                    'when the lease_cmds hooks library is loaded.'
+                )
             case 'dispensable_shared_network':
                 return (
                     'The checker verifying if a shared network can be removed ' +
@@ -184,8 +189,155 @@ export class ConfigCheckerPreferencePickerComponent {
                 )
             case 'overlapping_subnet':
                 return 'The checker verifying if subnet prefixes do not overlap.'
+            case 'duplicate_subnet_id':
+                return 'The checker verifying if the subnet IDs are unique.'
             case 'canonical_prefix':
                 return 'The checker verifying if subnet prefixes are in the ' + 'canonical form.'
+            case 'overlapping_shared_network_pool':
+                return (
+                    'The checker verifying if the address pools of the subnets ' +
+                    'belonging to the same shared network do not overlap.'
+                )
+            case 'overlapping_subnet_pool':
+                return 'The checker verifying if the address pools within the same subnet do not overlap.'
+            case 'pool_size_below_minimum':
+                return (
+                    'The checker verifying if the address pools contain at least ' +
+                    'the number of addresses specified in the min_pool_size setting.'
+                )
+            case 'pool_options_conflict':
+                return (
+                    'The checker verifying if the DHCP options specified for the ' +
+                    'address pools do not override the subnet-level options with ' +
+                    'different values.'
+                )
+            case 'shared_network_match_client_id':
+                return (
+                    'The checker verifying if the subnets belonging to the same shared ' +
+                    'network use the same effective match-client-id value.'
+                )
+            case 'shared_network_prefix_length':
+                return (
+                    'The checker verifying if the subnets belonging to the same ' +
+                    'shared network have the same prefix length. A subnet with a ' +
+                    'different prefix length may indicate a typo.'
+                )
+            case 'shared_network_daemons_consistency':
+                return (
+                    'The checker verifying if all subnets belonging to a shared ' +
+                    'network are served by the same set of daemons. Otherwise, the ' +
+                    'aggregated shared network utilization may be misleading.'
+                )
+            case 'subnet_daemons_consistency':
+                return (
+                    'The checker verifying if the subnets served by multiple daemons ' +
+                    'have the same pools and options on all of them. Otherwise, the ' +
+                    'clients may get inconsistent leases depending on which server responds.'
+                )
+            case 'interfaces_config':
+                return (
+                    'The checker verifying if the DHCP server listens on any ' +
+                    'interfaces and if it does not listen on all interfaces ' +
+                    'using the wildcard.'
+                )
+            case 'multi_threading':
+                return (
+                    'The checker verifying if the multi-threading settings of the ' +
+                    'DHCP server are consistent and effective.'
+                )
+            case 'lease_sanity_checks':
+                return (
+                    'The checker verifying if the lease sanity checks are not ' +
+                    'disabled in the DHCP server configuration.'
+                )
+            case 'in_pool_reservation_mode':
+                return (
+                    'The checker finding the subnets with the host reservations outside ' +
+                    'of the pools while the in-subnet reservation mode is enabled and ' +
+                    'the out-of-pool reservation mode is disabled.'
+                )
+            case 'ddns_flags':
+                return (
+                    'The checker verifying if the combinations of the DDNS flags ' +
+                    'at the global, shared network and subnet levels are consistent.'
+                )
+            case 'reservation_family_mismatch':
+                return (
+                    'The checker verifying if the host reservations specify the option ' +
+                    "spaces and the address fields appropriate for the server's family."
+                )
+            case 'pool_covers_entire_subnet':
+                return (
+                    'The checker verifying if the address pools of the DHCPv4 subnets ' +
+                    'exclude the network and broadcast addresses.'
+                )
+            case 'dhcp6_subnet_selectors':
+                return (
+                    'The checker verifying if the DHCPv6 subnets specify the interface, ' +
+                    'the relay addresses or the interface-id used to select the subnet for the clients.'
+                )
+            case 'pd_reservation_delegated_len':
+                return (
+                    'The checker verifying if the lengths of the reserved delegated prefixes ' +
+                    'match the delegated lengths of the prefix delegation pools in their subnets.'
+                )
+            case 'prefix_aggregation':
+                return (
+                    'The checker suggesting the aggregation of the adjacent DHCPv6 subnets with ' +
+                    'the same prefix length which form complete larger prefixes.'
+                )
+            case 'reservation_identifiers_not_listed':
+                return (
+                    'The checker verifying if the host reservations use the identifier types ' +
+                    'listed in the host-reservation-identifiers parameter.'
+                )
+            case 'option_def_standard_conflict':
+                return (
+                    'The checker verifying if the option definitions do not redefine ' +
+                    'the standard DHCP options with different types.'
+                )
+            case 'ca_basic_auth_realm':
+                return (
+                    'The checker verifying if the Kea Control Agent enabling the basic ' +
+                    'HTTP authentication specifies the authentication realm.'
+                )
+            case 'logger_file_output_systemd':
+                return (
+                    'The checker verifying if the Kea loggers write to the files while the daemon ' +
+                    'appears to run under systemd, which captures the output in the journal.'
+                )
+            case 'dns_servers_option':
+                return (
+                    'The checker verifying if the DHCPv4 subnets with the address ' +
+                    'pools have the domain-name-servers option specified at any ' +
+                    'configuration level.'
+                )
+            case 'address_space_exhaustion':
+                return (
+                    'The checker verifying if the address pools and host ' +
+                    'reservations of the small DHCPv4 subnets leave at least ' +
+                    'one address for the router.'
+                )
+            case 'preferred_lifetime':
+                return (
+                    'The checker verifying if the preferred lifetime of the ' +
+                    'DHCPv6 subnets is lower than the valid lifetime.'
+                )
+            case 'valid_lifetime_unspecified':
+                return (
+                    'The checker verifying if the valid lifetime is specified for the subnets ' +
+                    'at the subnet, shared network or global level.'
+                )
+            case 'reservation_hostname':
+                return (
+                    'The checker verifying if the hostnames specified in the host ' +
+                    'reservations are not blank and follow the basic hostname rules.'
+                )
+            case 'reservation_unknown_subnet':
+                return (
+                    'The checker verifying if the host reservations in the host database ' +
+                    'reference the subnets configured in the DHCP server.'
+                )
             default:
                 return ''
         }
@@ -285,6 +285,13 @@ describe('ConfigReviewPanelComponent', () => {
         expect(reviewButton).toBeTruthy()
     }))
 
+    it('should map the report severity to the tag severity', () => {
+        expect(component.getReportTagSeverity('info')).toBe('info')
+        expect(component.getReportTagSeverity('warning')).toBe('warning')
+        expect(component.getReportTagSeverity('error')).toBe('danger')
+        expect(component.getReportTagSeverity(undefined)).toBe('warning')
+    })
+
     it('should report an error when review request fails', fakeAsync(() => {
         // Simulate the situation that the reports were already fetched.
         component.reports = [
@@ -267,6 +267,25 @@ export class ConfigReviewPanelComponent implements OnInit {
         this.refreshDaemonConfigReports(event)
     }
 
+    /**
+     * Returns the severity of the tag presenting the report.
+     *
+     * The tag color indicates the severity of the issue described in
+     * the report. The reports without severity are presented as warnings.
+     *
+     * @param severity severity of the report returned by the server.
+     */
+    getReportTagSeverity(severity: string): string {
+        switch (severity) {
+            case 'info':
+                return 'info'
+            case 'error':
+                return 'danger'
+            default:
+                return 'warning'
+        }
+    }
+
     /**
      * Returns the status text displayed when no reports are available.
      *
@@ -6,8 +6,10 @@ import { BrowserAnimationsModule, NoopAnimationsModule } from '@angular/platform
 import { FieldsetModule } from 'primeng/fieldset'
 import { MessageService } from 'primeng/api'
 import { HttpClientTestingModule } from '@angular/common/http/testing'
+import { of } from 'rxjs'
 
 import { MessagesModule } from 'primeng/messages'
+import { CheckboxModule } from 'primeng/checkbox'
 
 import { SettingsPageComponent } from './settings-page.component'
 import { SettingsService } from '../backend/api/api'
@@ -21,6 +23,7 @@ import { RouterTestingModule } from '@angular/router/testing'
 describe('SettingsPageComponent', () => {
     let component: SettingsPageComponent
     let fixture: ComponentFixture<SettingsPageComponent>
+    let settingsApi: SettingsService
 
     beforeEach(waitForAsync(() => {
         TestBed.configureTestingModule({
@@ -35,6 +38,7 @@ describe('SettingsPageComponent', () => {
                 OverlayPanelModule,
                 NoopAnimationsModule,
                 RouterTestingModule,
+                CheckboxModule,
             ],
             declarations: [SettingsPageComponent, BreadcrumbsComponent, HelpTipComponent],
             providers: [
@@ -51,6 +55,7 @@ describe('SettingsPageComponent', () => {
     beforeEach(() => {
         fixture = TestBed.createComponent(SettingsPageComponent)
         component = fixture.componentInstance
+        settingsApi = fixture.debugElement.injector.get(SettingsService)
         fixture.detectChanges()
     })
 
@@ -72,4 +77,32 @@ describe('SettingsPageComponent', () => {
         expect(breadcrumbsComponent.items[0].label).toEqual('Configuration')
         expect(breadcrumbsComponent.items[1].label).toEqual('Settings')
     })
+
+    it('should load and save the utilization histogram setting', () => {
+        spyOn(settingsApi, 'getSettings').and.returnValue(
+            of({ metrics_utilization_histogram: true, max_checker_findings: 10 } as any)
+        )
+        const updateSpy = spyOn(settingsApi, 'updateSettings').and.returnValue(of({} as any))
+
+        component.ngOnInit()
+        fixture.detectChanges()
+
+        const checkbox = fixture.debugElement.query(By.css('#metrics-utilization-histogram'))
+        expect(checkbox).toBeTruthy()
+        expect(component.settingsForm.get('metrics_utilization_histogram').value).toBeTrue()
+
+        component.settingsForm.get('metrics_utilization_histogram').setValue(false)
+        component.saveSettings()
+
+        expect(updateSpy).toHaveBeenCalled()
+        expect(updateSpy.calls.mostRecent().args[0].metrics_utilization_histogram).toBeFalse()
+    })
+
+    it('should default the utilization histogram setting to false', () => {
+        spyOn(settingsApi, 'getSettings').and.returnValue(of({} as any))
+
+        component.ngOnInit()
+
+        expect(component.settingsForm.get('metrics_utilization_histogram').value).toBeFalse()
+    })
 })
@@ -22,8 +22,14 @@ export class SettingsPageComponent implements OnInit {
             grafana_url: [''],
             kea_hosts_puller_interval: ['', [Validators.required, Validators.min(0)]],
             kea_stats_puller_interval: ['', [Validators.required, Validators.min(0)]],
+            kea_stats_puller_batch_size: ['', [Validators.required, Validators.min(0)]],
             kea_status_puller_interval: ['', [Validators.required, Validators.min(0)]],
             prometheus_url: [''],
+            metrics_utilization_histogram: [false],
+            min_pool_size: ['', [Validators.required, Validators.min(0)]],
+            max_checker_findings: ['', [Validators.required, Validators.min(1)]],
+            overlaps_detection_time_budget: ['', [Validators.required, Validators.min(0)]],
+            config_review_puller_interval: ['', [Validators.required, Validators.min(0)]],
         })
     }
 
@@ -34,9 +40,15 @@ export class SettingsPageComponent implements OnInit {
                     'bind9_stats_puller_interval',
                     'kea_hosts_puller_interval',
                     'kea_stats_puller_interval',
+                    'kea_stats_puller_batch_size',
                     'kea_status_puller_interval',
+                    'min_pool_size',
+                    'max_checker_findings',
+                    'overlaps_detection_time_budget',
+                    'config_review_puller_interval',
                 ]
                 const stringSettings = ['grafana_url', 'prometheus_url']
+                const booleanSettings = ['metrics_utilization_histogram']
 
                 for (const s of numericSettings) {
                     if (data[s] === undefined) {
@@ -48,6 +60,11 @@ export class SettingsPageComponent implements OnInit {
                         data[s] = ''
                     }
                 }
+                for (const s of booleanSettings) {
+                    if (data[s] === undefined) {
+                        data[s] = false
+                    }
+                }
 
                 this.settingsForm.patchValue(data)
             },